@@ -0,0 +1,71 @@
+package peds
+
+import "cmp"
+
+// Number is a constraint permitting any integer or floating point type.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Sum returns the sum of the elements in v, zero if v is empty.
+func Sum[T Number](v *Vector[T]) T {
+	var sum T
+	v.RangeChunks(func(chunk []T) bool {
+		for _, item := range chunk {
+			sum += item
+		}
+
+		return true
+	})
+
+	return sum
+}
+
+// Mean returns the arithmetic mean of the elements in v. ok is set to false if v is empty.
+func Mean[T Number](v *Vector[T]) (mean float64, ok bool) {
+	if v.Len() == 0 {
+		return 0, false
+	}
+
+	// Summing as float64 avoids overflow of small integer types
+	var sum float64
+	v.RangeChunks(func(chunk []T) bool {
+		for _, item := range chunk {
+			sum += float64(item)
+		}
+
+		return true
+	})
+
+	return sum / float64(v.Len()), true
+}
+
+// Min returns the smallest element in v. ok is set to false if v is empty. For floating point
+// elements NaN is returned if any element is NaN, like the built in min.
+func Min[T cmp.Ordered](v *Vector[T]) (result T, ok bool) {
+	return extreme(v, func(a, b T) T { return min(a, b) })
+}
+
+// Max returns the largest element in v. ok is set to false if v is empty. For floating point
+// elements NaN is returned if any element is NaN, like the built in max.
+func Max[T cmp.Ordered](v *Vector[T]) (result T, ok bool) {
+	return extreme(v, func(a, b T) T { return max(a, b) })
+}
+
+func extreme[T cmp.Ordered](v *Vector[T], pick func(a, b T) T) (result T, ok bool) {
+	if result, ok = v.First(); !ok {
+		return result, false
+	}
+
+	v.RangeChunks(func(chunk []T) bool {
+		for _, item := range chunk {
+			result = pick(result, item)
+		}
+
+		return true
+	})
+
+	return result, true
+}
@@ -0,0 +1,46 @@
+package peds
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSum(t *testing.T) {
+	for _, size := range testSizes {
+		assertEqual(t, size*(size-1)/2, Sum(NewVector(inputSlice(0, size)...)))
+	}
+
+	assertEqualBool(t, true, Sum(NewVector(0.5, 1.25)) == 1.75)
+}
+
+func TestMean(t *testing.T) {
+	mean, ok := Mean(NewVector(inputSlice(0, 101)...))
+	assertEqualBool(t, true, ok)
+	assertEqualBool(t, true, mean == 50)
+
+	// No overflow for small integer types
+	mean, _ = Mean(NewVector[uint8](200, 250))
+	assertEqualBool(t, true, mean == 225)
+
+	_, ok = Mean(NewVector[int]())
+	assertEqualBool(t, false, ok)
+}
+
+func TestMinAndMax(t *testing.T) {
+	v := NewVector(inputSlice(0, 1000)...).Set(500, -5).Set(20, 5000)
+	smallest, ok := Min(v)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, -5, smallest)
+	largest, ok := Max(v)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 5000, largest)
+
+	s, _ := Max(NewVector("b", "c", "a"))
+	assertEqualString(t, "c", s)
+
+	_, ok = Min(NewVector[int]())
+	assertEqualBool(t, false, ok)
+
+	f, _ := Min(NewVector(1.0, math.NaN(), 0.5))
+	assertEqualBool(t, true, math.IsNaN(f))
+}
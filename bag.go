@@ -0,0 +1,139 @@
+package peds
+
+// /////////
+// / Bag ///
+// /////////
+
+// A Bag is a persistent/immutable multiset, a set where each element can occur multiple
+// times.
+type Bag[T comparable] struct {
+	counts *Map[T, int]
+	len    int
+}
+
+// NewBag returns a new bag containing the items provided in items, including duplicates.
+func NewBag[T comparable](items ...T) *Bag[T] {
+	b := NewMapBuilder[T, int]()
+	for _, item := range items {
+		count, _ := b.Load(item)
+		b.Set(item, count+1)
+	}
+
+	return &Bag[T]{counts: b.Freeze(), len: len(items)}
+}
+
+// Len returns the number of elements in b, counting each occurrence.
+func (b *Bag[T]) Len() int {
+	return b.len
+}
+
+// DistinctLen returns the number of distinct elements in b.
+func (b *Bag[T]) DistinctLen() int {
+	return b.counts.Len()
+}
+
+// Count returns the number of occurrences of item in b.
+func (b *Bag[T]) Count(item T) int {
+	count, _ := b.counts.Load(item)
+	return count
+}
+
+// Add returns a new bag with one more occurrence of item.
+func (b *Bag[T]) Add(item T) *Bag[T] {
+	return b.AddN(item, 1)
+}
+
+// AddN returns a new bag with n more occurrences of item. n must not be negative.
+func (b *Bag[T]) AddN(item T, n int) *Bag[T] {
+	if n < 0 {
+		panic("Negative count added to bag")
+	}
+
+	if n == 0 {
+		return b
+	}
+
+	return &Bag[T]{counts: b.counts.Update(item, func(count int, _ bool) int { return count + n }), len: b.len + n}
+}
+
+// Remove returns a new bag with one occurrence of item removed. The bag itself is returned if
+// item is not present.
+func (b *Bag[T]) Remove(item T) *Bag[T] {
+	count := b.Count(item)
+	switch count {
+	case 0:
+		return b
+	case 1:
+		return &Bag[T]{counts: b.counts.Delete(item), len: b.len - 1}
+	}
+
+	return &Bag[T]{counts: b.counts.Store(item, count-1), len: b.len - 1}
+}
+
+// RemoveAll returns a new bag without any occurrences of item.
+func (b *Bag[T]) RemoveAll(item T) *Bag[T] {
+	count, ok, counts := b.counts.LoadAndDelete(item)
+	if !ok {
+		return b
+	}
+
+	return &Bag[T]{counts: counts, len: b.len - count}
+}
+
+// Union returns a new bag where each element occurs the maximum number of times it occurs in
+// b and other.
+func (b *Bag[T]) Union(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return max(x, y) }, true)
+}
+
+// Intersection returns a new bag where each element occurs the minimum number of times it
+// occurs in b and other.
+func (b *Bag[T]) Intersection(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return min(x, y) }, false)
+}
+
+// Sum returns a new bag containing all occurrences of elements in both b and other.
+func (b *Bag[T]) Sum(other *Bag[T]) *Bag[T] {
+	return b.combine(other, func(x, y int) int { return x + y }, true)
+}
+
+// combine returns a new bag where the count of each element is op applied to its counts in b
+// and other. Elements only present in other are included if includeOther is set.
+func (b *Bag[T]) combine(other *Bag[T], op func(x, y int) int, includeOther bool) *Bag[T] {
+	result := NewMapBuilder[T, int]()
+	length := 0
+	add := func(item T, count int) {
+		if count > 0 {
+			result.Set(item, count)
+			length += count
+		}
+	}
+
+	b.counts.Range(func(item T, count int) bool {
+		add(item, op(count, other.Count(item)))
+		return true
+	})
+
+	if includeOther {
+		other.counts.Range(func(item T, count int) bool {
+			if b.Count(item) == 0 {
+				add(item, op(0, count))
+			}
+
+			return true
+		})
+	}
+
+	return &Bag[T]{counts: result.Freeze(), len: length}
+}
+
+// Range calls f repeatedly passing it each distinct element in b and its number of
+// occurrences until either all elements have been visited or f returns false.
+func (b *Bag[T]) Range(f func(T, int) bool) {
+	b.counts.Range(f)
+}
+
+// ToMap returns a map from each distinct element in b to its number of occurrences.
+func (b *Bag[T]) ToMap() *Map[T, int] {
+	return b.counts
+}
@@ -0,0 +1,64 @@
+package peds
+
+import "testing"
+
+func TestBagAddRemoveCount(t *testing.T) {
+	b := NewBag("a", "b", "a")
+	assertEqual(t, 3, b.Len())
+	assertEqual(t, 2, b.DistinctLen())
+	assertEqual(t, 2, b.Count("a"))
+	assertEqual(t, 0, b.Count("x"))
+
+	b2 := b.Add("b").AddN("c", 3).Remove("a")
+	assertEqual(t, 6, b2.Len())
+	assertEqual(t, 1, b2.Count("a"))
+	assertEqual(t, 2, b2.Count("b"))
+	assertEqual(t, 3, b2.Count("c"))
+	assertEqual(t, 3, b.Len())
+
+	assertEqual(t, 0, b2.Remove("a").Count("a"))
+	assertEqual(t, 2, b2.Remove("a").DistinctLen())
+	assertEqualBool(t, true, b.Remove("x") == b)
+	assertEqual(t, 3, b2.RemoveAll("c").Len())
+	assertEqualBool(t, true, b.RemoveAll("x") == b)
+	assertEqualBool(t, true, b.AddN("x", 0) == b)
+}
+
+func TestBagUnionIntersectionSum(t *testing.T) {
+	a := NewBag(1, 1, 1, 2, 3)
+	b := NewBag(1, 2, 2, 4)
+
+	u := a.Union(b)
+	assertEqual(t, 7, u.Len())
+	assertEqual(t, 3, u.Count(1))
+	assertEqual(t, 2, u.Count(2))
+	assertEqual(t, 1, u.Count(4))
+
+	i := a.Intersection(b)
+	assertEqual(t, 2, i.Len())
+	assertEqual(t, 1, i.Count(1))
+	assertEqual(t, 1, i.Count(2))
+	assertEqual(t, 0, i.Count(3))
+	assertEqual(t, 2, i.DistinctLen())
+
+	s := a.Sum(b)
+	assertEqual(t, 9, s.Len())
+	assertEqual(t, 4, s.Count(1))
+	assertEqual(t, 4, s.ToMap().Len())
+}
+
+func TestBagRange(t *testing.T) {
+	b := NewBag(1, 1, 2)
+	total := 0
+	b.Range(func(item, count int) bool {
+		total += item * count
+		return true
+	})
+
+	assertEqual(t, 4, total)
+}
+
+func TestBagAddNegative(t *testing.T) {
+	defer assertPanic(t, "Negative count")
+	NewBag[int]().AddN(1, -1)
+}
@@ -0,0 +1,422 @@
+package peds
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The binary format consists of a version byte, a kind byte identifying the collection
+// type, the number of elements as a uvarint and finally the elements themselves, encoded
+// back to back by the element codec(s).
+const binaryFormatVersion byte = 1
+
+const (
+	binaryKindVector byte = 'V'
+	binaryKindMap    byte = 'M'
+)
+
+// ErrInvalidBinaryData is returned when decoding data that is truncated or otherwise malformed.
+var ErrInvalidBinaryData = errors.New("peds: invalid binary data")
+
+// A Codec encodes and decodes single values of type T for the binary collection format.
+type Codec[T any] interface {
+	// Append appends the encoded form of value to dst and returns the extended buffer.
+	Append(dst []byte, value T) ([]byte, error)
+
+	// Decode decodes a value from the start of data and returns it together with the
+	// number of bytes consumed.
+	Decode(data []byte) (value T, n int, err error)
+}
+
+type varintCodec[I int | int8 | int16 | int32 | int64] struct{}
+
+func (varintCodec[I]) Append(dst []byte, value I) ([]byte, error) {
+	return binary.AppendVarint(dst, int64(value)), nil
+}
+
+func (varintCodec[I]) Decode(data []byte) (I, int, error) {
+	x, n := binary.Varint(data)
+	if n <= 0 || int64(I(x)) != x {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return I(x), n, nil
+}
+
+type uvarintCodec[U uint | uint8 | uint16 | uint32 | uint64 | uintptr] struct{}
+
+func (uvarintCodec[U]) Append(dst []byte, value U) ([]byte, error) {
+	return binary.AppendUvarint(dst, uint64(value)), nil
+}
+
+func (uvarintCodec[U]) Decode(data []byte) (U, int, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 || uint64(U(x)) != x {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return U(x), n, nil
+}
+
+type float64Codec struct{}
+
+func (float64Codec) Append(dst []byte, value float64) ([]byte, error) {
+	return binary.LittleEndian.AppendUint64(dst, math.Float64bits(value)), nil
+}
+
+func (float64Codec) Decode(data []byte) (float64, int, error) {
+	if len(data) < 8 {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return math.Float64frombits(binary.LittleEndian.Uint64(data)), 8, nil
+}
+
+type float32Codec struct{}
+
+func (float32Codec) Append(dst []byte, value float32) ([]byte, error) {
+	return binary.LittleEndian.AppendUint32(dst, math.Float32bits(value)), nil
+}
+
+func (float32Codec) Decode(data []byte) (float32, int, error) {
+	if len(data) < 4 {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return math.Float32frombits(binary.LittleEndian.Uint32(data)), 4, nil
+}
+
+type boolCodec struct{}
+
+func (boolCodec) Append(dst []byte, value bool) ([]byte, error) {
+	if value {
+		return append(dst, 1), nil
+	}
+
+	return append(dst, 0), nil
+}
+
+func (boolCodec) Decode(data []byte) (bool, int, error) {
+	if len(data) < 1 || data[0] > 1 {
+		return false, 0, ErrInvalidBinaryData
+	}
+
+	return data[0] == 1, 1, nil
+}
+
+// appendLengthPrefixed appends b to dst prefixed by its length as a uvarint.
+func appendLengthPrefixed(dst []byte, b []byte) []byte {
+	dst = binary.AppendUvarint(dst, uint64(len(b)))
+	return append(dst, b...)
+}
+
+// decodeLengthPrefixed returns the length prefixed bytes at the start of data and the total
+// number of bytes consumed. The returned slice refers to data.
+func decodeLengthPrefixed(data []byte) ([]byte, int, error) {
+	size, n := binary.Uvarint(data)
+	if n <= 0 || size > uint64(len(data)-n) {
+		return nil, 0, ErrInvalidBinaryData
+	}
+
+	return data[n : n+int(size)], n + int(size), nil
+}
+
+type stringCodec struct{}
+
+func (stringCodec) Append(dst []byte, value string) ([]byte, error) {
+	dst = binary.AppendUvarint(dst, uint64(len(value)))
+	return append(dst, value...), nil
+}
+
+func (stringCodec) Decode(data []byte) (string, int, error) {
+	b, n, err := decodeLengthPrefixed(data)
+	return string(b), n, err
+}
+
+type bytesCodec struct{}
+
+func (bytesCodec) Append(dst []byte, value []byte) ([]byte, error) {
+	return appendLengthPrefixed(dst, value), nil
+}
+
+func (bytesCodec) Decode(data []byte) ([]byte, int, error) {
+	b, n, err := decodeLengthPrefixed(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]byte(nil), b...), n, nil
+}
+
+// binaryMarshalerCodec handles types that implement encoding.BinaryMarshaler and whose
+// pointer type implements encoding.BinaryUnmarshaler.
+type binaryMarshalerCodec[T any] struct{}
+
+func (binaryMarshalerCodec[T]) Append(dst []byte, value T) ([]byte, error) {
+	b, err := any(value).(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+
+	return appendLengthPrefixed(dst, b), nil
+}
+
+func (binaryMarshalerCodec[T]) Decode(data []byte) (T, int, error) {
+	var value T
+	b, n, err := decodeLengthPrefixed(data)
+	if err != nil {
+		return value, 0, err
+	}
+
+	if err := any(&value).(encoding.BinaryUnmarshaler).UnmarshalBinary(b); err != nil {
+		return value, 0, err
+	}
+
+	return value, n, nil
+}
+
+// DefaultCodec returns the codec used by MarshalBinary and UnmarshalBinary for values of
+// type T. Codecs are available for booleans, numeric types except complex numbers, strings,
+// byte slices and any type implementing both encoding.BinaryMarshaler and, through its
+// pointer, encoding.BinaryUnmarshaler.
+func DefaultCodec[T any]() (Codec[T], error) {
+	var zero T
+	var codec any
+	switch any(zero).(type) {
+	case int:
+		codec = varintCodec[int]{}
+	case int8:
+		codec = varintCodec[int8]{}
+	case int16:
+		codec = varintCodec[int16]{}
+	case int32:
+		codec = varintCodec[int32]{}
+	case int64:
+		codec = varintCodec[int64]{}
+	case uint:
+		codec = uvarintCodec[uint]{}
+	case uint8:
+		codec = uvarintCodec[uint8]{}
+	case uint16:
+		codec = uvarintCodec[uint16]{}
+	case uint32:
+		codec = uvarintCodec[uint32]{}
+	case uint64:
+		codec = uvarintCodec[uint64]{}
+	case uintptr:
+		codec = uvarintCodec[uintptr]{}
+	case float32:
+		codec = float32Codec{}
+	case float64:
+		codec = float64Codec{}
+	case bool:
+		codec = boolCodec{}
+	case string:
+		codec = stringCodec{}
+	case []byte:
+		codec = bytesCodec{}
+	default:
+		_, isMarshaler := any(zero).(encoding.BinaryMarshaler)
+		_, isUnmarshaler := any(&zero).(encoding.BinaryUnmarshaler)
+		if !isMarshaler || !isUnmarshaler {
+			return nil, fmt.Errorf("peds: no binary codec available for type %T", zero)
+		}
+
+		codec = binaryMarshalerCodec[T]{}
+	}
+
+	return codec.(Codec[T]), nil
+}
+
+func appendBinaryHeader(dst []byte, kind byte, count int) []byte {
+	dst = append(dst, binaryFormatVersion, kind)
+	return binary.AppendUvarint(dst, uint64(count))
+}
+
+// decodeBinaryHeader verifies the header of data and returns the element count and the
+// number of bytes consumed.
+func decodeBinaryHeader(data []byte, kind byte) (int, int, error) {
+	if len(data) < 2 {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	if data[0] != binaryFormatVersion {
+		return 0, 0, fmt.Errorf("peds: unsupported binary format version %d", data[0])
+	}
+
+	if data[1] != kind {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	count, n := binary.Uvarint(data[2:])
+	if n <= 0 || count > uint64(len(data)) {
+		// Every element occupies at least one byte, this protects against bogus
+		// counts causing huge allocations.
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return int(count), n + 2, nil
+}
+
+// MarshalVectorBinary encodes v in the binary collection format using codec to encode
+// the elements.
+func MarshalVectorBinary[T any](v *Vector[T], codec Codec[T]) ([]byte, error) {
+	result := appendBinaryHeader(nil, binaryKindVector, v.Len())
+	var err error
+	v.Range(func(item T) bool {
+		result, err = codec.Append(result, item)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UnmarshalVectorBinary decodes a Vector encoded by MarshalVectorBinary using codec to
+// decode the elements.
+func UnmarshalVectorBinary[T any](data []byte, codec Codec[T]) (*Vector[T], error) {
+	count, pos, err := decodeBinaryHeader(data, binaryKindVector)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, count)
+	for i := 0; i < count; i++ {
+		item, n, err := codec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+		pos += n
+	}
+
+	if pos != len(data) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	return NewVector(items...), nil
+}
+
+// MarshalBinary encodes v in a compact binary format using the default codec for T.
+func (v *Vector[T]) MarshalBinary() ([]byte, error) {
+	codec, err := DefaultCodec[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalVectorBinary(v, codec)
+}
+
+// UnmarshalBinary replaces the contents of v with the elements decoded from data, which
+// must have been produced by MarshalBinary.
+func (v *Vector[T]) UnmarshalBinary(data []byte) error {
+	codec, err := DefaultCodec[T]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalVectorBinary(data, codec)
+	if err != nil {
+		return err
+	}
+
+	*v = *result
+	return nil
+}
+
+// MarshalMapBinary encodes m in the binary collection format using keyCodec and
+// valueCodec to encode the keys and values.
+func MarshalMapBinary[K comparable, V any](m *Map[K, V], keyCodec Codec[K], valueCodec Codec[V]) ([]byte, error) {
+	result := appendBinaryHeader(nil, binaryKindMap, m.Len())
+	var err error
+	m.Range(func(key K, value V) bool {
+		if result, err = keyCodec.Append(result, key); err != nil {
+			return false
+		}
+
+		result, err = valueCodec.Append(result, value)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// UnmarshalMapBinary decodes a Map encoded by MarshalMapBinary using keyCodec and
+// valueCodec to decode the keys and values.
+func UnmarshalMapBinary[K comparable, V any](data []byte, keyCodec Codec[K], valueCodec Codec[V]) (*Map[K, V], error) {
+	count, pos, err := decodeBinaryHeader(data, binaryKindMap)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewMapBuilder[K, V]()
+	for i := 0; i < count; i++ {
+		key, n, err := keyCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += n
+		value, n, err := valueCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += n
+		b.Set(key, value)
+	}
+
+	if pos != len(data) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	return b.Freeze(), nil
+}
+
+// MarshalBinary encodes m in a compact binary format using the default codecs for K and V.
+func (m *Map[K, V]) MarshalBinary() ([]byte, error) {
+	keyCodec, err := DefaultCodec[K]()
+	if err != nil {
+		return nil, err
+	}
+
+	valueCodec, err := DefaultCodec[V]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalMapBinary(m, keyCodec, valueCodec)
+}
+
+// UnmarshalBinary replaces the contents of m with the items decoded from data, which
+// must have been produced by MarshalBinary.
+func (m *Map[K, V]) UnmarshalBinary(data []byte) error {
+	keyCodec, err := DefaultCodec[K]()
+	if err != nil {
+		return err
+	}
+
+	valueCodec, err := DefaultCodec[V]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalMapBinary(data, keyCodec, valueCodec)
+	if err != nil {
+		return err
+	}
+
+	*m = *result
+	return nil
+}
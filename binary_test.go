@@ -0,0 +1,126 @@
+package peds
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVectorBinaryRoundTrip(t *testing.T) {
+	for _, l := range []int{0, 1, 33, 1000} {
+		data, err := NewVector(inputSlice(-l/2, l)...).MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v Vector[int]
+		if err := v.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, l, v.Len())
+		for i := 0; i < l; i++ {
+			assertEqual(t, i-l/2, v.Get(i))
+		}
+	}
+}
+
+func TestVectorBinaryIsCompact(t *testing.T) {
+	data, _ := NewVector[uint8](1, 2, 3).MarshalBinary()
+	assertEqual(t, 6, len(data))
+}
+
+func TestBinaryDefaultCodecs(t *testing.T) {
+	roundTrip := func(t *testing.T, data []byte, err error, target interface{ UnmarshalBinary([]byte) error }) {
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := target.UnmarshalBinary(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var floats Vector[float64]
+	data, err := NewVector(1.5, -2.25).MarshalBinary()
+	roundTrip(t, data, err, &floats)
+	assertEqualBool(t, true, floats.Get(1) == -2.25)
+
+	var strs Vector[string]
+	data, err = NewVector("", "abc").MarshalBinary()
+	roundTrip(t, data, err, &strs)
+	assertEqualString(t, "abc", strs.Get(1))
+
+	var bools Vector[bool]
+	data, err = NewVector(true, false).MarshalBinary()
+	roundTrip(t, data, err, &bools)
+	assertEqualBool(t, true, bools.Get(0))
+
+	// time.Time implements encoding.BinaryMarshaler
+	now := time.Now()
+	var times Vector[time.Time]
+	data, err = NewVector(now).MarshalBinary()
+	roundTrip(t, data, err, &times)
+	assertEqualBool(t, true, now.Equal(times.Get(0)))
+}
+
+func TestMapBinaryRoundTrip(t *testing.T) {
+	input := map[string]int{"a": 1, "b": -2, "c": 3}
+	data, err := NewMapFromNativeMap(input).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m Map[string, int]
+	if err := m.UnmarshalBinary(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, len(input), m.Len())
+	for key, value := range input {
+		v, _ := m.Load(key)
+		assertEqual(t, value, v)
+	}
+}
+
+type exclamationCodec struct{}
+
+func (exclamationCodec) Append(dst []byte, value string) ([]byte, error) {
+	return stringCodec{}.Append(dst, value+"!")
+}
+
+func (exclamationCodec) Decode(data []byte) (string, int, error) {
+	return stringCodec{}.Decode(data)
+}
+
+func TestBinaryCustomCodec(t *testing.T) {
+	data, err := MarshalVectorBinary(NewVector("a", "b"), Codec[string](exclamationCodec{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := UnmarshalVectorBinary[string](data, exclamationCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, "b!", v.Get(1))
+}
+
+func TestBinaryErrors(t *testing.T) {
+	_, err := NewVector(complex(1, 1)).MarshalBinary()
+	assertEqualBool(t, true, err != nil)
+
+	data, _ := NewVector(1, 2, 300).MarshalBinary()
+	var v Vector[int]
+	assertEqualBool(t, true, errors.Is(v.UnmarshalBinary(data[:len(data)-1]), ErrInvalidBinaryData))
+
+	var small Vector[int8]
+	assertEqualBool(t, true, errors.Is(small.UnmarshalBinary(data), ErrInvalidBinaryData))
+
+	var m Map[int, int]
+	assertEqualBool(t, true, errors.Is(m.UnmarshalBinary(data), ErrInvalidBinaryData))
+
+	data[0] = 99
+	assertEqualBool(t, true, v.UnmarshalBinary(data) != nil)
+}
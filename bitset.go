@@ -0,0 +1,153 @@
+package peds
+
+import "math/bits"
+
+// ////////////
+// / Bitset ///
+// ////////////
+
+// A Bitset is a persistent/immutable set of non-negative integers stored as a vector of 64 bit
+// words. Bitsets returned from operations share all words not affected by the operation with
+// the bitset they were derived from.
+type Bitset struct {
+	words *Vector[uint64]
+}
+
+// NewBitset returns a new bitset with the bits given in bits set.
+func NewBitset(bits ...int) *Bitset {
+	b := &Bitset{words: NewVector[uint64]()}
+	for _, i := range bits {
+		b = b.Set(i)
+	}
+
+	return b
+}
+
+// assertBitOk panics for negative bit indexes. Any non-negative index is valid, Len in the
+// error is the number of bits currently held by the words of b.
+func (b *Bitset) assertBitOk(i int) {
+	if i < 0 {
+		panic(ErrIndexOutOfBounds{Index: i, Len: 64 * b.words.Len(), Type: "Bitset"})
+	}
+}
+
+// Test reports whether bit i is set.
+func (b *Bitset) Test(i int) bool {
+	b.assertBitOk(i)
+	word, ok := b.words.TryGet(i / 64)
+	return ok && word&(1<<(i%64)) != 0
+}
+
+// Set returns a new bitset with bit i set. The bitset grows as needed to hold the bit.
+func (b *Bitset) Set(i int) *Bitset {
+	b.assertBitOk(i)
+	if b.Test(i) {
+		return b
+	}
+
+	words := b.words
+	if i/64 >= words.Len() {
+		words = words.Concat(NewVector(make([]uint64, i/64-words.Len()+1)...))
+	}
+
+	return &Bitset{words: words.Update(i/64, func(word uint64) uint64 { return word | 1<<(i%64) })}
+}
+
+// Clear returns a new bitset with bit i cleared.
+func (b *Bitset) Clear(i int) *Bitset {
+	b.assertBitOk(i)
+	if !b.Test(i) {
+		return b
+	}
+
+	return &Bitset{words: b.words.Update(i/64, func(word uint64) uint64 { return word &^ (1 << (i % 64)) })}
+}
+
+// Count returns the number of set bits in b.
+func (b *Bitset) Count() int {
+	count := 0
+	b.words.RangeChunks(func(words []uint64) bool {
+		for _, word := range words {
+			count += bits.OnesCount64(word)
+		}
+
+		return true
+	})
+
+	return count
+}
+
+// Range calls f repeatedly passing it each set bit in b, in ascending order, until either all
+// bits have been visited or f returns false.
+func (b *Bitset) Range(f func(int) bool) {
+	b.words.RangeIndexed(func(i int, word uint64) bool {
+		for word != 0 {
+			bit := bits.TrailingZeros64(word)
+			if !f(i*64 + bit) {
+				return false
+			}
+
+			word &^= 1 << bit
+		}
+
+		return true
+	})
+}
+
+// And returns a new bitset with the bits set in both b and other.
+func (b *Bitset) And(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x & y }, false)
+}
+
+// Or returns a new bitset with the bits set in either b or other.
+func (b *Bitset) Or(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x | y }, true)
+}
+
+// Xor returns a new bitset with the bits set in exactly one of b and other.
+func (b *Bitset) Xor(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x ^ y }, true)
+}
+
+// AndNot returns a new bitset with the bits set in b but not in other.
+func (b *Bitset) AndNot(other *Bitset) *Bitset {
+	return b.combine(other, func(x, y uint64) uint64 { return x &^ y }, false)
+}
+
+// combine applies op to each pair of words in b and other, treating missing words as zero.
+// Words beyond the end of b are taken from other if includeOther is set, which requires
+// op(0, y) == y. Only the words changed by op are copied.
+func (b *Bitset) combine(other *Bitset, op func(x, y uint64) uint64, includeOther bool) *Bitset {
+	words, otherWords := b.words, other.words
+	updates := make(map[int]uint64)
+	for i := uint(0); i < words.len; i += nodeSize {
+		leaf := words.sliceFor(i)
+		var otherLeaf []uint64
+		if i < otherWords.len {
+			otherLeaf = otherWords.sliceFor(i)
+		}
+
+		for j, word := range leaf {
+			var otherWord uint64
+			if j < len(otherLeaf) {
+				otherWord = otherLeaf[j]
+			}
+
+			if result := op(word, otherWord); result != word {
+				updates[int(i)+j] = result
+			}
+		}
+	}
+
+	words = words.SetMany(updates)
+	if includeOther && otherWords.len > words.len {
+		words = words.appendRange(otherWords, words.len, otherWords.len)
+	}
+
+	return &Bitset{words: words}
+}
+
+// Equal reports whether b and other have the same bits set.
+func (b *Bitset) Equal(other *Bitset) bool {
+	return b.Xor(other).Count() == 0
+}
@@ -0,0 +1,77 @@
+package peds
+
+import (
+	"errors"
+	"testing"
+)
+
+func bitsOf(b *Bitset) []int {
+	var result []int
+	b.Range(func(i int) bool {
+		result = append(result, i)
+		return true
+	})
+
+	return result
+}
+
+func assertBits(t *testing.T, expected []int, b *Bitset) {
+	t.Helper()
+	actual := bitsOf(b)
+	assertEqual(t, len(expected), len(actual))
+	for i := range expected {
+		assertEqual(t, expected[i], actual[i])
+	}
+
+	assertEqual(t, len(expected), b.Count())
+}
+
+func TestBitsetSetClearTest(t *testing.T) {
+	empty := NewBitset()
+	b := empty.Set(3).Set(64).Set(10000)
+	assertBits(t, []int{3, 64, 10000}, b)
+	assertBits(t, nil, empty)
+	assertEqualBool(t, true, b.Test(10000))
+	assertEqualBool(t, false, b.Test(10001))
+	assertEqualBool(t, false, b.Test(1000000))
+
+	c := b.Clear(64).Clear(5)
+	assertBits(t, []int{3, 10000}, c)
+	assertBits(t, []int{3, 64, 10000}, b)
+	assertEqualBool(t, true, b.Set(3) == b)
+}
+
+func TestBitsetOperations(t *testing.T) {
+	a := NewBitset(1, 2, 3, 200, 5000)
+	b := NewBitset(2, 3, 4, 9000)
+	assertBits(t, []int{2, 3}, a.And(b))
+	assertBits(t, []int{2, 3}, b.And(a))
+	assertBits(t, []int{1, 2, 3, 4, 200, 5000, 9000}, a.Or(b))
+	assertBits(t, []int{1, 2, 3, 4, 200, 5000, 9000}, b.Or(a))
+	assertBits(t, []int{1, 4, 200, 5000, 9000}, a.Xor(b))
+	assertBits(t, []int{1, 200, 5000}, a.AndNot(b))
+	assertEqualBool(t, true, a.Equal(NewBitset(5000, 200, 3, 2, 1)))
+	assertEqualBool(t, true, a.Or(b).And(b).Equal(b))
+	assertEqualBool(t, false, a.Equal(b))
+}
+
+func TestBitsetOperationsShareWords(t *testing.T) {
+	a := NewBitset()
+	for i := 0; i < 100000; i += 3 {
+		a = a.Set(i)
+	}
+
+	b := a.Or(NewBitset(1))
+	assertEqualBool(t, true, a.words.root.children[1] == b.words.root.children[1])
+	assertEqualBool(t, true, a.words.root.children[0] != b.words.root.children[0])
+}
+
+func TestBitsetNegativeIndex(t *testing.T) {
+	err := recoverError(func() { NewBitset(3).Test(-1) })
+	var indexErr ErrIndexOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqualBool(t, true, indexErr == ErrIndexOutOfBounds{Index: -1, Len: 64, Type: "Bitset"})
+
+	defer assertPanic(t, "Index out of bounds, index=-1")
+	NewBitset().Set(-1)
+}
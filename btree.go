@@ -0,0 +1,509 @@
+package peds
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ///////////////
+// / SortedMap ///
+// ///////////////
+
+// A SortedMap is a persistent/immutable map that keeps its keys ordered. BTreeMap is the
+// implementation provided by this package, the interface is what SortedMapIterator and
+// SortedSet work with and lets other backends be used in its place. Modifying operations
+// return a new map of the same implementation.
+type SortedMap[K, V any] interface {
+	// Len returns the number of items in the map.
+	Len() int
+
+	// Load returns the value stored for key and whether it was found.
+	Load(key K) (value V, ok bool)
+
+	// Store returns a new map with value stored for key.
+	Store(key K, value V) SortedMap[K, V]
+
+	// Delete returns a new map with key removed.
+	Delete(key K) SortedMap[K, V]
+
+	// Range calls f repeatedly passing it each key and value in ascending key order until
+	// either all items have been visited or f returns false.
+	Range(f func(K, V) bool)
+
+	// RangeBetween works like Range but only visits the keys between from and to, bounds
+	// selects whether from and to themselves are included.
+	RangeBetween(from, to K, bounds RangeBounds, f func(K, V) bool)
+
+	// Min returns the smallest key and its value, ok is false if the map is empty.
+	Min() (key K, value V, ok bool)
+
+	// Max returns the largest key and its value, ok is false if the map is empty.
+	Max() (key K, value V, ok bool)
+
+	// Floor returns the largest key less than or equal to key and its value.
+	Floor(key K) (floor K, value V, ok bool)
+
+	// Ceiling returns the smallest key greater than or equal to key and its value.
+	Ceiling(key K) (ceiling K, value V, ok bool)
+
+	// Lower returns the largest key strictly less than key and its value.
+	Lower(key K) (lower K, value V, ok bool)
+
+	// Higher returns the smallest key strictly greater than key and its value.
+	Higher(key K) (higher K, value V, ok bool)
+
+	// Rank returns the number of keys in the map strictly less than key.
+	Rank(key K) int
+
+	// Select returns the key at position i in ascending key order and its value.
+	Select(i int) (K, V)
+}
+
+// RangeBounds selects which ends of a key range are included, see SortedMap.RangeBetween.
+// The zero value excludes both ends.
+type RangeBounds uint8
+
+const (
+	// IncludeFrom includes the lower end of the range.
+	IncludeFrom RangeBounds = 1 << iota
+
+	// IncludeTo includes the upper end of the range.
+	IncludeTo
+
+	// IncludeBoth includes both ends of the range.
+	IncludeBoth = IncludeFrom | IncludeTo
+)
+
+// //////////////
+// / BTreeMap ///
+// //////////////
+
+// Bounds of the number of entries per B+ tree node. Nodes other than the root never hold
+// fewer than btreeMinSize entries after an update.
+const btreeMaxSize = nodeSize
+const btreeMinSize = btreeMaxSize / 4
+
+// btreeNode is a node of a B+ tree. A leaf holds a run of consecutive keys and values, a
+// branch holds its children together with the first key of each child.
+type btreeNode[K, V any] struct {
+	keys     []K
+	values   []V
+	children []*btreeNode[K, V]
+	size     int
+}
+
+func (n *btreeNode[K, V]) isLeaf() bool {
+	return n.children == nil
+}
+
+func newBTreeLeaf[K, V any](keys []K, values []V) *btreeNode[K, V] {
+	return &btreeNode[K, V]{keys: keys, values: values, size: len(keys)}
+}
+
+func newBTreeBranch[K, V any](children []*btreeNode[K, V]) *btreeNode[K, V] {
+	n := &btreeNode[K, V]{keys: make([]K, len(children)), children: children}
+	for i, child := range children {
+		n.keys[i] = child.keys[0]
+		n.size += child.size
+	}
+
+	return n
+}
+
+// split splits n in two at position at.
+func (n *btreeNode[K, V]) split(at int) (*btreeNode[K, V], *btreeNode[K, V]) {
+	if n.isLeaf() {
+		return newBTreeLeaf(n.keys[:at:at], n.values[:at:at]), newBTreeLeaf(n.keys[at:], n.values[at:])
+	}
+
+	return newBTreeBranch(n.children[:at:at]), newBTreeBranch(n.children[at:])
+}
+
+// joinBTreeNodes returns a node holding the entries of a followed by the entries of b.
+func joinBTreeNodes[K, V any](a, b *btreeNode[K, V]) *btreeNode[K, V] {
+	if a.isLeaf() {
+		return newBTreeLeaf(slices.Concat(a.keys, b.keys), slices.Concat(a.values, b.values))
+	}
+
+	return newBTreeBranch(slices.Concat(a.children, b.children))
+}
+
+// A BTreeMap is a SortedMap implemented as a persistent B+ tree, updated by path copying.
+// Nodes are split and merged to keep them between a quarter full and full. Iteration in key
+// order is fast since the leaves store keys and values contiguously, and appending keys larger
+// than all existing keys leaves nodes full, keeping memory usage low for mostly increasing
+// keys.
+type BTreeMap[K, V any] struct {
+	root    *btreeNode[K, V]
+	compare func(a, b K) int
+}
+
+// NewBTreeMap returns a new B+ tree map, ordered by the natural order of the keys,
+// containing all items in items.
+func NewBTreeMap[K cmp.Ordered, V any](items ...MapItem[K, V]) *BTreeMap[K, V] {
+	return NewBTreeMapFunc(cmp.Compare[K], items...)
+}
+
+// NewBTreeMapFunc returns a new B+ tree map, ordered by compare, containing all items in
+// items. compare must return a negative number if a < b, a positive number if a > b and zero
+// if a and b are equal.
+func NewBTreeMapFunc[K, V any](compare func(a, b K) int, items ...MapItem[K, V]) *BTreeMap[K, V] {
+	m := &BTreeMap[K, V]{root: newBTreeLeaf[K, V](nil, nil), compare: compare}
+	for _, item := range items {
+		m = m.store(item.Key, item.Value)
+	}
+
+	return m
+}
+
+// Len returns the number of items in m.
+func (m *BTreeMap[K, V]) Len() int {
+	return m.root.size
+}
+
+// childFor returns the position of the child of n that may contain key.
+func (m *BTreeMap[K, V]) childFor(n *btreeNode[K, V], key K) int {
+	i, found := slices.BinarySearchFunc(n.keys, key, m.compare)
+	if found || i == 0 {
+		return i
+	}
+
+	return i - 1
+}
+
+// Load returns the value stored for key and whether it was found.
+func (m *BTreeMap[K, V]) Load(key K) (value V, ok bool) {
+	n := m.root
+	for !n.isLeaf() {
+		n = n.children[m.childFor(n, key)]
+	}
+
+	if i, found := slices.BinarySearchFunc(n.keys, key, m.compare); found {
+		return n.values[i], true
+	}
+
+	return value, false
+}
+
+// Store returns a new map with value stored for key.
+func (m *BTreeMap[K, V]) Store(key K, value V) SortedMap[K, V] {
+	return m.store(key, value)
+}
+
+func (m *BTreeMap[K, V]) store(key K, value V) *BTreeMap[K, V] {
+	nodes := m.storeInNode(m.root, key, value)
+	root := nodes[0]
+	if len(nodes) > 1 {
+		root = newBTreeBranch(nodes)
+	}
+
+	return &BTreeMap[K, V]{root: root, compare: m.compare}
+}
+
+// storeInNode returns a copy of n with value stored for key, split in two nodes if it grew
+// too large.
+func (m *BTreeMap[K, V]) storeInNode(n *btreeNode[K, V], key K, value V) []*btreeNode[K, V] {
+	var result *btreeNode[K, V]
+	var appending bool
+	if n.isLeaf() {
+		pos, found := slices.BinarySearchFunc(n.keys, key, m.compare)
+		if found {
+			values := slices.Clone(n.values)
+			values[pos] = value
+			return []*btreeNode[K, V]{newBTreeLeaf(n.keys, values)}
+		}
+
+		result = newBTreeLeaf(insertAt(n.keys, pos, key), insertAt(n.values, pos, value))
+		appending = pos == len(n.keys)
+	} else {
+		pos := m.childFor(n, key)
+		children := slices.Clone(n.children)
+		children = slices.Replace(children, pos, pos+1, m.storeInNode(n.children[pos], key, value)...)
+		result = newBTreeBranch(children)
+		appending = pos == len(n.children)-1
+	}
+
+	if len(result.keys) <= btreeMaxSize {
+		return []*btreeNode[K, V]{result}
+	}
+
+	// Keep the left node full when appending to the end
+	at := len(result.keys) / 2
+	if appending {
+		at = btreeMaxSize
+	}
+
+	left, right := result.split(at)
+	return []*btreeNode[K, V]{left, right}
+}
+
+// Delete returns a new map with key removed. The map itself is returned if key is not present.
+func (m *BTreeMap[K, V]) Delete(key K) SortedMap[K, V] {
+	return m.delete(key)
+}
+
+func (m *BTreeMap[K, V]) delete(key K) *BTreeMap[K, V] {
+	root, removed := m.deleteFromNode(m.root, key)
+	if !removed {
+		return m
+	}
+
+	for !root.isLeaf() && len(root.children) == 1 {
+		root = root.children[0]
+	}
+
+	return &BTreeMap[K, V]{root: root, compare: m.compare}
+}
+
+func (m *BTreeMap[K, V]) deleteFromNode(n *btreeNode[K, V], key K) (*btreeNode[K, V], bool) {
+	if n.isLeaf() {
+		pos, found := slices.BinarySearchFunc(n.keys, key, m.compare)
+		if !found {
+			return n, false
+		}
+
+		return newBTreeLeaf(removeAt(n.keys, pos), removeAt(n.values, pos)), true
+	}
+
+	pos := m.childFor(n, key)
+	child, removed := m.deleteFromNode(n.children[pos], key)
+	if !removed {
+		return n, false
+	}
+
+	children := slices.Clone(n.children)
+	children[pos] = child
+	switch {
+	case len(child.keys) == 0:
+		// The child held a single entry, as nodes split off when appending do, and is dropped
+		children = slices.Delete(children, pos, pos+1)
+		if len(children) == 0 {
+			return newBTreeLeaf[K, V](nil, nil), true
+		}
+	case len(child.keys) < btreeMinSize && len(children) > 1:
+		// Merge the child with a neighbour, splitting the result evenly if it is too large
+		if pos == len(children)-1 {
+			pos--
+		}
+
+		joined := joinBTreeNodes(children[pos], children[pos+1])
+		replacement := []*btreeNode[K, V]{joined}
+		if len(joined.keys) > btreeMaxSize {
+			left, right := joined.split(len(joined.keys) / 2)
+			replacement = []*btreeNode[K, V]{left, right}
+		}
+
+		children = slices.Replace(children, pos, pos+2, replacement...)
+	}
+
+	return newBTreeBranch(children), true
+}
+
+// Range calls f repeatedly passing it each key and value in m in ascending key order until
+// either all items have been visited or f returns false.
+func (m *BTreeMap[K, V]) Range(f func(K, V) bool) {
+	m.root.rangeEntries(f)
+}
+
+func (n *btreeNode[K, V]) rangeEntries(f func(K, V) bool) bool {
+	if n.isLeaf() {
+		for i, key := range n.keys {
+			if !f(key, n.values[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	for _, child := range n.children {
+		if !child.rangeEntries(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RangeBetween works like Range but only visits the keys between from and to, bounds selects
+// whether from and to themselves are included. Only the nodes holding keys in the range are
+// visited.
+func (m *BTreeMap[K, V]) RangeBetween(from, to K, bounds RangeBounds, f func(K, V) bool) {
+	m.rangeBetween(m.root, from, to, bounds, f)
+}
+
+func (m *BTreeMap[K, V]) rangeBetween(n *btreeNode[K, V], from, to K, bounds RangeBounds, f func(K, V) bool) bool {
+	if n.isLeaf() {
+		start, found := slices.BinarySearchFunc(n.keys, from, m.compare)
+		if found && bounds&IncludeFrom == 0 {
+			start++
+		}
+
+		for i := start; i < len(n.keys); i++ {
+			if c := m.compare(n.keys[i], to); c > 0 || (c == 0 && bounds&IncludeTo == 0) {
+				return false
+			}
+
+			if !f(n.keys[i], n.values[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	first := m.childFor(n, from)
+	for i := first; i < len(n.children); i++ {
+		if i > first && m.compare(n.keys[i], to) > 0 {
+			return false
+		}
+
+		if !m.rangeBetween(n.children[i], from, to, bounds, f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Min returns the smallest key in m and its value, ok is false if m is empty.
+func (m *BTreeMap[K, V]) Min() (key K, value V, ok bool) {
+	return m.root.edge(false)
+}
+
+// Max returns the largest key in m and its value, ok is false if m is empty.
+func (m *BTreeMap[K, V]) Max() (key K, value V, ok bool) {
+	return m.root.edge(true)
+}
+
+// edge returns the first, or last if last is set, entry in n.
+func (n *btreeNode[K, V]) edge(last bool) (key K, value V, ok bool) {
+	if n.size == 0 {
+		return key, value, false
+	}
+
+	for !n.isLeaf() {
+		n = n.children[pickEdge(len(n.children), last)]
+	}
+
+	i := pickEdge(len(n.keys), last)
+	return n.keys[i], n.values[i], true
+}
+
+func pickEdge(length int, last bool) int {
+	if last {
+		return length - 1
+	}
+
+	return 0
+}
+
+// Floor returns the largest key in m less than or equal to key and its value, ok is false if
+// there is no such key.
+func (m *BTreeMap[K, V]) Floor(key K) (floor K, value V, ok bool) {
+	return m.before(m.root, key, false)
+}
+
+// Lower returns the largest key in m strictly less than key and its value, ok is false if
+// there is no such key.
+func (m *BTreeMap[K, V]) Lower(key K) (lower K, value V, ok bool) {
+	return m.before(m.root, key, true)
+}
+
+// Ceiling returns the smallest key in m greater than or equal to key and its value, ok is
+// false if there is no such key.
+func (m *BTreeMap[K, V]) Ceiling(key K) (ceiling K, value V, ok bool) {
+	return m.after(m.root, key, false)
+}
+
+// Higher returns the smallest key in m strictly greater than key and its value, ok is false
+// if there is no such key.
+func (m *BTreeMap[K, V]) Higher(key K) (higher K, value V, ok bool) {
+	return m.after(m.root, key, true)
+}
+
+// before returns the largest entry in n less than key, or equal to key unless strict is set.
+func (m *BTreeMap[K, V]) before(n *btreeNode[K, V], key K, strict bool) (k K, v V, ok bool) {
+	if n.isLeaf() {
+		i, found := slices.BinarySearchFunc(n.keys, key, m.compare)
+		if found && !strict {
+			return n.keys[i], n.values[i], true
+		}
+
+		if i == 0 {
+			return k, v, false
+		}
+
+		return n.keys[i-1], n.values[i-1], true
+	}
+
+	pos := m.childFor(n, key)
+	if k, v, ok = m.before(n.children[pos], key, strict); ok || pos == 0 {
+		return k, v, ok
+	}
+
+	return n.children[pos-1].edge(true)
+}
+
+// after returns the smallest entry in n greater than key, or equal to key unless strict is
+// set.
+func (m *BTreeMap[K, V]) after(n *btreeNode[K, V], key K, strict bool) (k K, v V, ok bool) {
+	if n.isLeaf() {
+		i, found := slices.BinarySearchFunc(n.keys, key, m.compare)
+		if found && strict {
+			i++
+		}
+
+		if i == len(n.keys) {
+			return k, v, false
+		}
+
+		return n.keys[i], n.values[i], true
+	}
+
+	pos := m.childFor(n, key)
+	if k, v, ok = m.after(n.children[pos], key, strict); ok || pos == len(n.children)-1 {
+		return k, v, ok
+	}
+
+	return n.children[pos+1].edge(false)
+}
+
+// Rank returns the number of keys in m strictly less than key. This is the position of key in
+// ascending key order if it is present.
+func (m *BTreeMap[K, V]) Rank(key K) int {
+	rank := 0
+	n := m.root
+	for !n.isLeaf() {
+		pos := m.childFor(n, key)
+		for _, child := range n.children[:pos] {
+			rank += child.size
+		}
+
+		n = n.children[pos]
+	}
+
+	i, _ := slices.BinarySearchFunc(n.keys, key, m.compare)
+	return rank + i
+}
+
+// Select returns the key at position i in ascending key order and its value.
+func (m *BTreeMap[K, V]) Select(i int) (K, V) {
+	if i < 0 || i >= m.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: m.Len(), Type: "BTreeMap"})
+	}
+
+	n := m.root
+	for !n.isLeaf() {
+		for _, child := range n.children {
+			if i < child.size {
+				n = child
+				break
+			}
+
+			i -= child.size
+		}
+	}
+
+	return n.keys[i], n.values[i]
+}
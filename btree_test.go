@@ -0,0 +1,247 @@
+package peds
+
+import (
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var _ SortedMap[int, int] = NewBTreeMap[int, int]()
+
+func sortedKeys[K, V any](m SortedMap[K, V]) []K {
+	var keys []K
+	m.Range(func(key K, value V) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+func TestBTreeMapStoreLoadDelete(t *testing.T) {
+	var m SortedMap[string, int] = NewBTreeMap(MapItem[string, int]{Key: "b", Value: 2}, MapItem[string, int]{Key: "a", Value: 1})
+	m = m.Store("c", 3).Store("a", 10)
+	assertEqual(t, 3, m.Len())
+	assertEqualString(t, "a,b,c", strings.Join(sortedKeys(m), ","))
+
+	value, ok := m.Load("a")
+	assertEqual(t, 10, value)
+	assertEqualBool(t, true, ok)
+
+	d := m.Delete("b")
+	assertEqualString(t, "a,c", strings.Join(sortedKeys(d), ","))
+	assertEqual(t, 3, m.Len())
+	assertEqualBool(t, true, d.Delete("x") == d)
+
+	_, ok = d.Load("b")
+	assertEqualBool(t, false, ok)
+}
+
+func TestBTreeMapCustomOrder(t *testing.T) {
+	m := NewBTreeMapFunc[int, int](func(a, b int) int { return b - a })
+	for i := 0; i < 100; i++ {
+		m = m.store(i, i)
+	}
+
+	keys := sortedKeys[int, int](m)
+	assertEqual(t, 99, keys[0])
+	assertEqual(t, 0, keys[99])
+}
+
+func TestBTreeMapAppendKeepsNodesFull(t *testing.T) {
+	m := NewBTreeMap[int, int]()
+	for i := 0; i < 100*btreeMaxSize; i++ {
+		m = m.store(i, i)
+	}
+
+	leaves := 0
+	var count func(n *btreeNode[int, int])
+	count = func(n *btreeNode[int, int]) {
+		if n.isLeaf() {
+			leaves++
+			return
+		}
+
+		for _, child := range n.children {
+			count(child)
+		}
+	}
+
+	count(m.root)
+	assertEqual(t, 100, leaves)
+}
+
+func TestBTreeMapRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	expected := make(map[int]int)
+	var m SortedMap[int, int] = NewBTreeMap[int, int]()
+	for i := 0; i < 20000; i++ {
+		key := rnd.Intn(3000)
+		if rnd.Intn(3) == 0 {
+			delete(expected, key)
+			m = m.Delete(key)
+		} else {
+			expected[key] = i
+			m = m.Store(key, i)
+		}
+	}
+
+	assertEqual(t, len(expected), m.Len())
+	keys := sortedKeys(m)
+	assertEqual(t, len(expected), len(keys))
+	for i, key := range keys {
+		assertEqualBool(t, true, i == 0 || keys[i-1] < key)
+		value, ok := m.Load(key)
+		assertEqualBool(t, true, ok)
+		assertEqual(t, expected[key], value)
+	}
+
+	for key := range expected {
+		m = m.Delete(key)
+	}
+
+	assertEqual(t, 0, m.Len())
+}
+
+func TestBTreeMapDeleteAfterAppend(t *testing.T) {
+	for _, size := range []int{33, 1025, 2000} {
+		m := NewBTreeMap[int, int]()
+		for i := 0; i < size; i++ {
+			m = m.store(i, i)
+		}
+
+		for i := size - 1; i >= 0; i-- {
+			m = m.delete(i)
+			assertEqual(t, i, m.Len())
+			if i > 0 {
+				key, _, _ := m.Max()
+				assertEqual(t, i-1, key)
+			}
+		}
+	}
+}
+
+func TestBTreeMapNavigation(t *testing.T) {
+	var m SortedMap[int, int] = NewBTreeMap[int, int]()
+	_, _, ok := m.Min()
+	assertEqualBool(t, false, ok)
+	_, _, ok = m.Floor(5)
+	assertEqualBool(t, false, ok)
+
+	// Keys 0, 10, 20, ..., spread over many nodes
+	for i := 0; i < 1000; i++ {
+		m = m.Store(i*10, i)
+	}
+
+	key, value, _ := m.Min()
+	assertEqual(t, 0, key)
+	assertEqual(t, 0, value)
+	key, value, _ = m.Max()
+	assertEqual(t, 9990, key)
+	assertEqual(t, 999, value)
+
+	for _, probe := range []int{-5, 0, 5, 10, 315, 320, 9990, 9995} {
+		expectFloor := probe - ((probe%10)+10)%10
+		key, _, ok = m.Floor(probe)
+		assertEqualBool(t, expectFloor >= 0, ok)
+		if ok {
+			assertEqual(t, expectFloor, key)
+		}
+
+		expectLower := expectFloor
+		if expectLower == probe {
+			expectLower -= 10
+		}
+
+		key, _, ok = m.Lower(probe)
+		assertEqualBool(t, expectLower >= 0, ok)
+		if ok {
+			assertEqual(t, expectLower, key)
+		}
+
+		expectCeiling := expectFloor
+		if expectCeiling < probe {
+			expectCeiling += 10
+		}
+
+		key, _, ok = m.Ceiling(probe)
+		assertEqualBool(t, expectCeiling <= 9990, ok)
+		if ok {
+			assertEqual(t, expectCeiling, key)
+		}
+
+		expectHigher := expectFloor + 10
+		key, _, ok = m.Higher(probe)
+		assertEqualBool(t, expectHigher <= 9990, ok)
+		if ok {
+			assertEqual(t, expectHigher, key)
+		}
+	}
+}
+
+func TestBTreeMapRankSelect(t *testing.T) {
+	var m SortedMap[int, string] = NewBTreeMap[int, string]()
+	for i := 999; i >= 0; i-- {
+		m = m.Store(i*2, "")
+	}
+
+	for i := 0; i < 1000; i += 37 {
+		key, _ := m.Select(i)
+		assertEqual(t, i*2, key)
+		assertEqual(t, i, m.Rank(i*2))
+		assertEqual(t, i+1, m.Rank(i*2+1))
+	}
+
+	assertEqual(t, 0, m.Rank(-1))
+	assertEqual(t, 1000, m.Rank(5000))
+}
+
+func TestBTreeMapSelectOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewBTreeMap[int, int]().Select(0)
+}
+
+func TestBTreeMapRangeBetween(t *testing.T) {
+	var m SortedMap[int, int] = NewBTreeMap[int, int]()
+	for i := 0; i < 5000; i++ {
+		m = m.Store(i*2, i)
+	}
+
+	for _, r := range [][2]int{{-10, -1}, {-10, 0}, {0, 0}, {0, 10}, {1, 9}, {333, 4567}, {9990, 20000}, {9998, 9998}, {50, 40}} {
+		for _, bounds := range []RangeBounds{0, IncludeFrom, IncludeTo, IncludeBoth} {
+			var expected, actual []int
+			for key := 0; key < 10000; key += 2 {
+				if (key > r[0] || (key == r[0] && bounds&IncludeFrom != 0)) && (key < r[1] || (key == r[1] && bounds&IncludeTo != 0)) {
+					expected = append(expected, key)
+				}
+			}
+
+			m.RangeBetween(r[0], r[1], bounds, func(key, value int) bool {
+				assertEqual(t, key/2, value)
+				actual = append(actual, key)
+				return true
+			})
+
+			if !slices.Equal(expected, actual) {
+				t.Errorf("Range %v, bounds %d: expected %v, got %v", r, bounds, expected, actual)
+			}
+		}
+	}
+}
+
+func TestBTreeMapRangeBetweenStop(t *testing.T) {
+	m := NewBTreeMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.store(i, i)
+	}
+
+	count := 0
+	m.RangeBetween(100, 900, IncludeBoth, func(key, _ int) bool {
+		assertEqual(t, 100+count, key)
+		count++
+		return count < 50
+	})
+
+	assertEqual(t, 50, count)
+}
@@ -0,0 +1,130 @@
+package peds
+
+import (
+	"errors"
+	"io"
+)
+
+// NewVectorFromReader returns a new vector containing all bytes read from r until EOF.
+func NewVectorFromReader(r io.Reader) (*Vector[byte], error) {
+	var b leafBuilder[byte]
+	buf := make([]byte, 128*nodeSize)
+	for {
+		n, err := r.Read(buf)
+		for _, c := range buf[:n] {
+			b.add(c)
+		}
+
+		if err == io.EOF {
+			return b.vector(), nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// WriteVectorBytes writes the contents of v to w one leaf at a time, without first copying
+// them into a single slice.
+func WriteVectorBytes(w io.Writer, v *Vector[byte]) (int64, error) {
+	return NewBytesReader(v).WriteTo(w)
+}
+
+// /////////////////
+// / BytesReader ///
+// /////////////////
+
+// A BytesReader implements io.Reader, io.ReaderAt, io.Seeker and io.WriterTo over the contents
+// of a byte vector. Since the vector is immutable the reader never observes any changes.
+type BytesReader struct {
+	v   *Vector[byte]
+	pos int64
+}
+
+// NewBytesReader returns a new BytesReader reading from v.
+func NewBytesReader(v *Vector[byte]) *BytesReader {
+	return &BytesReader{v: v}
+}
+
+// Len returns the number of unread bytes.
+func (r *BytesReader) Len() int {
+	if r.pos >= int64(r.v.len) {
+		return 0
+	}
+
+	return int(int64(r.v.len) - r.pos)
+}
+
+// Read implements io.Reader.
+func (r *BytesReader) Read(p []byte) (int, error) {
+	n, err := r.ReadAt(p, r.pos)
+	r.pos += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *BytesReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("peds: BytesReader.ReadAt: negative offset")
+	}
+
+	n := 0
+	for n < len(p) && off < int64(r.v.len) {
+		copied := copy(p[n:], r.v.sliceFor(uint(off))[off%nodeSize:])
+		n += copied
+		off += int64(copied)
+	}
+
+	if n < len(p) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// Seek implements io.Seeker.
+func (r *BytesReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+	switch whence {
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = r.pos + offset
+	case io.SeekEnd:
+		pos = int64(r.v.len) + offset
+	default:
+		return 0, errors.New("peds: BytesReader.Seek: invalid whence")
+	}
+
+	if pos < 0 {
+		return 0, errors.New("peds: BytesReader.Seek: negative position")
+	}
+
+	r.pos = pos
+	return pos, nil
+}
+
+// WriteTo implements io.WriterTo. The unread bytes are written one leaf at a time.
+func (r *BytesReader) WriteTo(w io.Writer) (int64, error) {
+	var written int64
+	for r.pos < int64(r.v.len) {
+		chunk := r.v.sliceFor(uint(r.pos))[r.pos%nodeSize:]
+		n, err := w.Write(chunk)
+		written += int64(n)
+		r.pos += int64(n)
+		if err != nil {
+			return written, err
+		}
+
+		if n < len(chunk) {
+			return written, io.ErrShortWrite
+		}
+	}
+
+	return written, nil
+}
@@ -0,0 +1,55 @@
+package peds
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func TestNewVectorFromReader(t *testing.T) {
+	for _, size := range []int{0, 1, 31, 32, 33, 1000, 10000} {
+		data := bytes.Repeat([]byte("abcdefg"), size)[:size]
+		v, err := NewVectorFromReader(iotest.HalfReader(bytes.NewReader(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqualBool(t, true, bytes.Equal(data, v.ToNativeSlice()))
+	}
+}
+
+func TestNewVectorFromReaderError(t *testing.T) {
+	_, err := NewVectorFromReader(iotest.ErrReader(io.ErrUnexpectedEOF))
+	assertEqualBool(t, true, err == io.ErrUnexpectedEOF)
+}
+
+func TestBytesReader(t *testing.T) {
+	data := make([]byte, 1000)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	v := NewVector(data...)
+	if err := iotest.TestReader(NewBytesReader(v), data); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewBytesReader(v)
+	pos, err := r.Seek(-10, io.SeekEnd)
+	assertEqual(t, 990, int(pos))
+	assertEqualBool(t, true, err == nil)
+	assertEqual(t, 10, r.Len())
+
+	_, err = r.Seek(-1, io.SeekStart)
+	assertEqualBool(t, true, err != nil)
+}
+
+func TestWriteVectorBytes(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 100)
+	var buf bytes.Buffer
+	n, err := WriteVectorBytes(&buf, NewVector(data...))
+	assertEqual(t, len(data), int(n))
+	assertEqualBool(t, true, err == nil)
+	assertEqualBool(t, true, bytes.Equal(data, buf.Bytes()))
+}
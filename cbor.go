@@ -0,0 +1,329 @@
+package peds
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// CBOR major types
+const (
+	cborUnsigned byte = 0
+	cborNegative byte = 1
+	cborBytes    byte = 2
+	cborText     byte = 3
+	cborArray    byte = 4
+	cborMap      byte = 5
+	cborSimple   byte = 7
+)
+
+// cborFormat encodes collections as CBOR (RFC 8949) arrays and maps. The collection methods
+// match the Marshaler/Unmarshaler interfaces of github.com/fxamacker/cbor so that
+// collections can be embedded in values encoded with that library.
+var cborFormat = &wireFormat{
+	appendInt:         cborAppendInt,
+	appendUint:        cborAppendUint,
+	readInteger:       cborReadInteger,
+	appendFloat32:     cborAppendFloat32,
+	appendFloat64:     cborAppendFloat64,
+	readFloat:         cborReadFloat,
+	appendBool:        cborAppendBool,
+	readBool:          cborReadBool,
+	appendString:      cborAppendString,
+	readString:        cborReadString,
+	appendBytes:       cborAppendBytes,
+	readBytes:         cborReadBytes,
+	appendArrayHeader: cborAppendArrayHeader,
+	readArrayHeader:   cborReadArrayHeader,
+	appendMapHeader:   cborAppendMapHeader,
+	readMapHeader:     cborReadMapHeader,
+}
+
+func cborAppendHead(dst []byte, major byte, arg uint64) []byte {
+	major <<= 5
+	switch {
+	case arg < 24:
+		return append(dst, major|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(dst, major|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, major|25), uint16(arg))
+	case arg <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, major|26), uint32(arg))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, major|27), arg)
+	}
+}
+
+// cborReadHead returns the major type and argument of the data item at the start of data.
+// Indefinite length items are not supported.
+func cborReadHead(data []byte) (byte, uint64, int, error) {
+	if len(data) == 0 {
+		return 0, 0, 0, ErrInvalidBinaryData
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	if info < 24 {
+		return major, uint64(info), 1, nil
+	}
+
+	if info > 27 {
+		return 0, 0, 0, ErrInvalidBinaryData
+	}
+
+	size := 1 << (info - 24)
+	if len(data) < 1+size {
+		return 0, 0, 0, ErrInvalidBinaryData
+	}
+
+	switch size {
+	case 1:
+		return major, uint64(data[1]), 2, nil
+	case 2:
+		return major, uint64(binary.BigEndian.Uint16(data[1:])), 3, nil
+	case 4:
+		return major, uint64(binary.BigEndian.Uint32(data[1:])), 5, nil
+	default:
+		return major, binary.BigEndian.Uint64(data[1:]), 9, nil
+	}
+}
+
+func cborAppendUint(dst []byte, value uint64) []byte {
+	return cborAppendHead(dst, cborUnsigned, value)
+}
+
+func cborAppendInt(dst []byte, value int64) []byte {
+	if value >= 0 {
+		return cborAppendHead(dst, cborUnsigned, uint64(value))
+	}
+
+	return cborAppendHead(dst, cborNegative, uint64(-1-value))
+}
+
+func cborReadInteger(data []byte) (uint64, bool, int, error) {
+	major, arg, n, err := cborReadHead(data)
+	if err != nil {
+		return 0, false, 0, err
+	}
+
+	switch major {
+	case cborUnsigned:
+		return arg, false, n, nil
+	case cborNegative:
+		if arg > math.MaxInt64 {
+			return 0, false, 0, ErrInvalidBinaryData
+		}
+
+		return uint64(-1 - int64(arg)), true, n, nil
+	}
+
+	return 0, false, 0, ErrInvalidBinaryData
+}
+
+func cborAppendFloat32(dst []byte, value float32) []byte {
+	return binary.BigEndian.AppendUint32(append(dst, cborSimple<<5|26), math.Float32bits(value))
+}
+
+func cborAppendFloat64(dst []byte, value float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, cborSimple<<5|27), math.Float64bits(value))
+}
+
+func cborReadFloat(data []byte) (float64, int, error) {
+	major, arg, n, err := cborReadHead(data)
+	if err != nil || major != cborSimple {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	switch n {
+	case 3:
+		return float64(float16ToFloat32(uint16(arg))), n, nil
+	case 5:
+		return float64(math.Float32frombits(uint32(arg))), n, nil
+	case 9:
+		return math.Float64frombits(arg), n, nil
+	}
+
+	return 0, 0, ErrInvalidBinaryData
+}
+
+// float16ToFloat32 converts an IEEE 754 half precision number, which other CBOR encoders
+// commonly use for compact floats, to a float32.
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+	switch {
+	case exp == 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | frac<<13)
+	case exp == 0 && frac == 0:
+		return math.Float32frombits(sign)
+	case exp == 0:
+		// Subnormal
+		f := float32(frac) / (1 << 24)
+		if sign != 0 {
+			return -f
+		}
+
+		return f
+	}
+
+	return math.Float32frombits(sign | (exp+127-15)<<23 | frac<<13)
+}
+
+func cborAppendBool(dst []byte, value bool) []byte {
+	if value {
+		return append(dst, 0xf5)
+	}
+
+	return append(dst, 0xf4)
+}
+
+func cborReadBool(data []byte) (bool, int, error) {
+	if len(data) == 0 || (data[0] != 0xf4 && data[0] != 0xf5) {
+		return false, 0, ErrInvalidBinaryData
+	}
+
+	return data[0] == 0xf5, 1, nil
+}
+
+func cborReadBlob(data []byte, expectedMajor byte) ([]byte, int, error) {
+	major, length, n, err := cborReadHead(data)
+	if err != nil || major != expectedMajor || length > uint64(len(data)-n) {
+		return nil, 0, ErrInvalidBinaryData
+	}
+
+	return data[n : n+int(length)], n + int(length), nil
+}
+
+func cborAppendString(dst []byte, value string) []byte {
+	return append(cborAppendHead(dst, cborText, uint64(len(value))), value...)
+}
+
+func cborReadString(data []byte) ([]byte, int, error) {
+	return cborReadBlob(data, cborText)
+}
+
+func cborAppendBytes(dst []byte, value []byte) []byte {
+	return append(cborAppendHead(dst, cborBytes, uint64(len(value))), value...)
+}
+
+func cborReadBytes(data []byte) ([]byte, int, error) {
+	return cborReadBlob(data, cborBytes)
+}
+
+func cborReadCount(data []byte, expectedMajor byte) (int, int, error) {
+	major, count, n, err := cborReadHead(data)
+	if err != nil || major != expectedMajor {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	count32, err := checkWireCount(count, len(data)-n)
+	return count32, n, err
+}
+
+func cborAppendArrayHeader(dst []byte, count int) []byte {
+	return cborAppendHead(dst, cborArray, uint64(count))
+}
+
+func cborReadArrayHeader(data []byte) (int, int, error) {
+	return cborReadCount(data, cborArray)
+}
+
+func cborAppendMapHeader(dst []byte, count int) []byte {
+	return cborAppendHead(dst, cborMap, uint64(count))
+}
+
+func cborReadMapHeader(data []byte) (int, int, error) {
+	return cborReadCount(data, cborMap)
+}
+
+// DefaultCBORCodec returns the codec used by MarshalCBOR and UnmarshalCBOR for values of
+// type T. Codecs are available for booleans, numeric types except complex numbers, strings
+// and byte slices. Other element types can be handled by implementing Codec using a full
+// featured CBOR library.
+func DefaultCBORCodec[T any]() (Codec[T], error) {
+	return defaultWireCodec[T](cborFormat, "CBOR")
+}
+
+// MarshalVectorCBOR encodes v as a CBOR array using codec to encode the elements.
+func MarshalVectorCBOR[T any](v *Vector[T], codec Codec[T]) ([]byte, error) {
+	return marshalVectorWire(cborFormat, v, codec)
+}
+
+// UnmarshalVectorCBOR decodes a Vector from a CBOR array using codec to decode the elements.
+func UnmarshalVectorCBOR[T any](data []byte, codec Codec[T]) (*Vector[T], error) {
+	return unmarshalVectorWire(cborFormat, data, codec)
+}
+
+// MarshalMapCBOR encodes m as a CBOR map using keyCodec and valueCodec to encode the keys
+// and values.
+func MarshalMapCBOR[K comparable, V any](m *Map[K, V], keyCodec Codec[K], valueCodec Codec[V]) ([]byte, error) {
+	return marshalMapWire(cborFormat, m, keyCodec, valueCodec)
+}
+
+// UnmarshalMapCBOR decodes a Map from a CBOR map using keyCodec and valueCodec to decode
+// the keys and values.
+func UnmarshalMapCBOR[K comparable, V any](data []byte, keyCodec Codec[K], valueCodec Codec[V]) (*Map[K, V], error) {
+	return unmarshalMapWire(cborFormat, data, keyCodec, valueCodec)
+}
+
+// MarshalCBOR encodes v as a CBOR array using the default codec for T.
+func (v *Vector[T]) MarshalCBOR() ([]byte, error) {
+	codec, err := DefaultCBORCodec[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalVectorCBOR(v, codec)
+}
+
+// UnmarshalCBOR replaces the contents of v with the elements of the CBOR array in data.
+func (v *Vector[T]) UnmarshalCBOR(data []byte) error {
+	codec, err := DefaultCBORCodec[T]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalVectorCBOR(data, codec)
+	if err != nil {
+		return err
+	}
+
+	*v = *result
+	return nil
+}
+
+// MarshalCBOR encodes m as a CBOR map using the default codecs for K and V.
+func (m *Map[K, V]) MarshalCBOR() ([]byte, error) {
+	keyCodec, err := DefaultCBORCodec[K]()
+	if err != nil {
+		return nil, err
+	}
+
+	valueCodec, err := DefaultCBORCodec[V]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalMapCBOR(m, keyCodec, valueCodec)
+}
+
+// UnmarshalCBOR replaces the contents of m with the items of the CBOR map in data.
+func (m *Map[K, V]) UnmarshalCBOR(data []byte) error {
+	keyCodec, err := DefaultCBORCodec[K]()
+	if err != nil {
+		return err
+	}
+
+	valueCodec, err := DefaultCBORCodec[V]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalMapCBOR(data, keyCodec, valueCodec)
+	if err != nil {
+		return err
+	}
+
+	*m = *result
+	return nil
+}
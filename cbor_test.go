@@ -0,0 +1,98 @@
+package peds
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestVectorCBORKnownEncoding(t *testing.T) {
+	// Examples from RFC 8949 appendix A
+	data, err := NewVector(1, 10, 23, 24, 100, 1000, -1, -1000).MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0x88, 0x01, 0x0a, 0x17, 0x18, 0x18, 0x18, 0x64, 0x19, 0x03, 0xe8, 0x20, 0x39, 0x03, 0xe7}
+	assertEqualBool(t, true, bytes.Equal(expected, data))
+
+	data, _ = NewVector("a", "IETF").MarshalCBOR()
+	assertEqualBool(t, true, bytes.Equal([]byte{0x82, 0x61, 0x61, 0x64, 0x49, 0x45, 0x54, 0x46}, data))
+
+	data, _ = NewVector(true, false).MarshalCBOR()
+	assertEqualBool(t, true, bytes.Equal([]byte{0x82, 0xf5, 0xf4}, data))
+}
+
+func TestVectorCBORRoundTrip(t *testing.T) {
+	for _, l := range []int{0, 1, 23, 24, 1000, 70000} {
+		data, err := NewVector(inputSlice(-l/2, l)...).MarshalCBOR()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v Vector[int]
+		if err := v.UnmarshalCBOR(data); err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, l, v.Len())
+		for i := 0; i < l; i += 1 + l/100 {
+			assertEqual(t, i-l/2, v.Get(i))
+		}
+	}
+}
+
+func TestCBORFloats(t *testing.T) {
+	data, _ := NewVector[float32](1.5).MarshalCBOR()
+	var v Vector[float32]
+	if err := v.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, v.Get(0) == 1.5)
+
+	// Half precision floats from RFC 8949 appendix A
+	var halfs Vector[float64]
+	if err := halfs.UnmarshalCBOR([]byte{0x84, 0xf9, 0x3c, 0x00, 0xf9, 0xc4, 0x00, 0xf9, 0x7c, 0x00, 0xf9, 0x00, 0x01}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, halfs.Get(0) == 1.0)
+	assertEqualBool(t, true, halfs.Get(1) == -4.0)
+	assertEqualBool(t, true, math.IsInf(halfs.Get(2), 1))
+	assertEqualBool(t, true, halfs.Get(3) == 5.960464477539063e-8)
+}
+
+func TestMapCBORRoundTrip(t *testing.T) {
+	data, err := NewMapFromNativeMap(map[int]string{1: "a", -2: "b"}).MarshalCBOR()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 0xa2, int(data[0]))
+	var m Map[int, string]
+	if err := m.UnmarshalCBOR(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 2, m.Len())
+	v, _ := m.Load(-2)
+	assertEqualString(t, "b", v)
+}
+
+func TestCBORErrors(t *testing.T) {
+	_, err := NewVector(complex(1, 2)).MarshalCBOR()
+	assertEqualBool(t, true, err != nil)
+
+	data, _ := NewVector(1, 2, 3).MarshalCBOR()
+	var m Map[int, int]
+	assertEqualBool(t, true, errors.Is(m.UnmarshalCBOR(data), ErrInvalidBinaryData))
+
+	var strs Vector[string]
+	assertEqualBool(t, true, errors.Is(strs.UnmarshalCBOR(data), ErrInvalidBinaryData))
+
+	// Indefinite length arrays are not supported
+	var v Vector[int]
+	assertEqualBool(t, true, errors.Is(v.UnmarshalCBOR([]byte{0x9f, 0x01, 0xff}), ErrInvalidBinaryData))
+}
@@ -0,0 +1,61 @@
+package peds
+
+import "context"
+
+// NewVectorFromChan returns a new vector containing the items received from ch, in order.
+// It blocks until ch is closed.
+func NewVectorFromChan[T any](ch <-chan T) *Vector[T] {
+	var b leafBuilder[T]
+	for item := range ch {
+		b.add(item)
+	}
+
+	return b.vector()
+}
+
+// NewVectorFromChanContext is like NewVectorFromChan but stops receiving when ctx is done. In
+// that case the items received so far are returned together with the error from ctx.
+func NewVectorFromChanContext[T any](ctx context.Context, ch <-chan T) (*Vector[T], error) {
+	var b leafBuilder[T]
+	for {
+		select {
+		case item, ok := <-ch:
+			if !ok {
+				return b.vector(), nil
+			}
+
+			b.add(item)
+		case <-ctx.Done():
+			return b.vector(), ctx.Err()
+		}
+	}
+}
+
+// CollectMap returns a new Map containing the items received from ch. If a key is received
+// more than once the last value wins. It blocks until ch is closed.
+func CollectMap[K comparable, V any](ch <-chan MapItem[K, V]) *Map[K, V] {
+	b := NewMapBuilder[K, V]()
+	for item := range ch {
+		b.Set(item.Key, item.Value)
+	}
+
+	return b.Freeze()
+}
+
+// CollectMapContext is like CollectMap but stops receiving when ctx is done. In that case the
+// items received so far are returned together with the error from ctx.
+func CollectMapContext[K comparable, V any](ctx context.Context, ch <-chan MapItem[K, V]) (*Map[K, V], error) {
+	b := NewMapBuilder[K, V]()
+	for {
+		select {
+		case item, ok := <-ch:
+			if !ok {
+				return b.Freeze(), nil
+			}
+
+			b.Set(item.Key, item.Value)
+		case <-ctx.Done():
+			return b.Freeze(), ctx.Err()
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package peds
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestNewVectorFromChan(t *testing.T) {
+	for _, size := range testSizes {
+		ch := make(chan int)
+		go func() {
+			for _, x := range inputSlice(0, size) {
+				ch <- x
+			}
+
+			close(ch)
+		}()
+
+		v := NewVectorFromChan(ch)
+		assertEqual(t, size, v.Len())
+		for i := 0; i < size; i++ {
+			assertEqual(t, i, v.Get(i))
+		}
+	}
+}
+
+func TestNewVectorFromChanContextCancelled(t *testing.T) {
+	ch := make(chan int)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		// Sends on an unbuffered channel complete once the item has been received
+		ch <- 1
+		ch <- 2
+		cancel()
+	}()
+
+	v, err := NewVectorFromChanContext(ctx, ch)
+	assertEqualBool(t, true, errors.Is(err, context.Canceled))
+	assertEqual(t, 2, v.Len())
+}
+
+func TestNewVectorFromChanContextClosed(t *testing.T) {
+	ch := make(chan int, 2)
+	ch <- 1
+	close(ch)
+	v, err := NewVectorFromChanContext(context.Background(), ch)
+	assertEqualBool(t, true, err == nil)
+	assertEqual(t, 1, v.Len())
+}
+
+func TestCollectMap(t *testing.T) {
+	ch := make(chan MapItem[string, int], 3)
+	ch <- MapItem[string, int]{Key: "a", Value: 1}
+	ch <- MapItem[string, int]{Key: "b", Value: 2}
+	ch <- MapItem[string, int]{Key: "a", Value: 3}
+	close(ch)
+
+	m := CollectMap(ch)
+	assertEqual(t, 2, m.Len())
+	assertEqual(t, 3, m.LoadOr("a", 0))
+}
+
+func TestCollectMapContext(t *testing.T) {
+	ch := make(chan MapItem[string, int])
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	m, err := CollectMapContext(ctx, ch)
+	assertEqualBool(t, true, errors.Is(err, context.Canceled))
+	assertEqual(t, 0, m.Len())
+
+	ch = make(chan MapItem[string, int], 1)
+	ch <- MapItem[string, int]{Key: "a", Value: 1}
+	close(ch)
+	m, err = CollectMapContext(context.Background(), ch)
+	assertEqualBool(t, true, err == nil)
+	assertEqual(t, 1, m.Len())
+}
@@ -0,0 +1,548 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+type config struct {
+	Package string
+	Command string
+	Imports []string
+	Vectors []vectorSpec
+	Maps    []mapSpec
+}
+
+// vectorSpec describes a generated vector. New is the name of the constructor and Node the
+// name of the node type.
+type vectorSpec struct {
+	Name string
+	New  string
+	Node string
+	Elem string
+}
+
+// mapSpec describes a generated map. Prefix is used for the unexported helpers of the map,
+// Buckets describes the vector of buckets backing it and HashFunc, if set, is a user provided
+// function hashing keys.
+type mapSpec struct {
+	Name     string
+	New      string
+	Item     string
+	Prefix   string
+	Key      string
+	Value    string
+	HashKind string
+	HashFunc string
+	Buckets  vectorSpec
+}
+
+const (
+	hashString  = "string"
+	hashInteger = "integer"
+	hashCustom  = "custom"
+	hashMaphash = "maphash"
+)
+
+var integerTypes = map[string]bool{
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true, "uintptr": true,
+	"byte": true, "rune": true,
+}
+
+func lowerFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+func upperFirst(s string) string {
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func newVectorSpec(name, elem string, exported bool) vectorSpec {
+	constructor := "New" + upperFirst(name)
+	if !exported {
+		constructor = "new" + upperFirst(name)
+	}
+
+	return vectorSpec{Name: name, New: constructor, Node: lowerFirst(name) + "Node", Elem: elem}
+}
+
+// parseConfig parses vector specifications on the form Name:ElemType and map specifications
+// on the form Name:KeyType:ValueType[:HashFunc].
+func parseConfig(pkg string, vectors, maps, imports []string) (config, error) {
+	c := config{Package: pkg, Imports: imports}
+	if !token.IsIdentifier(pkg) {
+		return c, fmt.Errorf("invalid package name %q", pkg)
+	}
+
+	names := map[string]bool{}
+	checkName := func(name string) error {
+		if !token.IsIdentifier(name) {
+			return fmt.Errorf("invalid type name %q", name)
+		}
+
+		if names[name] {
+			return fmt.Errorf("duplicate type name %q", name)
+		}
+
+		names[name] = true
+		return nil
+	}
+
+	for _, v := range vectors {
+		parts := strings.SplitN(v, ":", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			return c, fmt.Errorf("invalid vector %q, expected Name:ElemType", v)
+		}
+
+		if err := checkName(parts[0]); err != nil {
+			return c, err
+		}
+
+		c.Vectors = append(c.Vectors, newVectorSpec(parts[0], parts[1], true))
+	}
+
+	for _, m := range maps {
+		parts := strings.Split(m, ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[1] == "" || parts[2] == "" {
+			return c, fmt.Errorf("invalid map %q, expected Name:KeyType:ValueType[:HashFunc]", m)
+		}
+
+		if err := checkName(parts[0]); err != nil {
+			return c, err
+		}
+
+		spec := mapSpec{
+			Name:   parts[0],
+			New:    "New" + upperFirst(parts[0]),
+			Item:   parts[0] + "Item",
+			Prefix: lowerFirst(parts[0]),
+			Key:    parts[1],
+			Value:  parts[2],
+		}
+
+		switch {
+		case len(parts) == 4:
+			spec.HashKind, spec.HashFunc = hashCustom, parts[3]
+		case parts[1] == "string":
+			spec.HashKind = hashString
+		case integerTypes[parts[1]]:
+			spec.HashKind = hashInteger
+		default:
+			spec.HashKind = hashMaphash
+		}
+
+		spec.Buckets = newVectorSpec(spec.Prefix+"Buckets", "[]"+spec.Item, false)
+		c.Maps = append(c.Maps, spec)
+	}
+
+	if len(c.Vectors) == 0 && len(c.Maps) == 0 {
+		return c, errors.New("nothing to generate, specify at least one -vector or -map")
+	}
+
+	return c, nil
+}
+
+// generate returns the formatted source of the collections described by c.
+func generate(c config) ([]byte, error) {
+	imports := []string{"fmt"}
+	for _, m := range c.Maps {
+		if m.HashKind == hashMaphash {
+			imports = append(imports, "hash/maphash")
+			break
+		}
+	}
+
+	var buf bytes.Buffer
+	err := fileTemplate.Execute(&buf, struct {
+		config
+		AllImports []string
+	}{c, append(imports, c.Imports...)})
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated invalid code, check the type names: %w", err)
+	}
+
+	return src, nil
+}
+
+var fileTemplate = template.Must(template.New("file").Parse(`// Code generated by {{or .Command "peds-gen"}}. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+{{- range .AllImports}}
+	"{{.}}"
+{{- end}}
+)
+{{range .Vectors}}
+// {{.Name}} is a persistent/immutable vector of {{.Elem}}.
+{{template "vector" .}}
+{{- end}}
+{{- range .Maps}}
+{{template "map" .}}
+{{- end}}
+`))
+
+func init() {
+	template.Must(fileTemplate.New("vector").Parse(vectorTemplate))
+	template.Must(fileTemplate.New("map").Parse(mapTemplate))
+}
+
+const vectorTemplate = `type {{.Name}} struct {
+	root  *{{.Node}}
+	tail  []{{.Elem}}
+	len   int
+	shift uint
+}
+
+type {{.Node}} struct {
+	children *[32]*{{.Node}}
+	items    *[32]{{.Elem}}
+}
+
+// {{.New}} returns a new vector containing the items provided in items.
+func {{.New}}(items ...{{.Elem}}) *{{.Name}} {
+	return (&{{.Name}}{}).Append(items...)
+}
+
+// Len returns the length of v.
+func (v *{{.Name}}) Len() int {
+	return v.len
+}
+
+func (v *{{.Name}}) tailOffset() int {
+	return v.len - len(v.tail)
+}
+
+// leafFor returns the leaf holding element i, which must be stored in the tree.
+func (v *{{.Name}}) leafFor(i int) *[32]{{.Elem}} {
+	n := v.root
+	for level := v.shift; level > 0; level -= 5 {
+		n = n.children[(i>>level)&31]
+	}
+
+	return n.items
+}
+
+// Get returns the element at position i.
+func (v *{{.Name}}) Get(i int) {{.Elem}} {
+	if i < 0 || i >= v.len {
+		panic(fmt.Sprintf("Index out of bounds, index=%d, len=%d, type={{.Name}}", i, v.len))
+	}
+
+	if offset := v.tailOffset(); i >= offset {
+		return v.tail[i-offset]
+	}
+
+	return v.leafFor(i)[i&31]
+}
+
+// Set returns a new vector with the element at position i set to item.
+func (v *{{.Name}}) Set(i int, item {{.Elem}}) *{{.Name}} {
+	if i < 0 || i >= v.len {
+		panic(fmt.Sprintf("Index out of bounds, index=%d, len=%d, type={{.Name}}", i, v.len))
+	}
+
+	if offset := v.tailOffset(); i >= offset {
+		tail := make([]{{.Elem}}, len(v.tail), 32)
+		copy(tail, v.tail)
+		tail[i-offset] = item
+		return &{{.Name}}{root: v.root, tail: tail, len: v.len, shift: v.shift}
+	}
+
+	return &{{.Name}}{root: v.root.set(v.shift, i, item), tail: v.tail, len: v.len, shift: v.shift}
+}
+
+func (n *{{.Node}}) set(level uint, i int, item {{.Elem}}) *{{.Node}} {
+	if level == 0 {
+		items := *n.items
+		items[i&31] = item
+		return &{{.Node}}{items: &items}
+	}
+
+	children := *n.children
+	ix := (i >> level) & 31
+	children[ix] = children[ix].set(level-5, i, item)
+	return &{{.Node}}{children: &children}
+}
+
+// Append returns a new vector with item(s) appended to it.
+func (v *{{.Name}}) Append(items ...{{.Elem}}) *{{.Name}} {
+	result := v
+	for len(items) > 0 {
+		if len(result.tail) == 32 {
+			result = result.pushTail()
+		}
+
+		n := 32 - len(result.tail)
+		if n > len(items) {
+			n = len(items)
+		}
+
+		// Tails are never modified after creation, they are always copied
+		tail := make([]{{.Elem}}, len(result.tail)+n, 32)
+		copy(tail, result.tail)
+		copy(tail[len(result.tail):], items[:n])
+		result = &{{.Name}}{root: result.root, tail: tail, len: result.len + n, shift: result.shift}
+		items = items[n:]
+	}
+
+	return result
+}
+
+// pushTail returns a new vector with the full tail of v moved into the tree.
+func (v *{{.Name}}) pushTail() *{{.Name}} {
+	leaf := &{{.Node}}{items: (*[32]{{.Elem}})(v.tail)}
+	offset := v.tailOffset()
+	root, shift := v.root, v.shift
+	switch {
+	case root == nil:
+		root = leaf
+	case offset == 32<<shift:
+		root = &{{.Node}}{children: &[32]*{{.Node}}{root, leaf.path(shift)}}
+		shift += 5
+	default:
+		root = root.withLeaf(shift, offset, leaf)
+	}
+
+	return &{{.Name}}{root: root, len: v.len, shift: shift}
+}
+
+// path returns a node at level with the leaf n as its leftmost descendant.
+func (n *{{.Node}}) path(level uint) *{{.Node}} {
+	for ; level > 0; level -= 5 {
+		n = &{{.Node}}{children: &[32]*{{.Node}}{n}}
+	}
+
+	return n
+}
+
+// withLeaf returns a copy of n, at level, with leaf added as the leaf holding element i.
+func (n *{{.Node}}) withLeaf(level uint, i int, leaf *{{.Node}}) *{{.Node}} {
+	children := *n.children
+	ix := (i >> level) & 31
+	switch {
+	case level == 5:
+		children[ix] = leaf
+	case children[ix] == nil:
+		children[ix] = leaf.path(level - 5)
+	default:
+		children[ix] = children[ix].withLeaf(level-5, i, leaf)
+	}
+
+	return &{{.Node}}{children: &children}
+}
+
+// Range calls f repeatedly passing it each element in v in order as argument until either
+// all elements have been visited or f returns false.
+func (v *{{.Name}}) Range(f func({{.Elem}}) bool) {
+	offset := v.tailOffset()
+	for i := 0; i < offset; i += 32 {
+		for _, item := range v.leafFor(i) {
+			if !f(item) {
+				return
+			}
+		}
+	}
+
+	for _, item := range v.tail {
+		if !f(item) {
+			return
+		}
+	}
+}
+
+// ToNativeSlice returns a new native slice containing the elements of v.
+func (v *{{.Name}}) ToNativeSlice() []{{.Elem}} {
+	result := make([]{{.Elem}}, 0, v.len)
+	offset := v.tailOffset()
+	for i := 0; i < offset; i += 32 {
+		result = append(result, v.leafFor(i)[:]...)
+	}
+
+	return append(result, v.tail...)
+}
+`
+
+const mapTemplate = `// {{.Item}} is a key/value pair stored in a {{.Name}}.
+type {{.Item}} struct {
+	Key   {{.Key}}
+	Value {{.Value}}
+}
+
+// {{.Name}} is a persistent/immutable hash map from {{.Key}} to {{.Value}}. The number of
+// buckets doubles as the map grows, it never shrinks.
+type {{.Name}} struct {
+	buckets *{{.Buckets.Name}}
+	len     int
+}
+
+// {{.New}} returns a new map containing all items in items. If a key occurs more than once
+// the last value wins.
+func {{.New}}(items ...{{.Item}}) *{{.Name}} {
+	count := 8
+	for count < len(items) {
+		count *= 2
+	}
+
+	buckets := make([][]{{.Item}}, count)
+	length := 0
+	for _, item := range items {
+		pos := int({{.Prefix}}Hash(item.Key) & uint64(count-1))
+		found := false
+		for i := range buckets[pos] {
+			if buckets[pos][i].Key == item.Key {
+				buckets[pos][i].Value = item.Value
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			buckets[pos] = append(buckets[pos], item)
+			length++
+		}
+	}
+
+	return &{{.Name}}{buckets: {{.Buckets.New}}(buckets...), len: length}
+}
+
+{{if eq .HashKind "maphash" -}}
+var {{.Prefix}}Seed = maphash.MakeSeed()
+
+{{end -}}
+func {{.Prefix}}Hash(key {{.Key}}) uint64 {
+{{- if eq .HashKind "string"}}
+	// FNV-1a
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+
+	return h
+{{- else if eq .HashKind "integer"}}
+	// The finalizer of MurmurHash3
+	h := uint64(key)
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+{{- else if eq .HashKind "custom"}}
+	return {{.HashFunc}}(key)
+{{- else}}
+	return maphash.Comparable({{.Prefix}}Seed, key)
+{{- end}}
+}
+
+// Len returns the number of items in m.
+func (m *{{.Name}}) Len() int {
+	return m.len
+}
+
+func (m *{{.Name}}) pos(key {{.Key}}) int {
+	return int({{.Prefix}}Hash(key) & uint64(m.buckets.Len()-1))
+}
+
+// Load returns the value stored for key. ok is set to false if key is not present.
+func (m *{{.Name}}) Load(key {{.Key}}) (value {{.Value}}, ok bool) {
+	for _, item := range m.buckets.Get(m.pos(key)) {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	return value, false
+}
+
+// Store returns a new map with value stored for key.
+func (m *{{.Name}}) Store(key {{.Key}}, value {{.Value}}) *{{.Name}} {
+	pos := m.pos(key)
+	bucket := m.buckets.Get(pos)
+	newBucket := make([]{{.Item}}, len(bucket), len(bucket)+1)
+	copy(newBucket, bucket)
+	length := m.len
+	found := false
+	for i := range newBucket {
+		if newBucket[i].Key == key {
+			newBucket[i].Value = value
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		newBucket = append(newBucket, {{.Item}}{Key: key, Value: value})
+		length++
+	}
+
+	result := &{{.Name}}{buckets: m.buckets.Set(pos, newBucket), len: length}
+	if length > 2*result.buckets.Len() {
+		return {{.New}}(result.items()...)
+	}
+
+	return result
+}
+
+// Delete returns a new map with key removed. m itself is returned if key is not present.
+func (m *{{.Name}}) Delete(key {{.Key}}) *{{.Name}} {
+	pos := m.pos(key)
+	bucket := m.buckets.Get(pos)
+	for i, item := range bucket {
+		if item.Key == key {
+			var newBucket []{{.Item}}
+			if len(bucket) > 1 {
+				newBucket = make([]{{.Item}}, 0, len(bucket)-1)
+				newBucket = append(append(newBucket, bucket[:i]...), bucket[i+1:]...)
+			}
+
+			return &{{.Name}}{buckets: m.buckets.Set(pos, newBucket), len: m.len - 1}
+		}
+	}
+
+	return m
+}
+
+// Range calls f repeatedly passing it each key and value as argument until either all items
+// have been visited or f returns false.
+func (m *{{.Name}}) Range(f func({{.Key}}, {{.Value}}) bool) {
+	m.buckets.Range(func(bucket []{{.Item}}) bool {
+		for _, item := range bucket {
+			if !f(item.Key, item.Value) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (m *{{.Name}}) items() []{{.Item}} {
+	result := make([]{{.Item}}, 0, m.len)
+	m.buckets.Range(func(bucket []{{.Item}}) bool {
+		result = append(result, bucket...)
+		return true
+	})
+
+	return result
+}
+
+// {{.Buckets.Name}} is the vector of buckets backing a {{.Name}}.
+{{template "vector" .Buckets}}`
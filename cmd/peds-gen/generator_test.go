@@ -0,0 +1,206 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := parseConfig("demo", []string{"IntVector:int", "Points:[]image.Point"}, []string{
+		"Index:string:int", "ByID:int64:*Record", "ByPoint:image.Point:bool", "Custom:Key:int:hashKey",
+	}, []string{"image"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Vectors) != 2 || c.Vectors[1].Elem != "[]image.Point" || c.Vectors[0].New != "NewIntVector" {
+		t.Errorf("Unexpected vectors: %+v", c.Vectors)
+	}
+
+	kinds := []string{hashString, hashInteger, hashMaphash, hashCustom}
+	for i, m := range c.Maps {
+		if m.HashKind != kinds[i] {
+			t.Errorf("Unexpected hash kind for %s: %s, expected %s", m.Name, m.HashKind, kinds[i])
+		}
+	}
+
+	if b := c.Maps[1].Buckets; b.Name != "byIDBuckets" || b.New != "newByIDBuckets" || b.Elem != "[]ByIDItem" {
+		t.Errorf("Unexpected bucket vector: %+v", b)
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	for _, tc := range []struct {
+		pkg           string
+		vectors, maps []string
+		expected      string
+	}{
+		{"demo", nil, nil, "nothing to generate"},
+		{"", []string{"V:int"}, nil, "invalid package name"},
+		{"demo", []string{"V"}, nil, "expected Name:ElemType"},
+		{"demo", []string{"1V:int"}, nil, "invalid type name"},
+		{"demo", []string{"V:int"}, []string{"V:int:int"}, "duplicate type name"},
+		{"demo", nil, []string{"M:int"}, "expected Name:KeyType:ValueType"},
+	} {
+		_, err := parseConfig(tc.pkg, tc.vectors, tc.maps, nil)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("Expected error containing %q, got: %v", tc.expected, err)
+		}
+	}
+}
+
+func TestGenerateInvalidType(t *testing.T) {
+	c, err := parseConfig("demo", []string{"V:[int"}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = generate(c); err == nil || !strings.Contains(err.Error(), "generated invalid code") {
+		t.Errorf("Expected invalid code error, got: %v", err)
+	}
+}
+
+// generatedProgram exercises the collections generated by TestGeneratedCodeRuns, comparing
+// them against native slices and maps.
+const generatedProgram = `package main
+
+import (
+	"fmt"
+	"math/rand"
+	"os"
+)
+
+type point struct{ x, y int }
+
+func hashPoint(p point) uint64 { return uint64(p.x*31 + p.y) }
+
+func check(ok bool, format string, args ...any) {
+	if !ok {
+		fmt.Printf(format+"\n", args...)
+		os.Exit(1)
+	}
+}
+
+func main() {
+	r := rand.New(rand.NewSource(1))
+	var native []int
+	v := NewIntVector()
+	for i := 0; i < 40000; i++ {
+		n := r.Intn(5)
+		items := make([]int, n)
+		for j := range items {
+			items[j] = r.Int()
+		}
+
+		native = append(native, items...)
+		v = v.Append(items...)
+		if len(native) > 0 && r.Intn(4) == 0 {
+			i := r.Intn(len(native))
+			native[i] = -native[i]
+			v = v.Set(i, native[i])
+		}
+	}
+
+	check(v.Len() == len(native), "length %d, expected %d", v.Len(), len(native))
+	for i, item := range native {
+		check(v.Get(i) == item, "element %d: %d, expected %d", i, v.Get(i), item)
+	}
+
+	all := v.ToNativeSlice()
+	count := 0
+	v.Range(func(item int) bool {
+		check(all[count] == item && native[count] == item, "range element %d", count)
+		count++
+		return true
+	})
+	check(count == len(native), "range count %d", count)
+
+	old := NewNames("a", "b")
+	updated := old.Append("c").Set(0, "x")
+	check(old.Len() == 2 && old.Get(0) == "a", "original vector modified")
+	check(updated.Get(0) == "x" && updated.Get(2) == "c", "updated vector wrong")
+
+	nativeMap := map[string]int{}
+	m := NewIndex()
+	for i := 0; i < 20000; i++ {
+		key := fmt.Sprint(r.Intn(5000))
+		if r.Intn(3) == 0 {
+			delete(nativeMap, key)
+			m = m.Delete(key)
+		} else {
+			nativeMap[key] = i
+			m = m.Store(key, i)
+		}
+	}
+
+	check(m.Len() == len(nativeMap), "map length %d, expected %d", m.Len(), len(nativeMap))
+	for key, value := range nativeMap {
+		actual, ok := m.Load(key)
+		check(ok && actual == value, "map key %s: %d, expected %d", key, actual, value)
+	}
+
+	count = 0
+	m.Range(func(key string, value int) bool {
+		check(nativeMap[key] == value, "range key %s", key)
+		count++
+		return true
+	})
+	check(count == len(nativeMap), "map range count %d", count)
+
+	ids := NewByID(ByIDItem{Key: 1, Value: "a"}, ByIDItem{Key: 1, Value: "b"}).Store(2, "c")
+	value, _ := ids.Load(1)
+	check(ids.Len() == 2 && value == "b", "last value must win")
+
+	points := NewByPoint().Store(point{1, 2}, true)
+	_, ok := points.Load(point{1, 2})
+	check(ok, "point key missing")
+
+	custom := NewCustom().Store(point{3, 4}, 7).Delete(point{3, 4})
+	check(custom.Len() == 0, "custom hashed map not empty")
+	fmt.Println("ok")
+}
+`
+
+func TestGeneratedCodeRuns(t *testing.T) {
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not available")
+	}
+
+	c, err := parseConfig("main", []string{"IntVector:int", "Names:string"}, []string{
+		"Index:string:int", "ByID:int64:string", "ByPoint:point:bool", "Custom:point:int:hashPoint",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := generate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"go.mod":  "module generated\n\ngo 1.24\n",
+		"gen.go":  string(src),
+		"main.go": generatedProgram,
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{{"vet", "."}, {"run", "."}} {
+		cmd := exec.Command(goTool, args...)
+		cmd.Dir = dir
+		out, err := cmd.CombinedOutput()
+		if err != nil || (args[0] == "run" && strings.TrimSpace(string(out)) != "ok") {
+			t.Fatalf("go %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Command peds-gen generates specialized, non-generic, persistent vectors and maps for
+// concrete element and key types. The generated collections use concrete leaf arrays and
+// inlined hash functions for string and integer keys, and have no dependency on the peds
+// package.
+//
+// It is intended to be used with go generate:
+//
+//	//go:generate peds-gen -vector IntVector:int -map NameIndex:string:int -out collections_gen.go
+//
+// Flags:
+//
+//	-pkg     name of the package of the generated file, defaults to $GOPACKAGE
+//	-out     file to write, defaults to standard output
+//	-vector  Name:ElemType, may be repeated
+//	-map     Name:KeyType:ValueType, may be repeated
+//	-import  import path needed by the element, key or value types, may be repeated
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	var vectors, maps, imports listFlag
+	pkg := flag.String("pkg", os.Getenv("GOPACKAGE"), "name of the package of the generated file")
+	out := flag.String("out", "", "file to write, defaults to standard output")
+	flag.Var(&vectors, "vector", "Name:ElemType of a vector to generate, may be repeated")
+	flag.Var(&maps, "map", "Name:KeyType:ValueType of a map to generate, may be repeated")
+	flag.Var(&imports, "import", "import path needed by the generated types, may be repeated")
+	flag.Parse()
+
+	if err := run(*pkg, *out, vectors, maps, imports); err != nil {
+		fmt.Fprintln(os.Stderr, "peds-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, out string, vectors, maps, imports []string) error {
+	config, err := parseConfig(pkg, vectors, maps, imports)
+	if err != nil {
+		return err
+	}
+
+	config.Command = strings.Join(append([]string{"peds-gen"}, os.Args[1:]...), " ")
+	src, err := generate(config)
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+
+	return os.WriteFile(out, src, 0o644)
+}
@@ -0,0 +1,81 @@
+package peds
+
+import "cmp"
+
+// CompareFunc compares the elements of v and other lexicographically, using compare to compare
+// elements, like slices.CompareFunc. The result is 0 if v == other, -1 if v < other and +1 if
+// v > other. Subtrees shared between v and other, such as an unmodified prefix of two versions
+// of the same vector, are skipped without comparing their elements.
+func (v *Vector[T]) CompareFunc(other *Vector[T], compare func(a, b T) int) int {
+	if v == other {
+		return 0
+	}
+
+	n := uintMin(v.len, other.len)
+	i := uint(0)
+	if v.shift == other.shift && v.root != nil && other.root != nil {
+		// Leaves at the same position are found at the same place in both trees
+		i = uintMin(n, uintMin(v.tailOffset(), other.tailOffset()))
+		if c := compareNodes(v.shift, v.root, other.root, 0, i, compare); c != 0 {
+			return c
+		}
+	}
+
+	for i < n {
+		a, b := v.sliceFor(i), other.sliceFor(i)
+		offset := i & shiftBitMask
+		end := uintMin(uintMin(uint(len(a)), uint(len(b))), offset+n-i)
+		if c := compareLeaves(a[offset:end], b[offset:end], compare); c != 0 {
+			return c
+		}
+
+		i += end - offset
+	}
+
+	return cmp.Compare(v.len, other.len)
+}
+
+// Compare compares the elements of a and b lexicographically, like slices.Compare.
+func Compare[T cmp.Ordered](a, b *Vector[T]) int {
+	return a.CompareFunc(b, cmp.Compare[T])
+}
+
+// compareNodes compares the elements [start,limit) held by the nodes a and b, both at level
+// and both holding elements starting at position start.
+func compareNodes[T any](level uint, a, b *node[T], start, limit uint, compare func(a, b T) int) int {
+	if a == b {
+		return 0
+	}
+
+	if level == 0 {
+		end := uintMin(nodeSize, limit-start)
+		return compareLeaves(a.items[:end], b.items[:end], compare)
+	}
+
+	for i := uint(0); i < nodeSize; i++ {
+		childStart := start + i<<level
+		if childStart >= limit {
+			break
+		}
+
+		if c := compareNodes(level-shiftSize, a.children[i], b.children[i], childStart, limit, compare); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
+
+func compareLeaves[T any](a, b []T, compare func(a, b T) int) int {
+	if len(a) == 0 || &a[0] == &b[0] {
+		return 0
+	}
+
+	for i := range a {
+		if c := compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+
+	return 0
+}
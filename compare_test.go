@@ -0,0 +1,59 @@
+package peds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	for _, size := range testSizes {
+		a := inputSlice(0, size)
+		v := NewVector(a...)
+		assertEqual(t, 0, Compare(v, NewVector(a...)))
+		assertEqual(t, -1, Compare(v, v.Append(0)))
+		assertEqual(t, 1, Compare(v.Append(0), v))
+		if size == 0 {
+			continue
+		}
+
+		for _, i := range []int{0, size / 2, size - 1} {
+			larger := v.Set(i, size)
+			assertEqual(t, -1, Compare(v, larger))
+			assertEqual(t, 1, Compare(larger, v))
+
+			// A difference decides over length
+			assertEqual(t, 1, Compare(larger, v.Append(0)))
+			b := slices.Clone(a)
+			b[i] = size
+			assertEqual(t, slices.Compare(a, b), Compare(v, NewVector(b...)))
+		}
+	}
+}
+
+func TestCompareDifferentShapes(t *testing.T) {
+	long := NewVector(inputSlice(0, 40000)...)
+	short := NewVector(inputSlice(0, 100)...)
+	assertEqual(t, 1, Compare(long, short))
+	assertEqual(t, -1, Compare(short, long))
+	assertEqual(t, -1, Compare(short, long.Set(99, 1000)))
+	assertEqual(t, 1, Compare(short, long.Set(50, 0)))
+}
+
+func TestCompareFuncSkipsSharedSubtrees(t *testing.T) {
+	v := NewVector(inputSlice(0, 10000)...)
+	v2 := v.Set(9000, -1)
+	calls := 0
+	c := v.CompareFunc(v2, func(a, b int) int {
+		calls++
+		if a < b {
+			return -1
+		} else if a > b {
+			return 1
+		}
+
+		return 0
+	})
+
+	assertEqual(t, 1, c)
+	assertEqualBool(t, true, calls <= nodeSize)
+}
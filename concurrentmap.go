@@ -0,0 +1,125 @@
+package peds
+
+// ///////////////////
+// / ConcurrentMap ///
+// ///////////////////
+
+// A ConcurrentMap is a mutable map that is safe for concurrent use by multiple goroutines.
+// It has the same method set as sync.Map, including CompareAndSwap, CompareAndDelete and
+// Clear, and is implemented as atomic swaps of a persistent Map. Reads never block and
+// Snapshot returns a consistent view of all items in constant time, something that sync.Map
+// cannot provide. Writes copy part of the map and are more expensive than for sync.Map,
+// particularly under contention.
+type ConcurrentMap[K comparable, V any] struct {
+	ref *Ref[*Map[K, V]]
+}
+
+// NewConcurrentMap returns a new ConcurrentMap containing the items of m. m may be nil for
+// an initially empty map.
+func NewConcurrentMap[K comparable, V any](m *Map[K, V]) *ConcurrentMap[K, V] {
+	if m == nil {
+		m = NewMap[K, V]()
+	}
+
+	return &ConcurrentMap[K, V]{ref: NewRef(m)}
+}
+
+// Snapshot returns the current contents of c as a persistent Map. Later changes to c are
+// not visible in the returned map.
+func (c *ConcurrentMap[K, V]) Snapshot() *Map[K, V] {
+	return c.ref.Load()
+}
+
+// Len returns the number of items in c.
+func (c *ConcurrentMap[K, V]) Len() int {
+	return c.Snapshot().Len()
+}
+
+// Load returns the value stored for key. ok is set to false if key is not present.
+func (c *ConcurrentMap[K, V]) Load(key K) (value V, ok bool) {
+	return c.Snapshot().Load(key)
+}
+
+// Store sets the value for key.
+func (c *ConcurrentMap[K, V]) Store(key K, value V) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] { return m.Store(key, value) })
+}
+
+// LoadOrStore returns the existing value for key if present. Otherwise it stores and
+// returns value. loaded is true if the value was loaded, false if stored.
+func (c *ConcurrentMap[K, V]) LoadOrStore(key K, value V) (actual V, loaded bool) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] {
+		if actual, loaded = m.Load(key); loaded {
+			return m
+		}
+
+		actual = value
+		return m.Store(key, value)
+	})
+
+	return actual, loaded
+}
+
+// LoadAndDelete deletes the value for key, returning the previous value if any. loaded
+// reports whether key was present.
+func (c *ConcurrentMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] {
+		var result *Map[K, V]
+		value, loaded, result = m.LoadAndDelete(key)
+		return result
+	})
+
+	return value, loaded
+}
+
+// Delete deletes the value for key.
+func (c *ConcurrentMap[K, V]) Delete(key K) {
+	c.LoadAndDelete(key)
+}
+
+// Swap stores value for key and returns the previous value if any. loaded reports whether
+// key was present.
+func (c *ConcurrentMap[K, V]) Swap(key K, value V) (previous V, loaded bool) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] {
+		previous, loaded = m.Load(key)
+		return m.Store(key, value)
+	})
+
+	return previous, loaded
+}
+
+// CompareAndSwap swaps the old and new values for key if the value stored in c is equal to
+// old. Like for sync.Map the old value must be of a comparable type, otherwise it panics.
+func (c *ConcurrentMap[K, V]) CompareAndSwap(key K, old, new V) (swapped bool) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] {
+		var result *Map[K, V]
+		result, swapped = m.CompareAndSwapFunc(key, func(v V) bool { return any(v) == any(old) }, new)
+		return result
+	})
+
+	return swapped
+}
+
+// CompareAndDelete deletes the entry for key if its value is equal to old. Like for sync.Map
+// the old value must be of a comparable type, otherwise it panics.
+func (c *ConcurrentMap[K, V]) CompareAndDelete(key K, old V) (deleted bool) {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] {
+		var result *Map[K, V]
+		result, deleted = m.CompareAndDeleteFunc(key, func(v V) bool { return any(v) == any(old) })
+		return result
+	})
+
+	return deleted
+}
+
+// Clear deletes all items in c. The hasher and options of the current map are kept.
+func (c *ConcurrentMap[K, V]) Clear() {
+	c.ref.Update(func(m *Map[K, V]) *Map[K, V] { return newMap[K, V](nil, m.hasher, m.options) })
+}
+
+// Range calls f sequentially for each key and value in c until either all items have been
+// visited or f returns false. Unlike for sync.Map all items visited belong to the same
+// snapshot of c, changes made while ranging are not visible.
+func (c *ConcurrentMap[K, V]) Range(f func(key K, value V) bool) {
+	c.Snapshot().Range(f)
+}
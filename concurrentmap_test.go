@@ -0,0 +1,85 @@
+package peds
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestConcurrentMapOperations(t *testing.T) {
+	c := NewConcurrentMap[string, int](nil)
+	c.Store("a", 1)
+	value, ok := c.Load("a")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, value)
+
+	actual, loaded := c.LoadOrStore("a", 2)
+	assertEqualBool(t, true, loaded)
+	assertEqual(t, 1, actual)
+	actual, loaded = c.LoadOrStore("b", 2)
+	assertEqualBool(t, false, loaded)
+	assertEqual(t, 2, actual)
+
+	previous, loaded := c.Swap("b", 3)
+	assertEqualBool(t, true, loaded)
+	assertEqual(t, 2, previous)
+
+	snapshot := c.Snapshot()
+	value, loaded = c.LoadAndDelete("a")
+	assertEqualBool(t, true, loaded)
+	assertEqual(t, 1, value)
+	c.Delete("b")
+	c.Delete("missing")
+
+	assertEqual(t, 0, c.Len())
+	assertEqual(t, 2, snapshot.Len())
+}
+
+func TestConcurrentMapCompareAndSwap(t *testing.T) {
+	c := NewConcurrentMap(NewMapWithHasher[string, int](HasherFunc[string](func(key string) uint64 { return uint64(len(key)) })))
+	c.Store("a", 1)
+	assertEqualBool(t, false, c.CompareAndSwap("a", 2, 3))
+	assertEqualBool(t, false, c.CompareAndSwap("missing", 0, 3))
+	assertEqualBool(t, true, c.CompareAndSwap("a", 1, 3))
+	value, _ := c.Load("a")
+	assertEqual(t, 3, value)
+
+	assertEqualBool(t, false, c.CompareAndDelete("a", 1))
+	assertEqualBool(t, true, c.CompareAndDelete("a", 3))
+	assertEqual(t, 0, c.Len())
+
+	c.Store("b", 2)
+	snapshot := c.Snapshot()
+	c.Clear()
+	assertEqual(t, 0, c.Len())
+	assertEqual(t, 1, snapshot.Len())
+	assertEqualBool(t, true, c.Snapshot().hasher != nil)
+
+	slices := NewConcurrentMap[string, []int](nil)
+	slices.Store("a", []int{1})
+	defer assertPanic(t, "uncomparable")
+	slices.CompareAndSwap("a", nil, []int{2})
+}
+
+func TestConcurrentMapConcurrentStores(t *testing.T) {
+	c := NewConcurrentMap(NewMap(MapItem[int, int]{Key: -1, Value: -1}))
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				c.Store(g*100+i, i)
+				c.Range(func(key, value int) bool { return true })
+			}
+		}()
+	}
+
+	wg.Wait()
+	assertEqual(t, 801, c.Len())
+	sum := 0
+	c.Range(func(key, value int) bool {
+		sum += value
+		return true
+	})
+	assertEqual(t, 8*99*100/2-1, sum)
+}
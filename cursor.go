@@ -0,0 +1,35 @@
+package peds
+
+// A VectorCursor provides random access to the elements of a Vector while caching the leaf
+// holding the most recently accessed element. Accessing elements in sequence, or otherwise
+// close to each other, only requires a traversal from the root once per leaf instead of once
+// per element as for Vector.Get.
+type VectorCursor[T any] struct {
+	v         *Vector[T]
+	leaf      []T
+	leafStart uint
+}
+
+// Cursor returns a new cursor over v.
+func (v *Vector[T]) Cursor() *VectorCursor[T] {
+	return &VectorCursor[T]{v: v}
+}
+
+// Len returns the length of the vector.
+func (c *VectorCursor[T]) Len() int {
+	return c.v.Len()
+}
+
+// Get returns the element at position i.
+func (c *VectorCursor[T]) Get(i int) T {
+	if i < 0 || uint(i) >= c.v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: c.v.Len(), Type: "Vector"})
+	}
+
+	if uint(i)-c.leafStart >= uint(len(c.leaf)) {
+		c.leafStart = uint(i) &^ shiftBitMask
+		c.leaf = c.v.sliceFor(uint(i))
+	}
+
+	return c.leaf[uint(i)-c.leafStart]
+}
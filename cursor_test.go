@@ -0,0 +1,38 @@
+package peds
+
+import "testing"
+
+func TestVectorCursor(t *testing.T) {
+	for _, size := range testSizes {
+		v := NewVector(inputSlice(0, size)...)
+		c := v.Cursor()
+		assertEqual(t, size, c.Len())
+		for i := 0; i < size; i++ {
+			assertEqual(t, i, c.Get(i))
+		}
+
+		// Backwards and jumping between leaves
+		for i := size - 1; i >= 0; i -= 7 {
+			assertEqual(t, i, c.Get(i))
+			assertEqual(t, size/2, c.Get(size/2))
+		}
+	}
+}
+
+func TestVectorCursorOutOfBounds(t *testing.T) {
+	c := NewVector(1, 2, 3).Cursor()
+	c.Get(0)
+	defer assertPanic(t, "Index out of bounds")
+	c.Get(-1)
+}
+
+func BenchmarkVectorCursorGet(b *testing.B) {
+	v := NewVector(inputSlice(0, 100000)...)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		c := v.Cursor()
+		for i := 0; i < v.Len(); i++ {
+			c.Get(i)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package peds
+
+// FreezeDeep converts a nested structure of native maps and slices, such as the result of
+// decoding JSON into an any, into the corresponding nested structure of immutable
+// collections. map[string]any becomes *Map[string, any], map[any]any becomes
+// *Map[any, any] and []any becomes *Vector[any]. Other values are returned unchanged.
+func FreezeDeep(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		return freezeMapDeep(value)
+	case map[any]any:
+		return freezeMapDeep(value)
+	case []any:
+		items := make([]any, len(value))
+		for i, item := range value {
+			items[i] = FreezeDeep(item)
+		}
+
+		return AdoptSlice(items)
+	default:
+		return value
+	}
+}
+
+func freezeMapDeep[K comparable](m map[K]any) *Map[K, any] {
+	buckets := newPrivateItemBuckets[K, any](len(m), nil, nil)
+	for key, value := range m {
+		buckets.AddItem(MapItem[K, any]{Key: key, Value: FreezeDeep(value)})
+	}
+
+	return buckets.toMap()
+}
+
+// ThawDeep is the inverse of FreezeDeep. It converts a nested structure of *Map[string, any],
+// *Map[any, any] and *Vector[any] into the corresponding nested structure of native maps and
+// slices. Other values are returned unchanged.
+func ThawDeep(value any) any {
+	switch value := value.(type) {
+	case *Map[string, any]:
+		return thawMapDeep(value)
+	case *Map[any, any]:
+		return thawMapDeep(value)
+	case *Vector[any]:
+		items := make([]any, 0, value.Len())
+		value.Range(func(item any) bool {
+			items = append(items, ThawDeep(item))
+			return true
+		})
+
+		return items
+	default:
+		return value
+	}
+}
+
+func thawMapDeep[K comparable](m *Map[K, any]) map[K]any {
+	result := make(map[K]any, m.Len())
+	m.Range(func(key K, value any) bool {
+		result[key] = ThawDeep(value)
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,52 @@
+package peds
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestFreezeDeepJSON(t *testing.T) {
+	var doc any
+	input := `{"name":"x","tags":["a","b"],"nested":{"list":[{"n":1},2,null]}}`
+	if err := json.Unmarshal([]byte(input), &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	frozen := FreezeDeep(doc)
+	m, ok := frozen.(*Map[string, any])
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 3, m.Len())
+
+	tags, _ := m.Load("tags")
+	assertEqual(t, 2, tags.(*Vector[any]).Len())
+
+	n, ok := GetIn(frozen, "nested", "list", 0, "n")
+	assertEqualBool(t, true, ok)
+	if n != 1.0 {
+		t.Errorf("Expected 1, got %v", n)
+	}
+
+	if thawed := ThawDeep(frozen); !reflect.DeepEqual(doc, thawed) {
+		t.Errorf("Expected %v, got %v", doc, thawed)
+	}
+}
+
+func TestFreezeDeepAnyKeys(t *testing.T) {
+	doc := map[any]any{1: []any{map[any]any{"a": true}}, "b": "c"}
+	frozen := FreezeDeep(doc).(*Map[any, any])
+	v, _ := GetIn(frozen, 1, 0, "a")
+	assertEqualBool(t, true, v.(bool))
+
+	if thawed := ThawDeep(frozen); !reflect.DeepEqual(doc, thawed) {
+		t.Errorf("Expected %v, got %v", doc, thawed)
+	}
+}
+
+func TestFreezeDeepScalar(t *testing.T) {
+	assertEqual(t, 5, FreezeDeep(5).(int))
+	assertEqualString(t, "x", ThawDeep("x").(string))
+	if FreezeDeep(nil) != nil {
+		t.Error("Expected nil")
+	}
+}
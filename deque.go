@@ -0,0 +1,69 @@
+package peds
+
+// ///////////
+// / Deque ///
+// ///////////
+
+// A Deque is a persistent/immutable double-ended queue backed by a FingerTree. Pushing and
+// popping at either end is O(log n) in the worst case and amortized constant time when each
+// version of the deque is only pushed to or popped from once.
+type Deque[T any] struct {
+	items *FingerTree[T, struct{}]
+}
+
+// NewDeque returns a new deque containing the items provided in items, the first item
+// being at the front of the deque.
+func NewDeque[T any](items ...T) *Deque[T] {
+	return &Deque[T]{items: NewFingerSeq(items...)}
+}
+
+// Len returns the number of elements in d.
+func (d *Deque[T]) Len() int {
+	return d.items.Len()
+}
+
+// PushFront returns a new deque with item added to the front.
+func (d *Deque[T]) PushFront(item T) *Deque[T] {
+	return &Deque[T]{items: d.items.PushFront(item)}
+}
+
+// PushBack returns a new deque with item added to the back.
+func (d *Deque[T]) PushBack(item T) *Deque[T] {
+	return &Deque[T]{items: d.items.PushBack(item)}
+}
+
+// PopFront returns the element at the front of d and a new deque with that element removed.
+func (d *Deque[T]) PopFront() (T, *Deque[T]) {
+	if d.Len() == 0 {
+		panic("Pop from empty deque")
+	}
+
+	item, items := d.items.PopFront()
+	return item, &Deque[T]{items: items}
+}
+
+// PopBack returns the element at the back of d and a new deque with that element removed.
+func (d *Deque[T]) PopBack() (T, *Deque[T]) {
+	if d.Len() == 0 {
+		panic("Pop from empty deque")
+	}
+
+	item, items := d.items.PopBack()
+	return item, &Deque[T]{items: items}
+}
+
+// PeekFront returns the element at the front of d. ok is set to false if d is empty.
+func (d *Deque[T]) PeekFront() (item T, ok bool) {
+	return d.items.PeekFront()
+}
+
+// PeekBack returns the element at the back of d. ok is set to false if d is empty.
+func (d *Deque[T]) PeekBack() (item T, ok bool) {
+	return d.items.PeekBack()
+}
+
+// Range calls f repeatedly passing it each element in d, from front to back, as argument
+// until either all elements have been visited or f returns false.
+func (d *Deque[T]) Range(f func(T) bool) {
+	d.items.Range(f)
+}
@@ -0,0 +1,118 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func dequeToSlice(d *Deque[int]) []int {
+	result := make([]int, 0, d.Len())
+	d.Range(func(item int) bool {
+		result = append(result, item)
+		return true
+	})
+
+	return result
+}
+
+func TestDequePushAndPop(t *testing.T) {
+	d := NewDeque[int]()
+	for i := 0; i < 100; i++ {
+		d = d.PushBack(i).PushFront(-i - 1)
+	}
+
+	assertEqual(t, 200, d.Len())
+	for i := 0; i < 100; i++ {
+		var front, back int
+		front, d = d.PopFront()
+		back, d = d.PopBack()
+		assertEqual(t, -100+i, front)
+		assertEqual(t, 99-i, back)
+	}
+
+	assertEqual(t, 0, d.Len())
+}
+
+func TestDequePopFromOppositeEnd(t *testing.T) {
+	for _, l := range []int{1, 2, 33, 500} {
+		t.Run(fmt.Sprintf("PopFront %d", l), func(t *testing.T) {
+			d := NewDeque(inputSlice(0, l)...)
+			for i := 0; i < l; i++ {
+				var item int
+				item, d = d.PopFront()
+				assertEqual(t, i, item)
+			}
+		})
+
+		t.Run(fmt.Sprintf("PopBack %d", l), func(t *testing.T) {
+			d := NewDeque[int]()
+			for i := 0; i < l; i++ {
+				d = d.PushFront(i)
+			}
+
+			for i := 0; i < l; i++ {
+				var item int
+				item, d = d.PopBack()
+				assertEqual(t, i, item)
+			}
+		})
+	}
+}
+
+func TestDequePeek(t *testing.T) {
+	d := NewDeque[int]()
+	_, ok := d.PeekFront()
+	assertEqualBool(t, false, ok)
+	_, ok = d.PeekBack()
+	assertEqualBool(t, false, ok)
+
+	d = d.PushFront(2).PushFront(1)
+	front, _ := d.PeekFront()
+	back, _ := d.PeekBack()
+	assertEqual(t, 1, front)
+	assertEqual(t, 2, back)
+
+	d = NewDeque(1, 2, 3)
+	front, _ = d.PeekFront()
+	back, _ = d.PeekBack()
+	assertEqual(t, 1, front)
+	assertEqual(t, 3, back)
+}
+
+func TestDequeIsPersistent(t *testing.T) {
+	d := NewDeque(1, 2, 3)
+	_, d2 := d.PopFront()
+	d3 := d2.PushFront(10)
+
+	assertEqual(t, 3, len(dequeToSlice(d)))
+	expected := []int{10, 2, 3}
+	for i, item := range dequeToSlice(d3) {
+		assertEqual(t, expected[i], item)
+	}
+
+	assertEqual(t, 1, dequeToSlice(d)[0])
+}
+
+func TestDequePopEmpty(t *testing.T) {
+	defer assertPanic(t, "Pop from empty deque")
+	NewDeque[int]().PopBack()
+}
+
+func TestDequePopFromSameVersion(t *testing.T) {
+	// Popping repeatedly from one version must neither modify it nor depend on earlier pops
+	d := NewDeque[int]()
+	for i := 0; i < 1000; i++ {
+		d = d.PushBack(i)
+	}
+
+	for i := 0; i < 100; i++ {
+		front, rest := d.PopFront()
+		back, _ := d.PopBack()
+		assertEqual(t, 0, front)
+		assertEqual(t, 999, back)
+		assertEqual(t, 999, rest.Len())
+	}
+
+	assertEqual(t, 1000, d.Len())
+	assertEqual(t, 1000, len(dequeToSlice(d)))
+}
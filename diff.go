@@ -0,0 +1,296 @@
+package peds
+
+// EditKind identifies the kind of a VectorEdit.
+type EditKind uint8
+
+const (
+	// EditInsert inserts Items before the element at Index.
+	EditInsert EditKind = iota + 1
+
+	// EditDelete deletes Count elements starting at Index.
+	EditDelete
+
+	// EditReplace replaces the Count elements starting at Index with Items.
+	EditReplace
+)
+
+// A VectorEdit is a single operation in an edit script produced by Vector.DiffFunc. Index
+// refers to the vector that results from applying all preceding edits in the script. Count
+// is the number of elements inserted, deleted or replaced.
+type VectorEdit[T any] struct {
+	Kind  EditKind `json:"kind"`
+	Index int      `json:"index"`
+	Count int      `json:"count"`
+	Items []T      `json:"items,omitempty"`
+}
+
+// maxDiffCost limits the number of differing elements that the diff algorithm searches for
+// a minimal edit script. It bounds the time and memory used, which is quadratic in the cost.
+const maxDiffCost = 1024
+
+// DiffFunc returns an edit script that turns v into other, using eq to compare elements. The
+// edits are ordered by index. The script is minimal, in terms of the number of inserted and
+// deleted elements, unless v and other differ in more than about a thousand elements.
+// Leading and trailing subtrees shared between v and other are skipped without comparing
+// their elements, making it cheap to diff two versions of a large vector that differ in a few
+// places.
+func (v *Vector[T]) DiffFunc(other *Vector[T], eq func(T, T) bool) VectorPatch[T] {
+	return diffVectors(v, other, eq, false)
+}
+
+// VectorDiff returns an edit script that turns a into b, see Vector.DiffFunc. Like for
+// VectorEqual, leading and trailing subtrees with equal cached content hashes are also
+// skipped.
+func VectorDiff[T comparable](a, b *Vector[T]) VectorPatch[T] {
+	return diffVectors(a, b, func(x, y T) bool { return x == y }, true)
+}
+
+// diffVectors returns an edit script that turns v into other, see sameContent for useHashes.
+func diffVectors[T any](v, other *Vector[T], eq func(T, T) bool, useHashes bool) VectorPatch[T] {
+	prefix := commonPrefixLen(v, other, eq, useHashes)
+	suffix := commonSuffixLen(v, other, prefix, eq, useHashes)
+	a := v.Slice(prefix, v.Len()-suffix).AppendToSlice(nil)
+	b := other.Slice(prefix, other.Len()-suffix).AppendToSlice(nil)
+
+	ops, ok := diffOps(a, b, eq)
+	if !ok {
+		// Too many differences, replace everything in between the common prefix and suffix
+		ops = make([]diffOp, 0, len(a)+len(b))
+		for range a {
+			ops = append(ops, diffDelete)
+		}
+
+		for range b {
+			ops = append(ops, diffInsert)
+		}
+	}
+
+	return editScript(ops, b, prefix)
+}
+
+func sameLeaf[T any](a, b []T) bool {
+	return len(a) == len(b) && len(a) > 0 && &a[0] == &b[0]
+}
+
+// nodeFor returns the node at level in the trie of v that holds the element at index i, which
+// must be below the tail offset.
+func (v *Vector[T]) nodeFor(i, level uint) *node[T] {
+	n := v.root
+	for l := v.shift; l > level; l -= shiftSize {
+		n = n.children[(i>>l)&shiftBitMask]
+	}
+
+	return n
+}
+
+// equalSubtreeLen returns the number of elements in the largest subtrees of a and b, starting
+// at i and j respectively or ending at them if backward is set, that are known to hold the
+// same elements, see sameContent. Zero is returned if there are none.
+func equalSubtreeLen[T any](a, b *Vector[T], i, j uint, backward, useHashes bool) uint {
+	if i >= a.tailOffset() || j >= b.tailOffset() {
+		return 0
+	}
+
+	for level := min(a.shift, b.shift); ; level -= shiftSize {
+		size := uint(1) << (level + shiftSize)
+		start, otherStart := i, j
+		if backward {
+			start, otherStart = i+1, j+1
+		}
+
+		if start%size == 0 && otherStart%size == 0 && sameContent(a.nodeFor(i, level), b.nodeFor(j, level), useHashes) {
+			if backward {
+				// The subtree ends at i and is therefore full
+				return size
+			}
+
+			return min(size, a.tailOffset()-i, b.tailOffset()-j)
+		}
+
+		if level == 0 {
+			return 0
+		}
+	}
+}
+
+func commonPrefixLen[T any](a, b *Vector[T], eq func(T, T) bool, useHashes bool) int {
+	length := uintMin(a.len, b.len)
+	i := uint(0)
+	for i < length {
+		if n := equalSubtreeLen(a, b, i, i, false, useHashes); n > 0 {
+			i += n
+			continue
+		}
+
+		leafA, leafB := a.sliceFor(i), b.sliceFor(i)
+		if !sameLeaf(leafA, leafB) {
+			for j := 0; j < len(leafA) && j < len(leafB); j++ {
+				if !eq(leafA[j], leafB[j]) {
+					return int(i) + j
+				}
+			}
+		}
+
+		i += nodeSize
+	}
+
+	return int(uintMin(i, length))
+}
+
+// commonSuffixLen returns the number of equal elements at the end of a and b, not counting
+// the first skip elements of either.
+func commonSuffixLen[T any](a, b *Vector[T], skip int, eq func(T, T) bool, useHashes bool) int {
+	maxLen := min(a.Len(), b.Len()) - skip
+	n := 0
+	for n < maxLen {
+		i, j := a.Len()-n-1, b.Len()-n-1
+		if i&shiftBitMask == shiftBitMask && j&shiftBitMask == shiftBitMask {
+			// Whole subtrees at the same position relative to the end
+			if size := int(equalSubtreeLen(a, b, uint(i), uint(j), true, useHashes)); size > 0 && n+size <= maxLen {
+				n += size
+				continue
+			}
+
+			if n+nodeSize <= maxLen && sameLeaf(a.sliceFor(uint(i)), b.sliceFor(uint(j))) {
+				n += nodeSize
+				continue
+			}
+		}
+
+		if !eq(a.Get(i), b.Get(j)) {
+			break
+		}
+
+		n++
+	}
+
+	return n
+}
+
+type diffOp uint8
+
+const (
+	diffEqual diffOp = iota
+	diffDelete
+	diffInsert
+)
+
+// diffOps returns the operations of a minimal edit script turning a into b using the
+// Myers diff algorithm. ok is false if the edit script would exceed maxDiffCost.
+func diffOps[T any](a, b []T, eq func(T, T) bool) (ops []diffOp, ok bool) {
+	n, m := len(a), len(b)
+	maxCost := min(n+m, maxDiffCost)
+	offset := maxCost + 1
+	v := make([]int, 2*maxCost+3)
+
+	// trace[d] holds the furthest reaching x for diagonals -d..d before step d
+	var trace [][]int
+	for d := 0; d <= maxCost; d++ {
+		trace = append(trace, append([]int(nil), v[offset-d:offset+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+
+			y := x - k
+			for x < n && y < m && eq(a[x], b[y]) {
+				x++
+				y++
+			}
+
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrackDiff(trace, n, m), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+func backtrackDiff(trace [][]int, n, m int) []diffOp {
+	var reversed []diffOp
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[k-1+d] < v[k+1+d]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+
+		prevX := v[prevK+d]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			reversed = append(reversed, diffEqual)
+			x--
+			y--
+		}
+
+		if x == prevX {
+			reversed = append(reversed, diffInsert)
+			y--
+		} else {
+			reversed = append(reversed, diffDelete)
+			x--
+		}
+	}
+
+	for ; x > 0; x-- {
+		reversed = append(reversed, diffEqual)
+	}
+
+	ops := make([]diffOp, len(reversed))
+	for i, op := range reversed {
+		ops[len(ops)-1-i] = op
+	}
+
+	return ops
+}
+
+// editScript groups ops into edits. b holds the elements inserted by the ops, offset is the
+// index of the first element covered by ops.
+func editScript[T any](ops []diffOp, b []T, offset int) VectorPatch[T] {
+	var edits VectorPatch[T]
+	pos, j := offset, 0
+	for i := 0; i < len(ops); {
+		if ops[i] == diffEqual {
+			pos++
+			j++
+			i++
+			continue
+		}
+
+		deleted, insertStart := 0, j
+		for ; i < len(ops) && ops[i] != diffEqual; i++ {
+			if ops[i] == diffDelete {
+				deleted++
+			} else {
+				j++
+			}
+		}
+
+		inserted := b[insertStart:j:j]
+		replaced := min(deleted, len(inserted))
+		if replaced > 0 {
+			edits = append(edits, VectorEdit[T]{Kind: EditReplace, Index: pos, Count: replaced, Items: inserted[:replaced]})
+		}
+
+		if deleted > replaced {
+			edits = append(edits, VectorEdit[T]{Kind: EditDelete, Index: pos + replaced, Count: deleted - replaced})
+		}
+
+		if len(inserted) > replaced {
+			edits = append(edits, VectorEdit[T]{Kind: EditInsert, Index: pos + replaced, Count: len(inserted) - replaced, Items: inserted[replaced:]})
+		}
+
+		pos += len(inserted)
+	}
+
+	return edits
+}
@@ -0,0 +1,76 @@
+package peds
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func TestVectorDiff(t *testing.T) {
+	cases := []struct{ a, b []int }{
+		{nil, nil},
+		{nil, []int{1, 2}},
+		{[]int{1, 2}, nil},
+		{[]int{1, 2, 3}, []int{1, 2, 3}},
+		{[]int{1, 2, 3}, []int{1, 5, 3}},
+		{[]int{1, 2, 3, 4}, []int{2, 3, 5}},
+		{[]int{1, 2, 3}, []int{0, 1, 2, 3, 4}},
+		{[]int{1, 1, 1, 2}, []int{1, 2, 2, 2}},
+	}
+
+	for _, c := range cases {
+		a, b := NewVector(c.a...), NewVector(c.b...)
+		result, err := a.Apply(VectorDiff(a, b))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqualBool(t, true, VectorEqual(b, result))
+	}
+
+	edits := VectorDiff(NewVector(1, 2, 3), NewVector(1, 5, 3))
+	assertEqual(t, 1, len(edits))
+	assertEqualBool(t, true, edits[0].Kind == EditReplace)
+	assertEqual(t, 1, edits[0].Index)
+	assertEqual(t, 5, edits[0].Items[0])
+}
+
+func TestVectorDiffRandomEdits(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Diff %d", l), func(t *testing.T) {
+			a := NewVector(inputSlice(0, l)...)
+			b := a
+			for i := 0; i < 10; i++ {
+				switch pos := r.Intn(b.Len() + 1); {
+				case pos == b.Len():
+					b = b.Append(-i)
+				case i%2 == 0:
+					b = b.Set(pos, -i)
+				default:
+					b = b.Remove(pos)
+				}
+			}
+
+			edits := VectorDiff(a, b)
+			assertEqualBool(t, true, len(edits) <= 10)
+			result, err := a.Apply(edits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertEqualBool(t, true, VectorEqual(b, result))
+		})
+	}
+}
+
+func TestVectorDiffManyDifferences(t *testing.T) {
+	a := NewVector(inputSlice(0, 5000)...)
+	b := MapVector(a, func(i int) int { return -i })
+	result, err := a.Apply(VectorDiff(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(b, result))
+}
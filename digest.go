@@ -0,0 +1,74 @@
+package peds
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"hash/fnv"
+)
+
+// digestAppender returns a function that appends a stable byte representation of a value of
+// type T to a buffer. The binary codec for T is used if one is available, otherwise the Go
+// syntax representation of the value is used.
+func digestAppender[T any]() func([]byte, T) []byte {
+	codec, err := DefaultCodec[T]()
+	return func(dst []byte, value T) []byte {
+		if err == nil {
+			if result, err := codec.Append(dst, value); err == nil {
+				return result
+			}
+		}
+
+		return appendLengthPrefixed(dst, fmt.Appendf(nil, "%#v", value))
+	}
+}
+
+func newDigest(seed uint64) hash.Hash64 {
+	h := fnv.New64a()
+	h.Write(binary.LittleEndian.AppendUint64(nil, seed))
+	return h
+}
+
+// Sum64 returns a 64 bit hash of the contents of v. Vectors with equal contents produce the
+// same hash given the same seed, also across processes. Elements are hashed using their
+// binary encoding, see DefaultCodec, or their Go syntax representation if they have none.
+func (v *Vector[T]) Sum64(seed uint64) uint64 {
+	appendItem := digestAppender[T]()
+	h := newDigest(seed)
+	buf := binary.AppendUvarint(nil, uint64(v.len))
+	v.Range(func(item T) bool {
+		buf = appendItem(buf, item)
+		if len(buf) > 4096 {
+			h.Write(buf)
+			buf = buf[:0]
+		}
+
+		return true
+	})
+
+	h.Write(buf)
+	return h.Sum64()
+}
+
+// Sum64 returns a 64 bit hash of the contents of m. Maps with equal contents produce the same
+// hash given the same seed, regardless of the order in which items were inserted. Keys and
+// values are hashed as described for Vector.Sum64.
+func (m *Map[K, V]) Sum64(seed uint64) uint64 {
+	appendKey, appendValue := digestAppender[K](), digestAppender[V]()
+	var itemSum uint64
+	var buf []byte
+	m.Range(func(key K, value V) bool {
+		// Items are hashed individually and summed to make the result independent of
+		// the iteration order.
+		buf = appendValue(appendKey(buf[:0], key), value)
+		h := newDigest(seed)
+		h.Write(buf)
+		itemSum += h.Sum64()
+		return true
+	})
+
+	h := newDigest(seed)
+	buf = binary.AppendUvarint(buf[:0], uint64(m.len))
+	h.Write(binary.LittleEndian.AppendUint64(buf, itemSum))
+	return h.Sum64()
+}
@@ -0,0 +1,37 @@
+package peds
+
+import "testing"
+
+func TestVectorSum64(t *testing.T) {
+	v1 := NewVector(inputSlice(0, 1000)...)
+	v2 := NewVector(inputSlice(0, 999)...).Append(999)
+	assertEqualBool(t, true, v1.Sum64(0) == v2.Sum64(0))
+	assertEqualBool(t, false, v1.Sum64(0) == v2.Sum64(1))
+	assertEqualBool(t, false, v1.Sum64(0) == v2.Set(500, -1).Sum64(0))
+	assertEqualBool(t, false, NewVector[int]().Sum64(0) == NewVector(0).Sum64(0))
+
+	// Element boundaries are part of the hash
+	assertEqualBool(t, false, NewVector("ab", "c").Sum64(0) == NewVector("a", "bc").Sum64(0))
+}
+
+func TestVectorSum64IsStable(t *testing.T) {
+	// Changing this value breaks persisted hashes
+	assertEqualBool(t, true, NewVector(1, 2, 3).Sum64(42) == 0x720aecf95691dd0c)
+}
+
+func TestVectorSum64WithoutCodec(t *testing.T) {
+	type point struct{ x, y int }
+	v1 := NewVector(point{1, 2}, point{3, 4})
+	v2 := NewVector(point{1, 2}, point{3, 4})
+	assertEqualBool(t, true, v1.Sum64(0) == v2.Sum64(0))
+	assertEqualBool(t, false, v1.Sum64(0) == v2.Set(0, point{2, 1}).Sum64(0))
+}
+
+func TestMapSum64(t *testing.T) {
+	m1 := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2, "c": 3})
+	m2 := NewMap[string, int]().Store("c", 3).Store("b", 2).Store("a", 1)
+	assertEqualBool(t, true, m1.Sum64(7) == m2.Sum64(7))
+	assertEqualBool(t, false, m1.Sum64(7) == m2.Store("a", 2).Sum64(7))
+	assertEqualBool(t, false, m1.Sum64(7) == m2.Delete("a").Sum64(7))
+	assertEqualBool(t, false, NewMap[string, int]().Sum64(7) == m1.Sum64(7))
+}
@@ -0,0 +1,812 @@
+package peds
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash/fnv"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrBlobNotFound is returned by a BlobStore when no blob with the requested id exists.
+var ErrBlobNotFound = errors.New("peds: blob not found")
+
+// ErrInvalidName is returned for blob ids and DiskMap names that are empty, start with a dot
+// or contain path separators.
+var ErrInvalidName = errors.New("peds: invalid name")
+
+// A BlobStore stores blobs of data identified by string ids. It is the storage used by
+// DiskMap and is straightforward to implement on top of an embedded key-value store such as
+// bbolt or pebble. Implementations must be safe for concurrent use.
+type BlobStore interface {
+	// Get returns the blob stored with id, or ErrBlobNotFound. The caller must not modify
+	// the returned data.
+	Get(id string) ([]byte, error)
+
+	// Put stores data with id, replacing any previous blob with the same id. The blob must
+	// survive a crash once Put has returned.
+	Put(id string, data []byte) error
+}
+
+// A CollectableBlobStore is a BlobStore that can list and delete its blobs, which allows
+// CollectDiskMapGarbage to remove nodes that are no longer reachable.
+type CollectableBlobStore interface {
+	BlobStore
+
+	// Range calls f with the id of each stored blob until f returns false.
+	Range(f func(id string) bool) error
+
+	// Delete removes the blob stored with id. Deleting a missing blob is not an error.
+	Delete(id string) error
+}
+
+// validName reports whether name can be used as a blob id or DiskMap name. Names starting
+// with a dot are reserved for temporary files.
+func validName(name string) bool {
+	return name != "" && name[0] != '.' && !strings.ContainsAny(name, `/\`) && filepath.IsLocal(name)
+}
+
+type memoryBlobStore struct {
+	lock  sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewMemoryBlobStore returns a BlobStore that keeps all blobs in memory, mainly useful for
+// testing.
+func NewMemoryBlobStore() CollectableBlobStore {
+	return &memoryBlobStore{blobs: make(map[string][]byte)}
+}
+
+func (s *memoryBlobStore) Get(id string) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	data, ok := s.blobs[id]
+	if !ok {
+		return nil, ErrBlobNotFound
+	}
+
+	return data, nil
+}
+
+func (s *memoryBlobStore) Put(id string, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.blobs[id] = append([]byte(nil), data...)
+	return nil
+}
+
+func (s *memoryBlobStore) Range(f func(id string) bool) error {
+	s.lock.RLock()
+	ids := make([]string, 0, len(s.blobs))
+	for id := range s.blobs {
+		ids = append(ids, id)
+	}
+
+	s.lock.RUnlock()
+	for _, id := range ids {
+		if !f(id) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *memoryBlobStore) Delete(id string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.blobs, id)
+	return nil
+}
+
+type dirBlobStore struct {
+	dir string
+}
+
+// NewDirBlobStore returns a BlobStore keeping each blob in a file in dir, which is created if
+// it does not exist. Blobs are written to a temporary file that is synced and then renamed,
+// so that a blob is either completely written or not at all, after which dir is synced to make
+// the rename durable. Ids must be valid file names, ErrInvalidName is returned otherwise.
+func NewDirBlobStore(dir string) (CollectableBlobStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &dirBlobStore{dir: dir}, nil
+}
+
+func (s *dirBlobStore) Get(id string) ([]byte, error) {
+	if !validName(id) {
+		return nil, ErrInvalidName
+	}
+
+	data, err := os.ReadFile(filepath.Join(s.dir, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+
+	return data, err
+}
+
+func (s *dirBlobStore) Put(id string, data []byte) error {
+	if !validName(id) {
+		return ErrInvalidName
+	}
+
+	f, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Sync()
+	}
+
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Rename(f.Name(), filepath.Join(s.dir, id))
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+
+	return syncDir(s.dir)
+}
+
+func (s *dirBlobStore) Range(f func(id string) bool) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.Type().IsRegular() && validName(entry.Name()) && !f(entry.Name()) {
+			break
+		}
+	}
+
+	return nil
+}
+
+func (s *dirBlobStore) Delete(id string) error {
+	if !validName(id) {
+		return ErrInvalidName
+	}
+
+	if err := os.Remove(filepath.Join(s.dir, id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return syncDir(s.dir)
+}
+
+// syncDir flushes the directory entries of dir, making renames and removals in it durable.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+
+	err = d.Sync()
+	if closeErr := d.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// /////////////
+// / DiskMap ///
+// /////////////
+
+const (
+	diskNodeLeaf   = 0
+	diskNodeBranch = 1
+
+	// Maximum number of items in a leaf before it is split into a branch
+	diskLeafSize = 32
+
+	diskMapDefaultCacheSize = 1024
+	diskMapRefPrefix        = "ref-"
+)
+
+type diskItem[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+}
+
+// diskNode is either a leaf holding items or, if bitmap is non-zero, a branch holding the
+// ids of up to 32 children, one for each bit set in bitmap.
+type diskNode[K comparable, V any] struct {
+	bitmap   uint32
+	children []string
+	items    []diskItem[K, V]
+}
+
+// diskMapStorage is shared between all versions of a DiskMap opened from the same store.
+type diskMapStorage[K comparable, V any] struct {
+	store      BlobStore
+	keyCodec   Codec[K]
+	valueCodec Codec[V]
+	lock       sync.Mutex
+	cache      *LRU[string, *diskNode[K, V]]
+}
+
+// A DiskMap is a persistent/immutable hash map whose nodes are stored in a BlobStore, with
+// the most recently used nodes cached in memory. This allows maps larger than the available
+// memory. Nodes are content addressed and never modified, so all versions of the map share
+// unchanged nodes, just like versions of a Map do.
+//
+// Every update writes the changed nodes to the store, which is why the update methods
+// return errors. Commit records a version under a name so that it can be opened again,
+// for example after a crash, using OpenDiskMap. Nodes are never removed by updates, so the
+// store keeps growing until CollectDiskMapGarbage is called.
+type DiskMap[K comparable, V any] struct {
+	storage *diskMapStorage[K, V]
+	root    string
+	len     int
+}
+
+// OpenDiskMap returns the version of the map last committed under name in store, or an
+// empty map if no version has been committed. Keys and values are encoded using keyCodec
+// and valueCodec. Keys that are equal must have the same encoding. At most cacheSize nodes
+// are cached in memory, zero or less gives a default size. ErrInvalidName is returned if name
+// is empty, starts with a dot or contains path separators.
+func OpenDiskMap[K comparable, V any](store BlobStore, name string, keyCodec Codec[K], valueCodec Codec[V], cacheSize int) (*DiskMap[K, V], error) {
+	if !validName(name) {
+		return nil, ErrInvalidName
+	}
+
+	if cacheSize <= 0 {
+		cacheSize = diskMapDefaultCacheSize
+	}
+
+	storage := &diskMapStorage[K, V]{
+		store:      store,
+		keyCodec:   keyCodec,
+		valueCodec: valueCodec,
+		cache:      NewLRU[string, *diskNode[K, V]](cacheSize),
+	}
+
+	data, err := store.Get(diskMapRefPrefix + name)
+	if errors.Is(err, ErrBlobNotFound) {
+		return &DiskMap[K, V]{storage: storage}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	length, n := binary.Uvarint(data)
+	if n <= 0 {
+		return nil, ErrInvalidBinaryData
+	}
+
+	return &DiskMap[K, V]{storage: storage, root: string(data[n:]), len: int(length)}, nil
+}
+
+// Commit records m under name in its store, replacing any previously committed version.
+// All nodes of m have already been written to the store when Commit is called. The same
+// names as for OpenDiskMap are valid.
+func (m *DiskMap[K, V]) Commit(name string) error {
+	if !validName(name) {
+		return ErrInvalidName
+	}
+
+	data := binary.AppendUvarint(nil, uint64(m.len))
+	return m.storage.store.Put(diskMapRefPrefix+name, append(data, m.root...))
+}
+
+// CollectDiskMapGarbage removes all nodes in store that are not reachable from a version
+// committed under some name, and returns the number of blobs removed. It must not run
+// concurrently with updates of maps in store, versions that have not been committed become
+// invalid, and maps opened before the collection must be opened again since their caches may
+// refer to removed nodes.
+func CollectDiskMapGarbage(store CollectableBlobStore) (removed int, err error) {
+	var ids []string
+	if err := store.Range(func(id string) bool {
+		ids = append(ids, id)
+		return true
+	}); err != nil {
+		return 0, err
+	}
+
+	reachable := make(map[string]bool)
+	var mark func(id string) error
+	mark = func(id string) error {
+		if id == "" || reachable[id] {
+			return nil
+		}
+
+		reachable[id] = true
+		data, err := store.Get(id)
+		if err != nil || len(data) == 0 || data[0] != diskNodeBranch {
+			return err
+		}
+
+		if len(data) < 5 {
+			return ErrInvalidBinaryData
+		}
+
+		pos := 5
+		for range bits.OnesCount32(binary.LittleEndian.Uint32(data[1:])) {
+			child, size, err := decodeLengthPrefixed(data[pos:])
+			if err != nil {
+				return err
+			}
+
+			if err := mark(string(child)); err != nil {
+				return err
+			}
+
+			pos += size
+		}
+
+		return nil
+	}
+
+	for _, id := range ids {
+		if !strings.HasPrefix(id, diskMapRefPrefix) {
+			continue
+		}
+
+		data, err := store.Get(id)
+		if err != nil {
+			return 0, err
+		}
+
+		_, n := binary.Uvarint(data)
+		if n <= 0 {
+			return 0, ErrInvalidBinaryData
+		}
+
+		if err := mark(string(data[n:])); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, id := range ids {
+		if strings.HasPrefix(id, diskMapRefPrefix) || reachable[id] {
+			continue
+		}
+
+		if err := store.Delete(id); err != nil {
+			return removed, err
+		}
+
+		removed++
+	}
+
+	return removed, nil
+}
+
+// Len returns the number of items in m.
+func (m *DiskMap[K, V]) Len() int {
+	return m.len
+}
+
+func (m *DiskMap[K, V]) hashKey(key K) (uint64, error) {
+	encoded, err := m.storage.keyCodec.Append(nil, key)
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New64a()
+	h.Write(encoded)
+	return h.Sum64(), nil
+}
+
+func diskFragment(hash uint64, depth int) uint32 {
+	return uint32(hash>>(5*depth)) & 0x1f
+}
+
+// diskCanSplit reports whether there are hash bits left to distribute the items of a node at
+// depth among children.
+func diskCanSplit(depth int) bool {
+	return 5*depth < 64
+}
+
+func (s *diskMapStorage[K, V]) getNode(id string) (*diskNode[K, V], error) {
+	s.lock.Lock()
+	n, ok, cache := s.cache.Get(id)
+	s.cache = cache
+	s.lock.Unlock()
+	if ok {
+		return n, nil
+	}
+
+	data, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if n, err = s.decodeNode(data); err != nil {
+		return nil, err
+	}
+
+	s.lock.Lock()
+	s.cache = s.cache.Put(id, n)
+	s.lock.Unlock()
+	return n, nil
+}
+
+func (s *diskMapStorage[K, V]) putNode(n *diskNode[K, V]) (string, error) {
+	data, err := s.encodeNode(n)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	id := hex.EncodeToString(sum[:16])
+	s.lock.Lock()
+	_, cached := s.cache.Peek(id)
+	s.lock.Unlock()
+	if !cached {
+		if err := s.store.Put(id, data); err != nil {
+			return "", err
+		}
+	}
+
+	s.lock.Lock()
+	s.cache = s.cache.Put(id, n)
+	s.lock.Unlock()
+	return id, nil
+}
+
+func (s *diskMapStorage[K, V]) encodeNode(n *diskNode[K, V]) ([]byte, error) {
+	if n.bitmap != 0 {
+		data := binary.LittleEndian.AppendUint32([]byte{diskNodeBranch}, n.bitmap)
+		for _, child := range n.children {
+			data = appendLengthPrefixed(data, []byte(child))
+		}
+
+		return data, nil
+	}
+
+	var err error
+	data := binary.AppendUvarint([]byte{diskNodeLeaf}, uint64(len(n.items)))
+	for _, item := range n.items {
+		data = binary.LittleEndian.AppendUint64(data, item.hash)
+		if data, err = s.keyCodec.Append(data, item.key); err != nil {
+			return nil, err
+		}
+
+		if data, err = s.valueCodec.Append(data, item.value); err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+func (s *diskMapStorage[K, V]) decodeNode(data []byte) (*diskNode[K, V], error) {
+	if len(data) == 0 {
+		return nil, ErrInvalidBinaryData
+	}
+
+	if data[0] == diskNodeBranch {
+		if len(data) < 5 {
+			return nil, ErrInvalidBinaryData
+		}
+
+		n := &diskNode[K, V]{bitmap: binary.LittleEndian.Uint32(data[1:])}
+		pos := 5
+		for range bits.OnesCount32(n.bitmap) {
+			child, size, err := decodeLengthPrefixed(data[pos:])
+			if err != nil {
+				return nil, err
+			}
+
+			n.children = append(n.children, string(child))
+			pos += size
+		}
+
+		return n, nil
+	}
+
+	count, pos := binary.Uvarint(data[1:])
+	if data[0] != diskNodeLeaf || pos <= 0 || count > uint64(len(data)) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	pos++
+	n := &diskNode[K, V]{items: make([]diskItem[K, V], count)}
+	for i := range n.items {
+		if len(data)-pos < 8 {
+			return nil, ErrInvalidBinaryData
+		}
+
+		item := &n.items[i]
+		item.hash = binary.LittleEndian.Uint64(data[pos:])
+		pos += 8
+		key, size, err := s.keyCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += size
+		value, size, err := s.valueCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += size
+		item.key, item.value = key, value
+	}
+
+	return n, nil
+}
+
+// Load returns value identified by key. ok is set to true if key exists in the map, false
+// otherwise. err is set if the nodes could not be read from the store.
+func (m *DiskMap[K, V]) Load(key K) (value V, ok bool, err error) {
+	hash, err := m.hashKey(key)
+	if err != nil {
+		return value, false, err
+	}
+
+	id := m.root
+	for depth := 0; id != ""; depth++ {
+		n, err := m.storage.getNode(id)
+		if err != nil {
+			return value, false, err
+		}
+
+		if n.bitmap == 0 {
+			for _, item := range n.items {
+				if item.hash == hash && item.key == key {
+					return item.value, true, nil
+				}
+			}
+
+			return value, false, nil
+		}
+
+		bit := uint32(1) << diskFragment(hash, depth)
+		if n.bitmap&bit == 0 {
+			return value, false, nil
+		}
+
+		id = n.children[bits.OnesCount32(n.bitmap&(bit-1))]
+	}
+
+	return value, false, nil
+}
+
+// Store returns a new map with value stored under key, replacing any previous value.
+func (m *DiskMap[K, V]) Store(key K, value V) (*DiskMap[K, V], error) {
+	hash, err := m.hashKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	root, added, err := m.insert(m.root, 0, diskItem[K, V]{hash: hash, key: key, value: value})
+	if err != nil {
+		return nil, err
+	}
+
+	length := m.len
+	if added {
+		length++
+	}
+
+	return &DiskMap[K, V]{storage: m.storage, root: root, len: length}, nil
+}
+
+func (m *DiskMap[K, V]) insert(id string, depth int, item diskItem[K, V]) (newID string, added bool, err error) {
+	if id == "" {
+		newID, err = m.storage.putNode(&diskNode[K, V]{items: []diskItem[K, V]{item}})
+		return newID, true, err
+	}
+
+	n, err := m.storage.getNode(id)
+	if err != nil {
+		return "", false, err
+	}
+
+	if n.bitmap == 0 {
+		items := make([]diskItem[K, V], 0, len(n.items)+1)
+		added = true
+		for _, existing := range n.items {
+			if existing.hash == item.hash && existing.key == item.key {
+				existing, added = item, false
+			}
+
+			items = append(items, existing)
+		}
+
+		if added {
+			items = append(items, item)
+		}
+
+		newID, err = m.buildNode(items, depth)
+		return newID, added, err
+	}
+
+	bit := uint32(1) << diskFragment(item.hash, depth)
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	child := ""
+	if n.bitmap&bit != 0 {
+		child = n.children[pos]
+	}
+
+	newChild, added, err := m.insert(child, depth+1, item)
+	if err != nil {
+		return "", false, err
+	}
+
+	children := make([]string, 0, len(n.children)+1)
+	children = append(children, n.children[:pos]...)
+	children = append(children, newChild)
+	if n.bitmap&bit != 0 {
+		pos++
+	}
+
+	children = append(children, n.children[pos:]...)
+	newID, err = m.storage.putNode(&diskNode[K, V]{bitmap: n.bitmap | bit, children: children})
+	return newID, added, err
+}
+
+// buildNode stores items in a leaf at depth, or in a branch with leaves below it if there
+// are too many items for a single leaf.
+func (m *DiskMap[K, V]) buildNode(items []diskItem[K, V], depth int) (string, error) {
+	if len(items) <= diskLeafSize || !diskCanSplit(depth) {
+		return m.storage.putNode(&diskNode[K, V]{items: items})
+	}
+
+	var groups [32][]diskItem[K, V]
+	for _, item := range items {
+		f := diskFragment(item.hash, depth)
+		groups[f] = append(groups[f], item)
+	}
+
+	n := &diskNode[K, V]{}
+	for f, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		child, err := m.buildNode(group, depth+1)
+		if err != nil {
+			return "", err
+		}
+
+		n.bitmap |= 1 << f
+		n.children = append(n.children, child)
+	}
+
+	return m.storage.putNode(n)
+}
+
+// Delete returns a new map without the item identified by key. m is returned if key is not
+// present.
+func (m *DiskMap[K, V]) Delete(key K) (*DiskMap[K, V], error) {
+	hash, err := m.hashKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	root, removed, err := m.remove(m.root, 0, hash, key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !removed {
+		return m, nil
+	}
+
+	return &DiskMap[K, V]{storage: m.storage, root: root, len: m.len - 1}, nil
+}
+
+func (m *DiskMap[K, V]) remove(id string, depth int, hash uint64, key K) (newID string, removed bool, err error) {
+	if id == "" {
+		return "", false, nil
+	}
+
+	n, err := m.storage.getNode(id)
+	if err != nil {
+		return "", false, err
+	}
+
+	if n.bitmap == 0 {
+		for i, item := range n.items {
+			if item.hash == hash && item.key == key {
+				if len(n.items) == 1 {
+					return "", true, nil
+				}
+
+				items := make([]diskItem[K, V], 0, len(n.items)-1)
+				items = append(append(items, n.items[:i]...), n.items[i+1:]...)
+				newID, err = m.storage.putNode(&diskNode[K, V]{items: items})
+				return newID, true, err
+			}
+		}
+
+		return id, false, nil
+	}
+
+	bit := uint32(1) << diskFragment(hash, depth)
+	if n.bitmap&bit == 0 {
+		return id, false, nil
+	}
+
+	pos := bits.OnesCount32(n.bitmap & (bit - 1))
+	newChild, removed, err := m.remove(n.children[pos], depth+1, hash, key)
+	if err != nil || !removed {
+		return id, false, err
+	}
+
+	children := append([]string(nil), n.children...)
+	bitmap := n.bitmap
+	if newChild == "" {
+		children = append(children[:pos], children[pos+1:]...)
+		bitmap &^= bit
+	} else {
+		children[pos] = newChild
+	}
+
+	if bitmap == 0 {
+		return "", true, nil
+	}
+
+	if len(children) == 1 {
+		// A single remaining leaf can take the place of the branch since leaves are
+		// searched without regard to their depth.
+		child, err := m.storage.getNode(children[0])
+		if err != nil {
+			return "", false, err
+		}
+
+		if child.bitmap == 0 {
+			return children[0], true, nil
+		}
+	}
+
+	newID, err = m.storage.putNode(&diskNode[K, V]{bitmap: bitmap, children: children})
+	return newID, true, err
+}
+
+// Range calls f repeatedly passing it each key and value in m as argument until either all
+// items have been visited or f returns false. err is set if the nodes could not be read from
+// the store.
+func (m *DiskMap[K, V]) Range(f func(K, V) bool) error {
+	_, err := m.rangeNode(m.root, f)
+	return err
+}
+
+func (m *DiskMap[K, V]) rangeNode(id string, f func(K, V) bool) (bool, error) {
+	if id == "" {
+		return true, nil
+	}
+
+	n, err := m.storage.getNode(id)
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range n.items {
+		if !f(item.key, item.value) {
+			return false, nil
+		}
+	}
+
+	for _, child := range n.children {
+		if ok, err := m.rangeNode(child, f); !ok || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
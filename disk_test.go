@@ -0,0 +1,240 @@
+package peds
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+func openTestDiskMap(t *testing.T, store BlobStore, cacheSize int) *DiskMap[string, int] {
+	m, err := OpenDiskMap(store, "test", stringCodec{}, varintCodec[int]{}, cacheSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return m
+}
+
+func assertDiskMapEqual(t *testing.T, expected map[string]int, m *DiskMap[string, int]) {
+	t.Helper()
+	assertEqual(t, len(expected), m.Len())
+	for key, value := range expected {
+		actual, ok, err := m.Load(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !ok || actual != value {
+			t.Fatalf("Expected %s=%d, got %d, %v", key, value, actual, ok)
+		}
+	}
+
+	count := 0
+	err := m.Range(func(key string, value int) bool {
+		count++
+		if expected[key] != value {
+			t.Errorf("Unexpected item %s=%d", key, value)
+		}
+
+		return true
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, len(expected), count)
+}
+
+func TestDiskMapStoreLoadDelete(t *testing.T) {
+	for _, cacheSize := range []int{0, 1} {
+		m := openTestDiskMap(t, NewMemoryBlobStore(), cacheSize)
+		expected := make(map[string]int)
+		var err error
+		for i := 0; i < 3000; i++ {
+			key := strconv.Itoa(i % 2000)
+			if m, err = m.Store(key, i); err != nil {
+				t.Fatal(err)
+			}
+
+			expected[key] = i
+		}
+
+		assertDiskMapEqual(t, expected, m)
+		before := m
+		for i := 0; i < 2000; i += 3 {
+			key := strconv.Itoa(i)
+			if m, err = m.Delete(key); err != nil {
+				t.Fatal(err)
+			}
+
+			delete(expected, key)
+		}
+
+		assertDiskMapEqual(t, expected, m)
+		assertEqual(t, 2000, before.Len())
+
+		same, err := m.Delete("missing")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqualBool(t, true, same == m)
+
+		for key := range expected {
+			if m, err = m.Delete(key); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		assertEqual(t, 0, m.Len())
+		_, ok, _ := m.Load("1")
+		assertEqualBool(t, false, ok)
+	}
+}
+
+func TestDiskMapCommitAndReopen(t *testing.T) {
+	store, err := NewDirBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := openTestDiskMap(t, store, 0)
+	assertEqual(t, 0, m.Len())
+	expected := make(map[string]int)
+	for i := 0; i < 500; i++ {
+		key := "key" + strconv.Itoa(i)
+		if m, err = m.Store(key, i); err != nil {
+			t.Fatal(err)
+		}
+
+		expected[key] = i
+	}
+
+	if err := m.Commit("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Uncommitted changes are not visible after reopening
+	if _, err := m.Store("uncommitted", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	assertDiskMapEqual(t, expected, openTestDiskMap(t, store, 10))
+}
+
+func TestDiskMapMissingNode(t *testing.T) {
+	store := NewMemoryBlobStore()
+	if err := store.Put(diskMapRefPrefix+"test", append([]byte{1}, "missing"...)); err != nil {
+		t.Fatal(err)
+	}
+
+	m := openTestDiskMap(t, store, 0)
+	if _, _, err := m.Load("a"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+
+	if err := m.Range(func(string, int) bool { return true }); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+}
+
+func TestDiskMapRangeStop(t *testing.T) {
+	m := openTestDiskMap(t, NewMemoryBlobStore(), 0)
+	var err error
+	for i := 0; i < 100; i++ {
+		if m, err = m.Store(strconv.Itoa(i), i); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := 0
+	if err := m.Range(func(string, int) bool {
+		count++
+		return count < 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 10, count)
+}
+
+func TestDirBlobStoreNotFound(t *testing.T) {
+	store, err := NewDirBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := store.Get("nothing"); !errors.Is(err, ErrBlobNotFound) {
+		t.Errorf("Expected ErrBlobNotFound, got %v", err)
+	}
+}
+
+func TestDiskMapInvalidNames(t *testing.T) {
+	store, err := NewDirBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := openTestDiskMap(t, store, 0)
+	for _, name := range []string{"", "../x", "a/b", `a\b`, ".hidden", ".."} {
+		if err := m.Commit(name); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Expected ErrInvalidName committing %q, got %v", name, err)
+		}
+
+		if _, err := OpenDiskMap(store, name, stringCodec{}, varintCodec[int]{}, 0); !errors.Is(err, ErrInvalidName) {
+			t.Errorf("Expected ErrInvalidName opening %q, got %v", name, err)
+		}
+	}
+
+	if err := store.Put("../x", nil); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Expected ErrInvalidName, got %v", err)
+	}
+}
+
+func TestCollectDiskMapGarbage(t *testing.T) {
+	for name, newStore := range map[string]func() CollectableBlobStore{
+		"memory": NewMemoryBlobStore,
+		"dir": func() CollectableBlobStore {
+			store, err := NewDirBlobStore(t.TempDir())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			return store
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			store := newStore()
+			m := openTestDiskMap(t, store, 0)
+			expected := make(map[string]int)
+			var err error
+			for i := 0; i < 300; i++ {
+				key := "key" + strconv.Itoa(i%100)
+				if m, err = m.Store(key, i); err != nil {
+					t.Fatal(err)
+				}
+
+				expected[key] = i
+			}
+
+			if err := m.Commit("test"); err != nil {
+				t.Fatal(err)
+			}
+
+			removed, err := CollectDiskMapGarbage(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if removed == 0 {
+				t.Error("Expected unreachable nodes to be removed")
+			}
+
+			assertDiskMapEqual(t, expected, openTestDiskMap(t, store, 1))
+			if removed, err = CollectDiskMapGarbage(store); err != nil || removed != 0 {
+				t.Errorf("Expected nothing to remove after collection, got %d, %v", removed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package peds
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// maxDOTLabelLen is the maximum length of the element listing in leaf labels.
+const maxDOTLabelLen = 40
+
+// DumpDOT writes the internal trie of v to w in the Graphviz DOT format.
+func (v *Vector[T]) DumpDOT(w io.Writer) error {
+	return DumpVectorsDOT(w, v)
+}
+
+// DumpVectorsDOT writes the internal tries of vectors to w as a single Graphviz DOT graph. Nodes
+// shared between the vectors are written once, with edges from each vector referring to them,
+// which makes structural sharing between versions of a vector visible.
+func DumpVectorsDOT[T any](w io.Writer, vectors ...*Vector[T]) error {
+	d := dotWriter{w: bufio.NewWriter(w), written: make(map[any]bool)}
+	d.printf("digraph peds {\n\tnode [shape=box, fontname=monospace];\n")
+	for i, v := range vectors {
+		id := fmt.Sprintf("v%d", i)
+		d.printf("\t%s [shape=ellipse, label=\"Vector %d\\nlen=%d\"];\n", id, i, v.len)
+		if v.root != nil {
+			d.printf("\t%s -> %s [label=root];\n", id, dotNode(&d, v.shift, v.root))
+		}
+
+		if len(v.tail) > 0 {
+			d.printf("\t%s -> %s [label=tail];\n", id, dotLeaf(&d, &v.tail[0], v.tail))
+		}
+	}
+
+	d.printf("}\n")
+	return d.flush()
+}
+
+// DumpDOT writes the internal layout of m, the trie of buckets, to w in the Graphviz DOT format.
+func (m *Map[K, V]) DumpDOT(w io.Writer) error {
+	return m.backingVector.DumpDOT(w)
+}
+
+type dotWriter struct {
+	w       *bufio.Writer
+	written map[any]bool
+	err     error
+}
+
+func (d *dotWriter) printf(format string, args ...any) {
+	if d.err == nil {
+		_, d.err = fmt.Fprintf(d.w, format, args...)
+	}
+}
+
+func (d *dotWriter) flush() error {
+	if d.err != nil {
+		return d.err
+	}
+
+	return d.w.Flush()
+}
+
+// dotNode writes n, at level, and its descendants unless already written and returns its id.
+func dotNode[T any](d *dotWriter, level uint, n *node[T]) string {
+	if level == 0 {
+		return dotLeaf(d, n, n.items[:])
+	}
+
+	id := fmt.Sprintf("n%p", n)
+	if d.written[n] {
+		return id
+	}
+
+	d.written[n] = true
+	d.printf("\t%s [label=\"branch\\nlevel=%d\"];\n", id, level/shiftSize)
+	for i, child := range n.children {
+		if child == nil {
+			break
+		}
+
+		d.printf("\t%s -> %s [label=%d];\n", id, dotNode(d, level-shiftSize, child), i)
+	}
+
+	return id
+}
+
+// dotLeaf writes a leaf identified by key holding items unless already written and returns its
+// id.
+func dotLeaf[T any](d *dotWriter, key any, items []T) string {
+	id := fmt.Sprintf("n%p", key)
+	if d.written[key] {
+		return id
+	}
+
+	d.written[key] = true
+	label := fmt.Sprint(items)
+	if len(label) > maxDOTLabelLen {
+		label = label[:maxDOTLabelLen] + "..."
+	}
+
+	d.printf("\t%s [label=%s];\n", id, strconv.Quote(fmt.Sprintf("leaf len=%d %s", len(items), label)))
+	return id
+}
@@ -0,0 +1,47 @@
+package peds
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestVectorDumpDOT(t *testing.T) {
+	var buf bytes.Buffer
+	v := NewVector(inputSlice(0, 100)...)
+	if err := v.DumpDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	assertEqualBool(t, true, strings.HasPrefix(out, "digraph peds {"))
+	assertEqualBool(t, true, strings.HasSuffix(out, "}\n"))
+	assertEqual(t, 1, strings.Count(out, "branch"))
+	assertEqual(t, 4, strings.Count(out, "leaf len="))
+	assertEqual(t, 1, strings.Count(out, "label=tail"))
+}
+
+func TestDumpVectorsDOTSharesNodes(t *testing.T) {
+	var buf bytes.Buffer
+	v1 := NewVector(inputSlice(0, 1000)...)
+	v2 := v1.Set(0, -1)
+	if err := DumpVectorsDOT(&buf, v1, v2); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the leaf holding element 0 differs, the remaining 30 leaves and the tail are shared
+	out := buf.String()
+	assertEqual(t, 31+1+1, strings.Count(out, "leaf len="))
+	assertEqual(t, 2, strings.Count(out, "label=tail"))
+	assertEqual(t, 2, strings.Count(out, "shape=ellipse"))
+}
+
+func TestMapDumpDOT(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewMap(MapItem[string, int]{Key: "a", Value: 1})
+	if err := m.DumpDOT(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, strings.Contains(buf.String(), "{a 1}"))
+}
@@ -0,0 +1,41 @@
+package peds
+
+import "reflect"
+
+// Equal reports whether v and other contain equal elements in the same order. Elements with an
+// Equal method, such as other peds collections, are compared using it, other elements are
+// compared with reflect.DeepEqual.
+//
+// Equal is recognized by github.com/google/go-cmp, which allows cmp.Equal and cmp.Diff to be
+// used on structs holding vectors without options.
+func (v *Vector[T]) Equal(other *Vector[T]) bool {
+	if v == nil || other == nil {
+		return v == other
+	}
+
+	return v.EqualFunc(other, deepEqualFunc[T]())
+}
+
+// Equal reports whether m and other contain the same keys associated with equal values. Values
+// are compared as elements in Vector.Equal.
+//
+// Equal is recognized by github.com/google/go-cmp, which allows cmp.Equal and cmp.Diff to be
+// used on structs holding maps without options.
+func (m *Map[K, V]) Equal(other *Map[K, V]) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return m.EqualFunc(other, deepEqualFunc[V]())
+}
+
+// deepEqualFunc returns a function comparing values of type T using their Equal method if
+// they have one, and reflect.DeepEqual otherwise.
+func deepEqualFunc[T any]() func(a, b T) bool {
+	var zero T
+	if _, ok := any(zero).(interface{ Equal(T) bool }); ok {
+		return func(a, b T) bool { return any(a).(interface{ Equal(T) bool }).Equal(b) }
+	}
+
+	return func(a, b T) bool { return reflect.DeepEqual(a, b) }
+}
@@ -0,0 +1,39 @@
+package peds
+
+import "testing"
+
+func TestVectorEqualMethod(t *testing.T) {
+	a := NewVector(inputSlice(0, 100)...)
+	b := NewVector[int]().Concat(a)
+	assertEqualBool(t, true, a.Equal(b))
+	assertEqualBool(t, false, a.Equal(b.Set(5, -1)))
+
+	var nilVector *Vector[int]
+	assertEqualBool(t, true, nilVector.Equal(nil))
+	assertEqualBool(t, false, a.Equal(nil))
+}
+
+func TestVectorEqualNested(t *testing.T) {
+	// The inner vectors have different internal state, one has a cached content hash, but
+	// equal contents.
+	inner := NewVector(inputSlice(0, 1000)...)
+	inner.ContentHash()
+	a := NewVector(inner, NewVector(1))
+	b := NewVector(NewVector(inputSlice(0, 1000)...), NewVector(1))
+	assertEqualBool(t, true, a.Equal(b))
+	assertEqualBool(t, false, a.Equal(NewVector(inner, NewVector(2))))
+}
+
+func TestVectorEqualSlices(t *testing.T) {
+	a := NewVector([]int{1, 2}, []int{3})
+	assertEqualBool(t, true, a.Equal(NewVector([]int{1, 2}, []int{3})))
+	assertEqualBool(t, false, a.Equal(NewVector([]int{1, 2}, []int{4})))
+}
+
+func TestMapEqualMethod(t *testing.T) {
+	a := NewMap(MapItem[string, *Vector[int]]{Key: "a", Value: NewVector(1, 2)})
+	b := NewMap[string, *Vector[int]]().Store("a", NewVector(1).Append(2))
+	assertEqualBool(t, true, a.Equal(b))
+	assertEqualBool(t, false, a.Equal(b.Store("a", NewVector(1))))
+	assertEqualBool(t, false, a.Equal(nil))
+}
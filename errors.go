@@ -0,0 +1,47 @@
+package peds
+
+import "fmt"
+
+// ErrIndexOutOfBounds is the value passed to panic when a collection is accessed at an
+// index outside of its bounds. Type is the name of the collection type, e.g. "Vector".
+type ErrIndexOutOfBounds struct {
+	Index int
+	Len   int
+	Type  string
+}
+
+func (e ErrIndexOutOfBounds) Error() string {
+	return fmt.Sprintf("Index out of bounds, index=%d, len=%d, type=%s", e.Index, e.Len, e.Type)
+}
+
+// ErrPointOutOfBounds is the value passed to panic when a two dimensional collection, such
+// as a Grid, is accessed at a position outside of its bounds.
+type ErrPointOutOfBounds struct {
+	X      int
+	Y      int
+	Width  int
+	Height int
+}
+
+func (e ErrPointOutOfBounds) Error() string {
+	return fmt.Sprintf("Point out of bounds, x=%d, y=%d, size=%dx%d", e.X, e.Y, e.Width, e.Height)
+}
+
+// ErrSliceOutOfBounds is the value passed to panic when slicing a collection with indexes
+// that are outside of its bounds or where Start is greater than Stop.
+type ErrSliceOutOfBounds struct {
+	Start int
+	Stop  int
+	Len   int
+}
+
+func (e ErrSliceOutOfBounds) Error() string {
+	switch {
+	case e.Start < 0:
+		return fmt.Sprintf("Invalid slice index %d (index must be non-negative)", e.Start)
+	case e.Start > e.Stop:
+		return fmt.Sprintf("Invalid slice index: %d > %d", e.Start, e.Stop)
+	}
+
+	return fmt.Sprintf("Slice bounds out of range, start=%d, stop=%d, len=%d", e.Start, e.Stop, e.Len)
+}
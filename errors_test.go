@@ -0,0 +1,44 @@
+package peds
+
+import (
+	"errors"
+	"testing"
+)
+
+func recoverError(f func()) (err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+
+	f()
+	return nil
+}
+
+func TestIndexOutOfBoundsPanicValue(t *testing.T) {
+	err := recoverError(func() { NewVector(1, 2, 3).Get(5) })
+	var indexErr ErrIndexOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqual(t, 5, indexErr.Index)
+	assertEqual(t, 3, indexErr.Len)
+	assertEqualString(t, "Vector", indexErr.Type)
+
+	err = recoverError(func() { NewVectorSlice(1, 2, 3).Slice(1, 3).Set(-1, 0) })
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqual(t, -1, indexErr.Index)
+	assertEqual(t, 2, indexErr.Len)
+	assertEqualString(t, "VectorSlice", indexErr.Type)
+
+	err = recoverError(func() { NewRRBVector(1, 2, 3).Remove(3) })
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqualString(t, "RRBVector", indexErr.Type)
+}
+
+func TestSliceOutOfBoundsPanicValue(t *testing.T) {
+	err := recoverError(func() { NewVector(1, 2, 3).Slice(2, 4) })
+	var sliceErr ErrSliceOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &sliceErr))
+	assertEqual(t, 2, sliceErr.Start)
+	assertEqual(t, 4, sliceErr.Stop)
+	assertEqual(t, 3, sliceErr.Len)
+	assertEqualString(t, "Slice bounds out of range, start=2, stop=4, len=3", sliceErr.Error())
+}
@@ -0,0 +1,91 @@
+package peds
+
+import "time"
+
+// /////////////////
+// / ExpiringMap ///
+// /////////////////
+
+type expiringEntry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// expired reports whether e has expired at now. Entries with a zero expiry never expire.
+func (e expiringEntry[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// An ExpiringMap is a persistent/immutable map where each entry has an expiry time. Expired
+// entries are filtered out lazily by Load and Range, which take the current time as an
+// argument, but are kept in the map until removed by Compact. This makes the map
+// deterministic and suitable as shared session or cache state, for example held in a Ref.
+type ExpiringMap[K comparable, V any] struct {
+	entries *Map[K, expiringEntry[V]]
+}
+
+// NewExpiringMap returns a new, empty, expiring map.
+func NewExpiringMap[K comparable, V any]() *ExpiringMap[K, V] {
+	return &ExpiringMap[K, V]{entries: NewMap[K, expiringEntry[V]]()}
+}
+
+// Len returns the number of entries in m, including expired entries that have not yet been
+// removed by Compact.
+func (m *ExpiringMap[K, V]) Len() int {
+	return m.entries.Len()
+}
+
+// Store returns a new map with value stored for key, expiring at expires. A zero expires
+// means that the entry never expires.
+func (m *ExpiringMap[K, V]) Store(key K, value V, expires time.Time) *ExpiringMap[K, V] {
+	return &ExpiringMap[K, V]{entries: m.entries.Store(key, expiringEntry[V]{value: value, expires: expires})}
+}
+
+// StoreTTL returns a new map with value stored for key, expiring ttl after now.
+func (m *ExpiringMap[K, V]) StoreTTL(key K, value V, now time.Time, ttl time.Duration) *ExpiringMap[K, V] {
+	return m.Store(key, value, now.Add(ttl))
+}
+
+// Load returns the value stored for key and when it expires. ok is set to false if key is
+// not present or if the entry has expired at now.
+func (m *ExpiringMap[K, V]) Load(key K, now time.Time) (value V, expires time.Time, ok bool) {
+	entry, ok := m.entries.Load(key)
+	if !ok || entry.expired(now) {
+		return value, expires, false
+	}
+
+	return entry.value, entry.expires, true
+}
+
+// Delete returns a new map with key removed.
+func (m *ExpiringMap[K, V]) Delete(key K) *ExpiringMap[K, V] {
+	entries := m.entries.Delete(key)
+	if entries == m.entries {
+		return m
+	}
+
+	return &ExpiringMap[K, V]{entries: entries}
+}
+
+// Range calls f repeatedly passing it each key and value of the entries that have not
+// expired at now as argument until either all entries have been visited or f returns false.
+func (m *ExpiringMap[K, V]) Range(now time.Time, f func(K, V) bool) {
+	m.entries.Range(func(key K, entry expiringEntry[V]) bool {
+		if entry.expired(now) {
+			return true
+		}
+
+		return f(key, entry.value)
+	})
+}
+
+// Compact returns a new map with all entries that have expired at now removed. m itself is
+// returned if no entry has expired.
+func (m *ExpiringMap[K, V]) Compact(now time.Time) *ExpiringMap[K, V] {
+	entries := m.entries.Filter(func(_ K, entry expiringEntry[V]) bool { return !entry.expired(now) })
+	if entries == m.entries {
+		return m
+	}
+
+	return &ExpiringMap[K, V]{entries: entries}
+}
@@ -0,0 +1,76 @@
+package peds
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpiringMapLoad(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewExpiringMap[string, int]().
+		Store("a", 1, now.Add(time.Minute)).
+		StoreTTL("b", 2, now, time.Hour).
+		Store("c", 3, time.Time{})
+
+	value, expires, ok := m.Load("a", now)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, value)
+	assertEqualBool(t, true, expires.Equal(now.Add(time.Minute)))
+
+	_, _, ok = m.Load("a", now.Add(time.Minute))
+	assertEqualBool(t, false, ok)
+	_, _, ok = m.Load("b", now.Add(time.Minute))
+	assertEqualBool(t, true, ok)
+	value, _, ok = m.Load("c", now.Add(1000*time.Hour))
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 3, value)
+	_, _, ok = m.Load("d", now)
+	assertEqualBool(t, false, ok)
+}
+
+func TestExpiringMapRangeSkipsExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewExpiringMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Store(i, i, now.Add(time.Duration(i)*time.Second))
+	}
+
+	count := 0
+	m.Range(now.Add(50*time.Second), func(key, value int) bool {
+		assertEqualBool(t, true, key > 50)
+		count++
+		return true
+	})
+
+	assertEqual(t, 49, count)
+	assertEqual(t, 100, m.Len())
+}
+
+func TestExpiringMapCompact(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewExpiringMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Store(i, i, now.Add(time.Duration(i)*time.Second))
+	}
+
+	compacted := m.Compact(now.Add(30 * time.Second))
+	assertEqual(t, 69, compacted.Len())
+	assertEqual(t, 100, m.Len())
+	_, _, ok := compacted.Load(30, time.Time{})
+	assertEqualBool(t, false, ok)
+	_, _, ok = compacted.Load(31, time.Time{})
+	assertEqualBool(t, true, ok)
+
+	assertEqualBool(t, true, compacted == compacted.Compact(now.Add(30*time.Second)))
+	assertEqual(t, 0, m.Compact(now.Add(time.Hour)).Len())
+}
+
+func TestExpiringMapDelete(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewExpiringMap[string, int]().Store("a", 1, time.Time{})
+	assertEqual(t, 0, m.Delete("a").Len())
+	assertEqual(t, 1, m.Len())
+	assertEqualBool(t, true, m == m.Delete("b"))
+	_, _, ok := m.Delete("a").Load("a", now)
+	assertEqualBool(t, false, ok)
+}
@@ -0,0 +1,529 @@
+package peds
+
+// ////////////////
+// / FingerTree ///
+// ////////////////
+
+// A Measurer defines the measure, of type M, that a FingerTree maintains for its elements.
+// Measures form a monoid: Combine must be associative and Zero must be its identity. The
+// measure of a sequence of elements is the combination of the measures of its elements, for
+// example the largest priority or the sum of the weights of the elements.
+type Measurer[T, M any] interface {
+	// Zero returns the measure of an empty sequence.
+	Zero() M
+
+	// Measure returns the measure of a single element.
+	Measure(item T) M
+
+	// Combine returns the measure of the concatenation of two sequences with measures a
+	// and b.
+	Combine(a, b M) M
+}
+
+// ftNode is either a leaf holding an element or a 2-3 node holding two or three nodes one
+// level down. The size, the number of elements below the node, and the measure are cached.
+type ftNode[T, M any] struct {
+	size     int
+	measure  M
+	value    T
+	children []*ftNode[T, M]
+}
+
+// ftTree is a finger tree of nodes. A nil tree is empty, a tree with single set holds one
+// node, otherwise it is deep with one to four nodes in each of prefix and suffix and a tree
+// of 2-3 nodes in the middle.
+type ftTree[T, M any] struct {
+	size    int
+	measure M
+	single  *ftNode[T, M]
+	prefix  []*ftNode[T, M]
+	middle  *ftTree[T, M]
+	suffix  []*ftNode[T, M]
+}
+
+// A FingerTree is a persistent/immutable sequence, implemented as a 2-3 finger tree, which
+// maintains a measure of its elements defined by a Measurer. Adding and removing elements at
+// either end is amortized constant time, concatenation and splitting, by position or where
+// a predicate on the measure becomes true, are logarithmic. This makes finger trees a
+// suitable base for deques with fast splits and concatenation, priority queues, see
+// PriorityQueue, and ordered sequences.
+type FingerTree[T, M any] struct {
+	measurer Measurer[T, M]
+	root     *ftTree[T, M]
+}
+
+// NewFingerTree returns a new finger tree, measured by measurer, containing the items
+// provided in items.
+func NewFingerTree[T, M any](measurer Measurer[T, M], items ...T) *FingerTree[T, M] {
+	t := &FingerTree[T, M]{measurer: measurer}
+	for _, item := range items {
+		t.root = t.pushBack(t.root, t.leaf(item))
+	}
+
+	return t
+}
+
+type unitMeasurer[T any] struct{}
+
+func (unitMeasurer[T]) Zero() struct{}                 { return struct{}{} }
+func (unitMeasurer[T]) Measure(T) struct{}             { return struct{}{} }
+func (unitMeasurer[T]) Combine(_, _ struct{}) struct{} { return struct{}{} }
+
+// NewFingerSeq returns a new finger tree without a measure, only supporting positional
+// access, containing the items provided in items.
+func NewFingerSeq[T any](items ...T) *FingerTree[T, struct{}] {
+	return NewFingerTree[T, struct{}](unitMeasurer[T]{}, items...)
+}
+
+func (t *FingerTree[T, M]) with(root *ftTree[T, M]) *FingerTree[T, M] {
+	return &FingerTree[T, M]{measurer: t.measurer, root: root}
+}
+
+// Len returns the number of elements in t.
+func (t *FingerTree[T, M]) Len() int {
+	return t.root.sizeOf()
+}
+
+// Measure returns the measure of all elements in t.
+func (t *FingerTree[T, M]) Measure() M {
+	if t.root == nil {
+		return t.measurer.Zero()
+	}
+
+	return t.root.measure
+}
+
+func (n *ftTree[T, M]) sizeOf() int {
+	if n == nil {
+		return 0
+	}
+
+	return n.size
+}
+
+func (t *FingerTree[T, M]) leaf(item T) *ftNode[T, M] {
+	return &ftNode[T, M]{size: 1, measure: t.measurer.Measure(item), value: item}
+}
+
+func (t *FingerTree[T, M]) node(children ...*ftNode[T, M]) *ftNode[T, M] {
+	n := &ftNode[T, M]{children: children, size: children[0].size, measure: children[0].measure}
+	for _, child := range children[1:] {
+		n.size += child.size
+		n.measure = t.measurer.Combine(n.measure, child.measure)
+	}
+
+	return n
+}
+
+func (t *FingerTree[T, M]) treeMeasure(n *ftTree[T, M]) M {
+	if n == nil {
+		return t.measurer.Zero()
+	}
+
+	return n.measure
+}
+
+func (t *FingerTree[T, M]) single(n *ftNode[T, M]) *ftTree[T, M] {
+	return &ftTree[T, M]{size: n.size, measure: n.measure, single: n}
+}
+
+func (t *FingerTree[T, M]) deep(prefix []*ftNode[T, M], middle *ftTree[T, M], suffix []*ftNode[T, M]) *ftTree[T, M] {
+	result := &ftTree[T, M]{prefix: prefix, middle: middle, suffix: suffix, measure: t.measurer.Zero()}
+	for _, n := range prefix {
+		result.size += n.size
+		result.measure = t.measurer.Combine(result.measure, n.measure)
+	}
+
+	if middle != nil {
+		result.size += middle.size
+		result.measure = t.measurer.Combine(result.measure, middle.measure)
+	}
+
+	for _, n := range suffix {
+		result.size += n.size
+		result.measure = t.measurer.Combine(result.measure, n.measure)
+	}
+
+	return result
+}
+
+// digit returns a new slice holding nodes. Digits are never modified after creation so that
+// they can be shared between trees.
+func digit[T, M any](nodes ...*ftNode[T, M]) []*ftNode[T, M] {
+	return append([]*ftNode[T, M](nil), nodes...)
+}
+
+func (t *FingerTree[T, M]) pushFront(tree *ftTree[T, M], n *ftNode[T, M]) *ftTree[T, M] {
+	switch {
+	case tree == nil:
+		return t.single(n)
+	case tree.single != nil:
+		return t.deep(digit(n), nil, digit(tree.single))
+	case len(tree.prefix) < 4:
+		return t.deep(append(digit(n), tree.prefix...), tree.middle, tree.suffix)
+	}
+
+	p := tree.prefix
+	return t.deep(digit(n, p[0]), t.pushFront(tree.middle, t.node(p[1], p[2], p[3])), tree.suffix)
+}
+
+func (t *FingerTree[T, M]) pushBack(tree *ftTree[T, M], n *ftNode[T, M]) *ftTree[T, M] {
+	switch {
+	case tree == nil:
+		return t.single(n)
+	case tree.single != nil:
+		return t.deep(digit(tree.single), nil, digit(n))
+	case len(tree.suffix) < 4:
+		return t.deep(tree.prefix, tree.middle, append(digit(tree.suffix...), n))
+	}
+
+	s := tree.suffix
+	return t.deep(tree.prefix, t.pushBack(tree.middle, t.node(s[0], s[1], s[2])), digit(s[3], n))
+}
+
+// viewFront returns the first node of tree and the tree without it.
+func (t *FingerTree[T, M]) viewFront(tree *ftTree[T, M]) (*ftNode[T, M], *ftTree[T, M]) {
+	if tree.single != nil {
+		return tree.single, nil
+	}
+
+	return tree.prefix[0], t.deepFront(tree.prefix[1:], tree.middle, tree.suffix)
+}
+
+// viewBack returns the last node of tree and the tree without it.
+func (t *FingerTree[T, M]) viewBack(tree *ftTree[T, M]) (*ftNode[T, M], *ftTree[T, M]) {
+	if tree.single != nil {
+		return tree.single, nil
+	}
+
+	last := len(tree.suffix) - 1
+	return tree.suffix[last], t.deepBack(tree.prefix, tree.middle, tree.suffix[:last])
+}
+
+// deepFront returns a tree of the nodes in prefix, middle and suffix where prefix may be
+// empty, in which case it is refilled from middle.
+func (t *FingerTree[T, M]) deepFront(prefix []*ftNode[T, M], middle *ftTree[T, M], suffix []*ftNode[T, M]) *ftTree[T, M] {
+	if len(prefix) > 0 {
+		return t.deep(prefix, middle, suffix)
+	}
+
+	if middle == nil {
+		return t.digitToTree(suffix)
+	}
+
+	first, rest := t.viewFront(middle)
+	return t.deep(first.children, rest, suffix)
+}
+
+// deepBack is the mirror image of deepFront, suffix may be empty.
+func (t *FingerTree[T, M]) deepBack(prefix []*ftNode[T, M], middle *ftTree[T, M], suffix []*ftNode[T, M]) *ftTree[T, M] {
+	if len(suffix) > 0 {
+		return t.deep(prefix, middle, suffix)
+	}
+
+	if middle == nil {
+		return t.digitToTree(prefix)
+	}
+
+	last, rest := t.viewBack(middle)
+	return t.deep(prefix, rest, last.children)
+}
+
+func (t *FingerTree[T, M]) digitToTree(nodes []*ftNode[T, M]) *ftTree[T, M] {
+	var tree *ftTree[T, M]
+	for _, n := range nodes {
+		tree = t.pushBack(tree, n)
+	}
+
+	return tree
+}
+
+// PushFront returns a new tree with item added to the front.
+func (t *FingerTree[T, M]) PushFront(item T) *FingerTree[T, M] {
+	return t.with(t.pushFront(t.root, t.leaf(item)))
+}
+
+// PushBack returns a new tree with item added to the back.
+func (t *FingerTree[T, M]) PushBack(item T) *FingerTree[T, M] {
+	return t.with(t.pushBack(t.root, t.leaf(item)))
+}
+
+// PopFront returns the element at the front of t and a new tree with that element removed.
+func (t *FingerTree[T, M]) PopFront() (T, *FingerTree[T, M]) {
+	if t.root == nil {
+		panic("Pop from empty finger tree")
+	}
+
+	first, rest := t.viewFront(t.root)
+	return first.value, t.with(rest)
+}
+
+// PopBack returns the element at the back of t and a new tree with that element removed.
+func (t *FingerTree[T, M]) PopBack() (T, *FingerTree[T, M]) {
+	if t.root == nil {
+		panic("Pop from empty finger tree")
+	}
+
+	last, rest := t.viewBack(t.root)
+	return last.value, t.with(rest)
+}
+
+// PeekFront returns the element at the front of t. ok is set to false if t is empty.
+func (t *FingerTree[T, M]) PeekFront() (item T, ok bool) {
+	switch {
+	case t.root == nil:
+		return item, false
+	case t.root.single != nil:
+		return t.root.single.value, true
+	}
+
+	return t.root.prefix[0].value, true
+}
+
+// PeekBack returns the element at the back of t. ok is set to false if t is empty.
+func (t *FingerTree[T, M]) PeekBack() (item T, ok bool) {
+	switch {
+	case t.root == nil:
+		return item, false
+	case t.root.single != nil:
+		return t.root.single.value, true
+	}
+
+	return t.root.suffix[len(t.root.suffix)-1].value, true
+}
+
+// Get returns the element at position i.
+func (t *FingerTree[T, M]) Get(i int) T {
+	if i < 0 || i >= t.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: t.Len(), Type: "FingerTree"})
+	}
+
+	tree := t.root
+	for {
+		if tree.single != nil {
+			return tree.single.get(i)
+		}
+
+		for _, n := range tree.prefix {
+			if i < n.size {
+				return n.get(i)
+			}
+
+			i -= n.size
+		}
+
+		if size := tree.middle.sizeOf(); i >= size {
+			i -= size
+			for _, n := range tree.suffix {
+				if i < n.size {
+					return n.get(i)
+				}
+
+				i -= n.size
+			}
+		}
+
+		tree = tree.middle
+	}
+}
+
+func (n *ftNode[T, M]) get(i int) T {
+	for n.children != nil {
+		for _, child := range n.children {
+			if i < child.size {
+				n = child
+				break
+			}
+
+			i -= child.size
+		}
+	}
+
+	return n.value
+}
+
+// Concat returns a new tree containing all elements of t followed by all elements of other.
+func (t *FingerTree[T, M]) Concat(other *FingerTree[T, M]) *FingerTree[T, M] {
+	return t.with(t.concat(t.root, nil, other.root))
+}
+
+// concat returns the concatenation of a, the nodes in middle and b.
+func (t *FingerTree[T, M]) concat(a *ftTree[T, M], middle []*ftNode[T, M], b *ftTree[T, M]) *ftTree[T, M] {
+	switch {
+	case a == nil:
+		for i := len(middle) - 1; i >= 0; i-- {
+			b = t.pushFront(b, middle[i])
+		}
+
+		return b
+	case b == nil:
+		for _, n := range middle {
+			a = t.pushBack(a, n)
+		}
+
+		return a
+	case a.single != nil:
+		return t.pushFront(t.concat(nil, middle, b), a.single)
+	case b.single != nil:
+		return t.pushBack(t.concat(a, middle, nil), b.single)
+	}
+
+	nodes := make([]*ftNode[T, M], 0, len(a.suffix)+len(middle)+len(b.prefix))
+	nodes = append(append(append(nodes, a.suffix...), middle...), b.prefix...)
+	return t.deep(a.prefix, t.concat(a.middle, t.groupNodes(nodes), b.middle), b.suffix)
+}
+
+// groupNodes groups nodes, at least two of them, into 2-3 nodes.
+func (t *FingerTree[T, M]) groupNodes(nodes []*ftNode[T, M]) []*ftNode[T, M] {
+	var result []*ftNode[T, M]
+	for len(nodes) > 0 {
+		switch len(nodes) {
+		case 2, 4:
+			result = append(result, t.node(nodes[0], nodes[1]))
+			nodes = nodes[2:]
+		default:
+			result = append(result, t.node(nodes[0], nodes[1], nodes[2]))
+			nodes = nodes[3:]
+		}
+	}
+
+	return result
+}
+
+// ftAccumulator holds the size and measure of a prefix of a tree during a split.
+type ftAccumulator[M any] struct {
+	size    int
+	measure M
+}
+
+func (t *FingerTree[T, M]) accumulate(acc ftAccumulator[M], size int, measure M) ftAccumulator[M] {
+	return ftAccumulator[M]{size: acc.size + size, measure: t.measurer.Combine(acc.measure, measure)}
+}
+
+// Split returns two trees, the first holding the elements of t before the position where
+// pred, called with the measure of the elements up to and including that position, first
+// returns true, the second holding the rest of the elements. pred must be monotonic, once
+// true it must remain true for longer prefixes. If pred never returns true the first tree
+// holds all elements of t.
+func (t *FingerTree[T, M]) Split(pred func(M) bool) (*FingerTree[T, M], *FingerTree[T, M]) {
+	return t.split(func(acc ftAccumulator[M]) bool { return pred(acc.measure) })
+}
+
+// SplitAt returns two trees, the first holding the first i elements of t and the second
+// holding the rest.
+func (t *FingerTree[T, M]) SplitAt(i int) (*FingerTree[T, M], *FingerTree[T, M]) {
+	assertSliceOk(i, i, t.Len())
+
+	return t.split(func(acc ftAccumulator[M]) bool { return acc.size > i })
+}
+
+func (t *FingerTree[T, M]) split(pred func(ftAccumulator[M]) bool) (*FingerTree[T, M], *FingerTree[T, M]) {
+	if t.root == nil || !pred(ftAccumulator[M]{size: t.root.size, measure: t.root.measure}) {
+		return t, t.with(nil)
+	}
+
+	left, x, right := t.splitTree(pred, ftAccumulator[M]{measure: t.measurer.Zero()}, t.root)
+	return t.with(left), t.with(t.pushFront(right, x))
+}
+
+// splitTree splits tree, which must not be empty, at the node where pred first returns true
+// given that the nodes before tree have the accumulated size and measure acc.
+func (t *FingerTree[T, M]) splitTree(pred func(ftAccumulator[M]) bool, acc ftAccumulator[M], tree *ftTree[T, M]) (*ftTree[T, M], *ftNode[T, M], *ftTree[T, M]) {
+	if tree.single != nil {
+		return nil, tree.single, nil
+	}
+
+	afterPrefix := acc
+	for _, n := range tree.prefix {
+		afterPrefix = t.accumulate(afterPrefix, n.size, n.measure)
+	}
+
+	if pred(afterPrefix) {
+		left, x, right := t.splitDigit(pred, acc, tree.prefix)
+		return t.digitToTree(left), x, t.deepFront(right, tree.middle, tree.suffix)
+	}
+
+	afterMiddle := afterPrefix
+	if tree.middle != nil {
+		afterMiddle = t.accumulate(afterPrefix, tree.middle.size, tree.middle.measure)
+		if pred(afterMiddle) {
+			middleLeft, xs, middleRight := t.splitTree(pred, afterPrefix, tree.middle)
+			beforeXs := afterPrefix
+			if middleLeft != nil {
+				beforeXs = t.accumulate(afterPrefix, middleLeft.size, middleLeft.measure)
+			}
+
+			left, x, right := t.splitDigit(pred, beforeXs, xs.children)
+			return t.deepBack(tree.prefix, middleLeft, left), x, t.deepFront(right, middleRight, tree.suffix)
+		}
+	}
+
+	left, x, right := t.splitDigit(pred, afterMiddle, tree.suffix)
+	return t.deepBack(tree.prefix, tree.middle, left), x, t.digitToTree(right)
+}
+
+// splitDigit returns the nodes before the node where pred first returns true, that node and
+// the nodes after it. The last node is returned if pred never returns true.
+func (t *FingerTree[T, M]) splitDigit(pred func(ftAccumulator[M]) bool, acc ftAccumulator[M], nodes []*ftNode[T, M]) ([]*ftNode[T, M], *ftNode[T, M], []*ftNode[T, M]) {
+	for i, n := range nodes[:len(nodes)-1] {
+		acc = t.accumulate(acc, n.size, n.measure)
+		if pred(acc) {
+			return digit(nodes[:i]...), n, digit(nodes[i+1:]...)
+		}
+	}
+
+	return digit(nodes[:len(nodes)-1]...), nodes[len(nodes)-1], nil
+}
+
+// Range calls f repeatedly passing it each element in t, from front to back, as argument
+// until either all elements have been visited or f returns false.
+func (t *FingerTree[T, M]) Range(f func(T) bool) {
+	t.root.rangeTree(f)
+}
+
+func (n *ftTree[T, M]) rangeTree(f func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if n.single != nil {
+		return n.single.rangeNode(f)
+	}
+
+	for _, child := range n.prefix {
+		if !child.rangeNode(f) {
+			return false
+		}
+	}
+
+	if !n.middle.rangeTree(f) {
+		return false
+	}
+
+	for _, child := range n.suffix {
+		if !child.rangeNode(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n *ftNode[T, M]) rangeNode(f func(T) bool) bool {
+	if n.children == nil {
+		return f(n.value)
+	}
+
+	for _, child := range n.children {
+		if !child.rangeNode(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToNativeSlice returns a new native slice containing the elements of t in order.
+func (t *FingerTree[T, M]) ToNativeSlice() []T {
+	return rangeToNativeSlice(t.Len(), t.Range)
+}
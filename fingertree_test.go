@@ -0,0 +1,194 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func assertFingerTreeContents[M any](t *testing.T, expected []int, tree *FingerTree[int, M]) {
+	t.Helper()
+	assertEqual(t, len(expected), tree.Len())
+	for i, e := range expected {
+		assertEqual(t, e, tree.Get(i))
+	}
+
+	actual := tree.ToNativeSlice()
+	assertEqual(t, len(expected), len(actual))
+	for i, e := range expected {
+		assertEqual(t, e, actual[i])
+	}
+}
+
+type sumMeasurer struct{}
+
+func (sumMeasurer) Zero() int            { return 0 }
+func (sumMeasurer) Measure(item int) int { return item }
+func (sumMeasurer) Combine(a, b int) int { return a + b }
+
+func TestPropertiesOfNewFingerTree(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("NewFingerSeq %d", l), func(t *testing.T) {
+			assertFingerTreeContents(t, inputSlice(0, l), NewFingerSeq(inputSlice(0, l)...))
+		})
+	}
+}
+
+func TestFingerTreePushAndPop(t *testing.T) {
+	tree := NewFingerTree[int, int](sumMeasurer{})
+	for i := 0; i < 1000; i++ {
+		tree = tree.PushBack(i).PushFront(-i - 1)
+	}
+
+	assertEqual(t, 2000, tree.Len())
+	assertEqual(t, -1000, tree.Measure())
+	for i := 0; i < 1000; i++ {
+		var front, back int
+		front, tree = tree.PopFront()
+		back, tree = tree.PopBack()
+		assertEqual(t, -1000+i, front)
+		assertEqual(t, 999-i, back)
+		assertEqual(t, -1000+i+1, tree.Measure())
+	}
+
+	assertEqual(t, 0, tree.Len())
+	assertEqual(t, 0, tree.Measure())
+}
+
+func TestFingerTreePopIsPersistent(t *testing.T) {
+	tree := NewFingerSeq(inputSlice(0, 100)...)
+	for i := 0; i < 100; i++ {
+		_, rest := tree.PopFront()
+		assertFingerTreeContents(t, inputSlice(i+1, 99-i), rest)
+		tree = rest.PushFront(i)
+		_, tree = tree.PopFront()
+	}
+
+	assertEqual(t, 0, tree.Len())
+}
+
+func TestFingerTreePeek(t *testing.T) {
+	tree := NewFingerSeq[int]()
+	_, ok := tree.PeekFront()
+	assertEqualBool(t, false, ok)
+	_, ok = tree.PeekBack()
+	assertEqualBool(t, false, ok)
+
+	tree = tree.PushBack(1)
+	front, _ := tree.PeekFront()
+	back, _ := tree.PeekBack()
+	assertEqual(t, 1, front)
+	assertEqual(t, 1, back)
+
+	tree = NewFingerSeq(inputSlice(0, 50)...)
+	front, _ = tree.PeekFront()
+	back, _ = tree.PeekBack()
+	assertEqual(t, 0, front)
+	assertEqual(t, 49, back)
+}
+
+func TestFingerTreeConcat(t *testing.T) {
+	sizes := []int{0, 1, 2, 5, 9, 32, 100, 1000}
+	for _, l1 := range sizes {
+		for _, l2 := range sizes {
+			t.Run(fmt.Sprintf("Concat %d %d", l1, l2), func(t *testing.T) {
+				t1 := NewFingerTree(sumMeasurer{}, inputSlice(0, l1)...)
+				t2 := NewFingerTree(sumMeasurer{}, inputSlice(l1, l2)...)
+				result := t1.Concat(t2)
+				assertFingerTreeContents(t, inputSlice(0, l1+l2), result)
+				assertEqual(t, (l1+l2)*(l1+l2-1)/2, result.Measure())
+				assertFingerTreeContents(t, inputSlice(0, l1), t1)
+				assertFingerTreeContents(t, inputSlice(l1, l2), t2)
+			})
+		}
+	}
+}
+
+func TestFingerTreeRepeatedConcatOfSmallTrees(t *testing.T) {
+	tree := NewFingerSeq[int]()
+	for i := 0; i < 3000; i += 3 {
+		tree = tree.Concat(NewFingerSeq(inputSlice(i, 3)...))
+	}
+
+	assertFingerTreeContents(t, inputSlice(0, 3000), tree)
+}
+
+func TestFingerTreeSplitAt(t *testing.T) {
+	for _, l := range []int{0, 1, 4, 9, 33, 1025} {
+		tree := NewFingerSeq(inputSlice(0, l)...)
+		for i := 0; i <= l; i += 1 + l/17 {
+			t.Run(fmt.Sprintf("SplitAt %d at %d", l, i), func(t *testing.T) {
+				left, right := tree.SplitAt(i)
+				assertFingerTreeContents(t, inputSlice(0, i), left)
+				assertFingerTreeContents(t, inputSlice(i, l-i), right)
+				assertFingerTreeContents(t, inputSlice(0, l), left.Concat(right))
+				assertFingerTreeContents(t, inputSlice(0, l), tree)
+			})
+		}
+	}
+}
+
+func TestFingerTreeSplitAtEveryPosition(t *testing.T) {
+	tree := NewFingerSeq[int]()
+	for i := 0; i < 200; i++ {
+		tree = tree.PushFront(199 - i)
+	}
+
+	for i := 0; i <= 200; i++ {
+		left, right := tree.SplitAt(i)
+		assertFingerTreeContents(t, inputSlice(0, i), left)
+		assertFingerTreeContents(t, inputSlice(i, 200-i), right)
+	}
+}
+
+func TestFingerTreeSplitByMeasure(t *testing.T) {
+	// Elements are all 1 so the sum of a prefix is its length
+	tree := NewFingerTree[int, int](sumMeasurer{})
+	for i := 0; i < 500; i++ {
+		tree = tree.PushBack(1)
+	}
+
+	for _, n := range []int{1, 2, 100, 499, 500} {
+		left, right := tree.Split(func(sum int) bool { return sum >= n })
+		assertEqual(t, n-1, left.Len())
+		assertEqual(t, n-1, left.Measure())
+		assertEqual(t, 500-n+1, right.Len())
+	}
+
+	left, right := tree.Split(func(sum int) bool { return sum > 500 })
+	assertEqual(t, 500, left.Len())
+	assertEqual(t, 0, right.Len())
+}
+
+func TestFingerTreeRangeStops(t *testing.T) {
+	tree := NewFingerSeq(inputSlice(0, 100)...)
+	count := 0
+	tree.Range(func(item int) bool {
+		count++
+		return item < 50
+	})
+
+	assertEqual(t, 51, count)
+}
+
+func TestFingerTreeOutOfBounds(t *testing.T) {
+	tree := NewFingerSeq(inputSlice(0, 10)...)
+	t.Run("Get", func(t *testing.T) {
+		defer assertPanic(t, "Index out of bounds")
+		tree.Get(10)
+	})
+
+	t.Run("SplitAt", func(t *testing.T) {
+		defer assertPanic(t, "Slice bounds out of range")
+		tree.SplitAt(11)
+	})
+
+	t.Run("PopFront", func(t *testing.T) {
+		defer assertPanic(t, "Pop from empty finger tree")
+		NewFingerSeq[int]().PopFront()
+	})
+
+	t.Run("PopBack", func(t *testing.T) {
+		defer assertPanic(t, "Pop from empty finger tree")
+		NewFingerSeq[int]().PopBack()
+	})
+}
@@ -0,0 +1,236 @@
+package peds
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+)
+
+// MapVector returns a new vector containing the result of calling f with each element of v,
+// in order.
+func MapVector[T, U any](v *Vector[T], f func(T) U) *Vector[U] {
+	var b leafBuilder[U]
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, item := range v.sliceFor(i) {
+			b.add(f(item))
+		}
+	}
+
+	return b.vector()
+}
+
+// Filter returns a new vector containing the elements of v for which pred returns true,
+// in order.
+func Filter[T any](v *Vector[T], pred func(T) bool) *Vector[T] {
+	var b leafBuilder[T]
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, item := range v.sliceFor(i) {
+			if pred(item) {
+				b.add(item)
+			}
+		}
+	}
+
+	return b.vector()
+}
+
+// Reduce combines the elements of v, in order, into a single value by repeatedly calling f
+// with the accumulated value, starting with init, and the next element.
+func Reduce[T, A any](v *Vector[T], init A, f func(A, T) A) A {
+	acc := init
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, item := range v.sliceFor(i) {
+			acc = f(acc, item)
+		}
+	}
+
+	return acc
+}
+
+// IndexOf returns the index of the first occurrence of item in v, or -1 if not present.
+func IndexOf[T comparable](v *Vector[T], item T) int {
+	for i := uint(0); i < v.len; i += nodeSize {
+		for j, candidate := range v.sliceFor(i) {
+			if candidate == item {
+				return int(i) + j
+			}
+		}
+	}
+
+	return -1
+}
+
+// Contains reports whether item is present in v.
+func Contains[T comparable](v *Vector[T], item T) bool {
+	return IndexOf(v, item) >= 0
+}
+
+// ContainsValue reports whether any key in m maps to value.
+func ContainsValue[K, V comparable](m *Map[K, V], value V) bool {
+	_, ok := m.FindKey(func(_ K, v V) bool { return v == value })
+	return ok
+}
+
+// Sort returns a new vector containing the elements of v in ascending order.
+func Sort[T cmp.Ordered](v *Vector[T]) *Vector[T] {
+	items := v.ToNativeSlice()
+	slices.Sort(items)
+	return NewVector(items...)
+}
+
+// IsSorted reports whether the elements of v are in ascending order.
+func IsSorted[T cmp.Ordered](v *Vector[T]) bool {
+	return v.IsSortedFunc(cmp.Less[T])
+}
+
+// Chunk splits v into consecutive vectors of n elements each. The last chunk contains the
+// remaining elements if the length of v is not a multiple of n. Chunks share leaf nodes with
+// v when n is a multiple of the node size.
+func Chunk[T any](v *Vector[T], n int) *Vector[*Vector[T]] {
+	if n <= 0 {
+		panic(fmt.Sprintf("Invalid chunk size %d (size must be positive)", n))
+	}
+
+	var b leafBuilder[*Vector[T]]
+	for i := uint(0); i < v.len; i += uint(n) {
+		b.add(NewVector[T]().appendRange(v, i, uintMin(i+uint(n), v.len)))
+	}
+
+	return b.vector()
+}
+
+// Windows returns all overlapping windows of n consecutive elements in v, in order. The
+// windows are slices referring to v so creating them does not copy any elements. The result
+// is empty if v has fewer than n elements.
+func Windows[T any](v *Vector[T], n int) *Vector[*VectorSlice[T]] {
+	if n <= 0 {
+		panic(fmt.Sprintf("Invalid window size %d (size must be positive)", n))
+	}
+
+	var b leafBuilder[*VectorSlice[T]]
+	for i := 0; i+n <= v.Len(); i++ {
+		b.add(v.Slice(i, i+n))
+	}
+
+	return b.vector()
+}
+
+// Pair holds two values of possibly different types.
+type Pair[A, B any] struct {
+	First  A
+	Second B
+}
+
+// Zip returns a vector of pairs where the i:th pair holds the i:th elements of a and b. The
+// result has the length of the shorter of a and b.
+func Zip[A, B any](a *Vector[A], b *Vector[B]) *Vector[Pair[A, B]] {
+	var builder leafBuilder[Pair[A, B]]
+	length := uintMin(a.len, b.len)
+	for i := uint(0); i < length; i += nodeSize {
+		// Leaves start at the same indexes in all vectors so they can be walked in lockstep
+		leafA, leafB := a.sliceFor(i), b.sliceFor(i)
+		for j := 0; j < len(leafA) && j < len(leafB) && i+uint(j) < length; j++ {
+			builder.add(Pair[A, B]{First: leafA[j], Second: leafB[j]})
+		}
+	}
+
+	return builder.vector()
+}
+
+// Unzip is the inverse of Zip, it returns one vector with the first values and one with the
+// second values of the pairs in v.
+func Unzip[A, B any](v *Vector[Pair[A, B]]) (*Vector[A], *Vector[B]) {
+	var a leafBuilder[A]
+	var b leafBuilder[B]
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, p := range v.sliceFor(i) {
+			a.add(p.First)
+			b.add(p.Second)
+		}
+	}
+
+	return a.vector(), b.vector()
+}
+
+// Flatten returns a new vector containing the elements of all vectors in v, in order. Full
+// leaf nodes of the inner vectors are shared with the result when they line up with its
+// leaves.
+func Flatten[T any](v *Vector[*Vector[T]]) *Vector[T] {
+	result := NewVector[T]()
+	v.Range(func(inner *Vector[T]) bool {
+		result = result.appendRange(inner, 0, inner.len)
+		return true
+	})
+
+	return result
+}
+
+// FlatMap returns a new vector containing the elements of the vectors returned by calling f
+// with each element of v, in order.
+func FlatMap[T, U any](v *Vector[T], f func(T) *Vector[U]) *Vector[U] {
+	result := NewVector[U]()
+	v.Range(func(item T) bool {
+		inner := f(item)
+		result = result.appendRange(inner, 0, inner.len)
+		return true
+	})
+
+	return result
+}
+
+// GroupBy returns a map from each key returned by calling key with the elements of v to a
+// vector holding the elements with that key, in the order they appear in v.
+func GroupBy[T any, K comparable](v *Vector[T], key func(T) K) *Map[K, *Vector[T]] {
+	groups := make(map[K]*leafBuilder[T])
+	v.Range(func(item T) bool {
+		k := key(item)
+		group, ok := groups[k]
+		if !ok {
+			group = &leafBuilder[T]{}
+			groups[k] = group
+		}
+
+		group.add(item)
+		return true
+	})
+
+	b := NewMapBuilder[K, *Vector[T]]()
+	for k, group := range groups {
+		b.Set(k, group.vector())
+	}
+
+	return b.Freeze()
+}
+
+// Frequencies returns a map from each distinct element of v to the number of times it occurs
+// in v.
+func Frequencies[T comparable](v *Vector[T]) *Map[T, int] {
+	b := NewMapBuilder[T, int]()
+	v.Range(func(item T) bool {
+		count, _ := b.Load(item)
+		b.Set(item, count+1)
+		return true
+	})
+
+	return b.Freeze()
+}
+
+// Dedup returns a new vector where each run of consecutive equal elements in v is replaced
+// by a single element. Applied to a sorted vector it removes all duplicates.
+func Dedup[T comparable](v *Vector[T]) *Vector[T] {
+	return v.DedupFunc(func(a, b T) bool { return a == b })
+}
+
+// Distinct returns a new vector containing the elements of v with all duplicates removed.
+// The first occurrence of each element is kept, in order.
+func Distinct[T comparable](v *Vector[T]) *Vector[T] {
+	seen := make(map[T]struct{})
+	return Filter(v, func(item T) bool {
+		if _, ok := seen[item]; ok {
+			return false
+		}
+
+		seen[item] = struct{}{}
+		return true
+	})
+}
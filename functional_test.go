@@ -0,0 +1,255 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestMapVector(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("MapVector %d", l), func(t *testing.T) {
+			result := MapVector(NewVector(inputSlice(0, l)...), func(i int) string { return fmt.Sprint(i * 2) })
+			assertEqual(t, l, result.Len())
+			for i := 0; i < l; i++ {
+				assertEqualString(t, fmt.Sprint(i*2), result.Get(i))
+			}
+
+			// The result can be appended to like any other vector
+			result = result.Append("x")
+			assertEqualString(t, "x", result.Get(l))
+		})
+	}
+}
+
+func TestFilterVector(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Filter %d", l), func(t *testing.T) {
+			result := Filter(NewVector(inputSlice(0, l)...), func(i int) bool { return i%3 == 0 })
+			assertEqual(t, (l+2)/3, result.Len())
+			for i := 0; i < result.Len(); i++ {
+				assertEqual(t, i*3, result.Get(i))
+			}
+		})
+	}
+}
+
+func TestReduce(t *testing.T) {
+	for _, l := range testSizes {
+		sum := Reduce(NewVector(inputSlice(0, l)...), 0, func(acc, i int) int { return acc + i })
+		assertEqual(t, l*(l-1)/2, sum)
+	}
+
+	assertEqualString(t, "abc", Reduce(NewVector("a", "b", "c"), "", func(acc, s string) string { return acc + s }))
+}
+
+func TestIndexOfAndContains(t *testing.T) {
+	vec := NewVector(inputSlice(0, 1000)...).Append(5)
+	assertEqual(t, 0, IndexOf(vec, 0))
+	assertEqual(t, 5, IndexOf(vec, 5))
+	assertEqual(t, 999, IndexOf(vec, 999))
+	assertEqual(t, -1, IndexOf(vec, 1000))
+	assertEqualBool(t, true, Contains(vec, 500))
+	assertEqualBool(t, false, Contains(vec, -1))
+	assertEqualBool(t, false, Contains(NewVector[string](), ""))
+}
+
+func TestContainsValue(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 1).Store("b", 2)
+	assertEqualBool(t, true, ContainsValue(m, 2))
+	assertEqualBool(t, false, ContainsValue(m, 3))
+	assertEqualBool(t, false, ContainsValue(m.Delete("b"), 2))
+	assertEqualBool(t, false, ContainsValue(NewMap[string, int](), 0))
+}
+
+func TestSort(t *testing.T) {
+	input := make([]int, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		input = append(input, (i*7919)%1000)
+	}
+
+	vec := NewVector(input...)
+	sorted := Sort(vec)
+	for i := 0; i < 1000; i++ {
+		assertEqual(t, i, sorted.Get(i))
+	}
+
+	// The original is unchanged
+	assertEqual(t, input[1], vec.Get(1))
+	assertEqual(t, 0, Sort(NewVector[int]()).Len())
+}
+
+func TestIsSorted(t *testing.T) {
+	for _, size := range testSizes {
+		v := NewVector(inputSlice(0, size)...)
+		assertEqualBool(t, true, IsSorted(v))
+		if size > 1 {
+			assertEqualBool(t, false, IsSorted(v.Set(size-1, -1)))
+			assertEqualBool(t, false, IsSorted(v.Set(0, size)))
+		}
+	}
+
+	assertEqualBool(t, true, IsSorted(NewVector(1, 1, 2)))
+}
+
+func TestIsSortedFunc(t *testing.T) {
+	v := NewVector(inputSlice(0, 100)...)
+	descending := func(a, b int) bool { return a > b }
+	assertEqualBool(t, false, v.IsSortedFunc(descending))
+	assertEqualBool(t, true, v.SortFunc(descending).IsSortedFunc(descending))
+}
+
+func TestSortFunc(t *testing.T) {
+	type pair struct{ key, value int }
+	vec := NewVector(pair{2, 0}, pair{1, 1}, pair{2, 2}, pair{1, 3})
+	sorted := vec.SortFunc(func(a, b pair) bool { return a.key < b.key })
+	expected := []int{1, 3, 0, 2}
+	for i, e := range expected {
+		assertEqual(t, e, sorted.Get(i).value)
+	}
+}
+
+func TestChunk(t *testing.T) {
+	l := 32*32 + 70
+	vec := NewVector(inputSlice(0, l)...)
+	for _, n := range []int{1, 7, 32, 64, l, l + 1} {
+		t.Run(fmt.Sprintf("Chunk %d", n), func(t *testing.T) {
+			chunks := Chunk(vec, n)
+			assertEqual(t, (l+n-1)/n, chunks.Len())
+			joined := NewVector[int]()
+			chunks.Range(func(chunk *Vector[int]) bool {
+				assertEqualBool(t, true, chunk.Len() == n || chunk == chunks.Get(chunks.Len()-1))
+				joined = joined.Concat(chunk)
+				return true
+			})
+
+			assertEqualBool(t, true, VectorEqual(vec, joined))
+		})
+	}
+
+	assertEqual(t, 0, Chunk(NewVector[int](), 3).Len())
+}
+
+func TestWindows(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100)...)
+	windows := Windows(vec, 3)
+	assertEqual(t, 98, windows.Len())
+	windows.Range(func(w *VectorSlice[int]) bool {
+		assertEqual(t, 3, w.Len())
+		assertEqual(t, w.Get(0)+2, w.Get(2))
+		return true
+	})
+
+	assertEqual(t, 97, windows.Get(97).Get(0))
+	assertEqual(t, 0, Windows(NewVector(1, 2), 3).Len())
+}
+
+func TestChunkInvalidSize(t *testing.T) {
+	defer assertPanic(t, "Invalid chunk size")
+	Chunk(NewVector(1, 2, 3), 0)
+}
+
+func TestZipAndUnzip(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Zip %d", l), func(t *testing.T) {
+			a := NewVector(inputSlice(0, l)...)
+			b := MapVector(NewVector(inputSlice(0, l+5)...), func(i int) string { return fmt.Sprint(i) })
+			zipped := Zip(a, b)
+			assertEqual(t, l, zipped.Len())
+			zipped.All()(func(i int, p Pair[int, string]) bool {
+				assertEqual(t, i, p.First)
+				assertEqualString(t, fmt.Sprint(i), p.Second)
+				return true
+			})
+
+			first, second := Unzip(zipped)
+			assertEqualBool(t, true, VectorEqual(a, first))
+			assertEqualBool(t, true, VectorEqual(b.Take(l), second))
+		})
+	}
+}
+
+func TestFlatten(t *testing.T) {
+	l := 32*32 + 70
+	vec := NewVector(inputSlice(0, l)...)
+	for _, n := range []int{1, 7, 32, 100} {
+		t.Run(fmt.Sprintf("Flatten %d", n), func(t *testing.T) {
+			assertEqualBool(t, true, VectorEqual(vec, Flatten(Chunk(vec, n))))
+		})
+	}
+
+	assertEqual(t, 0, Flatten(NewVector[*Vector[int]]()).Len())
+	assertEqual(t, 0, Flatten(NewVector(NewVector[int](), NewVector[int]())).Len())
+}
+
+func TestFlatMap(t *testing.T) {
+	result := FlatMap(NewVector(inputSlice(0, 100)...), func(i int) *Vector[int] {
+		return NewVector(inputSlice(0, i%3)...)
+	})
+
+	// Each group of three inputs produce 0, 1 and 2 elements, the last input produce none
+	assertEqual(t, 33*3, result.Len())
+	assertEqual(t, 0, result.Get(0))
+	assertEqual(t, 1, result.Get(2))
+}
+
+func TestGroupBy(t *testing.T) {
+	groups := GroupBy(NewVector(inputSlice(0, 1000)...), func(i int) int { return i % 7 })
+	assertEqual(t, 7, groups.Len())
+	groups.Range(func(key int, group *Vector[int]) bool {
+		group.All()(func(i, item int) bool {
+			assertEqual(t, key+7*i, item)
+			return true
+		})
+
+		return true
+	})
+
+	three, _ := groups.Load(3)
+	assertEqual(t, 143, three.Len())
+	assertEqual(t, 0, GroupBy(NewVector[int](), func(i int) int { return i }).Len())
+}
+
+func TestFrequencies(t *testing.T) {
+	freq := Frequencies(NewVector("a", "b", "a", "c", "a", "b"))
+	assertEqual(t, 3, freq.Len())
+	for key, expected := range map[string]int{"a": 3, "b": 2, "c": 1} {
+		count, ok := freq.Load(key)
+		assertEqualBool(t, true, ok)
+		assertEqual(t, expected, count)
+	}
+
+	assertEqual(t, 0, Frequencies(NewVector[int]()).Len())
+}
+
+func TestDedup(t *testing.T) {
+	result := Dedup(NewVector(1, 1, 2, 3, 3, 3, 1, 4, 4))
+	assertEqualBool(t, true, VectorEqual(NewVector(1, 2, 3, 1, 4), result))
+	assertEqual(t, 0, Dedup(NewVector[int]()).Len())
+
+	l := 32*32 + 70
+	items := inputSlice(0, l)
+	for i := range items {
+		items[i] /= 3
+	}
+
+	assertEqual(t, (l+2)/3, Dedup(NewVector(items...)).Len())
+}
+
+func TestDedupFunc(t *testing.T) {
+	sameParity := func(a, b int) bool { return a%2 == b%2 }
+	result := NewVector(1, 3, 5, 2, 4, 7, 6).DedupFunc(sameParity)
+	assertEqualBool(t, true, VectorEqual(NewVector(1, 2, 7, 6), result))
+}
+
+func TestDistinct(t *testing.T) {
+	result := Distinct(NewVector(3, 1, 3, 2, 1, 4, 2))
+	assertEqualBool(t, true, VectorEqual(NewVector(3, 1, 2, 4), result))
+	assertEqual(t, 0, Distinct(NewVector[string]()).Len())
+
+	items := inputSlice(0, 1000)
+	for i := range items {
+		items[i] %= 100
+	}
+
+	assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, 100)...), Distinct(NewVector(items...))))
+}
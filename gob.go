@@ -0,0 +1,131 @@
+package peds
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+func gobEncode(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func gobDecode(data []byte, value any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(value)
+}
+
+// GobEncode encodes the elements of v as a gob slice.
+func (v *Vector[T]) GobEncode() ([]byte, error) {
+	return gobEncode(v.ToNativeSlice())
+}
+
+// GobDecode replaces the contents of v with the elements decoded from data.
+func (v *Vector[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*v = *NewVector(items...)
+	return nil
+}
+
+// GobEncode encodes the elements of s as a gob slice.
+func (s *VectorSlice[T]) GobEncode() ([]byte, error) {
+	return gobEncode(s.AppendToSlice(nil))
+}
+
+// GobDecode replaces the contents of s with the elements decoded from data.
+func (s *VectorSlice[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*s = *NewVectorSlice(items...)
+	return nil
+}
+
+// GobEncode encodes the items of m as a gob map.
+func (m *Map[K, V]) GobEncode() ([]byte, error) {
+	return gobEncode(m.ToNativeMap())
+}
+
+// GobDecode replaces the contents of m with the items decoded from data.
+func (m *Map[K, V]) GobDecode(data []byte) error {
+	var items map[K]V
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*m = *NewMapFromNativeMap(items)
+	return nil
+}
+
+// GobEncode encodes the elements of v as a gob slice.
+func (v *RRBVector[T]) GobEncode() ([]byte, error) {
+	return gobEncode(v.ToNativeSlice())
+}
+
+// GobDecode replaces the contents of v with the elements decoded from data.
+func (v *RRBVector[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*v = *NewRRBVector(items...)
+	return nil
+}
+
+// GobEncode encodes the elements of d, from front to back, as a gob slice.
+func (d *Deque[T]) GobEncode() ([]byte, error) {
+	return gobEncode(rangeToNativeSlice(d.Len(), d.Range))
+}
+
+// GobDecode replaces the contents of d with the elements decoded from data.
+func (d *Deque[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*d = *NewDeque(items...)
+	return nil
+}
+
+// GobEncode encodes the elements of q, in dequeue order, as a gob slice.
+func (q *Queue[T]) GobEncode() ([]byte, error) {
+	return gobEncode(rangeToNativeSlice(q.Len(), q.Range))
+}
+
+// GobDecode replaces the contents of q with the elements decoded from data.
+func (q *Queue[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*q = *NewQueue(items...)
+	return nil
+}
+
+// GobEncode encodes the elements of s, from the bottom of the stack to the top, as a gob slice.
+func (s *Stack[T]) GobEncode() ([]byte, error) {
+	return gobEncode(s.vector.ToNativeSlice())
+}
+
+// GobDecode replaces the contents of s with the elements decoded from data.
+func (s *Stack[T]) GobDecode(data []byte) error {
+	var items []T
+	if err := gobDecode(data, &items); err != nil {
+		return err
+	}
+
+	*s = *NewStack(items...)
+	return nil
+}
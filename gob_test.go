@@ -0,0 +1,89 @@
+package peds
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+)
+
+func gobRoundTrip[T any](t *testing.T, in T, out *T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(in); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := gob.NewDecoder(&buf).Decode(out); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVectorGobRoundTrip(t *testing.T) {
+	for _, l := range []int{0, 1, 33, 1000} {
+		var v *Vector[int]
+		gobRoundTrip(t, NewVector(inputSlice(0, l)...), &v)
+		assertEqual(t, l, v.Len())
+		for i := 0; i < l; i++ {
+			assertEqual(t, i, v.Get(i))
+		}
+	}
+}
+
+func TestVectorSliceGobRoundTrip(t *testing.T) {
+	var s *VectorSlice[int]
+	gobRoundTrip(t, NewVector(inputSlice(0, 100)...).Slice(10, 20), &s)
+	assertEqual(t, 10, s.Len())
+	assertEqual(t, 10, s.Get(0))
+	assertEqual(t, 19, s.Get(9))
+}
+
+func TestMapGobRoundTrip(t *testing.T) {
+	var m *Map[string, int]
+	gobRoundTrip(t, NewMapFromNativeMap(map[string]int{"a": 1, "b": 2}), &m)
+	assertEqual(t, 2, m.Len())
+	v, _ := m.Load("a")
+	assertEqual(t, 1, v)
+}
+
+func TestRRBVectorGobRoundTrip(t *testing.T) {
+	var v *RRBVector[int]
+	gobRoundTrip(t, NewRRBVector(inputSlice(0, 100)...).Insert(0, -1), &v)
+	assertEqual(t, 101, v.Len())
+	assertEqual(t, -1, v.Get(0))
+	assertEqual(t, 99, v.Get(100))
+}
+
+func TestDequeQueueAndStackGobRoundTrip(t *testing.T) {
+	var d *Deque[int]
+	gobRoundTrip(t, NewDeque(2, 3).PushFront(1), &d)
+	front, _ := d.PeekFront()
+	back, _ := d.PeekBack()
+	assertEqual(t, 1, front)
+	assertEqual(t, 3, back)
+
+	var q *Queue[int]
+	gobRoundTrip(t, NewQueue(1, 2, 3), &q)
+	item, _ := q.Peek()
+	assertEqual(t, 1, item)
+	assertEqual(t, 3, q.Len())
+
+	var s *Stack[int]
+	gobRoundTrip(t, NewStack(1, 2, 3), &s)
+	item, _ = s.Peek()
+	assertEqual(t, 3, item)
+	assertEqual(t, 3, s.Len())
+}
+
+func TestGobNestedInStruct(t *testing.T) {
+	type cacheEntry struct {
+		Name  string
+		Items *Vector[string]
+		Index *Map[string, int]
+	}
+
+	var out cacheEntry
+	gobRoundTrip(t, cacheEntry{Name: "x", Items: NewVector("a", "b"), Index: NewMap(MapItem[string, int]{Key: "a", Value: 0})}, &out)
+	assertEqualString(t, "x", out.Name)
+	assertEqualString(t, "b", out.Items.Get(1))
+	v, _ := out.Index.Load("a")
+	assertEqual(t, 0, v)
+}
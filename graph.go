@@ -0,0 +1,221 @@
+package peds
+
+import "iter"
+
+// ///////////
+// / Graph ///
+// ///////////
+
+// A Graph is a persistent/immutable directed graph with nodes of type N and edges labeled
+// with values of type E. There is at most one edge from one node to another. Adjacency is
+// stored in both directions so that removing nodes does not require scanning the whole
+// graph. Since graphs are immutable, algorithms can traverse a snapshot while other
+// goroutines derive new versions.
+type Graph[N comparable, E any] struct {
+	out   *Map[N, *Map[N, E]]
+	in    *Map[N, *Map[N, struct{}]]
+	edges int
+}
+
+// NewGraph returns a new graph containing the nodes in nodes and no edges.
+func NewGraph[N comparable, E any](nodes ...N) *Graph[N, E] {
+	g := &Graph[N, E]{out: NewMap[N, *Map[N, E]](), in: NewMap[N, *Map[N, struct{}]]()}
+	for _, n := range nodes {
+		g = g.AddNode(n)
+	}
+
+	return g
+}
+
+// NodeLen returns the number of nodes in g.
+func (g *Graph[N, E]) NodeLen() int {
+	return g.out.Len()
+}
+
+// EdgeLen returns the number of edges in g.
+func (g *Graph[N, E]) EdgeLen() int {
+	return g.edges
+}
+
+// HasNode reports whether n is a node in g.
+func (g *Graph[N, E]) HasNode(n N) bool {
+	_, ok := g.out.Load(n)
+	return ok
+}
+
+// AddNode returns a new graph with n added. The graph itself is returned if n is already
+// present.
+func (g *Graph[N, E]) AddNode(n N) *Graph[N, E] {
+	if g.HasNode(n) {
+		return g
+	}
+
+	return &Graph[N, E]{out: g.out.Store(n, NewMap[N, E]()), in: g.in.Store(n, NewMap[N, struct{}]()), edges: g.edges}
+}
+
+// RemoveNode returns a new graph with n and all edges to and from it removed.
+func (g *Graph[N, E]) RemoveNode(n N) *Graph[N, E] {
+	successors, ok := g.out.Load(n)
+	if !ok {
+		return g
+	}
+
+	predecessors, _ := g.in.Load(n)
+	result := g
+	successors.Range(func(to N, _ E) bool {
+		result = result.RemoveEdge(n, to)
+		return true
+	})
+
+	predecessors.Range(func(from N, _ struct{}) bool {
+		result = result.RemoveEdge(from, n)
+		return true
+	})
+
+	return &Graph[N, E]{out: result.out.Delete(n), in: result.in.Delete(n), edges: result.edges}
+}
+
+// AddEdge returns a new graph with an edge from one node to another labeled with e. The nodes
+// are added if not already present, an existing edge between them is replaced.
+func (g *Graph[N, E]) AddEdge(from, to N, e E) *Graph[N, E] {
+	g = g.AddNode(from).AddNode(to)
+	edges := g.edges
+	if _, ok := g.Edge(from, to); !ok {
+		edges++
+	}
+
+	successors, _ := g.out.Load(from)
+	predecessors, _ := g.in.Load(to)
+	return &Graph[N, E]{
+		out:   g.out.Store(from, successors.Store(to, e)),
+		in:    g.in.Store(to, predecessors.Store(from, struct{}{})),
+		edges: edges,
+	}
+}
+
+// RemoveEdge returns a new graph without the edge from one node to another. The graph itself
+// is returned if there is no such edge.
+func (g *Graph[N, E]) RemoveEdge(from, to N) *Graph[N, E] {
+	if _, ok := g.Edge(from, to); !ok {
+		return g
+	}
+
+	successors, _ := g.out.Load(from)
+	predecessors, _ := g.in.Load(to)
+	return &Graph[N, E]{
+		out:   g.out.Store(from, successors.Delete(to)),
+		in:    g.in.Store(to, predecessors.Delete(from)),
+		edges: g.edges - 1,
+	}
+}
+
+// Edge returns the label of the edge from one node to another and whether there is such an
+// edge.
+func (g *Graph[N, E]) Edge(from, to N) (e E, ok bool) {
+	successors, ok := g.out.Load(from)
+	if !ok {
+		return e, false
+	}
+
+	return successors.Load(to)
+}
+
+// Neighbors returns a map from each node that n has an edge to, to the label of that edge.
+// The map is empty if n is not present.
+func (g *Graph[N, E]) Neighbors(n N) *Map[N, E] {
+	if successors, ok := g.out.Load(n); ok {
+		return successors
+	}
+
+	return NewMap[N, E]()
+}
+
+// Predecessors calls f repeatedly passing it each node that has an edge to n until either
+// all such nodes have been visited or f returns false.
+func (g *Graph[N, E]) Predecessors(n N, f func(N) bool) {
+	if predecessors, ok := g.in.Load(n); ok {
+		predecessors.Range(func(from N, _ struct{}) bool { return f(from) })
+	}
+}
+
+// RangeNodes calls f repeatedly passing it each node in g until either all nodes have been
+// visited or f returns false.
+func (g *Graph[N, E]) RangeNodes(f func(N) bool) {
+	g.out.Range(func(n N, _ *Map[N, E]) bool { return f(n) })
+}
+
+// RangeEdges calls f repeatedly passing it each edge in g until either all edges have been
+// visited or f returns false.
+func (g *Graph[N, E]) RangeEdges(f func(from, to N, e E) bool) {
+	g.out.Range(func(from N, successors *Map[N, E]) bool {
+		cont := true
+		successors.Range(func(to N, e E) bool {
+			cont = f(from, to, e)
+			return cont
+		})
+
+		return cont
+	})
+}
+
+// BFS returns an iterator over the nodes reachable from start, including start, in breadth
+// first order. The sequence is empty if start is not present.
+func (g *Graph[N, E]) BFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+
+		visited := map[N]struct{}{start: {}}
+		queue := []N{start}
+		for len(queue) > 0 {
+			n := queue[0]
+			queue = queue[1:]
+			if !yield(n) {
+				return
+			}
+
+			g.Neighbors(n).Range(func(to N, _ E) bool {
+				if _, ok := visited[to]; !ok {
+					visited[to] = struct{}{}
+					queue = append(queue, to)
+				}
+
+				return true
+			})
+		}
+	}
+}
+
+// DFS returns an iterator over the nodes reachable from start, including start, in depth
+// first pre-order. The sequence is empty if start is not present.
+func (g *Graph[N, E]) DFS(start N) iter.Seq[N] {
+	return func(yield func(N) bool) {
+		if !g.HasNode(start) {
+			return
+		}
+
+		visited := make(map[N]struct{})
+		stack := []N{start}
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if _, ok := visited[n]; ok {
+				continue
+			}
+
+			visited[n] = struct{}{}
+			if !yield(n) {
+				return
+			}
+
+			g.Neighbors(n).Range(func(to N, _ E) bool {
+				if _, ok := visited[to]; !ok {
+					stack = append(stack, to)
+				}
+
+				return true
+			})
+		}
+	}
+}
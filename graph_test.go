@@ -0,0 +1,94 @@
+package peds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGraphNodesAndEdges(t *testing.T) {
+	empty := NewGraph[string, int]()
+	g := empty.AddEdge("a", "b", 1).AddEdge("b", "c", 2).AddEdge("a", "c", 3).AddNode("d")
+	assertEqual(t, 4, g.NodeLen())
+	assertEqual(t, 3, g.EdgeLen())
+	assertEqual(t, 0, empty.NodeLen())
+
+	e, ok := g.Edge("a", "c")
+	assertEqual(t, 3, e)
+	assertEqualBool(t, true, ok)
+	_, ok = g.Edge("c", "a")
+	assertEqualBool(t, false, ok)
+
+	g2 := g.AddEdge("a", "c", 4)
+	assertEqual(t, 3, g2.EdgeLen())
+	e, _ = g2.Edge("a", "c")
+	assertEqual(t, 4, e)
+
+	assertEqual(t, 2, g.Neighbors("a").Len())
+	assertEqual(t, 0, g.Neighbors("x").Len())
+
+	r := g.RemoveEdge("a", "b")
+	assertEqual(t, 2, r.EdgeLen())
+	assertEqual(t, 3, g.EdgeLen())
+	assertEqualBool(t, true, r.RemoveEdge("a", "b") == r)
+	assertEqualBool(t, true, g.AddNode("a") == g)
+}
+
+func TestGraphRemoveNode(t *testing.T) {
+	g := NewGraph[int, string]().AddEdge(1, 2, "").AddEdge(2, 3, "").AddEdge(3, 2, "").AddEdge(1, 3, "")
+	r := g.RemoveNode(2)
+	assertEqual(t, 2, r.NodeLen())
+	assertEqual(t, 1, r.EdgeLen())
+	assertEqualBool(t, false, r.HasNode(2))
+
+	predecessors := 0
+	r.Predecessors(3, func(int) bool {
+		predecessors++
+		return true
+	})
+
+	assertEqual(t, 1, predecessors)
+	assertEqualBool(t, true, r.RemoveNode(2) == r)
+
+	edges := 0
+	g.RangeEdges(func(from, to int, _ string) bool {
+		edges++
+		return true
+	})
+
+	assertEqual(t, 4, edges)
+
+	nodes := 0
+	g.RangeNodes(func(int) bool {
+		nodes++
+		return true
+	})
+
+	assertEqual(t, 3, nodes)
+}
+
+func TestGraphTraversal(t *testing.T) {
+	// 1 -> 2 -> 4, 1 -> 3 -> 4 -> 1, 5 unreachable
+	g := NewGraph[int, struct{}](5).
+		AddEdge(1, 2, struct{}{}).
+		AddEdge(1, 3, struct{}{}).
+		AddEdge(2, 4, struct{}{}).
+		AddEdge(3, 4, struct{}{}).
+		AddEdge(4, 1, struct{}{})
+
+	bfs := slices.Collect(g.BFS(1))
+	assertEqual(t, 4, len(bfs))
+	assertEqual(t, 1, bfs[0])
+	assertEqual(t, 4, bfs[3])
+
+	dfs := slices.Collect(g.DFS(1))
+	assertEqual(t, 4, len(dfs))
+	assertEqual(t, 1, dfs[0])
+	assertEqualBool(t, false, slices.Contains(dfs, 5))
+
+	assertEqual(t, 0, len(slices.Collect(g.BFS(7))))
+	assertEqual(t, 0, len(slices.Collect(g.DFS(7))))
+	for n := range g.BFS(1) {
+		assertEqual(t, 1, n)
+		break
+	}
+}
@@ -0,0 +1,195 @@
+package peds
+
+import "fmt"
+
+// //////////
+// / Grid ///
+// //////////
+
+// A Grid is a persistent/immutable two dimensional array of elements, stored as a vector of
+// row vectors. Updating an element copies only the paths to it in the outer and inner vector,
+// making it cheap to keep snapshots of, for example, game boards or spreadsheet models.
+type Grid[T any] struct {
+	rows  *Vector[*Vector[T]]
+	width int
+}
+
+// NewGrid returns a new grid of the given size with all elements set to the zero value.
+func NewGrid[T any](width, height int) *Grid[T] {
+	return NewGridFunc(width, height, func(x, y int) T {
+		var zero T
+		return zero
+	})
+}
+
+// NewGridFunc returns a new grid of the given size with the element at (x, y) set to f(x, y).
+func NewGridFunc[T any](width, height int, f func(x, y int) T) *Grid[T] {
+	assertGridSizeOk(width, height)
+	var rows leafBuilder[*Vector[T]]
+	for y := 0; y < height; y++ {
+		var row leafBuilder[T]
+		for x := 0; x < width; x++ {
+			row.add(f(x, y))
+		}
+
+		rows.add(row.vector())
+	}
+
+	return &Grid[T]{rows: rows.vector(), width: width}
+}
+
+// NewGridFromRows returns a new grid containing the elements in rows, rows[y][x] becoming the
+// element at (x, y). All rows must have the same length.
+func NewGridFromRows[T any](rows [][]T) *Grid[T] {
+	width := 0
+	if len(rows) > 0 {
+		width = len(rows[0])
+	}
+
+	var b leafBuilder[*Vector[T]]
+	for y, row := range rows {
+		if len(row) != width {
+			panic(fmt.Sprintf("Grid rows must have equal length, row %d has length %d, expected %d", y, len(row), width))
+		}
+
+		b.add(NewVector(row...))
+	}
+
+	return &Grid[T]{rows: b.vector(), width: width}
+}
+
+func assertGridSizeOk(width, height int) {
+	if width < 0 || height < 0 {
+		panic(fmt.Sprintf("Invalid grid size %dx%d (size must be non-negative)", width, height))
+	}
+}
+
+func (g *Grid[T]) assertPointOk(x, y int) {
+	if x < 0 || x >= g.width || y < 0 || y >= g.Height() {
+		panic(ErrPointOutOfBounds{X: x, Y: y, Width: g.width, Height: g.Height()})
+	}
+}
+
+func (g *Grid[T]) assertRowOk(y int) {
+	if y < 0 || y >= g.Height() {
+		panic(ErrIndexOutOfBounds{Index: y, Len: g.Height(), Type: "Grid row"})
+	}
+}
+
+func (g *Grid[T]) assertColumnOk(x int) {
+	if x < 0 || x >= g.width {
+		panic(ErrIndexOutOfBounds{Index: x, Len: g.width, Type: "Grid column"})
+	}
+}
+
+// Width returns the number of columns in g.
+func (g *Grid[T]) Width() int {
+	return g.width
+}
+
+// Height returns the number of rows in g.
+func (g *Grid[T]) Height() int {
+	return g.rows.Len()
+}
+
+// Get returns the element at column x and row y.
+func (g *Grid[T]) Get(x, y int) T {
+	g.assertPointOk(x, y)
+	return g.rows.Get(y).Get(x)
+}
+
+// Set returns a new grid with the element at column x and row y set to item.
+func (g *Grid[T]) Set(x, y int, item T) *Grid[T] {
+	g.assertPointOk(x, y)
+	return &Grid[T]{rows: g.rows.Update(y, func(row *Vector[T]) *Vector[T] { return row.Set(x, item) }), width: g.width}
+}
+
+// Row returns row y of g. The vector shares all its elements with g.
+func (g *Grid[T]) Row(y int) *Vector[T] {
+	g.assertRowOk(y)
+	return g.rows.Get(y)
+}
+
+// Column returns a new vector containing column x of g.
+func (g *Grid[T]) Column(x int) *Vector[T] {
+	g.assertColumnOk(x)
+	return MapVector(g.rows, func(row *Vector[T]) T { return row.Get(x) })
+}
+
+// SetRow returns a new grid with row y replaced by row, which must have the width of g.
+func (g *Grid[T]) SetRow(y int, row *Vector[T]) *Grid[T] {
+	g.assertRowOk(y)
+	if row.Len() != g.width {
+		panic(fmt.Sprintf("Grid row length %d does not match grid width %d", row.Len(), g.width))
+	}
+
+	return &Grid[T]{rows: g.rows.Set(y, row), width: g.width}
+}
+
+// SubGrid returns a new grid containing the columns [x0,x1) of the rows [y0,y1) of g.
+func (g *Grid[T]) SubGrid(x0, y0, x1, y1 int) *Grid[T] {
+	assertSliceOk(x0, x1, g.width)
+	assertSliceOk(y0, y1, g.Height())
+	var b leafBuilder[*Vector[T]]
+	g.rows.Slice(y0, y1).Range(func(row *Vector[T]) bool {
+		if x0 == 0 && x1 == g.width {
+			b.add(row)
+		} else {
+			b.add(NewVector[T]().appendRange(row, uint(x0), uint(x1)))
+		}
+
+		return true
+	})
+
+	return &Grid[T]{rows: b.vector(), width: x1 - x0}
+}
+
+// Resize returns a new grid of the given size. Elements within both the old and the new size
+// are kept, new elements are set to the zero value.
+func (g *Grid[T]) Resize(width, height int) *Grid[T] {
+	assertGridSizeOk(width, height)
+	rows := g.rows.Take(height)
+	if width != g.width {
+		rows = MapVector(rows, func(row *Vector[T]) *Vector[T] {
+			if width < g.width {
+				return row.Take(width)
+			}
+
+			return row.Concat(NewVector(make([]T, width-g.width)...))
+		})
+	}
+
+	if height > rows.Len() {
+		empty := NewVector(make([]T, width)...)
+		for rows.Len() < height {
+			rows = rows.Append(empty)
+		}
+	}
+
+	return &Grid[T]{rows: rows, width: width}
+}
+
+// Range calls f repeatedly passing it the position and value of each element in g, row by
+// row, until either all elements have been visited or f returns false.
+func (g *Grid[T]) Range(f func(x, y int, item T) bool) {
+	g.rows.RangeIndexed(func(y int, row *Vector[T]) bool {
+		cont := true
+		row.RangeIndexed(func(x int, item T) bool {
+			cont = f(x, y, item)
+			return cont
+		})
+
+		return cont
+	})
+}
+
+// ToNativeSlice returns the rows of g as Go slices.
+func (g *Grid[T]) ToNativeSlice() [][]T {
+	result := make([][]T, 0, g.Height())
+	g.rows.Range(func(row *Vector[T]) bool {
+		result = append(result, row.ToNativeSlice())
+		return true
+	})
+
+	return result
+}
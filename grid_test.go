@@ -0,0 +1,109 @@
+package peds
+
+import (
+	"errors"
+	"testing"
+)
+
+func assertGridRows(t *testing.T, expected [][]int, g *Grid[int]) {
+	t.Helper()
+	assertEqual(t, len(expected), g.Height())
+	for y, row := range expected {
+		assertEqual(t, len(row), g.Width())
+		for x, item := range row {
+			assertEqual(t, item, g.Get(x, y))
+		}
+	}
+}
+
+func TestGridGetSet(t *testing.T) {
+	g := NewGridFunc(40, 3, func(x, y int) int { return y*100 + x })
+	assertEqual(t, 40, g.Width())
+	assertEqual(t, 3, g.Height())
+	assertEqual(t, 239, g.Get(39, 2))
+
+	g2 := g.Set(1, 1, -1)
+	assertEqual(t, -1, g2.Get(1, 1))
+	assertEqual(t, 101, g.Get(1, 1))
+	assertEqualBool(t, true, g.Row(0) == g2.Row(0))
+
+	col := g.Column(5)
+	assertEqual(t, 3, col.Len())
+	assertEqual(t, 205, col.Get(2))
+}
+
+func TestGridFromRowsAndSubGrid(t *testing.T) {
+	g := NewGridFromRows([][]int{{1, 2, 3}, {4, 5, 6}, {7, 8, 9}})
+	assertGridRows(t, [][]int{{5, 6}, {8, 9}}, g.SubGrid(1, 1, 3, 3))
+	assertGridRows(t, [][]int{{4, 5, 6}}, g.SubGrid(0, 1, 3, 2))
+	assertGridRows(t, [][]int{{1, 2, 3}, {0, 0, 0}, {7, 8, 9}}, g.SetRow(1, NewVector(0, 0, 0)))
+	assertEqual(t, 0, NewGridFromRows[int](nil).Height())
+
+	rows := g.ToNativeSlice()
+	assertEqual(t, 3, len(rows))
+	assertEqual(t, 8, rows[2][1])
+}
+
+func TestGridResize(t *testing.T) {
+	g := NewGridFromRows([][]int{{1, 2}, {3, 4}})
+	assertGridRows(t, [][]int{{1, 2, 0}, {3, 4, 0}, {0, 0, 0}}, g.Resize(3, 3))
+	assertGridRows(t, [][]int{{1}}, g.Resize(1, 1))
+	assertEqual(t, 0, g.Resize(0, 0).Height())
+	assertGridRows(t, [][]int{{0, 0}}, NewGrid[int](2, 1))
+}
+
+func TestGridRange(t *testing.T) {
+	g := NewGridFromRows([][]int{{1, 2}, {3, 4}})
+	sum := 0
+	g.Range(func(x, y, item int) bool {
+		sum += item * (x + 1) * (y + 1)
+		return true
+	})
+
+	assertEqual(t, 1+4+6+16, sum)
+
+	count := 0
+	g.Range(func(x, y, item int) bool {
+		count++
+		return item < 2
+	})
+
+	assertEqual(t, 2, count)
+}
+
+func TestGridOutOfBounds(t *testing.T) {
+	err := recoverError(func() { NewGrid[int](2, 3).Get(2, 0) })
+	var pointErr ErrPointOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &pointErr))
+	assertEqualBool(t, true, pointErr == ErrPointOutOfBounds{X: 2, Y: 0, Width: 2, Height: 3})
+	assertEqualString(t, "Point out of bounds, x=2, y=0, size=2x3", pointErr.Error())
+
+	defer assertPanic(t, "Point out of bounds")
+	NewGrid[int](2, 2).Set(0, -1, 1)
+}
+
+func TestGridZeroSized(t *testing.T) {
+	g := NewGrid[int](0, 3)
+	assertEqual(t, 0, g.Row(1).Len())
+	assertEqual(t, 0, g.SetRow(2, NewVector[int]()).Row(2).Len())
+
+	g = NewGrid[int](3, 0)
+	assertEqual(t, 0, g.Column(1).Len())
+	assertEqual(t, 0, len(g.ToNativeSlice()))
+}
+
+func TestGridRowColumnOutOfBounds(t *testing.T) {
+	err := recoverError(func() { NewGrid[int](0, 3).Row(3) })
+	var indexErr ErrIndexOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqualBool(t, true, indexErr == ErrIndexOutOfBounds{Index: 3, Len: 3, Type: "Grid row"})
+
+	err = recoverError(func() { NewGrid[int](2, 0).Column(-1) })
+	assertEqualBool(t, true, errors.As(err, &indexErr))
+	assertEqualBool(t, true, indexErr == ErrIndexOutOfBounds{Index: -1, Len: 2, Type: "Grid column"})
+}
+
+func TestGridUnevenRows(t *testing.T) {
+	defer assertPanic(t, "Grid rows must have equal length")
+	NewGridFromRows([][]int{{1}, {1, 2}})
+}
@@ -1,10 +1,40 @@
 package peds
 
-import "unsafe"
+import "hash/maphash"
 
-func nilinterhash(p unsafe.Pointer, h uintptr) uintptr { return 0 }
+// Seed used for all map hashing in this process. It is randomly chosen at startup so that
+// bucket positions can not be predicted, and hence not be forced to collide, by an adversary.
+var hashSeed = maphash.MakeSeed()
 
-// TODO: Try to avoid interfaces for hashing
-func genericHash(x interface{}) uint32 {
-	return uint32(nilinterhash(unsafe.Pointer(&x), 0))
+// genericHash hashes key without converting it to an interface and does not allocate.
+func genericHash[K comparable](key K) uint32 {
+	return uint32(maphash.Comparable(hashSeed, key))
+}
+
+// A Hasher computes hash values for keys of type K. Keys that are equal must produce the
+// same hash value.
+type Hasher[K any] interface {
+	Hash(key K) uint64
+}
+
+// HasherFunc is an adapter that allows an ordinary function to be used as a Hasher.
+type HasherFunc[K any] func(key K) uint64
+
+// Hash returns f(key).
+func (f HasherFunc[K]) Hash(key K) uint64 {
+	return f(key)
+}
+
+// hashKey hashes key using hasher, or the built in hash function if hasher is nil.
+func hashKey[K comparable](hasher Hasher[K], key K) uint32 {
+	if hasher == nil {
+		return genericHash(key)
+	}
+
+	return foldHash(hasher.Hash(key))
+}
+
+// foldHash folds a 64 bit hash from a Hasher into the 32 bits used to address buckets.
+func foldHash(h uint64) uint32 {
+	return uint32(h ^ h>>32)
 }
@@ -0,0 +1,201 @@
+package peds
+
+import (
+	"iter"
+	"math/bits"
+)
+
+// keyOps hashes and compares the keys of a hashTable. Map uses comparableKeys, which compares
+// keys using ==, and MapFunc uses the HashEqualer it was created with. The operations are
+// passed as a type parameter rather than an interface to avoid allocating on each update.
+type keyOps[K any] interface {
+	hash(key K) uint32
+	equal(a, b K) bool
+}
+
+// comparableKeys are the key operations of a Map, hasher is nil if the built in hash function
+// is used.
+type comparableKeys[K comparable] struct {
+	hasher Hasher[K]
+}
+
+func (k comparableKeys[K]) hash(key K) uint32 {
+	return hashKey(k.hasher, key)
+}
+
+func (k comparableKeys[K]) equal(a, b K) bool {
+	return a == b
+}
+
+// hashEqualerKeys are the key operations of a MapFunc.
+type hashEqualerKeys[K any] struct {
+	hashEqualer HashEqualer[K]
+}
+
+func (k hashEqualerKeys[K]) hash(key K) uint32 {
+	return foldHash(k.hashEqualer.Hash(key))
+}
+
+func (k hashEqualerKeys[K]) equal(a, b K) bool {
+	return k.hashEqualer.Equal(a, b)
+}
+
+// hashTable holds the items of a Map or a MapFunc in buckets addressed using linear hashing,
+// see bucketPos. The functions operating on it implement the growing and shrinking of the
+// bucket vector shared by both map types.
+type hashTable[K any, V any] struct {
+	buckets *Vector[privateItemBucket[K, V]]
+	len     int
+	options *MapOptions
+}
+
+// newHashTable returns a table holding items with buckets sized for itemCount items. If a key
+// occurs more than once the last item wins.
+func newHashTable[K, V any, O keyOps[K]](ops O, options *MapOptions, itemCount int, items iter.Seq[MapItem[K, V]]) hashTable[K, V] {
+	buckets := make([]privateItemBucket[K, V], int(float64(itemCount)/options.initial())+1)
+	length := 0
+	for item := range items {
+		pos := bucketPos(ops.hash(item.Key), len(buckets))
+		found := false
+		for ix, existing := range buckets[pos] {
+			if ops.equal(existing.Key, item.Key) {
+				buckets[pos][ix], found = item, true
+				break
+			}
+		}
+
+		if !found {
+			buckets[pos] = append(buckets[pos], item)
+			length++
+		}
+	}
+
+	return hashTable[K, V]{buckets: NewVector(buckets...), len: length, options: options}
+}
+
+// items returns an iterator over the items of t in bucket order.
+func (t hashTable[K, V]) items() iter.Seq[MapItem[K, V]] {
+	return func(yield func(MapItem[K, V]) bool) {
+		t.buckets.Range(func(bucket privateItemBucket[K, V]) bool {
+			for _, item := range bucket {
+				if !yield(item) {
+					return false
+				}
+			}
+
+			return true
+		})
+	}
+}
+
+// tableLoad returns the value identified by key. ok is set to true if key exists in t.
+func tableLoad[K, V any, O keyOps[K]](t hashTable[K, V], ops O, key K) (value V, ok bool) {
+	for _, item := range t.buckets.Get(bucketPos(ops.hash(key), t.buckets.Len())) {
+		if ops.equal(item.Key, key) {
+			return item.Value, true
+		}
+	}
+
+	return value, false
+}
+
+// tableWithSplitBucket returns a new table with one more bucket than t. Only the items of the
+// bucket being split are moved, which keeps the cost of growing the table by one step
+// proportional to the bucket size rather than the table size.
+func tableWithSplitBucket[K, V any, O keyOps[K]](t hashTable[K, V], ops O) hashTable[K, V] {
+	count := t.buckets.Len()
+	splitPos := count - 1<<(bits.Len(uint(count))-1)
+	var kept, moved privateItemBucket[K, V]
+	for _, item := range t.buckets.Get(splitPos) {
+		if bucketPos(ops.hash(item.Key), count+1) == splitPos {
+			kept = append(kept, item)
+		} else {
+			moved = append(moved, item)
+		}
+	}
+
+	return hashTable[K, V]{buckets: t.buckets.Set(splitPos, kept).Append(moved), len: t.len, options: t.options}
+}
+
+// withMergedBucket returns a new table with one bucket less than t, the inverse of
+// tableWithSplitBucket.
+func (t hashTable[K, V]) withMergedBucket() hashTable[K, V] {
+	count := t.buckets.Len() - 1
+	mergePos := count - 1<<(bits.Len(uint(count))-1)
+	last, buckets := t.buckets.Pop()
+	if len(last) > 0 {
+		bucket := buckets.Get(mergePos)
+		merged := make(privateItemBucket[K, V], 0, len(bucket)+len(last))
+		buckets = buckets.Set(mergePos, append(append(merged, bucket...), last...))
+	}
+
+	return hashTable[K, V]{buckets: buckets, len: t.len, options: t.options}
+}
+
+// tableCompacted returns t or, if t occupies excessive space, a new table with fewer buckets
+// containing the same items. A single bucket is merged if that is enough, as it is after
+// deleting one item, otherwise the table is rebuilt. changed reports whether a new table was
+// returned.
+func tableCompacted[K, V any, O keyOps[K]](t hashTable[K, V], ops O) (result hashTable[K, V], changed bool) {
+	count := t.buckets.Len()
+	if !t.options.underloaded(t.len, count) {
+		return t, false
+	}
+
+	if !t.options.underloaded(t.len, count-1) {
+		return t.withMergedBucket(), true
+	}
+
+	return newHashTable(ops, t.options, t.len, t.items()), true
+}
+
+// tableUpdate returns a new table where the value identified by key has been replaced by the
+// result of calling f with the current value, or the zero value and ok set to false if key
+// does not exist in t.
+func tableUpdate[K, V any, O keyOps[K]](t hashTable[K, V], ops O, key K, f func(value V, ok bool) V) hashTable[K, V] {
+	// Grow the buckets by one if the load factor is too high
+	if t.options.overloaded(t.len, t.buckets.Len()) {
+		t = tableWithSplitBucket(t, ops)
+	}
+
+	pos := bucketPos(ops.hash(key), t.buckets.Len())
+	bucket := t.buckets.Get(pos)
+	for ix, item := range bucket {
+		if ops.equal(item.Key, key) {
+			// Overwrite existing item
+			newBucket := make(privateItemBucket[K, V], len(bucket))
+			copy(newBucket, bucket)
+			newBucket[ix] = MapItem[K, V]{Key: key, Value: f(item.Value, true)}
+			return hashTable[K, V]{buckets: t.buckets.Set(pos, newBucket), len: t.len, options: t.options}
+		}
+	}
+
+	var zeroValue V
+	newBucket := make(privateItemBucket[K, V], len(bucket), len(bucket)+1)
+	copy(newBucket, bucket)
+	newBucket = append(newBucket, MapItem[K, V]{Key: key, Value: f(zeroValue, false)})
+	return hashTable[K, V]{buckets: t.buckets.Set(pos, newBucket), len: t.len + 1, options: t.options}
+}
+
+// tableLoadAndDelete returns the value identified by key together with a new, compacted,
+// table without that item. ok is set to true if key exists in t, if not t is returned as is.
+func tableLoadAndDelete[K, V any, O keyOps[K]](t hashTable[K, V], ops O, key K) (value V, ok bool, result hashTable[K, V]) {
+	pos := bucketPos(ops.hash(key), t.buckets.Len())
+	bucket := t.buckets.Get(pos)
+	for ix, item := range bucket {
+		if !ops.equal(item.Key, key) {
+			continue
+		}
+
+		var newBucket privateItemBucket[K, V]
+		if len(bucket) > 1 {
+			newBucket = make(privateItemBucket[K, V], 0, len(bucket)-1)
+			newBucket = append(append(newBucket, bucket[:ix]...), bucket[ix+1:]...)
+		}
+
+		result, _ = tableCompacted(hashTable[K, V]{buckets: t.buckets.Set(pos, newBucket), len: t.len - 1, options: t.options}, ops)
+		return item.Value, true, result
+	}
+
+	return value, false, t
+}
@@ -0,0 +1,91 @@
+package peds
+
+// /////////////
+// / History ///
+// /////////////
+
+// A History is a persistent/immutable record of successive versions of a value, typically one
+// of the persistent collections, with support for undo and redo. Versions of persistent
+// collections share most of their structure so keeping many of them is cheap.
+type History[T any] struct {
+	versions *Vector[T]
+
+	// Number of discarded versions at the start of versions. They are dropped in batches to
+	// keep recording amortized constant time when the limit has been reached.
+	offset  int
+	current int
+	limit   int
+}
+
+// NewHistory returns a new history with initial as its only, and current, version. At most
+// limit versions are retained, the oldest versions are discarded when more are recorded. A
+// limit of zero or less means that all versions are retained. Discarded versions are released
+// in batches, up to limit of them may remain referenced by h.
+func NewHistory[T any](initial T, limit int) *History[T] {
+	return &History[T]{versions: NewVector(initial), limit: limit}
+}
+
+// Len returns the number of versions retained in h.
+func (h *History[T]) Len() int {
+	return h.versions.Len() - h.offset
+}
+
+// Position returns the index of the current version of h.
+func (h *History[T]) Position() int {
+	return h.current
+}
+
+// Current returns the current version of h.
+func (h *History[T]) Current() T {
+	return h.versions.Get(h.offset + h.current)
+}
+
+// At returns the version at position i, zero being the oldest retained version.
+func (h *History[T]) At(i int) T {
+	return h.versions.Get(h.offset + i)
+}
+
+// Record returns a new history with value added as the current version. Versions that had
+// been undone in h are discarded and can no longer be redone. Recording is amortized constant
+// time, also when the oldest version has to be discarded.
+func (h *History[T]) Record(value T) *History[T] {
+	versions, offset := h.versions.Take(h.offset+h.current+1).Append(value), h.offset
+	if h.limit > 0 && versions.Len()-offset > h.limit {
+		offset = versions.Len() - h.limit
+		if offset >= h.limit {
+			versions, offset = versions.Drop(offset), 0
+		}
+	}
+
+	return &History[T]{versions: versions, offset: offset, current: versions.Len() - offset - 1, limit: h.limit}
+}
+
+// CanUndo reports whether there is a version before the current one.
+func (h *History[T]) CanUndo() bool {
+	return h.current > 0
+}
+
+// CanRedo reports whether there is a version after the current one.
+func (h *History[T]) CanRedo() bool {
+	return h.current < h.Len()-1
+}
+
+// Undo returns a new history where the version preceding the current one is current. h is
+// returned unchanged if there is no such version.
+func (h *History[T]) Undo() *History[T] {
+	if !h.CanUndo() {
+		return h
+	}
+
+	return &History[T]{versions: h.versions, offset: h.offset, current: h.current - 1, limit: h.limit}
+}
+
+// Redo returns a new history where the version following the current one is current, undoing
+// the effect of Undo. h is returned unchanged if there is no such version.
+func (h *History[T]) Redo() *History[T] {
+	if !h.CanRedo() {
+		return h
+	}
+
+	return &History[T]{versions: h.versions, offset: h.offset, current: h.current + 1, limit: h.limit}
+}
@@ -0,0 +1,73 @@
+package peds
+
+import "testing"
+
+func TestHistoryUndoRedo(t *testing.T) {
+	h := NewHistory(NewVector[int](), 0)
+	for i := 0; i < 5; i++ {
+		h = h.Record(h.Current().Append(i))
+	}
+
+	assertEqual(t, 6, h.Len())
+	assertEqual(t, 5, h.Current().Len())
+	assertEqualBool(t, false, h.CanRedo())
+
+	h = h.Undo().Undo()
+	assertEqual(t, 3, h.Current().Len())
+	assertEqual(t, 3, h.Position())
+	assertEqualBool(t, true, h.CanRedo())
+
+	redone := h.Redo()
+	assertEqual(t, 4, redone.Current().Len())
+
+	h = h.Record(h.Current().Append(-1))
+	assertEqual(t, 5, h.Len())
+	assertEqualBool(t, false, h.CanRedo())
+	assertEqual(t, -1, h.Current().Get(3))
+	assertEqual(t, 2, h.At(2).Len())
+
+	for h.CanUndo() {
+		h = h.Undo()
+	}
+
+	assertEqual(t, 0, h.Current().Len())
+	assertEqualBool(t, true, h == h.Undo())
+}
+
+func TestHistoryLimit(t *testing.T) {
+	h := NewHistory(0, 3)
+	for i := 1; i <= 10; i++ {
+		h = h.Record(i)
+	}
+
+	assertEqual(t, 3, h.Len())
+	assertEqual(t, 8, h.At(0))
+	assertEqual(t, 10, h.Current())
+	assertEqual(t, 9, h.Undo().Current())
+}
+
+func TestHistoryLimitBatchesDiscards(t *testing.T) {
+	h := NewHistory(0, 100)
+	expected, current := []int{0}, 0
+	for i := 1; i <= 1000; i++ {
+		h = h.Record(i)
+		expected, current = append(expected[:current+1], i), current+1
+		if len(expected) > 100 {
+			expected, current = expected[1:], current-1
+		}
+
+		if i%7 == 0 {
+			h, current = h.Undo().Undo().Redo(), current-1
+		}
+
+		if h.versions.Len() > 2*h.limit {
+			t.Fatalf("Backing vector has %d versions, expected at most %d", h.versions.Len(), 2*h.limit)
+		}
+
+		assertEqual(t, len(expected), h.Len())
+		assertEqual(t, current, h.Position())
+		assertEqual(t, expected[current], h.Current())
+		assertEqual(t, expected[0], h.At(0))
+		assertEqualBool(t, current < len(expected)-1, h.CanRedo())
+	}
+}
@@ -0,0 +1,161 @@
+package peds
+
+import "sort"
+
+// ///////////////////
+// / IndexedVector ///
+// ///////////////////
+
+// An IndexedVector is a persistent/immutable vector that maintains one or more indexes from
+// keys, extracted from the elements, to the positions of the elements having that key. The
+// indexes are kept consistent as the vector is modified which makes looking up elements by
+// key, for example by a field of a struct, O(1) rather than requiring a scan.
+type IndexedVector[T any, K comparable] struct {
+	items   *Vector[T]
+	keys    []func(T) K
+	indexes []*Map[K, *Vector[int]]
+}
+
+// NewIndexedVector returns a new IndexedVector containing items, with one index for each of
+// the key functions in keys. Indexes are identified by the position of their key function in
+// keys.
+func NewIndexedVector[T any, K comparable](keys []func(T) K, items ...T) *IndexedVector[T, K] {
+	return newIndexedVector(keys, NewVector(items...))
+}
+
+func newIndexedVector[T any, K comparable](keys []func(T) K, items *Vector[T]) *IndexedVector[T, K] {
+	indexes := make([]*Map[K, *Vector[int]], len(keys))
+	for ix, key := range keys {
+		positions := make(map[K][]int)
+		items.RangeIndexed(func(i int, item T) bool {
+			k := key(item)
+			positions[k] = append(positions[k], i)
+			return true
+		})
+
+		buckets := newPrivateItemBuckets[K, *Vector[int]](len(positions), nil, nil)
+		for k, p := range positions {
+			buckets.AddItem(MapItem[K, *Vector[int]]{Key: k, Value: NewVector(p...)})
+		}
+
+		indexes[ix] = buckets.toMap()
+	}
+
+	return &IndexedVector[T, K]{items: items, keys: keys, indexes: indexes}
+}
+
+// Len returns the length of iv.
+func (iv *IndexedVector[T, K]) Len() int {
+	return iv.items.Len()
+}
+
+// Get returns the element at position i.
+func (iv *IndexedVector[T, K]) Get(i int) T {
+	return iv.items.Get(i)
+}
+
+// Vector returns the elements of iv as a Vector.
+func (iv *IndexedVector[T, K]) Vector() *Vector[T] {
+	return iv.items
+}
+
+// Lookup returns the positions, in ascending order, of the elements for which the key function
+// of index returns key. The vector is empty if there are no such elements.
+func (iv *IndexedVector[T, K]) Lookup(index int, key K) *Vector[int] {
+	if positions, ok := iv.indexes[index].Load(key); ok {
+		return positions
+	}
+
+	return NewVector[int]()
+}
+
+// Find returns the first element for which the key function of index returns key. ok is set
+// to false if there is no such element.
+func (iv *IndexedVector[T, K]) Find(index int, key K) (item T, ok bool) {
+	if positions, ok := iv.indexes[index].Load(key); ok {
+		return iv.items.Get(positions.Get(0)), true
+	}
+
+	return item, false
+}
+
+// Append returns a new IndexedVector with item(s) appended to it.
+func (iv *IndexedVector[T, K]) Append(items ...T) *IndexedVector[T, K] {
+	indexes := make([]*Map[K, *Vector[int]], len(iv.indexes))
+	copy(indexes, iv.indexes)
+	for i, item := range items {
+		pos := iv.items.Len() + i
+		for ix, key := range iv.keys {
+			indexes[ix] = indexes[ix].Update(key(item), func(positions *Vector[int], ok bool) *Vector[int] {
+				if !ok {
+					return NewVector(pos)
+				}
+
+				return positions.Append(pos)
+			})
+		}
+	}
+
+	return &IndexedVector[T, K]{items: iv.items.Append(items...), keys: iv.keys, indexes: indexes}
+}
+
+// Set returns a new IndexedVector with the element at position i set to item.
+func (iv *IndexedVector[T, K]) Set(i int, item T) *IndexedVector[T, K] {
+	old := iv.items.Get(i)
+	indexes := make([]*Map[K, *Vector[int]], len(iv.indexes))
+	for ix, key := range iv.keys {
+		oldKey, newKey := key(old), key(item)
+		indexes[ix] = iv.indexes[ix]
+		if oldKey != newKey {
+			indexes[ix] = insertPosition(removePosition(indexes[ix], oldKey, i), newKey, i)
+		}
+	}
+
+	return &IndexedVector[T, K]{items: iv.items.Set(i, item), keys: iv.keys, indexes: indexes}
+}
+
+// Remove returns a new IndexedVector with the element at position i removed. Since the
+// positions of all following elements change this requires the indexes to be rebuilt, which
+// is O(n), unless i is the last position.
+func (iv *IndexedVector[T, K]) Remove(i int) *IndexedVector[T, K] {
+	items := iv.items.Remove(i)
+	if i != items.Len() {
+		return newIndexedVector(iv.keys, items)
+	}
+
+	indexes := make([]*Map[K, *Vector[int]], len(iv.indexes))
+	for ix, key := range iv.keys {
+		indexes[ix] = removePosition(iv.indexes[ix], key(iv.items.Get(i)), i)
+	}
+
+	return &IndexedVector[T, K]{items: items, keys: iv.keys, indexes: indexes}
+}
+
+// Range calls f repeatedly passing it each element in iv in order as argument until either
+// all elements have been visited or f returns false.
+func (iv *IndexedVector[T, K]) Range(f func(T) bool) {
+	iv.items.Range(f)
+}
+
+// removePosition returns index without pos among the positions for key.
+func removePosition[K comparable](index *Map[K, *Vector[int]], key K, pos int) *Map[K, *Vector[int]] {
+	positions, _ := index.Load(key)
+	if positions.Len() == 1 {
+		return index.Delete(key)
+	}
+
+	i := sort.Search(positions.Len(), func(i int) bool { return positions.Get(i) >= pos })
+	return index.Store(key, positions.Remove(i))
+}
+
+// insertPosition returns index with pos added, in order, among the positions for key.
+func insertPosition[K comparable](index *Map[K, *Vector[int]], key K, pos int) *Map[K, *Vector[int]] {
+	return index.Update(key, func(positions *Vector[int], ok bool) *Vector[int] {
+		if !ok {
+			return NewVector(pos)
+		}
+
+		i := sort.Search(positions.Len(), func(i int) bool { return positions.Get(i) >= pos })
+		return positions.Take(i).Append(pos).Concat(positions.Drop(i))
+	})
+}
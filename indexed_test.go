@@ -0,0 +1,77 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+type indexedPerson struct {
+	name string
+	city string
+}
+
+func indexedPersonKeys() []func(indexedPerson) string {
+	return []func(indexedPerson) string{
+		func(p indexedPerson) string { return p.name },
+		func(p indexedPerson) string { return p.city },
+	}
+}
+
+func assertIndexConsistent(t *testing.T, iv *IndexedVector[indexedPerson, string]) {
+	t.Helper()
+	expected := newIndexedVector(iv.keys, iv.items)
+	for ix := range iv.keys {
+		equal := iv.indexes[ix].EqualFunc(expected.indexes[ix], func(a, b *Vector[int]) bool {
+			return fmt.Sprint(a.ToNativeSlice()) == fmt.Sprint(b.ToNativeSlice())
+		})
+
+		if !equal {
+			t.Errorf("Index %d inconsistent with items %v", ix, iv.items.ToNativeSlice())
+		}
+	}
+}
+
+func TestIndexedVectorLookup(t *testing.T) {
+	iv := NewIndexedVector(indexedPersonKeys(),
+		indexedPerson{name: "alice", city: "oslo"},
+		indexedPerson{name: "bob", city: "lund"},
+		indexedPerson{name: "carol", city: "oslo"})
+
+	assertEqual(t, 3, iv.Len())
+	p, ok := iv.Find(0, "bob")
+	assertEqualBool(t, true, ok)
+	assertEqualString(t, "lund", p.city)
+	_, ok = iv.Find(0, "dave")
+	assertEqualBool(t, false, ok)
+
+	positions := iv.Lookup(1, "oslo")
+	assertEqual(t, 2, positions.Len())
+	assertEqual(t, 0, positions.Get(0))
+	assertEqual(t, 2, positions.Get(1))
+	assertEqual(t, 0, iv.Lookup(1, "rome").Len())
+}
+
+func TestIndexedVectorModifications(t *testing.T) {
+	iv := NewIndexedVector(indexedPersonKeys())
+	for i := 0; i < 100; i++ {
+		iv = iv.Append(indexedPerson{name: fmt.Sprintf("p%d", i), city: fmt.Sprintf("c%d", i%7)})
+	}
+
+	assertIndexConsistent(t, iv)
+
+	iv2 := iv.Set(10, indexedPerson{name: "p10", city: "c0"}).Set(50, indexedPerson{name: "x", city: "c1"})
+	assertIndexConsistent(t, iv2)
+	assertEqual(t, 50, iv2.Lookup(0, "x").Get(0))
+	assertEqual(t, 0, iv2.Lookup(0, "p50").Len())
+	assertEqual(t, 10, iv.Lookup(0, "p10").Get(0))
+
+	iv3 := iv2.Remove(99).Remove(0).Remove(40)
+	assertEqual(t, 97, iv3.Len())
+	assertIndexConsistent(t, iv3)
+	assertEqual(t, 0, iv3.Lookup(0, "p99").Len())
+	assertEqual(t, 0, iv3.Lookup(0, "p1").Get(0))
+
+	// The original is unaffected
+	assertIndexConsistent(t, iv)
+	assertEqual(t, 100, iv.Len())
+}
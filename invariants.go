@@ -0,0 +1,130 @@
+package peds
+
+import "fmt"
+
+// CheckInvariants validates the internal structure of v and returns an error describing the
+// first violation found, or nil if the structure is consistent. It walks the whole tree and
+// is intended for debugging and fuzz tests, a non-nil error indicates a bug in this package
+// or memory corruption.
+func (v *Vector[T]) CheckInvariants() error {
+	tailOffset := v.tailOffset()
+	if uint(len(v.tail)) != v.len-tailOffset {
+		return fmt.Errorf("peds: invalid vector: tail length %d, expected %d for length %d", len(v.tail), v.len-tailOffset, v.len)
+	}
+
+	if v.tailClaim != nil {
+		if claim := v.tailClaim.Load(); claim < uint32(len(v.tail)) || claim > nodeSize {
+			return fmt.Errorf("peds: invalid vector: tail claim %d, tail length %d", claim, len(v.tail))
+		}
+	}
+
+	if v.shift < shiftSize || v.shift%shiftSize != 0 {
+		return fmt.Errorf("peds: invalid vector: shift %d", v.shift)
+	}
+
+	if tailOffset == 0 {
+		if v.root != nil {
+			return fmt.Errorf("peds: invalid vector: root present without elements in the tree")
+		}
+
+		return nil
+	}
+
+	if v.root == nil {
+		return fmt.Errorf("peds: invalid vector: root missing for %d elements in the tree", tailOffset)
+	}
+
+	if tailOffset > 1<<(v.shift+shiftSize) {
+		return fmt.Errorf("peds: invalid vector: %d elements in the tree exceed the capacity for shift %d", tailOffset, v.shift)
+	}
+
+	return checkNode(v.root, v.shift, tailOffset)
+}
+
+// checkNode checks that n, at level, is a complete node holding count elements in its leaves,
+// where count is a non-zero multiple of the node size.
+func checkNode[T any](n *node[T], level uint, count uint) error {
+	if level == 0 {
+		if n.items == nil || n.children != nil {
+			return fmt.Errorf("peds: invalid vector: malformed leaf node")
+		}
+
+		return nil
+	}
+
+	if n.children == nil || n.items != nil {
+		return fmt.Errorf("peds: invalid vector: malformed branch node at level %d", level)
+	}
+
+	childCapacity := uint(1) << level
+	expected := int((count + childCapacity - 1) / childCapacity)
+	if childCount := n.childCount(); childCount != expected {
+		return fmt.Errorf("peds: invalid vector: branch node at level %d has %d children, expected %d", level, childCount, expected)
+	}
+
+	for i, child := range n.children[expected:] {
+		if child != nil {
+			return fmt.Errorf("peds: invalid vector: unexpected child %d at level %d", expected+i, level)
+		}
+	}
+
+	for i := 0; i < expected; i++ {
+		childCount := min(childCapacity, count-uint(i)*childCapacity)
+		if err := checkNode(n.children[i], level-shiftSize, childCount); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CheckInvariants validates the internal structure of s, including that of its backing vector,
+// see Vector.CheckInvariants.
+func (s *VectorSlice[T]) CheckInvariants() error {
+	if s.start < 0 || s.start > s.stop || s.stop > s.vector.Len() {
+		return fmt.Errorf("peds: invalid vector slice: bounds [%d,%d) with backing vector length %d", s.start, s.stop, s.vector.Len())
+	}
+
+	return s.vector.CheckInvariants()
+}
+
+// CheckInvariants validates the internal structure of m, including that of its bucket vector,
+// and that all items are stored in the bucket given by the hash of their key, see
+// Vector.CheckInvariants.
+func (m *Map[K, V]) CheckInvariants() error {
+	if err := m.backingVector.CheckInvariants(); err != nil {
+		return err
+	}
+
+	bucketCount := m.backingVector.Len()
+	if bucketCount == 0 {
+		return fmt.Errorf("peds: invalid map: no buckets")
+	}
+
+	length := 0
+	var err error
+	m.backingVector.RangeIndexed(func(pos int, bucket privateItemBucket[K, V]) bool {
+		for i, item := range bucket {
+			if expected := bucketPos(hashKey(m.hasher, item.Key), bucketCount); expected != pos {
+				err = fmt.Errorf("peds: invalid map: key %v in bucket %d, expected bucket %d", item.Key, pos, expected)
+				return false
+			}
+
+			for _, other := range bucket[:i] {
+				if other.Key == item.Key {
+					err = fmt.Errorf("peds: invalid map: duplicate key %v in bucket %d", item.Key, pos)
+					return false
+				}
+			}
+		}
+
+		length += len(bucket)
+		return true
+	})
+
+	if err == nil && length != m.len {
+		err = fmt.Errorf("peds: invalid map: length %d, found %d items", m.len, length)
+	}
+
+	return err
+}
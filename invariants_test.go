@@ -0,0 +1,137 @@
+package peds
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func assertInvariants(t *testing.T, c interface{ CheckInvariants() error }) {
+	t.Helper()
+	if err := c.CheckInvariants(); err != nil {
+		t.Fatalf("Unexpected invariant violation: %v", err)
+	}
+}
+
+func assertInvariantViolation(t *testing.T, c interface{ CheckInvariants() error }, substr string) {
+	t.Helper()
+	err := c.CheckInvariants()
+	if err == nil || !strings.Contains(err.Error(), substr) {
+		t.Fatalf("Expected invariant violation containing %q, got: %v", substr, err)
+	}
+}
+
+func TestVectorCheckInvariants(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("CheckInvariants %d", l), func(t *testing.T) {
+			v := NewVector(inputSlice(0, l)...)
+			assertInvariants(t, v)
+			assertInvariants(t, AdoptSlice(inputSlice(0, l)))
+			assertInvariants(t, v.Append(inputSlice(0, 40)...))
+			assertInvariants(t, v.Slice(l/3, l/2))
+			assertInvariants(t, v.Splice(l/3, l/2, inputSlice(0, 50)...))
+			assertInvariants(t, NewVectorRepeat(0, l))
+			for _, n := range []int{0, 1, 32, 33, l / 2, l} {
+				if n <= l {
+					assertInvariants(t, v.Shrink(n))
+					left, right := v.Split(n)
+					assertInvariants(t, left)
+					assertInvariants(t, right)
+				}
+			}
+
+			tr := v.Transient()
+			tr.Append(inputSlice(0, 1100)...)
+			assertInvariants(t, tr.Persistent())
+		})
+	}
+}
+
+func TestVectorCheckInvariantsRandomOperations(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	v := NewVector[int]()
+	for i := 0; i < 2000; i++ {
+		switch op := r.Intn(6); {
+		case op == 0 && v.Len() > 0:
+			v = v.Shrink(r.Intn(v.Len()))
+		case op == 1 && v.Len() > 0:
+			start := r.Intn(v.Len())
+			v = v.RemoveRange(start, start+r.Intn(v.Len()-start))
+		case op == 2 && v.Len() > 0:
+			v = v.Set(r.Intn(v.Len()), i)
+		case op == 3:
+			v = v.Concat(NewVector(inputSlice(0, r.Intn(100))...))
+		default:
+			v = v.Append(inputSlice(0, r.Intn(70))...)
+		}
+
+		assertInvariants(t, v)
+	}
+}
+
+func TestVectorCheckInvariantsDetectsCorruption(t *testing.T) {
+	v := NewVector(inputSlice(0, 100)...)
+	v.len = 99
+	assertInvariantViolation(t, v, "tail length")
+
+	v = NewVector(inputSlice(0, 2000)...)
+	v.root.children[0].children[3] = nil
+	assertInvariantViolation(t, v, "has 3 children, expected 32")
+
+	v = NewVector(inputSlice(0, 100)...)
+	v.root.children[0] = v.root
+	assertInvariantViolation(t, v, "malformed")
+
+	v = NewVector(inputSlice(0, 10)...)
+	v.shift = 3
+	assertInvariantViolation(t, v, "shift 3")
+
+	s := NewVector(inputSlice(0, 10)...).Slice(2, 5)
+	s.stop = 11
+	assertInvariantViolation(t, s, "bounds [2,11)")
+}
+
+func TestMapCheckInvariants(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	m := NewMap[int, int]()
+	for i := 0; i < 5000; i++ {
+		key := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			m = m.Delete(key)
+		} else {
+			m = m.Store(key, i)
+		}
+
+		if i%100 == 0 {
+			assertInvariants(t, m)
+		}
+	}
+
+	assertInvariants(t, m)
+	assertInvariants(t, m.Filter(func(key, _ int) bool { return key%2 == 0 }))
+	assertInvariants(t, NewMapWithCapacity[int, int](100))
+}
+
+func TestMapCheckInvariantsDetectsCorruption(t *testing.T) {
+	m := NewMap[int, int]().Store(1, 1).Store(2, 2)
+	m.len = 3
+	assertInvariantViolation(t, m, "length 3, found 2 items")
+
+	m = NewMap[int, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Store(i, i)
+	}
+
+	pos := m.pos(7)
+	other := (pos + 1) % m.backingVector.Len()
+	m.backingVector = m.backingVector.Set(other, append(privateItemBucket[int, int]{{Key: 7, Value: 7}}, m.backingVector.Get(other)...))
+	m.len++
+	assertInvariantViolation(t, m, fmt.Sprintf("key 7 in bucket %d, expected bucket %d", other, pos))
+
+	m = NewMap[int, int]().Store(1, 1)
+	pos = m.pos(1)
+	m.backingVector = m.backingVector.Set(pos, privateItemBucket[int, int]{{Key: 1, Value: 1}, {Key: 1, Value: 2}})
+	m.len++
+	assertInvariantViolation(t, m, "duplicate key 1")
+}
@@ -0,0 +1,201 @@
+package peds
+
+// positionIterator keeps track of the position of a bidirectional iterator over length
+// elements. The position is -1 before the first element and length after the last.
+type positionIterator struct {
+	pos    int
+	length int
+}
+
+func (it *positionIterator) seek(i int) bool {
+	it.pos = min(max(i, -1), it.length)
+	return it.valid()
+}
+
+func (it *positionIterator) valid() bool {
+	return it.pos >= 0 && it.pos < it.length
+}
+
+// A VectorIterator is a bidirectional iterator over the elements of a Vector that can be
+// moved forwards, backwards or to an arbitrary position. It is initially positioned before
+// the first element. Moving to a neighbouring element is O(1) in the common case. A
+// VectorIterator is also used to iterate over the elements of a VectorSlice.
+type VectorIterator[T any] struct {
+	positionIterator
+	cursor *VectorCursor[T]
+	offset int
+}
+
+// Iterator returns a new iterator over v positioned before the first element.
+func (v *Vector[T]) Iterator() *VectorIterator[T] {
+	return &VectorIterator[T]{positionIterator: positionIterator{pos: -1, length: v.Len()}, cursor: v.Cursor()}
+}
+
+// Iterator returns a new iterator over s positioned before the first element. Positions are
+// relative to the start of s.
+func (s *VectorSlice[T]) Iterator() *VectorIterator[T] {
+	return &VectorIterator[T]{positionIterator: positionIterator{pos: -1, length: s.Len()}, cursor: s.vector.Cursor(), offset: s.start}
+}
+
+// Next moves it to the next element and reports whether there is such an element.
+func (it *VectorIterator[T]) Next() bool {
+	return it.seek(it.pos + 1)
+}
+
+// Prev moves it to the previous element and reports whether there is such an element.
+func (it *VectorIterator[T]) Prev() bool {
+	return it.seek(it.pos - 1)
+}
+
+// Seek moves it to the element at position i and reports whether there is such an element.
+// Positions out of bounds leave the iterator before the first or after the last element.
+func (it *VectorIterator[T]) Seek(i int) bool {
+	return it.seek(i)
+}
+
+// Valid reports whether it is positioned at an element.
+func (it *VectorIterator[T]) Valid() bool {
+	return it.valid()
+}
+
+// Index returns the position of it.
+func (it *VectorIterator[T]) Index() int {
+	return it.pos
+}
+
+// Value returns the element that it is positioned at. It panics if it is not Valid.
+func (it *VectorIterator[T]) Value() T {
+	if !it.valid() {
+		panic(ErrIndexOutOfBounds{Index: it.pos, Len: it.length, Type: "Vector"})
+	}
+
+	return it.cursor.Get(it.offset + it.pos)
+}
+
+// A SortedMapIterator is a bidirectional iterator over the items of a SortedMap, in ascending
+// key order, that can be moved forwards, backwards or to an arbitrary position or key. It is
+// initially positioned before the first item. Each move is O(log n).
+type SortedMapIterator[K, V any] struct {
+	positionIterator
+	m     SortedMap[K, V]
+	key   K
+	value V
+}
+
+// NewSortedMapIterator returns a new iterator over m positioned before the first item.
+func NewSortedMapIterator[K, V any](m SortedMap[K, V]) *SortedMapIterator[K, V] {
+	return &SortedMapIterator[K, V]{positionIterator: positionIterator{pos: -1, length: m.Len()}, m: m}
+}
+
+func (it *SortedMapIterator[K, V]) load() bool {
+	if !it.valid() {
+		var key K
+		var value V
+		it.key, it.value = key, value
+		return false
+	}
+
+	it.key, it.value = it.m.Select(it.pos)
+	return true
+}
+
+// Next moves it to the next item and reports whether there is such an item.
+func (it *SortedMapIterator[K, V]) Next() bool {
+	it.seek(it.pos + 1)
+	return it.load()
+}
+
+// Prev moves it to the previous item and reports whether there is such an item.
+func (it *SortedMapIterator[K, V]) Prev() bool {
+	it.seek(it.pos - 1)
+	return it.load()
+}
+
+// Seek moves it to the item at position i, in ascending key order, and reports whether there
+// is such an item.
+func (it *SortedMapIterator[K, V]) Seek(i int) bool {
+	it.seek(i)
+	return it.load()
+}
+
+// SeekKey moves it to the item with the smallest key greater than or equal to key and reports
+// whether there is such an item.
+func (it *SortedMapIterator[K, V]) SeekKey(key K) bool {
+	it.seek(it.m.Rank(key))
+	return it.load()
+}
+
+// Valid reports whether it is positioned at an item.
+func (it *SortedMapIterator[K, V]) Valid() bool {
+	return it.valid()
+}
+
+// Index returns the position of it in ascending key order.
+func (it *SortedMapIterator[K, V]) Index() int {
+	return it.pos
+}
+
+// Key returns the key of the item that it is positioned at, or the zero value if it is not
+// Valid.
+func (it *SortedMapIterator[K, V]) Key() K {
+	return it.key
+}
+
+// Value returns the value of the item that it is positioned at, or the zero value if it is
+// not Valid.
+func (it *SortedMapIterator[K, V]) Value() V {
+	return it.value
+}
+
+// A MapIterator is a forward iterator over the items of a Map, in the same order as Range. It
+// is initially positioned before the first item.
+type MapIterator[K comparable, V any] struct {
+	buckets *VectorIterator[privateItemBucket[K, V]]
+	bucket  privateItemBucket[K, V]
+	pos     int
+}
+
+// Iterator returns a new iterator over m positioned before the first item.
+func (m *Map[K, V]) Iterator() *MapIterator[K, V] {
+	return &MapIterator[K, V]{buckets: m.backingVector.Iterator()}
+}
+
+// Next moves it to the next item and reports whether there is such an item.
+func (it *MapIterator[K, V]) Next() bool {
+	it.pos++
+	for it.pos >= len(it.bucket) {
+		if !it.buckets.Next() {
+			it.bucket, it.pos = nil, 0
+			return false
+		}
+
+		it.bucket, it.pos = it.buckets.Value(), 0
+	}
+
+	return true
+}
+
+// Valid reports whether it is positioned at an item.
+func (it *MapIterator[K, V]) Valid() bool {
+	return it.pos < len(it.bucket)
+}
+
+// Key returns the key of the item that it is positioned at, or the zero value if it is not
+// Valid.
+func (it *MapIterator[K, V]) Key() (key K) {
+	if !it.Valid() {
+		return key
+	}
+
+	return it.bucket[it.pos].Key
+}
+
+// Value returns the value of the item that it is positioned at, or the zero value if it is
+// not Valid.
+func (it *MapIterator[K, V]) Value() (value V) {
+	if !it.Valid() {
+		return value
+	}
+
+	return it.bucket[it.pos].Value
+}
@@ -0,0 +1,153 @@
+package peds
+
+import "testing"
+
+func TestVectorIterator(t *testing.T) {
+	v := NewVector(inputSlice(0, 100)...)
+	it := v.Iterator()
+	assertEqualBool(t, false, it.Valid())
+	assertEqualBool(t, false, it.Prev())
+
+	count := 0
+	for it.Next() {
+		assertEqual(t, count, it.Value())
+		assertEqual(t, count, it.Index())
+		count++
+	}
+
+	assertEqual(t, 100, count)
+	assertEqualBool(t, false, it.Next())
+	assertEqualBool(t, true, it.Prev())
+	assertEqual(t, 99, it.Value())
+
+	assertEqualBool(t, true, it.Seek(40))
+	assertEqualBool(t, true, it.Prev())
+	assertEqual(t, 39, it.Value())
+	assertEqualBool(t, false, it.Seek(1000))
+	assertEqualBool(t, true, it.Prev())
+	assertEqual(t, 99, it.Value())
+	assertEqualBool(t, false, it.Seek(-5))
+	assertEqualBool(t, true, it.Next())
+	assertEqual(t, 0, it.Value())
+}
+
+func TestVectorIteratorEmpty(t *testing.T) {
+	it := NewVector[int]().Iterator()
+	assertEqualBool(t, false, it.Next())
+	assertEqualBool(t, false, it.Prev())
+	defer assertPanic(t, "Index out of bounds")
+	it.Value()
+}
+
+func TestVectorSliceIterator(t *testing.T) {
+	s := NewVector(inputSlice(0, 100)...).Slice(30, 70)
+	it := s.Iterator()
+	count := 0
+	for it.Next() {
+		assertEqual(t, 30+count, it.Value())
+		assertEqual(t, count, it.Index())
+		count++
+	}
+
+	assertEqual(t, 40, count)
+	assertEqualBool(t, true, it.Seek(0))
+	assertEqual(t, 30, it.Value())
+	assertEqualBool(t, false, it.Prev())
+
+	defer assertPanic(t, "Index out of bounds")
+	it.Value()
+}
+
+func TestMapIterator(t *testing.T) {
+	m := NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Store(i, -i)
+	}
+
+	seen := make(map[int]bool)
+	it := m.Iterator()
+	assertEqualBool(t, false, it.Valid())
+	for it.Next() {
+		assertEqualBool(t, true, it.Valid())
+		assertEqual(t, -it.Key(), it.Value())
+		assertEqualBool(t, false, seen[it.Key()])
+		seen[it.Key()] = true
+	}
+
+	assertEqual(t, 1000, len(seen))
+	assertEqualBool(t, false, it.Valid())
+	assertEqualBool(t, false, it.Next())
+	assertEqual(t, 0, it.Key())
+}
+
+func TestMapIteratorInterleaved(t *testing.T) {
+	// Advancing two iterators in lock step, which can't be expressed with Range
+	a := NewMap[string, int]().Store("x", 1).Store("y", 2).Store("z", 3)
+	b := MapValues(a, func(_ string, v int) int { return v * 10 })
+	itA, itB := a.Iterator(), b.Iterator()
+	for itA.Next() {
+		assertEqualBool(t, true, itB.Next())
+		assertEqualString(t, itA.Key(), itB.Key())
+		assertEqual(t, itA.Value()*10, itB.Value())
+	}
+
+	assertEqualBool(t, false, itB.Next())
+	assertEqualBool(t, false, NewMap[string, int]().Iterator().Next())
+}
+
+func TestSortedMapIterator(t *testing.T) {
+	m := NewBTreeMap[int, string]()
+	for i := 0; i < 200; i += 2 {
+		m = m.store(i, string(rune('a'+i%26)))
+	}
+
+	it := NewSortedMapIterator[int, string](m)
+	count := 0
+	for it.Next() {
+		assertEqual(t, 2*count, it.Key())
+		count++
+	}
+
+	assertEqual(t, 100, count)
+	assertEqualBool(t, true, it.SeekKey(51))
+	assertEqual(t, 52, it.Key())
+	assertEqual(t, 26, it.Index())
+	assertEqualBool(t, true, it.Prev())
+	assertEqual(t, 50, it.Key())
+	assertEqualBool(t, true, it.SeekKey(50))
+	assertEqual(t, 50, it.Key())
+	assertEqualBool(t, false, it.SeekKey(1000))
+	assertEqual(t, 0, it.Key())
+	assertEqualBool(t, true, it.Prev())
+	assertEqual(t, 198, it.Key())
+	assertEqualBool(t, true, it.Seek(0))
+	assertEqual(t, 0, it.Key())
+	assertEqualString(t, "a", it.Value())
+}
+
+func TestSortedMapIteratorMergeJoin(t *testing.T) {
+	a := NewBTreeMap[int, int]()
+	b := NewBTreeMap[int, int]()
+	for i := 0; i < 100; i++ {
+		a = a.store(2*i, i)
+		b = b.store(3*i, i)
+	}
+
+	// Advance two iterators in tandem to find the common keys
+	common := 0
+	itA, itB := NewSortedMapIterator[int, int](a), NewSortedMapIterator[int, int](b)
+	for okA, okB := itA.Next(), itB.Next(); okA && okB; {
+		switch {
+		case itA.Key() < itB.Key():
+			okA = itA.SeekKey(itB.Key())
+		case itA.Key() > itB.Key():
+			okB = itB.SeekKey(itA.Key())
+		default:
+			assertEqual(t, 0, itA.Key()%6)
+			common++
+			okA, okB = itA.Next(), itB.Next()
+		}
+	}
+
+	assertEqual(t, 34, common)
+}
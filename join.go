@@ -0,0 +1,54 @@
+package peds
+
+// InnerJoin returns a new Map containing the keys present in both a and b, each associated
+// with a pair of the value in a and the value in b.
+func InnerJoin[K comparable, A, B any](a *Map[K, A], b *Map[K, B]) *Map[K, Pair[A, B]] {
+	return InnerJoinFunc(a, b, func(_ K, x A, y B) Pair[A, B] { return Pair[A, B]{First: x, Second: y} })
+}
+
+// InnerJoinFunc returns a new Map containing the keys present in both a and b, each associated
+// with the result of calling f with the key, the value in a and the value in b.
+func InnerJoinFunc[K comparable, A, B, C any](a *Map[K, A], b *Map[K, B], f func(key K, x A, y B) C) *Map[K, C] {
+	capacity := a.Len()
+	if b.Len() < capacity {
+		capacity = b.Len()
+	}
+
+	buckets := newPrivateItemBuckets[K, C](capacity, a.hasher, a.options)
+	if a.Len() <= b.Len() {
+		a.Range(func(key K, x A) bool {
+			if y, ok := b.Load(key); ok {
+				buckets.AddItem(MapItem[K, C]{Key: key, Value: f(key, x, y)})
+			}
+
+			return true
+		})
+	} else {
+		b.Range(func(key K, y B) bool {
+			if x, ok := a.Load(key); ok {
+				buckets.AddItem(MapItem[K, C]{Key: key, Value: f(key, x, y)})
+			}
+
+			return true
+		})
+	}
+
+	return buckets.toMap()
+}
+
+// LeftJoin returns a new Map containing all keys in a, each associated with a pair of the value
+// in a and the value in b. The second value of the pair is the zero value for keys missing from
+// b, use LeftJoinFunc if those need to be told apart from zero values present in b.
+func LeftJoin[K comparable, A, B any](a *Map[K, A], b *Map[K, B]) *Map[K, Pair[A, B]] {
+	return LeftJoinFunc(a, b, func(_ K, x A, y B, _ bool) Pair[A, B] { return Pair[A, B]{First: x, Second: y} })
+}
+
+// LeftJoinFunc returns a new Map containing all keys in a, each associated with the result of
+// calling f with the key, the value in a and the value in b. ok is set to false, and y to the
+// zero value, for keys missing from b.
+func LeftJoinFunc[K comparable, A, B, C any](a *Map[K, A], b *Map[K, B], f func(key K, x A, y B, ok bool) C) *Map[K, C] {
+	return MapValues(a, func(key K, x A) C {
+		y, ok := b.Load(key)
+		return f(key, x, y, ok)
+	})
+}
@@ -0,0 +1,66 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestInnerJoin(t *testing.T) {
+	a := NewMapFromNativeMap(map[int]string{1: "a", 2: "b", 3: "c"})
+	b := NewMapFromNativeMap(map[int]bool{2: true, 3: false, 4: true})
+
+	for _, joined := range []*Map[int, Pair[string, bool]]{InnerJoin(a, b), InnerJoin(a, b.Delete(4).Store(5, true).Store(6, true))} {
+		assertEqual(t, 2, joined.Len())
+		p, _ := joined.Load(2)
+		assertEqualString(t, "b", p.First)
+		assertEqualBool(t, true, p.Second)
+		p, _ = joined.Load(3)
+		assertEqualString(t, "c", p.First)
+		assertEqualBool(t, false, p.Second)
+		assertEqualBool(t, false, joined.Contains(1))
+	}
+
+	assertEqual(t, 0, InnerJoin(a, NewMap[int, bool]()).Len())
+}
+
+func TestInnerJoinFunc(t *testing.T) {
+	a, b := NewMap[int, int](), NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		a = a.Store(i, i)
+		if i%3 == 0 {
+			b = b.Store(i, 2*i)
+		}
+	}
+
+	joined := InnerJoinFunc(a, b, func(_ int, x, y int) int { return x + y })
+	assertEqual(t, 334, joined.Len())
+	joined.Range(func(key, value int) bool {
+		assertEqual(t, 3*key, value)
+		return true
+	})
+}
+
+func TestLeftJoin(t *testing.T) {
+	a := NewMapFromNativeMap(map[int]string{1: "a", 2: "b"})
+	b := NewMapFromNativeMap(map[int]int{2: 20, 3: 30})
+
+	joined := LeftJoin(a, b)
+	assertEqual(t, 2, joined.Len())
+	p, _ := joined.Load(1)
+	assertEqualString(t, "a", p.First)
+	assertEqual(t, 0, p.Second)
+	p, _ = joined.Load(2)
+	assertEqual(t, 20, p.Second)
+
+	described := LeftJoinFunc(a, b, func(key int, x string, y int, ok bool) string {
+		if !ok {
+			return x + "-"
+		}
+
+		return fmt.Sprintf("%s%d", x, y)
+	})
+	v, _ := described.Load(1)
+	assertEqualString(t, "a-", v)
+	v, _ = described.Load(2)
+	assertEqualString(t, "b20", v)
+}
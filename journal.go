@@ -0,0 +1,231 @@
+package peds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidJournal is returned when replaying journal entries that are out of sequence or
+// that do not fit the collection.
+var ErrInvalidJournal = errors.New("peds: invalid journal")
+
+// OpKind identifies the kind of a journaled operation.
+type OpKind uint8
+
+const (
+	// OpStore stores Value under Key in a map.
+	OpStore OpKind = iota + 1
+
+	// OpDelete deletes Key from a map.
+	OpDelete
+
+	// OpAppend appends Value to a vector.
+	OpAppend
+
+	// OpSet sets the element at Index of a vector to Value.
+	OpSet
+)
+
+var opKindNames = map[OpKind]string{OpStore: "store", OpDelete: "delete", OpAppend: "append", OpSet: "set"}
+
+// MarshalText encodes k as its name, e.g. "store".
+func (k OpKind) MarshalText() ([]byte, error) {
+	name, ok := opKindNames[k]
+	if !ok {
+		return nil, fmt.Errorf("peds: unknown op kind %d", k)
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a name produced by MarshalText.
+func (k *OpKind) UnmarshalText(text []byte) error {
+	for kind, name := range opKindNames {
+		if name == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+
+	return fmt.Errorf("peds: unknown op kind %q", text)
+}
+
+// //////////////////
+// / JournaledMap ///
+// //////////////////
+
+// A MapOp is a journal entry recording an operation on a map. Version is the version of the
+// map that resulted from the operation.
+type MapOp[K comparable, V any] struct {
+	Kind    OpKind `json:"kind"`
+	Version int    `json:"version"`
+	Key     K      `json:"key"`
+	Value   V      `json:"value,omitempty"`
+}
+
+// A JournaledMap is a persistent/immutable Map that records each operation applied to it.
+// The recorded operations can be serialized, for example using encoding/json or encoding/gob,
+// and replayed using ReplayMap to rebuild the map, which makes it a suitable base for event
+// sourcing.
+type JournaledMap[K comparable, V any] struct {
+	m       *Map[K, V]
+	version int
+	ops     *Vector[MapOp[K, V]]
+}
+
+// NewJournaledMap returns a journaled map, at version zero and with an empty journal, that
+// wraps m.
+func NewJournaledMap[K comparable, V any](m *Map[K, V]) *JournaledMap[K, V] {
+	return &JournaledMap[K, V]{m: m, ops: NewVector[MapOp[K, V]]()}
+}
+
+// Map returns the current version of the map.
+func (j *JournaledMap[K, V]) Map() *Map[K, V] {
+	return j.m
+}
+
+// Version returns the number of operations applied since j was created.
+func (j *JournaledMap[K, V]) Version() int {
+	return j.version
+}
+
+// Ops returns the operations recorded since j was created or since the journal was last
+// cleared, oldest first.
+func (j *JournaledMap[K, V]) Ops() *Vector[MapOp[K, V]] {
+	return j.ops
+}
+
+// ClearOps returns a journaled map with the same map and version as j but with an empty
+// journal, typically used once the recorded operations have been persisted.
+func (j *JournaledMap[K, V]) ClearOps() *JournaledMap[K, V] {
+	return &JournaledMap[K, V]{m: j.m, version: j.version, ops: NewVector[MapOp[K, V]]()}
+}
+
+func (j *JournaledMap[K, V]) record(m *Map[K, V], op MapOp[K, V]) *JournaledMap[K, V] {
+	op.Version = j.version + 1
+	return &JournaledMap[K, V]{m: m, version: op.Version, ops: j.ops.Append(op)}
+}
+
+// Store returns a new journaled map with value stored under key and the operation recorded.
+func (j *JournaledMap[K, V]) Store(key K, value V) *JournaledMap[K, V] {
+	return j.record(j.m.Store(key, value), MapOp[K, V]{Kind: OpStore, Key: key, Value: value})
+}
+
+// Delete returns a new journaled map with key removed and the operation recorded. The
+// operation is recorded even if key is not present.
+func (j *JournaledMap[K, V]) Delete(key K) *JournaledMap[K, V] {
+	return j.record(j.m.Delete(key), MapOp[K, V]{Kind: OpDelete, Key: key})
+}
+
+// ReplayMap returns a new map with ops applied to m, in order. The versions of ops must be
+// consecutive, ErrInvalidJournal is returned otherwise or if an operation is not a map
+// operation.
+func ReplayMap[K comparable, V any](m *Map[K, V], ops []MapOp[K, V]) (*Map[K, V], error) {
+	for i, op := range ops {
+		if op.Version != ops[0].Version+i {
+			return nil, ErrInvalidJournal
+		}
+
+		switch op.Kind {
+		case OpStore:
+			m = m.Store(op.Key, op.Value)
+		case OpDelete:
+			m = m.Delete(op.Key)
+		default:
+			return nil, ErrInvalidJournal
+		}
+	}
+
+	return m, nil
+}
+
+// /////////////////////
+// / JournaledVector ///
+// /////////////////////
+
+// A VectorOp is a journal entry recording an operation on a vector. Version is the version
+// of the vector that resulted from the operation. Index is not used by OpAppend.
+type VectorOp[T any] struct {
+	Kind    OpKind `json:"kind"`
+	Version int    `json:"version"`
+	Index   int    `json:"index,omitempty"`
+	Value   T      `json:"value"`
+}
+
+// A JournaledVector is a persistent/immutable Vector that records each operation applied to
+// it. See JournaledMap.
+type JournaledVector[T any] struct {
+	v       *Vector[T]
+	version int
+	ops     *Vector[VectorOp[T]]
+}
+
+// NewJournaledVector returns a journaled vector, at version zero and with an empty journal,
+// that wraps v.
+func NewJournaledVector[T any](v *Vector[T]) *JournaledVector[T] {
+	return &JournaledVector[T]{v: v, ops: NewVector[VectorOp[T]]()}
+}
+
+// Vector returns the current version of the vector.
+func (j *JournaledVector[T]) Vector() *Vector[T] {
+	return j.v
+}
+
+// Version returns the number of operations applied since j was created.
+func (j *JournaledVector[T]) Version() int {
+	return j.version
+}
+
+// Ops returns the operations recorded since j was created or since the journal was last
+// cleared, oldest first.
+func (j *JournaledVector[T]) Ops() *Vector[VectorOp[T]] {
+	return j.ops
+}
+
+// ClearOps returns a journaled vector with the same vector and version as j but with an
+// empty journal.
+func (j *JournaledVector[T]) ClearOps() *JournaledVector[T] {
+	return &JournaledVector[T]{v: j.v, version: j.version, ops: NewVector[VectorOp[T]]()}
+}
+
+func (j *JournaledVector[T]) record(v *Vector[T], op VectorOp[T]) *JournaledVector[T] {
+	op.Version = j.version + 1
+	return &JournaledVector[T]{v: v, version: op.Version, ops: j.ops.Append(op)}
+}
+
+// Append returns a new journaled vector with item appended and the operation recorded.
+func (j *JournaledVector[T]) Append(item T) *JournaledVector[T] {
+	return j.record(j.v.Append(item), VectorOp[T]{Kind: OpAppend, Value: item})
+}
+
+// Set returns a new journaled vector with the element at position i set to item and the
+// operation recorded. Set panics if i is out of bounds.
+func (j *JournaledVector[T]) Set(i int, item T) *JournaledVector[T] {
+	return j.record(j.v.Set(i, item), VectorOp[T]{Kind: OpSet, Index: i, Value: item})
+}
+
+// ReplayVector returns a new vector with ops applied to v, in order. The versions of ops must
+// be consecutive, ErrInvalidJournal is returned otherwise, if an operation is not a vector
+// operation or if an index is out of bounds.
+func ReplayVector[T any](v *Vector[T], ops []VectorOp[T]) (*Vector[T], error) {
+	for i, op := range ops {
+		if op.Version != ops[0].Version+i {
+			return nil, ErrInvalidJournal
+		}
+
+		switch op.Kind {
+		case OpAppend:
+			v = v.Append(op.Value)
+		case OpSet:
+			if op.Index < 0 || op.Index >= v.Len() {
+				return nil, ErrInvalidJournal
+			}
+
+			v = v.Set(op.Index, op.Value)
+		default:
+			return nil, ErrInvalidJournal
+		}
+	}
+
+	return v, nil
+}
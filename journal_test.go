@@ -0,0 +1,118 @@
+package peds
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJournaledMapReplay(t *testing.T) {
+	j := NewJournaledMap(NewMap[string, int]())
+	j1 := j.Store("a", 1).Store("b", 2)
+	j2 := j1.Delete("a").Store("c", 3)
+
+	assertEqual(t, 0, j.Version())
+	assertEqual(t, 2, j1.Version())
+	assertEqual(t, 4, j2.Version())
+	assertEqual(t, 4, j2.Ops().Len())
+	assertEqual(t, 2, j1.Map().Len())
+
+	data, err := json.Marshal(j2.Ops())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ops []MapOp[string, int]
+	if err := json.Unmarshal(data, &ops); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, int(OpDelete), int(ops[2].Kind))
+	m, err := ReplayMap(NewMap[string, int](), ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, m.Equal(j2.Map()))
+
+	// Replay from a snapshot
+	cleared := j1.ClearOps()
+	assertEqual(t, 2, cleared.Version())
+	j3 := cleared.Store("d", 4)
+	m, err = ReplayMap(j1.Map(), j3.Ops().ToNativeSlice())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, m.Equal(j3.Map()))
+	assertEqual(t, 3, j3.Ops().Get(0).Version)
+}
+
+func TestJournaledVectorReplay(t *testing.T) {
+	j := NewJournaledVector(NewVector(1, 2))
+	for i := 0; i < 100; i++ {
+		j = j.Append(i)
+	}
+
+	j = j.Set(0, 10).Set(50, 20)
+	assertEqual(t, 102, j.Version())
+
+	data, err := json.Marshal(j.Ops())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ops []VectorOp[int]
+	if err := json.Unmarshal(data, &ops); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := ReplayVector(NewVector(1, 2), ops)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, v.Equal(j.Vector()))
+}
+
+func TestReplayInvalidJournal(t *testing.T) {
+	if _, err := ReplayMap(NewMap[int, int](), []MapOp[int, int]{{Kind: OpStore, Version: 1}, {Kind: OpStore, Version: 3}}); err != ErrInvalidJournal {
+		t.Errorf("Expected ErrInvalidJournal, got %v", err)
+	}
+
+	if _, err := ReplayMap(NewMap[int, int](), []MapOp[int, int]{{Kind: OpAppend, Version: 1}}); err != ErrInvalidJournal {
+		t.Errorf("Expected ErrInvalidJournal, got %v", err)
+	}
+
+	if _, err := ReplayVector(NewVector(1), []VectorOp[int]{{Kind: OpSet, Version: 1, Index: 1}}); err != ErrInvalidJournal {
+		t.Errorf("Expected ErrInvalidJournal, got %v", err)
+	}
+
+	if _, err := ReplayVector(NewVector(1), []VectorOp[int]{{Kind: OpDelete, Version: 1}}); err != ErrInvalidJournal {
+		t.Errorf("Expected ErrInvalidJournal, got %v", err)
+	}
+}
+
+func TestOpKindText(t *testing.T) {
+	for _, kind := range []OpKind{OpStore, OpDelete, OpAppend, OpSet} {
+		text, err := kind.MarshalText()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var decoded OpKind
+		if err := decoded.UnmarshalText(text); err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, int(kind), int(decoded))
+	}
+
+	if _, err := OpKind(0).MarshalText(); err == nil {
+		t.Error("Expected error")
+	}
+
+	var k OpKind
+	if err := k.UnmarshalText([]byte("bogus")); err == nil {
+		t.Error("Expected error")
+	}
+}
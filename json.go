@@ -0,0 +1,126 @@
+package peds
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+)
+
+// MarshalJSON encodes v as a JSON array.
+func (v *Vector[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.ToNativeSlice())
+}
+
+// UnmarshalJSON replaces the contents of v with the elements of the JSON array in data.
+func (v *Vector[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*v = *NewVector(items...)
+	return nil
+}
+
+// MarshalJSON encodes s as a JSON array.
+func (s *VectorSlice[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.AppendToSlice(nil))
+}
+
+// UnmarshalJSON replaces the contents of s with the elements of the JSON array in data.
+func (s *VectorSlice[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*s = *NewVectorSlice(items...)
+	return nil
+}
+
+// MarshalJSON encodes m as a JSON object. The same restrictions on key types as for native
+// Go maps apply. As for native maps the members are sorted by key, which makes the output
+// deterministic. See MarshalJSONFunc and MarshalJSONInOrder for other orderings.
+func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.ToNativeMap())
+}
+
+// UnmarshalJSON replaces the contents of m with the members of the JSON object in data.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	var items map[K]V
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	*m = *NewMapFromNativeMap(items)
+	return nil
+}
+
+// MarshalJSONFunc encodes m as a JSON object with the members ordered by key according to
+// compare.
+func MarshalJSONFunc[V any](m *Map[string, V], compare func(a, b string) int) ([]byte, error) {
+	keys := make([]string, 0, m.Len())
+	m.Range(func(key string, _ V) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	slices.SortFunc(keys, compare)
+	return marshalJSONObject(m, keys)
+}
+
+// MarshalJSONInOrder encodes m as a JSON object with the members in the order given by keys,
+// for example the insertion order as tracked by the caller. Keys that are not present in m
+// are skipped. Members of m whose key is not part of keys follow at the end, sorted by key.
+func MarshalJSONInOrder[V any](m *Map[string, V], keys []string) ([]byte, error) {
+	ordered := make([]string, 0, m.Len())
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if _, ok := seen[key]; ok || !m.Contains(key) {
+			continue
+		}
+
+		seen[key] = struct{}{}
+		ordered = append(ordered, key)
+	}
+
+	rest := len(ordered)
+	m.Range(func(key string, _ V) bool {
+		if _, ok := seen[key]; !ok {
+			ordered = append(ordered, key)
+		}
+
+		return true
+	})
+
+	slices.Sort(ordered[rest:])
+	return marshalJSONObject(m, ordered)
+}
+
+func marshalJSONObject[V any](m *Map[string, V], keys []string) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		encodedKey, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+
+		value, _ := m.Load(key)
+		encodedValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.Write(encodedKey)
+		buf.WriteByte(':')
+		buf.Write(encodedValue)
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,155 @@
+package peds
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestVectorJSONRoundTrip(t *testing.T) {
+	for _, l := range []int{0, 1, 33, 1000} {
+		data, err := json.Marshal(NewVector(inputSlice(0, l)...))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v Vector[int]
+		if err := json.Unmarshal(data, &v); err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, l, v.Len())
+		for i := 0; i < l; i++ {
+			assertEqual(t, i, v.Get(i))
+		}
+	}
+}
+
+func TestVectorJSONFormat(t *testing.T) {
+	data, _ := json.Marshal(NewVector(1, 2, 3))
+	assertEqualString(t, "[1,2,3]", string(data))
+
+	data, _ = json.Marshal(NewVector[int]())
+	assertEqualString(t, "[]", string(data))
+}
+
+func TestVectorSliceJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(NewVector(inputSlice(0, 100)...).Slice(10, 13))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, "[10,11,12]", string(data))
+
+	var s VectorSlice[int]
+	if err := json.Unmarshal(data, &s); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 3, s.Len())
+	assertEqual(t, 12, s.Get(2))
+}
+
+func TestMapJSONRoundTrip(t *testing.T) {
+	data, err := json.Marshal(NewMapFromNativeMap(map[string]int{"a": 1, "b": 2}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `{"a":1,"b":2}`, string(data))
+
+	var m Map[string, int]
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 2, m.Len())
+	v, _ := m.Load("b")
+	assertEqual(t, 2, v)
+}
+
+func TestJSONNestedInStruct(t *testing.T) {
+	type document struct {
+		Tags   *Vector[string]
+		Counts *Map[int, int]
+	}
+
+	data, err := json.Marshal(document{Tags: NewVector("x", "y"), Counts: NewMap(MapItem[int, int]{Key: 1, Value: 10})})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, "y", doc.Tags.Get(1))
+	v, _ := doc.Counts.Load(1)
+	assertEqual(t, 10, v)
+}
+
+func TestJSONUnmarshalError(t *testing.T) {
+	var v Vector[int]
+	assertEqualBool(t, true, json.Unmarshal([]byte(`{"a":1}`), &v) != nil)
+
+	var m Map[string, int]
+	assertEqualBool(t, true, json.Unmarshal([]byte(`[1]`), &m) != nil)
+}
+
+func TestMapJSONSortedKeys(t *testing.T) {
+	m := NewMap[string, int]()
+	for _, key := range []string{"d", "b", "a", "c", "e"} {
+		m = m.Store(key, m.Len())
+	}
+
+	for i := 0; i < 5; i++ {
+		data, err := json.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqualString(t, `{"a":2,"b":1,"c":3,"d":0,"e":4}`, string(data))
+	}
+}
+
+func TestMarshalJSONFunc(t *testing.T) {
+	m := NewMap[string, bool]().Store("a", true).Store("c", false).Store("b", true)
+	data, err := MarshalJSONFunc(m, func(a, b string) int { return strings.Compare(b, a) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `{"c":false,"b":true,"a":true}`, string(data))
+
+	data, err = MarshalJSONFunc(NewMap[string, bool](), strings.Compare)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `{}`, string(data))
+}
+
+func TestMarshalJSONInOrder(t *testing.T) {
+	m := NewMap[string, string]().Store("z", "1").Store("y", "\"q\"").Store("b", "3").Store("a", "4")
+	data, err := MarshalJSONInOrder(m, []string{"z", "missing", "y", "z"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `{"z":"1","y":"\"q\"","a":"4","b":"3"}`, string(data))
+
+	var decoded Map[string, string]
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, decoded.Equal(m))
+}
+
+func TestMarshalJSONInOrderError(t *testing.T) {
+	m := NewMap[string, func()]().Store("a", func() {})
+	if _, err := MarshalJSONInOrder(m, nil); err == nil {
+		t.Error("Expected error")
+	}
+}
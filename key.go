@@ -0,0 +1,101 @@
+package peds
+
+import (
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// A Key is a comparable value identifying the contents of a persistent collection of type C,
+// obtained from Vector.Key or Map.Key. Keys of collections with equal contents are equal,
+// keys of collections with different contents are not, which allows collections to be used,
+// through their keys, as keys in native Go maps, for example for memoization.
+//
+// A key refers to the first version seen with its contents, the canonical version, keeping
+// it alive as long as the key is. Keys are only meaningful within the process creating them.
+type Key[C any] struct {
+	digest  uint64
+	version *C
+}
+
+// Digest returns the content hash from which k was derived.
+func (k Key[C]) Digest() uint64 {
+	return k.digest
+}
+
+// Value returns the canonical version of the collection that k identifies, its contents are
+// equal to those of the collection that k was obtained from.
+func (k Key[C]) Value() *C {
+	return k.version
+}
+
+// keyRegistry holds weak pointers to the canonical versions of the collections of one type,
+// grouped by digest.
+type keyRegistry[C any] struct {
+	lock     sync.Mutex
+	versions map[uint64][]weak.Pointer[C]
+}
+
+type keyRegistration[C any] struct {
+	digest  uint64
+	version weak.Pointer[C]
+}
+
+// keyRegistries holds one keyRegistry per collection type, keyed by a nil pointer to the
+// collection type.
+var keyRegistries sync.Map
+
+// internKey returns the key for c, registering c as the canonical version of its contents
+// unless a collection with equal contents is already registered.
+func internKey[C any](c *C, digest uint64, equal func(a, b *C) bool) Key[C] {
+	r, ok := keyRegistries.Load((*C)(nil))
+	if !ok {
+		r, _ = keyRegistries.LoadOrStore((*C)(nil), &keyRegistry[C]{versions: make(map[uint64][]weak.Pointer[C])})
+	}
+
+	registry := r.(*keyRegistry[C])
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	for _, w := range registry.versions[digest] {
+		if version := w.Value(); version != nil && (version == c || equal(version, c)) {
+			return Key[C]{digest: digest, version: version}
+		}
+	}
+
+	w := weak.Make(c)
+	registry.versions[digest] = append(registry.versions[digest], w)
+	runtime.AddCleanup(c, registry.unregister, keyRegistration[C]{digest: digest, version: w})
+	return Key[C]{digest: digest, version: c}
+}
+
+func (r *keyRegistry[C]) unregister(registration keyRegistration[C]) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	versions := r.versions[registration.digest]
+	for i, w := range versions {
+		if w == registration.version {
+			versions = append(versions[:i:i], versions[i+1:]...)
+			break
+		}
+	}
+
+	if len(versions) == 0 {
+		delete(r.versions, registration.digest)
+	} else {
+		r.versions[registration.digest] = versions
+	}
+}
+
+// Key returns a comparable key identifying the contents of v. Elements are compared as in
+// Equal. The content hash, see ContentHash, is cached so computing the key of a vector
+// derived from a vector with a known key is cheap, apart from the comparison with any
+// collection of equal contents seen before.
+func (v *Vector[T]) Key() Key[Vector[T]] {
+	return internKey(v, v.ContentHash(), (*Vector[T]).Equal)
+}
+
+// Key returns a comparable key identifying the contents of m. Values are compared as in
+// Equal. Computing the key requires hashing all items, see Sum64.
+func (m *Map[K, V]) Key() Key[Map[K, V]] {
+	return internKey(m, m.Sum64(0), (*Map[K, V]).Equal)
+}
@@ -0,0 +1,67 @@
+package peds
+
+import (
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestVectorKey(t *testing.T) {
+	v1 := NewVector(inputSlice(0, 100)...)
+	v2 := NewVector(inputSlice(0, 99)...).Append(99)
+	v3 := v1.Set(5, -1)
+
+	assertEqualBool(t, true, v1.Key() == v2.Key())
+	assertEqualBool(t, true, v1.Key() == v1.Key())
+	assertEqualBool(t, false, v1.Key() == v3.Key())
+	assertEqualBool(t, true, v2.Key().Value() == v1)
+	assertEqualBool(t, true, v1.Key().Digest() == v1.ContentHash())
+
+	memo := map[Key[Vector[int]]]int{}
+	memo[v1.Key()] = 1
+	memo[v3.Key()] = 2
+	assertEqual(t, 1, memo[v2.Key()])
+	assertEqual(t, 2, memo[v3.Set(5, -1).Key()])
+	assertEqual(t, 2, len(memo))
+}
+
+func TestMapKey(t *testing.T) {
+	m1 := NewMap[string, int]()
+	m2 := NewMapWithCapacity[string, int](1000)
+	for i := 0; i < 100; i++ {
+		m1 = m1.Store(string(rune('a'+i%26))+string(rune('a'+i/26)), i)
+	}
+
+	m1.Range(func(key string, value int) bool {
+		m2 = m2.Store(key, value)
+		return true
+	})
+
+	assertEqualBool(t, true, m1.Key() == m2.Key())
+	assertEqualBool(t, false, m1.Key() == m1.Store("x", 1).Key())
+	assertEqualBool(t, true, NewMap[string, int]().Key() == NewMap[string, int]().Key())
+}
+
+func TestKeyRegistryCleanup(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		NewVector(i, i, i).Key()
+	}
+
+	r, _ := keyRegistries.Load((*Vector[int])(nil))
+	registry := r.(*keyRegistry[Vector[int]])
+	count := func() int {
+		registry.lock.Lock()
+		defer registry.lock.Unlock()
+		return len(registry.versions)
+	}
+
+	// Cleanups run asynchronously after the collections have been garbage collected
+	for i := 0; i < 100 && count() > 10; i++ {
+		runtime.GC()
+		time.Sleep(time.Millisecond)
+	}
+
+	if c := count(); c > 10 {
+		t.Errorf("Expected registrations to be removed, found %d", c)
+	}
+}
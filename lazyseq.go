@@ -0,0 +1,133 @@
+package peds
+
+import (
+	"iter"
+	"sync"
+)
+
+// /////////////
+// / LazySeq ///
+// /////////////
+
+// lazySeqState is shared by all copies of a LazySeq. realized holds the elements produced by
+// next so far, next is set to nil once it has been exhausted.
+type lazySeqState[T any] struct {
+	lock     sync.Mutex
+	next     func() (T, bool)
+	realized *Vector[T]
+}
+
+// A LazySeq is a possibly infinite sequence of elements produced on demand by a generator
+// function, in the style of Clojure lazy sequences. Each element is produced once, the
+// realized prefix of the sequence is memoized in a Vector so reading it again is cheap.
+// A LazySeq is safe for concurrent use by multiple goroutines.
+type LazySeq[T any] struct {
+	state *lazySeqState[T]
+}
+
+// NewLazySeq returns a new sequence of the elements produced by calling next repeatedly until
+// it returns false. next is only called as far as the sequence is read, never concurrently.
+func NewLazySeq[T any](next func() (T, bool)) *LazySeq[T] {
+	return &LazySeq[T]{state: &lazySeqState[T]{next: next, realized: NewVector[T]()}}
+}
+
+// Iterate returns a new infinite sequence of seed, f(seed), f(f(seed)) and so on.
+func Iterate[T any](seed T, f func(T) T) *LazySeq[T] {
+	current, started := seed, false
+	return NewLazySeq(func() (T, bool) {
+		if started {
+			current = f(current)
+		}
+
+		started = true
+		return current, true
+	})
+}
+
+// realize produces elements until at least n have been realized or the sequence has been
+// exhausted, and returns the realized elements.
+func (s *LazySeq[T]) realize(n int) *Vector[T] {
+	s.state.lock.Lock()
+	defer s.state.lock.Unlock()
+	for s.state.realized.Len() < n && s.state.next != nil {
+		item, ok := s.state.next()
+		if !ok {
+			s.state.next = nil
+			break
+		}
+
+		s.state.realized = s.state.realized.Append(item)
+	}
+
+	return s.state.realized
+}
+
+// Get returns the element at position i. ok is set to false if the sequence ends before i.
+func (s *LazySeq[T]) Get(i int) (item T, ok bool) {
+	if i < 0 {
+		return item, false
+	}
+
+	return s.realize(i + 1).TryGet(i)
+}
+
+// Take returns a vector of the first n elements of s, or of all elements of s if it has fewer
+// than n elements.
+func (s *LazySeq[T]) Take(n int) *Vector[T] {
+	return s.realize(n).Take(n)
+}
+
+// Realized returns the elements of s that have been produced so far.
+func (s *LazySeq[T]) Realized() *Vector[T] {
+	return s.realize(0)
+}
+
+// Range calls f repeatedly passing it each element in s in order as argument until either
+// the sequence ends or f returns false. For an infinite sequence f must eventually return
+// false.
+func (s *LazySeq[T]) Range(f func(T) bool) {
+	for i := 0; ; i++ {
+		item, ok := s.Get(i)
+		if !ok || !f(item) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the elements of s.
+func (s *LazySeq[T]) All() iter.Seq[T] {
+	return s.Range
+}
+
+// Filter returns a new lazy sequence of the elements of s for which pred returns true.
+func (s *LazySeq[T]) Filter(pred func(T) bool) *LazySeq[T] {
+	i := 0
+	return NewLazySeq(func() (T, bool) {
+		for {
+			item, ok := s.Get(i)
+			if !ok {
+				return item, false
+			}
+
+			i++
+			if pred(item) {
+				return item, true
+			}
+		}
+	})
+}
+
+// MapLazySeq returns a new lazy sequence of the results of calling f with each element of s.
+func MapLazySeq[T, U any](s *LazySeq[T], f func(T) U) *LazySeq[U] {
+	i := 0
+	return NewLazySeq(func() (U, bool) {
+		item, ok := s.Get(i)
+		if !ok {
+			var zero U
+			return zero, false
+		}
+
+		i++
+		return f(item), true
+	})
+}
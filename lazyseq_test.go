@@ -0,0 +1,101 @@
+package peds
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestLazySeqInfinite(t *testing.T) {
+	naturals := Iterate(0, func(i int) int { return i + 1 })
+	first := naturals.Take(100)
+	assertEqual(t, 100, first.Len())
+	assertEqual(t, 99, first.Get(99))
+
+	v, ok := naturals.Get(1000)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1000, v)
+	assertEqual(t, 1001, naturals.Realized().Len())
+}
+
+func TestLazySeqMemoizes(t *testing.T) {
+	calls := 0
+	s := NewLazySeq(func() (int, bool) {
+		calls++
+		return calls, calls <= 10
+	})
+
+	assertEqual(t, 5, s.Take(5).Len())
+	assertEqual(t, 5, calls)
+	assertEqual(t, 3, s.Take(3).Get(2))
+	assertEqual(t, 5, calls)
+
+	assertEqual(t, 10, s.Take(100).Len())
+	assertEqual(t, 11, calls)
+	_, ok := s.Get(10)
+	assertEqualBool(t, false, ok)
+	assertEqual(t, 11, calls)
+	_, ok = s.Get(-1)
+	assertEqualBool(t, false, ok)
+}
+
+func TestLazySeqMapAndFilter(t *testing.T) {
+	naturals := Iterate(0, func(i int) int { return i + 1 })
+	evenSquares := MapLazySeq(naturals.Filter(func(i int) bool { return i%2 == 0 }), func(i int) int { return i * i })
+	squares := evenSquares.Take(4)
+	for i, expected := range []int{0, 4, 16, 36} {
+		assertEqual(t, expected, squares.Get(i))
+	}
+
+	// Only as much of the source as needed has been realized
+	assertEqual(t, 7, naturals.Realized().Len())
+
+	sum := 0
+	for i := range evenSquares.All() {
+		if i > 100 {
+			break
+		}
+
+		sum += i
+	}
+	assertEqual(t, 0+4+16+36+64+100, sum)
+}
+
+func TestLazySeqFinite(t *testing.T) {
+	items := []string{"a", "b", "c"}
+	s := NewLazySeq(func() (string, bool) {
+		if len(items) == 0 {
+			return "", false
+		}
+
+		item := items[0]
+		items = items[1:]
+		return item, true
+	})
+
+	var result []string
+	s.Range(func(item string) bool {
+		result = append(result, item)
+		return true
+	})
+	assertEqual(t, 3, len(result))
+	assertEqual(t, 0, MapLazySeq(s.Filter(func(string) bool { return false }), func(s string) int { return len(s) }).Take(10).Len())
+}
+
+func TestLazySeqConcurrent(t *testing.T) {
+	s := Iterate(0, func(i int) int { return i + 1 })
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				v, _ := s.Get(i)
+				if v != i {
+					t.Errorf("Expected %d, got %d", i, v)
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
@@ -0,0 +1,144 @@
+package peds
+
+import "fmt"
+
+// A Lens focuses on a part of type A of a persistent structure of type S. Get returns the
+// part and Set returns a new structure with the part replaced. Lenses compose, see
+// ComposeLens, which makes them convenient for updating deeply nested structures.
+type Lens[S, A any] struct {
+	Get func(s S) A
+	Set func(s S, a A) S
+}
+
+// Update returns a new structure where the part focused by l has been replaced by the result
+// of calling f with the current part.
+func (l Lens[S, A]) Update(s S, f func(A) A) S {
+	return l.Set(s, f(l.Get(s)))
+}
+
+// ComposeLens returns a lens focusing on the part focused by inner within the part focused by
+// outer. Setting a value rebuilds the structure from the inside out.
+func ComposeLens[A, B, C any](outer Lens[A, B], inner Lens[B, C]) Lens[A, C] {
+	return Lens[A, C]{
+		Get: func(a A) C { return inner.Get(outer.Get(a)) },
+		Set: func(a A, c C) A { return outer.Set(a, inner.Set(outer.Get(a), c)) },
+	}
+}
+
+// MapLens returns a lens focusing on the value identified by key in a Map. Get returns the
+// zero value if key is missing.
+func MapLens[K comparable, V any](key K) Lens[*Map[K, V], V] {
+	return Lens[*Map[K, V], V]{
+		Get: func(m *Map[K, V]) V {
+			value, _ := m.Load(key)
+			return value
+		},
+		Set: func(m *Map[K, V], value V) *Map[K, V] { return m.Store(key, value) },
+	}
+}
+
+// VectorLens returns a lens focusing on the element at position i in a Vector.
+func VectorLens[T any](i int) Lens[*Vector[T], T] {
+	return Lens[*Vector[T], T]{
+		Get: func(v *Vector[T]) T { return v.Get(i) },
+		Set: func(v *Vector[T], item T) *Vector[T] { return v.Set(i, item) },
+	}
+}
+
+// pathStep is implemented by the collections that GetIn and UpdateIn can step through.
+type pathStep interface {
+	loadAny(key any) (any, bool)
+	storeAny(key any, value any) any
+}
+
+func (m *Map[K, V]) loadAny(key any) (any, bool) {
+	k, ok := key.(K)
+	if !ok {
+		return nil, false
+	}
+
+	return m.Load(k)
+}
+
+func (m *Map[K, V]) storeAny(key any, value any) any {
+	k, ok := key.(K)
+	if !ok {
+		panic(fmt.Sprintf("Invalid key type %T for map in path", key))
+	}
+
+	v, ok := value.(V)
+	if !ok && value != nil {
+		panic(fmt.Sprintf("Invalid value type %T for map in path", value))
+	}
+
+	return m.Store(k, v)
+}
+
+func (v *Vector[T]) loadAny(key any) (any, bool) {
+	i, ok := key.(int)
+	if !ok {
+		return nil, false
+	}
+
+	return v.TryGet(i)
+}
+
+func (v *Vector[T]) storeAny(key any, value any) any {
+	i, ok := key.(int)
+	if !ok {
+		panic(fmt.Sprintf("Invalid index type %T for vector in path", key))
+	}
+
+	item, ok := value.(T)
+	if !ok && value != nil {
+		panic(fmt.Sprintf("Invalid value type %T for vector in path", value))
+	}
+
+	return v.Set(i, item)
+}
+
+// GetIn returns the value found by following path from root through nested Maps and Vectors,
+// using each element of path as a key or index in turn. ok is set to false if any step of
+// the path is missing or of the wrong type.
+func GetIn(root any, path ...any) (value any, ok bool) {
+	value = root
+	for _, key := range path {
+		step, isStep := value.(pathStep)
+		if !isStep {
+			return nil, false
+		}
+
+		if value, ok = step.loadAny(key); !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// UpdateIn returns a new root where the value found by following path, as for GetIn, has
+// been replaced by the result of calling f with the current value. ok is set to false if
+// the last key of the path is missing, in which case it is added. All collections along the
+// path are rebuilt, from the inside out, to refer to the new value. UpdateIn panics if an
+// intermediate step of the path is missing or if a key or value has the wrong type.
+func UpdateIn(root any, path []any, f func(value any, ok bool) any) any {
+	if len(path) == 0 {
+		return f(root, true)
+	}
+
+	step, isStep := root.(pathStep)
+	if !isStep {
+		panic(fmt.Sprintf("Invalid path, %T is not a Map or Vector", root))
+	}
+
+	value, ok := step.loadAny(path[0])
+	if len(path) == 1 {
+		return step.storeAny(path[0], f(value, ok))
+	}
+
+	if !ok {
+		panic(fmt.Sprintf("Invalid path, %v not found", path[0]))
+	}
+
+	return step.storeAny(path[0], UpdateIn(value, path[1:], f))
+}
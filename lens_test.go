@@ -0,0 +1,79 @@
+package peds
+
+import "testing"
+
+type lensUser struct {
+	name  string
+	roles *Vector[string]
+}
+
+func TestLens(t *testing.T) {
+	users := NewMap[string, *Vector[int]]().Store("a", NewVector(1, 2, 3))
+	second := ComposeLens(MapLens[string, *Vector[int]]("a"), VectorLens[int](1))
+	assertEqual(t, 2, second.Get(users))
+
+	users2 := second.Update(users, func(i int) int { return i * 10 })
+	assertEqual(t, 20, second.Get(users2))
+	assertEqual(t, 2, second.Get(users))
+	v, _ := users2.Load("a")
+	assertEqual(t, 3, v.Len())
+}
+
+func TestLensOnStruct(t *testing.T) {
+	roles := Lens[lensUser, *Vector[string]]{
+		Get: func(u lensUser) *Vector[string] { return u.roles },
+		Set: func(u lensUser, roles *Vector[string]) lensUser {
+			u.roles = roles
+			return u
+		},
+	}
+
+	firstRole := ComposeLens(ComposeLens(MapLens[int, lensUser](1), roles), VectorLens[string](0))
+	m := NewMap[int, lensUser]().Store(1, lensUser{name: "x", roles: NewVector("reader")})
+	m2 := firstRole.Set(m, "admin")
+	assertEqualString(t, "admin", firstRole.Get(m2))
+	assertEqualString(t, "reader", firstRole.Get(m))
+}
+
+func TestGetInAndUpdateIn(t *testing.T) {
+	inner := NewMap[string, int]().Store("count", 1)
+	root := NewMap[string, *Vector[*Map[string, int]]]().Store("items", NewVector(inner, inner))
+
+	value, ok := GetIn(root, "items", 1, "count")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, value.(int))
+
+	for _, path := range [][]any{{"missing"}, {"items", 5}, {"items", "x"}, {"items", 0, "count", "deeper"}} {
+		_, ok = GetIn(root, path...)
+		assertEqualBool(t, false, ok)
+	}
+
+	updated := UpdateIn(root, []any{"items", 1, "count"}, func(v any, ok bool) any {
+		return v.(int) + 1
+	}).(*Map[string, *Vector[*Map[string, int]]])
+
+	value, _ = GetIn(updated, "items", 1, "count")
+	assertEqual(t, 2, value.(int))
+	value, _ = GetIn(updated, "items", 0, "count")
+	assertEqual(t, 1, value.(int))
+	value, _ = GetIn(root, "items", 1, "count")
+	assertEqual(t, 1, value.(int))
+
+	// A missing last key is added
+	updated = UpdateIn(root, []any{"items", 0, "new"}, func(v any, ok bool) any {
+		assertEqualBool(t, false, ok)
+		return 7
+	}).(*Map[string, *Vector[*Map[string, int]]])
+	value, _ = GetIn(updated, "items", 0, "new")
+	assertEqual(t, 7, value.(int))
+}
+
+func TestUpdateInInvalidValueType(t *testing.T) {
+	defer assertPanic(t, "Invalid value type string")
+	UpdateIn(NewMap[string, int](), []any{"a"}, func(any, bool) any { return "x" })
+}
+
+func TestUpdateInMissingIntermediate(t *testing.T) {
+	defer assertPanic(t, "Invalid path, b not found")
+	UpdateIn(NewMap[string, *Map[string, int]](), []any{"b", "c"}, func(any, bool) any { return 1 })
+}
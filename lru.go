@@ -0,0 +1,116 @@
+package peds
+
+import "fmt"
+
+// /////////
+// / LRU ///
+// /////////
+
+type lruEntry[V any] struct {
+	value V
+	tick  uint64
+}
+
+// An LRU is a persistent/immutable cache holding at most a fixed number of items. When full,
+// storing a new item evicts the least recently used one. Since every operation, including
+// Get which updates the recency of the item, returns a new cache, caches can be snapshotted
+// and shared between goroutines, for example through a Ref, without locking.
+type LRU[K comparable, V any] struct {
+	entries  *Map[K, lruEntry[V]]
+	order    *BTreeMap[uint64, K]
+	tick     uint64
+	capacity int
+}
+
+// NewLRU returns a new, empty, cache holding at most capacity items.
+func NewLRU[K comparable, V any](capacity int) *LRU[K, V] {
+	if capacity <= 0 {
+		panic(fmt.Sprintf("Invalid LRU capacity %d (capacity must be positive)", capacity))
+	}
+
+	return &LRU[K, V]{entries: NewMap[K, lruEntry[V]](), order: NewBTreeMap[uint64, K](), capacity: capacity}
+}
+
+// Len returns the number of items in c.
+func (c *LRU[K, V]) Len() int {
+	return c.entries.Len()
+}
+
+// Cap returns the maximum number of items in c.
+func (c *LRU[K, V]) Cap() int {
+	return c.capacity
+}
+
+// Peek returns the value stored for key and whether it was found without marking it as used.
+func (c *LRU[K, V]) Peek(key K) (value V, ok bool) {
+	entry, ok := c.entries.Load(key)
+	return entry.value, ok
+}
+
+// Get returns the value stored for key, whether it was found, and a new cache where the item
+// is marked as the most recently used. The cache itself is returned if key is not present.
+func (c *LRU[K, V]) Get(key K) (value V, ok bool, result *LRU[K, V]) {
+	entry, ok := c.entries.Load(key)
+	if !ok {
+		return value, false, c
+	}
+
+	return entry.value, true, c.put(key, entry.value, entry.tick, true)
+}
+
+// Put returns a new cache with value stored for key as the most recently used item. If the
+// cache is full the least recently used item is evicted.
+func (c *LRU[K, V]) Put(key K, value V) *LRU[K, V] {
+	entry, ok := c.entries.Load(key)
+	return c.put(key, value, entry.tick, ok)
+}
+
+func (c *LRU[K, V]) put(key K, value V, oldTick uint64, exists bool) *LRU[K, V] {
+	order := c.order
+	if exists {
+		order = order.delete(oldTick)
+	}
+
+	tick := c.tick + 1
+	result := &LRU[K, V]{
+		entries:  c.entries.Store(key, lruEntry[V]{value: value, tick: tick}),
+		order:    order.store(tick, key),
+		tick:     tick,
+		capacity: c.capacity,
+	}
+
+	if result.entries.Len() > c.capacity {
+		result.order.Range(func(oldest uint64, oldestKey K) bool {
+			result.entries = result.entries.Delete(oldestKey)
+			result.order = result.order.delete(oldest)
+			return false
+		})
+	}
+
+	return result
+}
+
+// Remove returns a new cache with key removed. The cache itself is returned if key is not
+// present.
+func (c *LRU[K, V]) Remove(key K) *LRU[K, V] {
+	entry, ok := c.entries.Load(key)
+	if !ok {
+		return c
+	}
+
+	return &LRU[K, V]{
+		entries:  c.entries.Delete(key),
+		order:    c.order.delete(entry.tick),
+		tick:     c.tick,
+		capacity: c.capacity,
+	}
+}
+
+// Range calls f repeatedly passing it each key and value in c, from the least to the most
+// recently used, until either all items have been visited or f returns false.
+func (c *LRU[K, V]) Range(f func(K, V) bool) {
+	c.order.Range(func(_ uint64, key K) bool {
+		entry, _ := c.entries.Load(key)
+		return f(key, entry.value)
+	})
+}
@@ -0,0 +1,72 @@
+package peds
+
+import (
+	"strings"
+	"testing"
+)
+
+func lruKeys(c *LRU[string, int]) string {
+	var keys []string
+	c.Range(func(key string, value int) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return strings.Join(keys, ",")
+}
+
+func TestLRUEviction(t *testing.T) {
+	c := NewLRU[string, int](2).Put("a", 1).Put("b", 2)
+	assertEqualString(t, "a,b", lruKeys(c))
+
+	value, ok, c2 := c.Get("a")
+	assertEqual(t, 1, value)
+	assertEqualBool(t, true, ok)
+	assertEqualString(t, "b,a", lruKeys(c2))
+
+	c3 := c2.Put("c", 3)
+	assertEqual(t, 2, c3.Len())
+	assertEqualString(t, "a,c", lruKeys(c3))
+	_, ok = c3.Peek("b")
+	assertEqualBool(t, false, ok)
+
+	// Older versions are unaffected
+	assertEqualString(t, "a,b", lruKeys(c))
+	assertEqualString(t, "b,c", lruKeys(c.Put("c", 3)))
+}
+
+func TestLRUUpdateAndRemove(t *testing.T) {
+	c := NewLRU[string, int](3).Put("a", 1).Put("b", 2).Put("a", 10)
+	assertEqual(t, 2, c.Len())
+	assertEqualString(t, "b,a", lruKeys(c))
+
+	value, _ := c.Peek("a")
+	assertEqual(t, 10, value)
+
+	_, ok, same := c.Get("x")
+	assertEqualBool(t, false, ok)
+	assertEqualBool(t, true, same == c)
+
+	r := c.Remove("b")
+	assertEqualString(t, "a", lruKeys(r))
+	assertEqualBool(t, true, r.Remove("b") == r)
+	assertEqual(t, 3, r.Cap())
+}
+
+func TestLRUManyItems(t *testing.T) {
+	c := NewLRU[int, int](100)
+	for i := 0; i < 1000; i++ {
+		c = c.Put(i, i)
+	}
+
+	assertEqual(t, 100, c.Len())
+	_, ok := c.Peek(899)
+	assertEqualBool(t, false, ok)
+	_, ok = c.Peek(900)
+	assertEqualBool(t, true, ok)
+}
+
+func TestLRUInvalidCapacity(t *testing.T) {
+	defer assertPanic(t, "Invalid LRU capacity")
+	NewLRU[int, int](0)
+}
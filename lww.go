@@ -0,0 +1,128 @@
+package peds
+
+// ////////////
+// / LWWMap ///
+// ////////////
+
+// lwwEntry is the state of a key in an LWWMap. Deleted entries are kept as tombstones so
+// that deletes propagate when merging.
+type lwwEntry[V any] struct {
+	value     V
+	timestamp int64
+	replica   string
+	deleted   bool
+}
+
+// newer reports whether e wins over other. Ties in timestamp are broken by replica id and then
+// in favour of deletes, which makes the choice independent of the order of comparison.
+func (e lwwEntry[V]) newer(other lwwEntry[V]) bool {
+	if e.timestamp != other.timestamp {
+		return e.timestamp > other.timestamp
+	}
+
+	if e.replica != other.replica {
+		return e.replica > other.replica
+	}
+
+	return e.deleted && !other.deleted
+}
+
+// An LWWMap is a persistent/immutable last-writer-wins map, a conflict-free replicated data
+// type. Every store and delete is tagged with a timestamp and replicas that have been updated
+// independently converge to the same contents when merged, regardless of the order in which
+// merges happen. Timestamps are supplied by the caller, typically from a hybrid logical clock
+// or a wall clock with sufficient precision.
+type LWWMap[K comparable, V any] struct {
+	entries *Map[K, lwwEntry[V]]
+	replica string
+	len     int
+}
+
+// NewLWWMap returns a new, empty, LWWMap for the replica identified by replica. Replica ids
+// must be unique among the replicas that are merged.
+func NewLWWMap[K comparable, V any](replica string) *LWWMap[K, V] {
+	return &LWWMap[K, V]{entries: NewMap[K, lwwEntry[V]](), replica: replica}
+}
+
+// Len returns the number of items in m, not counting deleted items.
+func (m *LWWMap[K, V]) Len() int {
+	return m.len
+}
+
+// Load returns the value identified by key. ok is set to false if key does not exist or has
+// been deleted.
+func (m *LWWMap[K, V]) Load(key K) (value V, ok bool) {
+	entry, ok := m.entries.Load(key)
+	if !ok || entry.deleted {
+		return value, false
+	}
+
+	return entry.value, true
+}
+
+func (m *LWWMap[K, V]) write(key K, entry lwwEntry[V]) *LWWMap[K, V] {
+	current, ok := m.entries.Load(key)
+	if ok && !entry.newer(current) {
+		return m
+	}
+
+	length := m.len
+	if !ok || current.deleted {
+		length++
+	}
+
+	if entry.deleted {
+		length--
+	}
+
+	return &LWWMap[K, V]{entries: m.entries.Store(key, entry), replica: m.replica, len: length}
+}
+
+// Store returns a new map with value stored for key at time timestamp. m is returned unchanged
+// if key has been written at a later time.
+func (m *LWWMap[K, V]) Store(key K, value V, timestamp int64) *LWWMap[K, V] {
+	return m.write(key, lwwEntry[V]{value: value, timestamp: timestamp, replica: m.replica})
+}
+
+// Delete returns a new map with key deleted at time timestamp. m is returned unchanged if key
+// has been written at a later time.
+func (m *LWWMap[K, V]) Delete(key K, timestamp int64) *LWWMap[K, V] {
+	return m.write(key, lwwEntry[V]{timestamp: timestamp, replica: m.replica, deleted: true})
+}
+
+// Merge returns a new map, belonging to the same replica as m, where each key holds the most
+// recently written value of m and other. Merge is commutative, associative and idempotent.
+func (m *LWWMap[K, V]) Merge(other *LWWMap[K, V]) *LWWMap[K, V] {
+	entries := m.entries.Merge(other.entries, func(_ K, a, b lwwEntry[V]) lwwEntry[V] {
+		if b.newer(a) {
+			return b
+		}
+
+		return a
+	})
+
+	length := 0
+	entries.Range(func(_ K, entry lwwEntry[V]) bool {
+		if !entry.deleted {
+			length++
+		}
+
+		return true
+	})
+
+	return &LWWMap[K, V]{entries: entries, replica: m.replica, len: length}
+}
+
+// Range calls f repeatedly passing it each key and value, not including deleted items, until
+// either all items have been visited or f returns false.
+func (m *LWWMap[K, V]) Range(f func(K, V) bool) {
+	m.entries.Range(func(key K, entry lwwEntry[V]) bool {
+		return entry.deleted || f(key, entry.value)
+	})
+}
+
+// ToMap returns the items of m, not including deleted items, as a Map.
+func (m *LWWMap[K, V]) ToMap() *Map[K, V] {
+	return MapValues(m.entries.Filter(func(_ K, entry lwwEntry[V]) bool { return !entry.deleted }),
+		func(_ K, entry lwwEntry[V]) V { return entry.value })
+}
@@ -0,0 +1,47 @@
+package peds
+
+import "testing"
+
+func TestLWWMapStoreAndDelete(t *testing.T) {
+	m := NewLWWMap[string, int]("a")
+	m = m.Store("x", 1, 10)
+	m = m.Store("x", 2, 5)
+	value, ok := m.Load("x")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, value)
+
+	m = m.Delete("x", 20).Store("y", 3, 1)
+	_, ok = m.Load("x")
+	assertEqualBool(t, false, ok)
+	assertEqual(t, 1, m.Len())
+
+	m = m.Store("x", 4, 30)
+	assertEqual(t, 2, m.Len())
+	assertEqual(t, 2, m.ToMap().Len())
+}
+
+func TestLWWMapMergeConverges(t *testing.T) {
+	a := NewLWWMap[string, string]("a").Store("k1", "a1", 1).Store("k2", "a2", 5).Store("k3", "a3", 7)
+	b := NewLWWMap[string, string]("b").Store("k1", "b1", 2).Store("k2", "b2", 5).Delete("k3", 8)
+	c := NewLWWMap[string, string]("c").Store("k4", "c4", 1).Delete("k1", 1)
+
+	results := []*LWWMap[string, string]{
+		a.Merge(b).Merge(c),
+		c.Merge(b).Merge(a),
+		b.Merge(a.Merge(c)),
+		a.Merge(b).Merge(c).Merge(b),
+	}
+
+	for _, r := range results {
+		assertEqual(t, 3, r.Len())
+		expected := map[string]string{"k1": "b1", "k2": "b2", "k4": "c4"}
+		assertEqualBool(t, true, MapEqual(NewMapFromNativeMap(expected), r.ToMap()))
+		count := 0
+		r.Range(func(key, value string) bool {
+			assertEqualString(t, expected[key], value)
+			count++
+			return true
+		})
+		assertEqual(t, 3, count)
+	}
+}
@@ -1,41 +1,173 @@
 package peds
 
 import (
+	"fmt"
+	"iter"
 	"math"
+	"math/bits"
+	"sort"
 )
 
 const upperMapLoadFactor float64 = 8.0
 const lowerMapLoadFactor float64 = 2.0
 const initialMapLoadFactor float64 = (upperMapLoadFactor + lowerMapLoadFactor) / 2
 
-type MapItem[K comparable, V any] struct {
+// MapOptions controls when a Map or a MapFunc changes its number of buckets. The zero value gives the
+// default behaviour.
+type MapOptions struct {
+	// UpperLoadFactor is the average number of items per bucket above which the map grows.
+	// Zero means the default of 8.
+	UpperLoadFactor float64
+
+	// LowerLoadFactor is the average number of items per bucket below which the map shrinks.
+	// Zero means the default of 2.
+	LowerLoadFactor float64
+
+	// DisableShrink prevents the map from shrinking when items are deleted. This avoids
+	// repeated restructuring in delete heavy workloads where the map size oscillates, at the
+	// cost of keeping the buckets of the largest size reached.
+	DisableShrink bool
+}
+
+func (o *MapOptions) upper() float64 {
+	if o == nil || o.UpperLoadFactor == 0 {
+		return upperMapLoadFactor
+	}
+
+	return o.UpperLoadFactor
+}
+
+func (o *MapOptions) lower() float64 {
+	if o == nil || o.LowerLoadFactor == 0 {
+		return lowerMapLoadFactor
+	}
+
+	return o.LowerLoadFactor
+}
+
+func (o *MapOptions) initial() float64 {
+	return (o.upper() + o.lower()) / 2
+}
+
+// overloaded reports whether itemCount items in bucketCount buckets exceeds the upper load
+// factor.
+func (o *MapOptions) overloaded(itemCount, bucketCount int) bool {
+	return float64(itemCount) >= float64(bucketCount)*o.upper()
+}
+
+// underloaded reports whether itemCount items in bucketCount buckets is below the lower load
+// factor and the map should shrink.
+func (o *MapOptions) underloaded(itemCount, bucketCount int) bool {
+	return (o == nil || !o.DisableShrink) && bucketCount > 1 && float64(itemCount) < float64(bucketCount)*o.lower()
+}
+
+type MapItem[K any, V any] struct {
 	Key   K
 	Value V
 }
 
-type privateItemBucket[K comparable, V any] []MapItem[K, V]
+type privateItemBucket[K any, V any] []MapItem[K, V]
 
 // Helper type used during map creation and reallocation
 type privateItemBuckets[K comparable, V any] struct {
 	buckets []privateItemBucket[K, V]
 	length  int
+	hasher  Hasher[K]
+	options *MapOptions
+
+	// Only set for the buckets of a MapBuilder, see newScratchItemBuckets. table holds
+	// buckets and spare holds emptied buckets, discarded by rehashing or deletes, that are
+	// reused when new buckets are needed.
+	table *[]privateItemBucket[K, V]
+	spare *[]privateItemBucket[K, V]
 }
 
-func newPrivateItemBuckets[K comparable, V any](itemCount int) *privateItemBuckets[K, V] {
-	size := int(float64(itemCount)/initialMapLoadFactor) + 1
+func newPrivateItemBuckets[K comparable, V any](itemCount int, hasher Hasher[K], options *MapOptions) *privateItemBuckets[K, V] {
+	size := int(float64(itemCount)/options.initial()) + 1
 
 	// TODO: The need for parenthesis below are slightly surprising
 	buckets := make([](privateItemBucket[K, V]), size)
-	return &privateItemBuckets[K, V]{buckets: buckets}
+	return &privateItemBuckets[K, V]{buckets: buckets, hasher: hasher, options: options}
+}
+
+// newScratchItemBuckets is like newPrivateItemBuckets but the slice holding the buckets is
+// recycled from earlier builds, see getScratch, and new buckets are taken from spare when
+// possible. The slice should be handed back through release when it is no longer used.
+func newScratchItemBuckets[K comparable, V any](itemCount int, hasher Hasher[K], options *MapOptions, spare *[]privateItemBucket[K, V]) *privateItemBuckets[K, V] {
+	size := int(float64(itemCount)/options.initial()) + 1
+	table := getScratch[privateItemBucket[K, V]](size)
+	return &privateItemBuckets[K, V]{buckets: (*table)[:size], hasher: hasher, options: options, table: table, spare: spare}
+}
+
+// release hands the slice holding the buckets back for reuse by later builds. If discard is
+// set the buckets are no longer used, they are emptied and added to spare.
+func (b *privateItemBuckets[K, V]) release(discard bool) {
+	if discard {
+		for _, bucket := range b.buckets {
+			if bucket != nil {
+				clear(bucket)
+				*b.spare = append(*b.spare, bucket[:0])
+			}
+		}
+	}
+
+	*b.table = b.buckets
+	putScratch(b.table)
+}
+
+// newBucket returns an empty bucket, reused from spare if possible.
+func (b *privateItemBuckets[K, V]) newBucket() privateItemBucket[K, V] {
+	if b.spare != nil && len(*b.spare) > 0 {
+		last := len(*b.spare) - 1
+		bucket := (*b.spare)[last]
+		(*b.spare)[last] = nil
+		*b.spare = (*b.spare)[:last]
+		return bucket
+	}
+
+	return make(privateItemBucket[K, V], 0, int(math.Max(b.options.initial(), 1.0)))
 }
 
 type Map[K comparable, V any] struct {
 	backingVector *Vector[privateItemBucket[K, V]]
 	len           int
+
+	// Custom hasher, nil if the built in hash function is used
+	hasher Hasher[K]
+
+	// Custom options, nil if the defaults are used
+	options *MapOptions
+}
+
+// bucketPos returns the position of the bucket for hash in a map with bucketCount buckets.
+// Buckets are addressed using linear hashing. With 2^level <= bucketCount < 2^(level+1) the
+// low level bits of the hash select the bucket, except for the first bucketCount-2^level
+// buckets which have been split, for those one more bit is used. Adding one bucket hence only
+// requires the items of a single bucket to be moved, see tableWithSplitBucket.
+func bucketPos(hash uint32, bucketCount int) int {
+	level := bits.Len(uint(bucketCount)) - 1
+	pos := int(hash & (1<<level - 1))
+	if pos < bucketCount-1<<level {
+		pos = int(hash & (1<<(level+1) - 1))
+	}
+
+	return pos
+}
+
+func (b *privateItemBuckets[K, V]) pos(key K) int {
+	return bucketPos(hashKey(b.hasher, key), len(b.buckets))
+}
+
+func (b *privateItemBuckets[K, V]) toMap() *Map[K, V] {
+	return &Map[K, V]{backingVector: NewVector(b.buckets...), len: b.length, hasher: b.hasher, options: b.options}
 }
 
 func (b *privateItemBuckets[K, V]) AddItem(item MapItem[K, V]) {
-	ix := int(uint64(genericHash(item.Key)) % uint64(len(b.buckets)))
+	b.addItemAt(b.pos(item.Key), item)
+}
+
+// addItemAt adds item to the bucket at position ix which must be the position of its key.
+func (b *privateItemBuckets[K, V]) addItemAt(ix int, item MapItem[K, V]) {
 	bucket := b.buckets[ix]
 	if bucket != nil {
 		// Hash collision, merge with existing bucket
@@ -49,12 +181,45 @@ func (b *privateItemBuckets[K, V]) AddItem(item MapItem[K, V]) {
 		b.buckets[ix] = append(bucket, MapItem[K, V]{Key: item.Key, Value: item.Value})
 		b.length++
 	} else {
-		bucket := make(privateItemBucket[K, V], 0, int(math.Max(initialMapLoadFactor, 1.0)))
-		b.buckets[ix] = append(bucket, item)
+		b.buckets[ix] = append(b.newBucket(), item)
 		b.length++
 	}
 }
 
+func (b *privateItemBuckets[K, V]) RemoveItem(key K) {
+	ix := b.pos(key)
+	bucket := b.buckets[ix]
+	for keyIx, bItem := range bucket {
+		if key == bItem.Key {
+			last := len(bucket) - 1
+			bucket[keyIx] = bucket[last]
+			bucket[last] = MapItem[K, V]{}
+			if last == 0 {
+				b.buckets[ix] = nil
+				if b.spare != nil {
+					*b.spare = append(*b.spare, bucket[:0])
+				}
+			} else {
+				b.buckets[ix] = bucket[:last]
+			}
+
+			b.length--
+			return
+		}
+	}
+}
+
+func (b *privateItemBuckets[K, V]) LoadItem(key K) (value V, ok bool) {
+	for _, item := range b.buckets[b.pos(key)] {
+		if item.Key == key {
+			return item.Value, true
+		}
+	}
+
+	var zeroValue V
+	return zeroValue, false
+}
+
 func (b *privateItemBuckets[K, V]) AddItemsFromMap(m *Map[K, V]) {
 	m.backingVector.Range(func(bucket privateItemBucket[K, V]) bool {
 		for _, item := range bucket {
@@ -64,27 +229,79 @@ func (b *privateItemBuckets[K, V]) AddItemsFromMap(m *Map[K, V]) {
 	})
 }
 
-func newMap[K comparable, V any](items []MapItem[K, V]) *Map[K, V] {
-	buckets := newPrivateItemBuckets[K, V](len(items))
+func newMap[K comparable, V any](items []MapItem[K, V], hasher Hasher[K], options *MapOptions) *Map[K, V] {
+	buckets := newPrivateItemBuckets[K, V](len(items), hasher, options)
 	for _, item := range items {
 		buckets.AddItem(item)
 	}
-	return &Map[K, V]{backingVector: NewVector(buckets.buckets...), len: buckets.length}
+	return buckets.toMap()
 }
 
 // NewMap returns a new map containing all items in items.
 func NewMap[K comparable, V any](items ...MapItem[K, V]) *Map[K, V] {
-	return newMap(items)
+	return newMap(items, nil, nil)
+}
+
+// NewMapWithHasher returns a new map containing all items in items that uses hasher to hash
+// keys. All maps derived from the returned map use the same hasher.
+func NewMapWithHasher[K comparable, V any](hasher Hasher[K], items ...MapItem[K, V]) *Map[K, V] {
+	return newMap(items, hasher, nil)
+}
+
+// NewMapWithCapacity returns a new, empty, map with buckets sized for capacity items. Storing
+// up to capacity items does not require the map to grow.
+func NewMapWithCapacity[K comparable, V any](capacity int) *Map[K, V] {
+	return newPrivateItemBuckets[K, V](max(capacity, 0), nil, nil).toMap()
+}
+
+// NewMapWithOptions returns a new map containing all items in items that grows and shrinks as
+// specified by options. All maps derived from the returned map use the same options.
+func NewMapWithOptions[K comparable, V any](options MapOptions, items ...MapItem[K, V]) *Map[K, V] {
+	assertMapOptionsOk(options)
+	return newMap(items, nil, &options)
+}
+
+func assertMapOptionsOk(options MapOptions) {
+	if options.upper() < 1 || options.lower() < 0 || options.lower() >= options.upper() {
+		panic(fmt.Sprintf("Invalid map load factors, lower=%g, upper=%g (must satisfy 0 <= lower < upper, 1 <= upper)",
+			options.lower(), options.upper()))
+	}
 }
 
 // NewMapFromNativeMap returns a new Map containing all items in m.
 func NewMapFromNativeMap[K comparable, V any](m map[K]V) *Map[K, V] {
-	buckets := newPrivateItemBuckets[K, V](len(m))
+	buckets := newPrivateItemBuckets[K, V](len(m), nil, nil)
 	for key, value := range m {
 		buckets.AddItem(MapItem[K, V]{Key: key, Value: value})
 	}
 
-	return &Map[K, V]{backingVector: NewVector(buckets.buckets...), len: buckets.length}
+	return buckets.toMap()
+}
+
+// NewMapFromSlices returns a new Map where keys[i] is associated with values[i]. keys and values
+// must have the same length. If a key occurs more than once the last value wins.
+func NewMapFromSlices[K comparable, V any](keys []K, values []V) *Map[K, V] {
+	if len(keys) != len(values) {
+		panic(fmt.Sprintf("Key and value slices must have equal length, len(keys)=%d, len(values)=%d", len(keys), len(values)))
+	}
+
+	buckets := newPrivateItemBuckets[K, V](len(keys), nil, nil)
+	for i, key := range keys {
+		buckets.AddItem(MapItem[K, V]{Key: key, Value: values[i]})
+	}
+
+	return buckets.toMap()
+}
+
+// NewMapFromSeq2 returns a new Map containing all key/value pairs produced by seq. If a key
+// is produced more than once the last value wins.
+func NewMapFromSeq2[K comparable, V any](seq iter.Seq2[K, V]) *Map[K, V] {
+	b := NewMapBuilder[K, V]()
+	for key, value := range seq {
+		b.Set(key, value)
+	}
+
+	return b.Freeze()
 }
 
 // Len returns the number of items in m.
@@ -93,11 +310,35 @@ func (m *Map[K, V]) Len() int {
 }
 
 func (m *Map[K, V]) pos(key K) int {
-	return int(uint64(genericHash(key)) % uint64(m.backingVector.Len()))
+	return bucketPos(hashKey(m.hasher, key), m.backingVector.Len())
+}
+
+// table returns the buckets of m as a hashTable, for use with the functions shared with
+// MapFunc.
+func (m *Map[K, V]) table() hashTable[K, V] {
+	return hashTable[K, V]{buckets: m.backingVector, len: m.len, options: m.options}
+}
+
+// withTable returns a new Map[K, V] holding the items of t, using the hasher of m.
+func (m *Map[K, V]) withTable(t hashTable[K, V]) *Map[K, V] {
+	return &Map[K, V]{backingVector: t.buckets, len: t.len, hasher: m.hasher, options: t.options}
+}
+
+func (m *Map[K, V]) keys() comparableKeys[K] {
+	return comparableKeys[K]{hasher: m.hasher}
+}
+
+// sameBucketLayout returns true if items with the same key are guaranteed to be stored in
+// buckets at the same position in m and other.
+func (m *Map[K, V]) sameBucketLayout(other *Map[K, V]) bool {
+	// Custom hashers can't be safely compared, only maps using the built in hash function
+	// are known to hash keys the same way.
+	return m.backingVector.Len() == other.backingVector.Len() && m.hasher == nil && other.hasher == nil
 }
 
 // Load returns value identified by key. ok is set to true if key exists in the map, false otherwise.
 func (m *Map[K, V]) Load(key K) (value V, ok bool) {
+	// Compared to tableLoad, comparing keys using == directly makes lookups faster
 	bucket := m.backingVector.Get(m.pos(key))
 	if bucket != nil {
 		for _, item := range bucket {
@@ -111,74 +352,220 @@ func (m *Map[K, V]) Load(key K) (value V, ok bool) {
 	return zeroValue, false
 }
 
+// LoadOr returns the value identified by key, or def if key does not exist in the map.
+func (m *Map[K, V]) LoadOr(key K, def V) V {
+	if value, ok := m.Load(key); ok {
+		return value
+	}
+
+	return def
+}
+
+// Contains reports whether key exists in the map.
+func (m *Map[K, V]) Contains(key K) bool {
+	for _, item := range m.backingVector.Get(m.pos(key)) {
+		if item.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Store returns a new Map[K, V] containing value identified by key.
 func (m *Map[K, V]) Store(key K, value V) *Map[K, V] {
-	// Grow backing vector if load factor is too high
-	if m.Len() >= m.backingVector.Len()*int(upperMapLoadFactor) {
-		buckets := newPrivateItemBuckets[K, V](m.Len() + 1)
-		buckets.AddItemsFromMap(m)
-		buckets.AddItem(MapItem[K, V]{Key: key, Value: value})
-		return &Map[K, V]{backingVector: NewVector[privateItemBucket[K, V]](buckets.buckets...), len: buckets.length}
+	return m.Update(key, func(V, bool) V { return value })
+}
+
+// Update returns a new Map[K, V] where the value identified by key has been replaced by the
+// result of calling f with the current value. ok is set to true if key exists in the map,
+// otherwise f is called with the zero value and ok set to false.
+func (m *Map[K, V]) Update(key K, f func(value V, ok bool) V) *Map[K, V] {
+	return m.withTable(tableUpdate(m.table(), m.keys(), key, f))
+}
+
+// Delete returns a new Map[K, V] without the element identified by key.
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	_, _, result := m.LoadAndDelete(key)
+	return result
+}
+
+// LoadAndDelete returns the value identified by key together with a new Map[K, V] without
+// that element. ok is set to true if key exists in the map, false otherwise.
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, ok bool, result *Map[K, V]) {
+	value, ok, t := tableLoadAndDelete(m.table(), m.keys(), key)
+	if !ok {
+		return value, false, m
 	}
 
-	pos := m.pos(key)
-	bucket := m.backingVector.Get(pos)
-	if bucket != nil {
-		for ix, item := range bucket {
-			if item.Key == key {
-				// Overwrite existing item
-				newBucket := make(privateItemBucket[K, V], len(bucket))
-				copy(newBucket, bucket)
-				newBucket[ix] = MapItem[K, V]{Key: key, Value: value}
-				return &Map[K, V]{backingVector: m.backingVector.Set(pos, newBucket), len: m.len}
-			}
+	return value, true, m.withTable(t)
+}
+
+// CompareAndSwapFunc returns a new Map[K, V] where the value identified by key has been replaced
+// by value, provided that key exists and pred returns true for the current value. swapped reports
+// whether the value was replaced, if not m is returned as is.
+func (m *Map[K, V]) CompareAndSwapFunc(key K, pred func(V) bool, value V) (result *Map[K, V], swapped bool) {
+	current, ok := m.Load(key)
+	if !ok || !pred(current) {
+		return m, false
+	}
+
+	return m.Store(key, value), true
+}
+
+// CompareAndDeleteFunc returns a new Map[K, V] without the element identified by key, provided
+// that key exists and pred returns true for its value. deleted reports whether the element was
+// removed, if not m is returned as is.
+func (m *Map[K, V]) CompareAndDeleteFunc(key K, pred func(V) bool) (result *Map[K, V], deleted bool) {
+	current, ok := m.Load(key)
+	if !ok || !pred(current) {
+		return m, false
+	}
+
+	return m.Delete(key), true
+}
+
+// MapCompareAndSwap returns a new Map[K, V] where the value identified by key has been replaced
+// by new, provided that the current value is equal to old. Like for sync.Map it is useful for
+// optimistic concurrency, for example when updating a Map held by a Ref.
+func MapCompareAndSwap[K, V comparable](m *Map[K, V], key K, old, new V) (result *Map[K, V], swapped bool) {
+	return m.CompareAndSwapFunc(key, func(v V) bool { return v == old }, new)
+}
+
+// MapCompareAndDelete returns a new Map[K, V] without the element identified by key, provided
+// that its value is equal to old.
+func MapCompareAndDelete[K, V comparable](m *Map[K, V], key K, old V) (result *Map[K, V], deleted bool) {
+	return m.CompareAndDeleteFunc(key, func(v V) bool { return v == old })
+}
+
+// compacted returns m or, if m occupies excessive space, a new Map[K, V] with fewer buckets
+// containing the same items, see tableCompacted.
+func (m *Map[K, V]) compacted() *Map[K, V] {
+	t, changed := tableCompacted(m.table(), m.keys())
+	if !changed {
+		return m
+	}
+
+	return m.withTable(t)
+}
+
+// Merge returns a new Map[K, V] containing all items in m and other. For keys present in both
+// maps the value is determined by calling resolve with the key, the value in m and the value in
+// other. Buckets that are shared between m and other are reused as is without calling resolve.
+func (m *Map[K, V]) Merge(other *Map[K, V], resolve func(key K, a, b V) V) *Map[K, V] {
+	if other.Len() == 0 || m == other {
+		return m
+	}
+
+	if m.Len() == 0 {
+		return other
+	}
+
+	if !m.sameBucketLayout(other) {
+		result := m
+		other.Range(func(key K, b V) bool {
+			result = result.Update(key, func(a V, ok bool) V {
+				if ok {
+					return resolve(key, a, b)
+				}
+
+				return b
+			})
+			return true
+		})
+
+		return result
+	}
+
+	// Same bucket count, items with the same key are in the same bucket
+	backingVector, length := m.backingVector, m.len
+	for pos, otherBucket := range other.backingVector.All() {
+		bucket := backingVector.Get(pos)
+		if len(otherBucket) == 0 || (len(bucket) > 0 && &bucket[0] == &otherBucket[0]) {
+			continue
 		}
 
-		// Add new item to bucket
-		newBucket := make(privateItemBucket[K, V], len(bucket), len(bucket)+1)
+		newBucket := make(privateItemBucket[K, V], len(bucket), len(bucket)+len(otherBucket))
 		copy(newBucket, bucket)
-		newBucket = append(newBucket, MapItem[K, V]{Key: key, Value: value})
-		return &Map[K, V]{backingVector: m.backingVector.Set(pos, newBucket), len: m.len + 1}
+		for _, otherItem := range otherBucket {
+			found := false
+			for ix, item := range bucket {
+				if item.Key == otherItem.Key {
+					newBucket[ix].Value = resolve(item.Key, item.Value, otherItem.Value)
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				newBucket = append(newBucket, otherItem)
+				length++
+			}
+		}
+
+		backingVector = backingVector.Set(pos, newBucket)
+	}
+
+	result := &Map[K, V]{backingVector: backingVector, len: length, hasher: m.hasher, options: m.options}
+	if m.options.overloaded(length, backingVector.Len()) {
+		buckets := newPrivateItemBuckets[K, V](length, m.hasher, m.options)
+		buckets.AddItemsFromMap(result)
+		return buckets.toMap()
 	}
 
-	item := MapItem[K, V]{Key: key, Value: value}
-	newBucket := privateItemBucket[K, V]{item}
-	return &Map[K, V]{backingVector: m.backingVector.Set(pos, newBucket), len: m.len + 1}
+	return result
 }
 
-// Delete returns a new Map[K, V] without the element identified by key.
-func (m *Map[K, V]) Delete(key K) *Map[K, V] {
-	pos := m.pos(key)
-	bucket := m.backingVector.Get(pos)
-	if bucket != nil {
-		newBucket := make(privateItemBucket[K, V], 0)
+// Filter returns a new Map[K, V] containing only the items for which pred returns true.
+// Buckets where all items are kept are shared with m.
+func (m *Map[K, V]) Filter(pred func(K, V) bool) *Map[K, V] {
+	backingVector, length := m.backingVector, m.len
+	for pos, bucket := range m.backingVector.All() {
+		kept := make(privateItemBucket[K, V], 0, len(bucket))
 		for _, item := range bucket {
-			if item.Key != key {
-				newBucket = append(newBucket, item)
+			if pred(item.Key, item.Value) {
+				kept = append(kept, item)
 			}
 		}
 
-		removedItemCount := len(bucket) - len(newBucket)
-		if removedItemCount == 0 {
-			return m
+		if len(kept) == len(bucket) {
+			continue
 		}
 
-		if len(newBucket) == 0 {
-			newBucket = nil
+		if len(kept) == 0 {
+			kept = nil
 		}
 
-		newMap := &Map[K, V]{backingVector: m.backingVector.Set(pos, newBucket), len: m.len - removedItemCount}
-		if newMap.backingVector.Len() > 1 && newMap.Len() < newMap.backingVector.Len()*int(lowerMapLoadFactor) {
-			// Shrink backing vector if needed to avoid occupying excessive space
-			buckets := newPrivateItemBuckets[K, V](newMap.Len())
-			buckets.AddItemsFromMap(newMap)
-			return &Map[K, V]{backingVector: NewVector(buckets.buckets...), len: buckets.length}
-		}
+		length -= len(bucket) - len(kept)
+		backingVector = backingVector.Set(pos, kept)
+	}
 
-		return newMap
+	if length == m.len {
+		return m
 	}
 
-	return m
+	return (&Map[K, V]{backingVector: backingVector, len: length, hasher: m.hasher, options: m.options}).compacted()
+}
+
+// MapValues returns a new Map[K, V2] containing all keys in m associated with the result of
+// calling f with the key and the corresponding value in m.
+func MapValues[K comparable, V1, V2 any](m *Map[K, V1], f func(K, V1) V2) *Map[K, V2] {
+	// The keys are the same so the bucket layout of m can be kept
+	buckets := make([]privateItemBucket[K, V2], 0, m.backingVector.Len())
+	m.backingVector.Range(func(bucket privateItemBucket[K, V1]) bool {
+		var newBucket privateItemBucket[K, V2]
+		if bucket != nil {
+			newBucket = make(privateItemBucket[K, V2], len(bucket))
+			for ix, item := range bucket {
+				newBucket[ix] = MapItem[K, V2]{Key: item.Key, Value: f(item.Key, item.Value)}
+			}
+		}
+
+		buckets = append(buckets, newBucket)
+		return true
+	})
+
+	return &Map[K, V2]{backingVector: NewVector(buckets...), len: m.len, hasher: m.hasher, options: m.options}
 }
 
 // Range calls f repeatedly passing it each key and value as argument until either
@@ -194,6 +581,138 @@ func (m *Map[K, V]) Range(f func(K, V) bool) {
 	})
 }
 
+// FindKey returns the first key in the iteration order of m for which pred, called with the
+// key and its value, returns true. ok is set to false if there is no such key.
+func (m *Map[K, V]) FindKey(pred func(K, V) bool) (key K, ok bool) {
+	m.Range(func(k K, v V) bool {
+		if pred(k, v) {
+			key, ok = k, true
+			return false
+		}
+
+		return true
+	})
+
+	return key, ok
+}
+
+// RangeIndexed works like Range but also passes f the ordinal of each item in the iteration,
+// starting at zero.
+func (m *Map[K, V]) RangeIndexed(f func(int, K, V) bool) {
+	i := 0
+	m.Range(func(key K, value V) bool {
+		ok := f(i, key, value)
+		i++
+		return ok
+	})
+}
+
+// RangeSorted works like Range but visits the items in ascending key order as defined by less.
+// All items are collected and sorted before the first call to f.
+func (m *Map[K, V]) RangeSorted(less func(a, b K) bool, f func(K, V) bool) {
+	items := make([]MapItem[K, V], 0, m.len)
+	m.Range(func(key K, value V) bool {
+		items = append(items, MapItem[K, V]{Key: key, Value: value})
+		return true
+	})
+
+	sort.Slice(items, func(i, j int) bool { return less(items[i].Key, items[j].Key) })
+	for _, item := range items {
+		if !f(item.Key, item.Value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over the keys and values of m. The iteration order is not specified.
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return m.Range
+}
+
+// Keys returns an iterator over the keys of m. The iteration order is not specified.
+func (m *Map[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.Range(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+// Values returns an iterator over the values of m. The iteration order is not specified.
+func (m *Map[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.Range(func(_ K, value V) bool {
+			return yield(value)
+		})
+	}
+}
+
+// EqualFunc reports whether m and other contain the same keys associated with equal values,
+// using eq to compare values. Buckets shared between m and other are not compared item by item.
+func (m *Map[K, V]) EqualFunc(other *Map[K, V], eq func(V, V) bool) bool {
+	return mapsEqual(m, other, eq, false)
+}
+
+// MapEqual reports whether a and b contain the same keys associated with equal values. Like
+// for VectorEqual, buckets whose cached content hashes, see ContentHash, are equal are not
+// compared item by item.
+func MapEqual[K, V comparable](a, b *Map[K, V]) bool {
+	return mapsEqual(a, b, func(x, y V) bool { return x == y }, true)
+}
+
+// mapsEqual compares m and other, see sameContent for useHashes.
+func mapsEqual[K comparable, V any](m, other *Map[K, V], eq func(V, V) bool, useHashes bool) bool {
+	if m == other {
+		return true
+	}
+
+	if m.len != other.len {
+		return false
+	}
+
+	if m.sameBucketLayout(other) {
+		// Same bucket count, items with the same key are in the same bucket
+		return vectorsEqual(m.backingVector, other.backingVector, func(a, b privateItemBucket[K, V]) bool {
+			return bucketsEqual(a, b, eq)
+		}, useHashes)
+	}
+
+	equal := true
+	m.Range(func(key K, value V) bool {
+		otherValue, ok := other.Load(key)
+		equal = ok && eq(value, otherValue)
+		return equal
+	})
+
+	return equal
+}
+
+func bucketsEqual[K comparable, V any](a, b privateItemBucket[K, V], eq func(V, V) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	if len(a) == 0 || &a[0] == &b[0] {
+		return true
+	}
+
+	for _, aItem := range a {
+		found := false
+		for _, bItem := range b {
+			if aItem.Key == bItem.Key {
+				found = eq(aItem.Value, bItem.Value)
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
 // ToNativeMap returns a native Go map containing all elements of m.
 func (m *Map[K, V]) ToNativeMap() map[K]V {
 	result := make(map[K]V)
@@ -204,3 +723,90 @@ func (m *Map[K, V]) ToNativeMap() map[K]V {
 
 	return result
 }
+
+/////////////////
+/// Builder /////
+/////////////////
+
+// MapBuilder is a transient, mutable, map that can be used to efficiently construct a Map
+// from a large number of updates. Updates are applied in place and the result is turned
+// into an immutable Map by calling Freeze.
+type MapBuilder[K comparable, V any] struct {
+	buckets *privateItemBuckets[K, V]
+}
+
+// NewMapBuilder returns a new, empty, MapBuilder.
+func NewMapBuilder[K comparable, V any]() *MapBuilder[K, V] {
+	return NewMapBuilderWithCapacity[K, V](0)
+}
+
+// NewMapBuilderWithCapacity returns a new, empty, MapBuilder with buckets sized for capacity
+// items.
+func NewMapBuilderWithCapacity[K comparable, V any](capacity int) *MapBuilder[K, V] {
+	return &MapBuilder[K, V]{buckets: newScratchItemBuckets[K, V](max(capacity, 0), nil, nil, getFreeList[privateItemBucket[K, V]]())}
+}
+
+func (b *MapBuilder[K, V]) assertNotFrozen() {
+	if b.buckets == nil {
+		panic("MapBuilder used after Freeze")
+	}
+}
+
+// Len returns the number of items in b.
+func (b *MapBuilder[K, V]) Len() int {
+	b.assertNotFrozen()
+	return b.buckets.length
+}
+
+// Load returns value identified by key. ok is set to true if key exists in the builder, false otherwise.
+func (b *MapBuilder[K, V]) Load(key K) (value V, ok bool) {
+	b.assertNotFrozen()
+	return b.buckets.LoadItem(key)
+}
+
+// Set stores value identified by key in b, replacing any previous value.
+func (b *MapBuilder[K, V]) Set(key K, value V) {
+	b.assertNotFrozen()
+	if b.buckets.options.overloaded(b.buckets.length, len(b.buckets.buckets)) {
+		b.rehash(2 * b.buckets.length)
+	}
+
+	b.buckets.AddItem(MapItem[K, V]{Key: key, Value: value})
+}
+
+// Delete removes the item identified by key from b, if present.
+func (b *MapBuilder[K, V]) Delete(key K) {
+	b.assertNotFrozen()
+	b.buckets.RemoveItem(key)
+}
+
+func (b *MapBuilder[K, V]) rehash(itemCount int) {
+	buckets := newScratchItemBuckets[K, V](itemCount, b.buckets.hasher, b.buckets.options, b.buckets.spare)
+	for _, bucket := range b.buckets.buckets {
+		for _, item := range bucket {
+			buckets.AddItem(item)
+		}
+	}
+
+	// The items have been moved to the new buckets, the old ones can be reused
+	b.buckets.release(true)
+	b.buckets = buckets
+}
+
+// Freeze returns a Map containing all items in b. The builder must not be used after
+// it has been frozen.
+func (b *MapBuilder[K, V]) Freeze() *Map[K, V] {
+	b.assertNotFrozen()
+	if b.buckets.options.underloaded(b.buckets.length, len(b.buckets.buckets)) {
+		// Lots of deletes, compact before handing over the buckets
+		b.rehash(b.buckets.length)
+	}
+
+	// The buckets are copied into the backing vector of the map, the slice holding them
+	// can be reused. Spare buckets are handed over to later builders.
+	m := b.buckets.toMap()
+	b.buckets.release(false)
+	putFreeList(b.buckets.spare)
+	b.buckets = nil
+	return m
+}
@@ -2,6 +2,10 @@ package peds
 
 import (
 	"fmt"
+	"maps"
+	"math/bits"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -113,6 +117,220 @@ func TestLoadAndDeleteNonExistingItem(t *testing.T) {
 	}
 }
 
+func TestLoadAndDelete(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 1).Store("b", 2)
+	v, ok, m2 := m.LoadAndDelete("a")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, v)
+	assertEqual(t, 1, m2.Len())
+	assertEqual(t, 2, m.Len())
+	_, ok = m2.Load("a")
+	assertEqualBool(t, false, ok)
+
+	v, ok, m3 := m2.LoadAndDelete("a")
+	assertEqualBool(t, false, ok)
+	assertEqual(t, 0, v)
+	if m2 != m3 {
+		t.Errorf("m2 and m3 are not the same object: %p != %p", m2, m3)
+	}
+}
+
+func TestMapCompareAndSwap(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 1)
+
+	m2, swapped := MapCompareAndSwap(m, "a", 2, 3)
+	assertEqualBool(t, false, swapped)
+	if m != m2 {
+		t.Errorf("m and m2 are not the same object: %p != %p", m, m2)
+	}
+
+	m2, swapped = MapCompareAndSwap(m, "a", 1, 3)
+	assertEqualBool(t, true, swapped)
+	v, _ := m2.Load("a")
+	assertEqual(t, 3, v)
+	v, _ = m.Load("a")
+	assertEqual(t, 1, v)
+
+	m2, swapped = MapCompareAndSwap(m, "b", 0, 3)
+	assertEqualBool(t, false, swapped)
+	assertEqual(t, 1, m2.Len())
+}
+
+func TestMapCompareAndDelete(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 1).Store("b", 2)
+
+	m2, deleted := MapCompareAndDelete(m, "a", 2)
+	assertEqualBool(t, false, deleted)
+	assertEqual(t, 2, m2.Len())
+
+	m2, deleted = MapCompareAndDelete(m, "a", 1)
+	assertEqualBool(t, true, deleted)
+	assertEqual(t, 1, m2.Len())
+	_, ok := m2.Load("a")
+	assertEqualBool(t, false, ok)
+
+	m2, deleted = m.CompareAndDeleteFunc("b", func(v int) bool { return v > 1 })
+	assertEqualBool(t, true, deleted)
+	assertEqual(t, 1, m2.Len())
+}
+
+func TestMapCompareAndSwapFunc(t *testing.T) {
+	m := NewMap[string, []int]().Store("a", []int{1})
+	m2, swapped := m.CompareAndSwapFunc("a", func(v []int) bool { return len(v) == 1 }, []int{1, 2})
+	assertEqualBool(t, true, swapped)
+	v, _ := m2.Load("a")
+	assertEqual(t, 2, len(v))
+
+	_, swapped = m2.CompareAndSwapFunc("a", func(v []int) bool { return len(v) == 1 }, nil)
+	assertEqualBool(t, false, swapped)
+}
+
+func TestMapLoadOr(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 1)
+	assertEqual(t, 1, m.LoadOr("a", 10))
+	assertEqual(t, 10, m.LoadOr("b", 10))
+}
+
+func TestMapContains(t *testing.T) {
+	m := NewMap[string, int]().Store("a", 0)
+	assertEqualBool(t, true, m.Contains("a"))
+	assertEqualBool(t, false, m.Contains("b"))
+	assertEqualBool(t, false, m.Delete("a").Contains("a"))
+	assertEqualBool(t, false, NewMap[string, int]().Contains("a"))
+}
+
+func TestMapFindKey(t *testing.T) {
+	m := NewMap[string, int]()
+	for i := 0; i < 100; i++ {
+		m = m.Store(fmt.Sprintf("k%d", i), i)
+	}
+
+	key, ok := m.FindKey(func(_ string, v int) bool { return v == 42 })
+	assertEqualBool(t, true, ok)
+	assertEqualString(t, "k42", key)
+
+	calls := 0
+	m.FindKey(func(string, int) bool {
+		calls++
+		return true
+	})
+	assertEqual(t, 1, calls)
+
+	_, ok = m.FindKey(func(_ string, v int) bool { return v < 0 })
+	assertEqualBool(t, false, ok)
+	_, ok = NewMap[string, int]().FindKey(func(string, int) bool { return true })
+	assertEqualBool(t, false, ok)
+}
+
+func TestUpdate(t *testing.T) {
+	hits := func(v int, ok bool) int {
+		if !ok {
+			return 100
+		}
+
+		return v + 1
+	}
+
+	m := NewMap[string, int]().Update("hits", hits)
+	v, _ := m.Load("hits")
+	assertEqual(t, 100, v)
+
+	m2 := m.Update("hits", hits).Update("hits", hits)
+	v, _ = m2.Load("hits")
+	assertEqual(t, 102, v)
+	assertEqual(t, 1, m2.Len())
+
+	v, _ = m.Load("hits")
+	assertEqual(t, 100, v)
+
+	// Updates that grow the backing vector
+	for i := 0; i < 200; i++ {
+		m2 = m2.Update(fmt.Sprintf("%d", i%50), hits)
+	}
+
+	assertEqual(t, 51, m2.Len())
+	v, _ = m2.Load("7")
+	assertEqual(t, 103, v)
+}
+
+func TestMerge(t *testing.T) {
+	sum := func(_ string, a, b int) int { return a + b }
+	m1 := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2})
+	m2 := NewMapFromNativeMap(map[string]int{"b": 10, "c": 20})
+	m3 := m1.Merge(m2, sum)
+	assertEqual(t, 3, m3.Len())
+	assertEqualBool(t, true, MapEqual(m3, NewMapFromNativeMap(map[string]int{"a": 1, "b": 12, "c": 20})))
+
+	// Inputs are unchanged
+	assertEqual(t, 2, m1.Len())
+	v, _ := m1.Load("b")
+	assertEqual(t, 2, v)
+
+	assertEqualBool(t, true, m1 == m1.Merge(NewMap[string, int](), sum))
+	assertEqualBool(t, true, m2 == NewMap[string, int]().Merge(m2, sum))
+}
+
+func TestMergeLarge(t *testing.T) {
+	b1, b2 := NewMapBuilder[int, int](), NewMapBuilder[int, int]()
+	for i := 0; i < 1000; i++ {
+		b1.Set(i, i)
+		b2.Set(i+500, -i)
+	}
+
+	m1, m2 := b1.Freeze(), b2.Freeze()
+	result := m1.Merge(m2, func(key, a, b int) int { return a * 1000000 })
+	assertEqual(t, 1500, result.Len())
+	for i := 0; i < 1500; i++ {
+		v, ok := result.Load(i)
+		assertEqualBool(t, true, ok)
+		switch {
+		case i < 500:
+			assertEqual(t, i, v)
+		case i < 1000:
+			assertEqual(t, i*1000000, v)
+		default:
+			assertEqual(t, 500-i, v)
+		}
+	}
+
+	// Merging a derived map only touches the differing buckets
+	m3 := m1.Store(1, -1).Store(2000, 2000)
+	result = m1.Merge(m3, func(key, a, b int) int { return b })
+	assertEqualBool(t, true, MapEqual(m3, result))
+}
+
+func TestFilter(t *testing.T) {
+	b := NewMapBuilder[int, int]()
+	for i := 0; i < 1000; i++ {
+		b.Set(i, i)
+	}
+
+	m := b.Freeze()
+	even := m.Filter(func(key, _ int) bool { return key%2 == 0 })
+	assertEqual(t, 500, even.Len())
+	assertEqual(t, 1000, m.Len())
+	for i := 0; i < 1000; i++ {
+		_, ok := even.Load(i)
+		assertEqualBool(t, i%2 == 0, ok)
+	}
+
+	assertEqualBool(t, true, m == m.Filter(func(int, int) bool { return true }))
+	assertEqual(t, 0, m.Filter(func(int, int) bool { return false }).Len())
+	assertEqual(t, 2, m.Filter(func(key, _ int) bool { return key == 7 }).Store(8, 8).Len())
+}
+
+func TestMapValues(t *testing.T) {
+	m := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2})
+	m2 := MapValues(m, func(key string, value int) string { return fmt.Sprintf("%s%d", key, value) })
+	assertEqual(t, 2, m2.Len())
+	v, _ := m2.Load("b")
+	assertEqualString(t, "b2", v)
+
+	m2 = m2.Store("c", "c3").Delete("a")
+	assertEqual(t, 2, m2.Len())
+	assertEqual(t, 0, MapValues(NewMap[int, int](), func(int, int) bool { return true }).Len())
+}
+
 func TestRangeAllItems(t *testing.T) {
 	m := NewMap[string, int](MapItem[string, int]{Key: "a", Value: 1},
 		MapItem[string, int]{Key: "b", Value: 2},
@@ -125,6 +343,44 @@ func TestRangeAllItems(t *testing.T) {
 	assertEqual(t, 6, sum)
 }
 
+func TestRangeIndexedOrdinals(t *testing.T) {
+	m := NewMapFromNativeMap(map[int]int{1: 1, 2: 2, 3: 3, 4: 4})
+	ordinals := 0
+	m.RangeIndexed(func(i, key, value int) bool {
+		assertEqual(t, ordinals, i)
+		ordinals++
+		return i < 2
+	})
+	assertEqual(t, 3, ordinals)
+}
+
+func TestRangeSorted(t *testing.T) {
+	native := make(map[int]int)
+	for i := 0; i < 500; i++ {
+		native[(i*7919)%500] = i
+	}
+
+	m := NewMapFromNativeMap(native)
+	last := -1
+	count := 0
+	m.RangeSorted(func(a, b int) bool { return a < b }, func(key, value int) bool {
+		assertEqual(t, last+1, key)
+		assertEqual(t, native[key], value)
+		last = key
+		count++
+		return true
+	})
+	assertEqual(t, 500, count)
+
+	var keys []int
+	m.RangeSorted(func(a, b int) bool { return a > b }, func(key, value int) bool {
+		keys = append(keys, key)
+		return len(keys) < 3
+	})
+	assertEqual(t, 3, len(keys))
+	assertEqual(t, 497, keys[2])
+}
+
 func TestRangeStopOnKey(t *testing.T) {
 	m := NewMap[string, int](
 		MapItem[string, int]{Key: "a", Value: 1},
@@ -146,6 +402,99 @@ func TestRangeStopOnKey(t *testing.T) {
 	}
 }
 
+func TestAllIteratorOnMap(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	output := maps.Collect(NewMapFromNativeMap(input).All())
+	assertEqual(t, len(input), len(output))
+	for key, value := range input {
+		assertEqual(t, value, output[key])
+	}
+
+	count := 0
+	for range NewMapFromNativeMap(input).All() {
+		count++
+		break
+	}
+
+	assertEqual(t, 1, count)
+}
+
+func TestKeysAndValuesIteratorsOnMap(t *testing.T) {
+	m := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2, "c": 3})
+	keys := slices.Sorted(m.Keys())
+	assertEqual(t, 3, len(keys))
+	assertEqualString(t, "a", keys[0])
+	assertEqualString(t, "c", keys[2])
+
+	sum := 0
+	for value := range m.Values() {
+		sum += value
+	}
+
+	assertEqual(t, 6, sum)
+}
+
+func TestNewMapFromSlices(t *testing.T) {
+	m := NewMapFromSlices([]string{"a", "b", "a"}, []int{1, 2, 3})
+	assertEqual(t, 2, m.Len())
+	value, _ := m.Load("a")
+	assertEqual(t, 3, value)
+	value, _ = m.Load("b")
+	assertEqual(t, 2, value)
+	assertEqual(t, 0, NewMapFromSlices[int, int](nil, nil).Len())
+}
+
+func TestNewMapFromSlicesUnequalLength(t *testing.T) {
+	defer assertPanic(t, "Key and value slices must have equal length")
+	NewMapFromSlices([]int{1, 2}, []int{1})
+}
+
+func TestNewMapFromSeq2(t *testing.T) {
+	input := map[string]int{"a": 1, "b": 2, "c": 3}
+	m := NewMapFromSeq2(maps.All(input))
+	assertEqual(t, len(input), m.Len())
+	for key, value := range input {
+		v, ok := m.Load(key)
+		assertEqualBool(t, true, ok)
+		assertEqual(t, value, v)
+	}
+
+	// Later values for the same key win
+	m2 := NewMapFromSeq2(func(yield func(int, int) bool) {
+		_ = yield(1, 1) && yield(1, 2)
+	})
+	assertEqual(t, 1, m2.Len())
+	v, _ := m2.Load(1)
+	assertEqual(t, 2, v)
+}
+
+func TestMapEqual(t *testing.T) {
+	m1 := NewMapFromNativeMap(map[int]int{1: 1, 2: 2, 3: 3})
+	m2 := NewMap[int, int]().Store(3, 3).Store(2, 2).Store(1, 1)
+	assertEqualBool(t, true, MapEqual(m1, m1))
+	assertEqualBool(t, true, MapEqual(m1, m2))
+	assertEqualBool(t, false, MapEqual(m1, m2.Store(1, 2)))
+	assertEqualBool(t, false, MapEqual(m1, m2.Delete(1).Store(4, 1)))
+	assertEqualBool(t, false, MapEqual(m1, m2.Delete(1)))
+
+	large := NewMapBuilder[int, int]()
+	for i := 0; i < 1000; i++ {
+		large.Set(i, i)
+	}
+
+	m3 := large.Freeze()
+	m4 := m3.Store(5, -5)
+	assertEqualBool(t, false, MapEqual(m3, m4))
+	assertEqualBool(t, true, MapEqual(m3, m4.Store(5, 5)))
+}
+
+func TestMapEqualFunc(t *testing.T) {
+	m1 := NewMapFromNativeMap(map[int][]int{1: {1}})
+	m2 := NewMapFromNativeMap(map[int][]int{1: {1}})
+	assertEqualBool(t, true, m1.EqualFunc(m2, slices.Equal[[]int]))
+	assertEqualBool(t, false, m1.EqualFunc(m2.Store(1, []int{2}), slices.Equal[[]int]))
+}
+
 func TestLargeInsertLookupDelete(t *testing.T) {
 	// Is 50000 in original test but that seems crazy slow.
 	// More vector allocations, worse generic hash function, any other culprits?
@@ -170,6 +519,191 @@ func TestLargeInsertLookupDelete(t *testing.T) {
 	}
 }
 
+func TestStoreGrowsIncrementally(t *testing.T) {
+	m := NewMap[int, int]()
+	maxAllocs := 0.0
+	for i := 0; i < 20000; i++ {
+		if i%97 == 0 {
+			allocs := testing.AllocsPerRun(1, func() { m.Store(i, i) })
+			maxAllocs = max(maxAllocs, allocs)
+		}
+
+		buckets := m.backingVector.Len()
+		m = m.Store(i, i)
+		assertEqualBool(t, true, m.backingVector.Len()-buckets <= 1)
+	}
+
+	// No single store rebuilds the whole map
+	assertEqualBool(t, true, maxAllocs < 100)
+
+	for i := 0; i < 20000; i++ {
+		v, ok := m.Load(i)
+		assertEqualBool(t, true, ok)
+		assertEqual(t, i, v)
+	}
+
+	for i := 0; i < 20000; i++ {
+		buckets := m.backingVector.Len()
+		m = m.Delete(i)
+		assertEqualBool(t, true, buckets-m.backingVector.Len() <= 1)
+		if i%101 == 0 {
+			_, ok := m.Load(i + 1)
+			assertEqualBool(t, i == 19999, !ok)
+		}
+	}
+
+	assertEqual(t, 1, m.backingVector.Len())
+}
+
+func TestBucketPosLinearHashing(t *testing.T) {
+	for count := 1; count < 300; count++ {
+		for hash := uint32(0); hash < 2000; hash += 7 {
+			pos := bucketPos(hash, count)
+			assertEqualBool(t, true, pos >= 0 && pos < count)
+
+			// Adding a bucket only moves items from the split bucket to the new one
+			splitPos := count - 1<<(bits.Len(uint(count))-1)
+			if newPos := bucketPos(hash, count+1); newPos != pos {
+				assertEqual(t, splitPos, pos)
+				assertEqual(t, count, newPos)
+			}
+		}
+	}
+}
+
+func TestNewMapWithOptionsLoadFactors(t *testing.T) {
+	m := NewMapWithOptions[int, int](MapOptions{UpperLoadFactor: 2, LowerLoadFactor: 1})
+	for i := 0; i < 1000; i++ {
+		m = m.Store(i, i)
+	}
+
+	assertEqualBool(t, true, m.Stats().LoadFactor <= 2)
+
+	// Derived maps keep the options
+	m = MapValues(m.Filter(func(k, _ int) bool { return k < 900 }), func(_, v int) int { return v })
+	for i := 1000; i < 2000; i++ {
+		m = m.Store(i, i)
+	}
+
+	assertEqualBool(t, true, m.Stats().LoadFactor <= 2)
+	for i := 0; i < 1800; i++ {
+		m = m.Delete(i)
+	}
+
+	assertEqual(t, 200, m.Len())
+	assertEqualBool(t, true, m.Stats().LoadFactor >= 1)
+}
+
+func TestNewMapWithOptionsDisableShrink(t *testing.T) {
+	m := NewMapWithOptions[int, int](MapOptions{DisableShrink: true})
+	for i := 0; i < 1000; i++ {
+		m = m.Store(i, i)
+	}
+
+	buckets := m.backingVector.Len()
+	for i := 0; i < 1000; i++ {
+		m = m.Delete(i)
+	}
+
+	assertEqual(t, 0, m.Len())
+	assertEqual(t, buckets, m.backingVector.Len())
+}
+
+func TestNewMapWithOptionsInvalid(t *testing.T) {
+	defer assertPanic(t, "Invalid map load factors")
+	NewMapWithOptions[int, int](MapOptions{UpperLoadFactor: 2, LowerLoadFactor: 3})
+}
+
+func TestNewMapWithCapacity(t *testing.T) {
+	m := NewMapWithCapacity[int, int](10000)
+	buckets := m.backingVector.Len()
+	for i := 0; i < 10000; i++ {
+		m = m.Store(i, i)
+	}
+
+	assertEqual(t, buckets, m.backingVector.Len())
+	assertEqual(t, 10000, m.Len())
+	assertEqual(t, 0, NewMapWithCapacity[int, int](-1).Len())
+}
+
+func TestMapBuilderWithCapacity(t *testing.T) {
+	b := NewMapBuilderWithCapacity[int, int](10000)
+	buckets := len(b.buckets.buckets)
+	for i := 0; i < 10000; i++ {
+		b.Set(i, i)
+	}
+
+	assertEqual(t, buckets, len(b.buckets.buckets))
+	assertEqual(t, 10000, b.Freeze().Len())
+}
+
+func TestKeysAreSpreadOverBuckets(t *testing.T) {
+	b := NewMapBuilder[string, int]()
+	for i := 0; i < 10000; i++ {
+		b.Set(fmt.Sprintf("key-%d", i), i)
+	}
+
+	m := b.Freeze()
+	maxBucketLen := 0
+	m.backingVector.Range(func(bucket privateItemBucket[string, int]) bool {
+		maxBucketLen = max(maxBucketLen, len(bucket))
+		return true
+	})
+
+	if maxBucketLen > 4*int(upperMapLoadFactor) {
+		t.Errorf("Unexpectedly large bucket with %d items", maxBucketLen)
+	}
+}
+
+func TestNewMapWithHasher(t *testing.T) {
+	calls := 0
+	byLength := HasherFunc[string](func(key string) uint64 {
+		calls++
+		return uint64(len(key))
+	})
+
+	m := NewMapWithHasher[string, int](byLength, MapItem[string, int]{Key: "a", Value: 1})
+	for i := 0; i < 500; i++ {
+		m = m.Store(fmt.Sprintf("%d", i), i)
+	}
+
+	m = m.Delete("7")
+	assertEqual(t, 500, m.Len())
+	v, ok := m.Load("a")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, v)
+	v, _ = m.Load("499")
+	assertEqual(t, 499, v)
+	_, ok = m.Load("7")
+	assertEqualBool(t, false, ok)
+
+	// Derived maps use the same hasher
+	filtered := m.Filter(func(key string, _ int) bool { return key != "a" })
+	mapped := MapValues(m, func(_ string, v int) int { return v })
+	callsBefore := calls
+	filtered.Load("a")
+	mapped.Load("a")
+	assertEqual(t, callsBefore+2, calls)
+
+	// Maps with different hashers can still be compared and merged
+	m2 := NewMapFromNativeMap(m.ToNativeMap())
+	assertEqualBool(t, true, MapEqual(m, m2))
+	assertEqualBool(t, true, MapEqual(m2, m.Merge(m2, func(_ string, a, _ int) int { return a })))
+}
+
+func TestLoadWithStringKeyDoesNotAllocate(t *testing.T) {
+	m := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2, "c": 3})
+	key := strings.Repeat("b", 1)
+	allocs := testing.AllocsPerRun(100, func() {
+		m.Load(key)
+		m.Load("missing")
+	})
+
+	if allocs != 0 {
+		t.Errorf("Expected Load to not allocate, got %f allocations", allocs)
+	}
+}
+
 func TestFromToNativeMap(t *testing.T) {
 	input := map[string]int{
 		"a": 1,
@@ -182,3 +716,65 @@ func TestFromToNativeMap(t *testing.T) {
 		assertEqual(t, value, output[key])
 	}
 }
+
+///////////////
+/// Builder ///
+///////////////
+
+func TestMapBuilderSetAndDelete(t *testing.T) {
+	size := 1000
+	b := NewMapBuilder[int, int]()
+	for i := 0; i < size; i++ {
+		b.Set(i, i)
+	}
+
+	// Overwrite and delete some items
+	for i := 0; i < size; i += 2 {
+		b.Set(i, -i)
+	}
+
+	for i := 0; i < size; i += 3 {
+		b.Delete(i)
+	}
+
+	b.Delete(size + 1)
+	v, ok := b.Load(2)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, -2, v)
+
+	m := b.Freeze()
+	assertEqual(t, size-(size+2)/3, m.Len())
+	for i := 0; i < size; i++ {
+		v, ok := m.Load(i)
+		switch {
+		case i%3 == 0:
+			assertEqualBool(t, false, ok)
+		case i%2 == 0:
+			assertEqualBool(t, true, ok)
+			assertEqual(t, -i, v)
+		default:
+			assertEqualBool(t, true, ok)
+			assertEqual(t, i, v)
+		}
+	}
+
+	// The frozen map behaves like any other map
+	m2 := m.Store(size, size).Delete(1)
+	assertEqual(t, m.Len(), m2.Len())
+	_, ok = m.Load(1)
+	assertEqualBool(t, true, ok)
+}
+
+func TestMapBuilderEmpty(t *testing.T) {
+	m := NewMapBuilder[string, int]().Freeze()
+	assertEqual(t, 0, m.Len())
+	_, ok := m.Store("a", 1).Load("a")
+	assertEqualBool(t, true, ok)
+}
+
+func TestMapBuilderUseAfterFreeze(t *testing.T) {
+	defer assertPanic(t, "MapBuilder used after Freeze")
+	b := NewMapBuilder[string, int]()
+	b.Freeze()
+	b.Set("a", 1)
+}
@@ -0,0 +1,107 @@
+package peds
+
+import "slices"
+
+// /////////////
+// / MapFunc ///
+// /////////////
+
+// A HashEqualer hashes and compares keys of type K. Keys that are equal must produce the
+// same hash value.
+type HashEqualer[K any] interface {
+	Hasher[K]
+	Equal(a, b K) bool
+}
+
+// MapFunc is a persistent/immutable hash map that supports arbitrary key types, including
+// non-comparable ones such as slices or other persistent collections, by using a HashEqualer
+// to hash and compare keys. It shares its bucket layout and the growing and shrinking of its
+// buckets with Map, see MapOptions.
+type MapFunc[K any, V any] struct {
+	backingVector *Vector[privateItemBucket[K, V]]
+	len           int
+	hashEqualer   HashEqualer[K]
+
+	// Custom options, nil if the defaults are used
+	options *MapOptions
+}
+
+// NewMapFunc returns a new map containing all items in items that uses hashEqualer to hash
+// and compare keys.
+func NewMapFunc[K any, V any](hashEqualer HashEqualer[K], items ...MapItem[K, V]) *MapFunc[K, V] {
+	return newMapFunc(hashEqualer, nil, items)
+}
+
+// NewMapFuncWithOptions returns a new map containing all items in items that uses hashEqualer
+// to hash and compare keys and grows and shrinks as specified by options. All maps derived
+// from the returned map use the same options.
+func NewMapFuncWithOptions[K any, V any](hashEqualer HashEqualer[K], options MapOptions, items ...MapItem[K, V]) *MapFunc[K, V] {
+	assertMapOptionsOk(options)
+	return newMapFunc(hashEqualer, &options, items)
+}
+
+func newMapFunc[K any, V any](hashEqualer HashEqualer[K], options *MapOptions, items []MapItem[K, V]) *MapFunc[K, V] {
+	m := &MapFunc[K, V]{hashEqualer: hashEqualer}
+	return m.withTable(newHashTable(m.keys(), options, len(items), slices.Values(items)))
+}
+
+func (m *MapFunc[K, V]) table() hashTable[K, V] {
+	return hashTable[K, V]{buckets: m.backingVector, len: m.len, options: m.options}
+}
+
+func (m *MapFunc[K, V]) withTable(t hashTable[K, V]) *MapFunc[K, V] {
+	return &MapFunc[K, V]{backingVector: t.buckets, len: t.len, hashEqualer: m.hashEqualer, options: t.options}
+}
+
+func (m *MapFunc[K, V]) keys() hashEqualerKeys[K] {
+	return hashEqualerKeys[K]{hashEqualer: m.hashEqualer}
+}
+
+// Len returns the number of items in m.
+func (m *MapFunc[K, V]) Len() int {
+	return m.len
+}
+
+// Load returns value identified by key. ok is set to true if key exists in the map, false otherwise.
+func (m *MapFunc[K, V]) Load(key K) (value V, ok bool) {
+	return tableLoad(m.table(), m.keys(), key)
+}
+
+// Store returns a new MapFunc[K, V] containing value identified by key.
+func (m *MapFunc[K, V]) Store(key K, value V) *MapFunc[K, V] {
+	return m.Update(key, func(V, bool) V { return value })
+}
+
+// Update returns a new MapFunc[K, V] where the value identified by key has been replaced by
+// the result of calling f with the current value. ok is set to true if key exists in the map,
+// otherwise f is called with the zero value and ok set to false.
+func (m *MapFunc[K, V]) Update(key K, f func(value V, ok bool) V) *MapFunc[K, V] {
+	return m.withTable(tableUpdate(m.table(), m.keys(), key, f))
+}
+
+// Delete returns a new MapFunc[K, V] without the element identified by key.
+func (m *MapFunc[K, V]) Delete(key K) *MapFunc[K, V] {
+	_, _, result := m.LoadAndDelete(key)
+	return result
+}
+
+// LoadAndDelete returns the value identified by key together with a new MapFunc[K, V] without
+// that element. ok is set to true if key exists in the map, false otherwise.
+func (m *MapFunc[K, V]) LoadAndDelete(key K) (value V, ok bool, result *MapFunc[K, V]) {
+	value, ok, t := tableLoadAndDelete(m.table(), m.keys(), key)
+	if !ok {
+		return value, false, m
+	}
+
+	return value, true, m.withTable(t)
+}
+
+// Range calls f repeatedly passing it each key and value as argument until either
+// all elements have been visited or f returns false.
+func (m *MapFunc[K, V]) Range(f func(K, V) bool) {
+	for item := range m.table().items() {
+		if !f(item.Key, item.Value) {
+			return
+		}
+	}
+}
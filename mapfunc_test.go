@@ -0,0 +1,122 @@
+package peds
+
+import (
+	"hash/maphash"
+	"slices"
+	"testing"
+)
+
+type intSliceHashEqualer struct {
+	seed maphash.Seed
+}
+
+func (h intSliceHashEqualer) Hash(key []int) uint64 {
+	var mh maphash.Hash
+	mh.SetSeed(h.seed)
+	for _, i := range key {
+		maphash.WriteComparable(&mh, i)
+	}
+
+	return mh.Sum64()
+}
+
+func (h intSliceHashEqualer) Equal(a, b []int) bool {
+	return slices.Equal(a, b)
+}
+
+func TestMapFuncWithSliceKeys(t *testing.T) {
+	he := intSliceHashEqualer{seed: maphash.MakeSeed()}
+	m := NewMapFunc[[]int, string](he, MapItem[[]int, string]{Key: []int{1, 2}, Value: "a"})
+	m2 := m.Store([]int{1, 2}, "b").Store([]int{2, 1}, "c")
+	assertEqual(t, 1, m.Len())
+	assertEqual(t, 2, m2.Len())
+
+	v, ok := m.Load([]int{1, 2})
+	assertEqualBool(t, true, ok)
+	assertEqualString(t, "a", v)
+
+	v, _ = m2.Load([]int{1, 2})
+	assertEqualString(t, "b", v)
+
+	m3 := m2.Delete([]int{1, 2}).Delete([]int{3})
+	assertEqual(t, 1, m3.Len())
+	_, ok = m3.Load([]int{1, 2})
+	assertEqualBool(t, false, ok)
+}
+
+func TestMapFuncLargeInsertLookupDelete(t *testing.T) {
+	size := 1000
+	m := NewMapFunc[[]int, int](intSliceHashEqualer{seed: maphash.MakeSeed()})
+	for i := 0; i < size; i++ {
+		m = m.Store([]int{i, -i}, i)
+	}
+
+	assertEqual(t, size, m.Len())
+	sum := 0
+	m.Range(func(key []int, value int) bool {
+		assertEqual(t, key[0], value)
+		sum += value
+		return true
+	})
+	assertEqual(t, size*(size-1)/2, sum)
+
+	for i := 0; i < size; i++ {
+		m = m.Delete([]int{i, -i})
+		assertEqual(t, size-i-1, m.Len())
+		if i+1 < size {
+			v, ok := m.Load([]int{i + 1, -i - 1})
+			assertEqualBool(t, true, ok)
+			assertEqual(t, i+1, v)
+		}
+	}
+}
+
+func TestMapFuncGrowsAndShrinksOneBucketAtATime(t *testing.T) {
+	m := NewMapFunc[[]int, int](intSliceHashEqualer{seed: maphash.MakeSeed()})
+	for i := 0; i < 2000; i++ {
+		buckets := m.backingVector.Len()
+		m = m.Store([]int{i}, i)
+		if m.backingVector.Len() > buckets+1 {
+			t.Fatalf("Store grew the map from %d to %d buckets", buckets, m.backingVector.Len())
+		}
+	}
+
+	for i := 0; i < 2000; i++ {
+		buckets := m.backingVector.Len()
+		m = m.Delete([]int{i})
+		if m.backingVector.Len() < buckets-1 {
+			t.Fatalf("Delete shrank the map from %d to %d buckets", buckets, m.backingVector.Len())
+		}
+	}
+
+	assertEqual(t, 0, m.Len())
+	assertEqual(t, 1, m.backingVector.Len())
+}
+
+func TestMapFuncWithOptions(t *testing.T) {
+	he := intSliceHashEqualer{seed: maphash.MakeSeed()}
+	m := NewMapFuncWithOptions[[]int, int](he, MapOptions{DisableShrink: true})
+	for i := 0; i < 1000; i++ {
+		m = m.Store([]int{i}, i)
+	}
+
+	buckets := m.backingVector.Len()
+	for i := 0; i < 1000; i++ {
+		value, ok, result := m.LoadAndDelete([]int{i})
+		assertEqualBool(t, true, ok)
+		assertEqual(t, i, value)
+		m = result
+	}
+
+	assertEqual(t, buckets, m.backingVector.Len())
+	m = m.Update([]int{1}, func(value int, ok bool) int {
+		assertEqualBool(t, false, ok)
+		return 10
+	})
+
+	value, _ := m.Load([]int{1})
+	assertEqual(t, 10, value)
+
+	defer assertPanic(t, "Invalid map load factors")
+	NewMapFuncWithOptions[[]int, int](he, MapOptions{UpperLoadFactor: 0.5})
+}
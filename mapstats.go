@@ -0,0 +1,60 @@
+package peds
+
+import "fmt"
+
+// MapStats describes the internal layout of a Map. It is intended for diagnosing poor hash
+// distribution, which shows up as long buckets and a skewed BucketLenCounts.
+type MapStats struct {
+	// Len is the number of items in the map.
+	Len int
+
+	// Buckets is the total number of buckets and UsedBuckets the number of non-empty ones.
+	Buckets     int
+	UsedBuckets int
+
+	// MaxBucketLen is the number of items in the largest bucket and AvgBucketLen the
+	// average number of items in the non-empty buckets.
+	MaxBucketLen int
+	AvgBucketLen float64
+
+	// LoadFactor is the number of items per bucket.
+	LoadFactor float64
+
+	// BucketLenCounts holds the number of buckets of each length, BucketLenCounts[n] being
+	// the number of buckets holding n items.
+	BucketLenCounts []int
+}
+
+// String returns a short summary of s.
+func (s MapStats) String() string {
+	return fmt.Sprintf("len=%d buckets=%d used=%d max=%d avg=%.2f load=%.2f",
+		s.Len, s.Buckets, s.UsedBuckets, s.MaxBucketLen, s.AvgBucketLen, s.LoadFactor)
+}
+
+// Stats returns statistics about the internal bucket layout of m.
+func (m *Map[K, V]) Stats() MapStats {
+	s := MapStats{Len: m.len, Buckets: m.backingVector.Len()}
+	m.backingVector.Range(func(bucket privateItemBucket[K, V]) bool {
+		for len(s.BucketLenCounts) <= len(bucket) {
+			s.BucketLenCounts = append(s.BucketLenCounts, 0)
+		}
+
+		s.BucketLenCounts[len(bucket)]++
+		if len(bucket) > 0 {
+			s.UsedBuckets++
+		}
+
+		s.MaxBucketLen = max(s.MaxBucketLen, len(bucket))
+		return true
+	})
+
+	if s.UsedBuckets > 0 {
+		s.AvgBucketLen = float64(s.Len) / float64(s.UsedBuckets)
+	}
+
+	if s.Buckets > 0 {
+		s.LoadFactor = float64(s.Len) / float64(s.Buckets)
+	}
+
+	return s
+}
@@ -0,0 +1,51 @@
+package peds
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapStats(t *testing.T) {
+	m := NewMap[int, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Store(i, i)
+	}
+
+	s := m.Stats()
+	assertEqual(t, 1000, s.Len)
+	assertEqual(t, m.backingVector.Len(), s.Buckets)
+	assertEqualBool(t, true, s.UsedBuckets > 0 && s.UsedBuckets <= s.Buckets)
+	assertEqualBool(t, true, s.MaxBucketLen >= 1)
+	assertEqualBool(t, true, s.AvgBucketLen >= 1 && s.AvgBucketLen <= float64(s.MaxBucketLen))
+	assertEqualBool(t, true, s.LoadFactor > 0 && s.LoadFactor <= upperMapLoadFactor)
+
+	buckets, items := 0, 0
+	for length, count := range s.BucketLenCounts {
+		buckets += count
+		items += length * count
+	}
+
+	assertEqual(t, s.Buckets, buckets)
+	assertEqual(t, 1000, items)
+	assertEqual(t, s.Buckets-s.UsedBuckets, s.BucketLenCounts[0])
+	assertEqualBool(t, true, strings.HasPrefix(s.String(), "len=1000 "))
+}
+
+func TestMapStatsCollidingHasher(t *testing.T) {
+	m := NewMapWithHasher[int, int](HasherFunc[int](func(int) uint64 { return 0 }))
+	for i := 0; i < 10; i++ {
+		m = m.Store(i, i)
+	}
+
+	s := m.Stats()
+	assertEqual(t, 1, s.UsedBuckets)
+	assertEqual(t, 10, s.MaxBucketLen)
+	assertEqualBool(t, true, s.AvgBucketLen == 10)
+}
+
+func TestEmptyMapStats(t *testing.T) {
+	s := NewMap[int, int]().Stats()
+	assertEqual(t, 0, s.Len)
+	assertEqual(t, 0, s.UsedBuckets)
+	assertEqualBool(t, true, s.AvgBucketLen == 0)
+}
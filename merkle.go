@@ -0,0 +1,113 @@
+package peds
+
+import (
+	"encoding/binary"
+)
+
+// Content hashes are Merkle style hashes of the trie. The hash of each node is computed from
+// the hashes of its children, or the elements of a leaf, and is cached in the node the first
+// time it is needed. Nodes are never modified, so the cached hash stays valid, and are shared
+// between versions of a collection. Computing the content hash of a new version of a
+// collection whose previous version has been hashed is therefore proportional to the size of
+// the change rather than the size of the collection.
+
+// merkleHash returns the content hash of n at level, using leafHash to hash the elements of
+// leaves.
+func merkleHash[T any](level uint, n *node[T], leafHash func([]T) uint64) uint64 {
+	if h := n.hash.Load(); h != 0 {
+		return h
+	}
+
+	var h uint64
+	if level == 0 {
+		h = leafHash(n.items[:])
+	} else {
+		buf := make([]byte, 0, 8*nodeSize)
+		for _, child := range n.children {
+			if child == nil {
+				break
+			}
+
+			buf = binary.LittleEndian.AppendUint64(buf, merkleHash(level-shiftSize, child, leafHash))
+		}
+
+		d := newDigest(uint64(level))
+		d.Write(buf)
+		h = d.Sum64()
+	}
+
+	// Zero marks a hash that has not been computed
+	h = max(h, 1)
+	n.hash.Store(h)
+	return h
+}
+
+// contentHash returns the Merkle hash of v.
+func (v *Vector[T]) contentHash(leafHash func([]T) uint64) uint64 {
+	buf := binary.AppendUvarint(nil, uint64(v.len))
+	if v.root != nil {
+		buf = binary.LittleEndian.AppendUint64(buf, merkleHash(v.shift, v.root, leafHash))
+	}
+
+	buf = binary.LittleEndian.AppendUint64(buf, leafHash(v.tail))
+	d := newDigest(0)
+	d.Write(buf)
+	return d.Sum64()
+}
+
+func vectorLeafHash[T any]() func([]T) uint64 {
+	appendItem := digestAppender[T]()
+	return func(items []T) uint64 {
+		var buf []byte
+		for _, item := range items {
+			buf = appendItem(buf, item)
+		}
+
+		d := newDigest(0)
+		d.Write(buf)
+		return d.Sum64()
+	}
+}
+
+// ContentHash returns a 64 bit hash of the contents of v. Vectors with equal contents have
+// the same content hash, also across processes. Elements are hashed as for Sum64.
+//
+// Unlike Sum64 the hashes of the nodes of v are cached making it cheap to compute the content
+// hash of vectors derived from an already hashed vector. This makes comparing the content
+// hashes of two versions of a vector a fast, probabilistic, alternative to VectorEqual.
+func (v *Vector[T]) ContentHash() uint64 {
+	return v.contentHash(vectorLeafHash[T]())
+}
+
+// ContentHash returns a 64 bit hash of the contents of m, with caching of partial results
+// like for Vector.ContentHash. Maps with equal contents and the same number of buckets, which
+// is typically the case for different versions of the same map, have the same content hash.
+//
+// The hash depends on which bucket each item is stored in. Unless the map was created with a
+// Hasher, that is decided by a hash seed chosen when the process starts, so unlike the content
+// hash of a vector the result differs between processes and must not be persisted. Use Sum64
+// for a hash that is independent of the internal layout of the map.
+func (m *Map[K, V]) ContentHash() uint64 {
+	appendKey, appendValue := digestAppender[K](), digestAppender[V]()
+	return m.backingVector.contentHash(func(buckets []privateItemBucket[K, V]) uint64 {
+		buf := make([]byte, 0, 8*len(buckets))
+		var itemBuf []byte
+		for _, bucket := range buckets {
+			// Items are hashed individually and summed to make the result independent
+			// of the order of the items within the bucket.
+			var bucketSum uint64
+			for _, item := range bucket {
+				itemBuf = appendValue(appendKey(itemBuf[:0], item.Key), item.Value)
+				d := newDigest(0)
+				d.Write(itemBuf)
+				bucketSum += d.Sum64()
+			}
+
+			buf = binary.LittleEndian.AppendUint64(buf, bucketSum)
+		}
+
+		d := newDigest(0)
+		d.Write(buf)
+		return d.Sum64()
+	})
+}
@@ -0,0 +1,103 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVectorContentHash(t *testing.T) {
+	for _, l := range testSizes[1:] {
+		t.Run(fmt.Sprintf("ContentHash %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			built := NewVector[int]()
+			for _, i := range inputSlice(0, l) {
+				built = built.Append(i)
+			}
+
+			assertEqualBool(t, true, vec.ContentHash() == built.ContentHash())
+			updated := vec.Set(l/2, -1)
+			assertEqualBool(t, true, vec.ContentHash() != updated.ContentHash())
+			assertEqualBool(t, true, vec.ContentHash() == updated.Set(l/2, l/2).ContentHash())
+			assertEqualBool(t, true, vec.ContentHash() != vec.Append(0).ContentHash())
+		})
+	}
+}
+
+func TestVectorContentHashCachesNodes(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100000)...)
+	vec.ContentHash()
+
+	// Only the nodes on the path to the updated element need to be hashed
+	updated := vec.Set(500, -1)
+	allocs := testing.AllocsPerRun(10, func() { updated.ContentHash() })
+	assertEqualBool(t, true, allocs < 200)
+}
+
+func TestMapContentHash(t *testing.T) {
+	items := make(map[int]string)
+	for i := 0; i < 1000; i++ {
+		items[i] = fmt.Sprint(i)
+	}
+
+	m := NewMapFromNativeMap(items)
+	other := m.Delete(3).Store(3, "3")
+	assertEqualBool(t, true, m.ContentHash() == other.ContentHash())
+	assertEqualBool(t, true, m.ContentHash() != m.Store(3, "x").ContentHash())
+	assertEqualBool(t, true, m.ContentHash() != m.Delete(3).ContentHash())
+}
+
+func TestVectorEqualUsesContentHashes(t *testing.T) {
+	a := NewVector(inputSlice(0, 10000)...)
+	b := NewVector[int]()
+	for _, i := range inputSlice(0, 10000) {
+		b = b.Append(i)
+	}
+
+	a.ContentHash()
+	b.ContentHash()
+
+	// Modify a hashed leaf in place, only comparisons that trust the cached hashes miss it
+	b.sliceFor(5000)[3] = -1
+	assertEqualBool(t, true, VectorEqual(a, b))
+	assertEqualBool(t, false, a.EqualFunc(b, func(x, y int) bool { return x == y }))
+	assertEqual(t, 0, len(VectorDiff(a, b)))
+	assertEqualBool(t, true, len(a.DiffFunc(b, func(x, y int) bool { return x == y })) > 0)
+}
+
+func TestVectorEqualDiffWithContentHashes(t *testing.T) {
+	a := NewVector(inputSlice(0, 10000)...)
+	a.ContentHash()
+	for _, pos := range []int{0, 31, 32, 1023, 1024, 5000, 9950, 9999} {
+		b := NewVector(inputSlice(0, 10000)...).Set(pos, -1)
+		b.ContentHash()
+		assertEqualBool(t, false, VectorEqual(a, b))
+
+		for _, other := range []*Vector[int]{b, b.Remove(pos), b.Remove(pos).Append(-2)} {
+			other.ContentHash()
+			edits := VectorDiff(a, other)
+			assertEqualBool(t, true, len(edits) <= 3)
+			result, err := a.Apply(edits)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			assertEqualBool(t, true, other.EqualFunc(result, func(x, y int) bool { return x == y }))
+		}
+	}
+}
+
+func TestMapEqualWithContentHashes(t *testing.T) {
+	items := make(map[int]string)
+	for i := 0; i < 1000; i++ {
+		items[i] = fmt.Sprint(i)
+	}
+
+	a, b := NewMapFromNativeMap(items), NewMapFromNativeMap(items)
+	a.ContentHash()
+	b.ContentHash()
+	assertEqualBool(t, true, MapEqual(a, b))
+
+	c := b.Store(500, "x")
+	c.ContentHash()
+	assertEqualBool(t, false, MapEqual(a, c))
+}
@@ -0,0 +1,152 @@
+package peds
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"unsafe"
+)
+
+// MappableElement is the set of element types that can be stored in memory mapped vectors.
+// They have a fixed size and contain no pointers, allowing the elements to be used directly
+// from the mapped memory.
+type MappableElement interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 | ~bool
+}
+
+// The mapped vector file format consists of a magic string, the element size as a uint32,
+// a byte order mark, the element count as a uint64 and finally the elements. Everything is
+// stored in the native byte order of the machine writing the file, which must match that of
+// the machine reading it. The header size keeps the elements 8 byte aligned.
+var mappedVectorMagic = []byte("PEDSMMAP")
+
+const mappedVectorHeaderSize = 8 + 4 + 4 + 8
+const mappedVectorByteOrderMark uint32 = 0x01020304
+
+// WriteMappedVector writes v to w in the format read by OpenMappedVector.
+func WriteMappedVector[T MappableElement](w io.Writer, v *Vector[T]) error {
+	var zero T
+	bw := bufio.NewWriter(w)
+	header := make([]byte, 0, mappedVectorHeaderSize)
+	header = append(header, mappedVectorMagic...)
+	header = binary.NativeEndian.AppendUint32(header, uint32(unsafe.Sizeof(zero)))
+	header = binary.NativeEndian.AppendUint32(header, mappedVectorByteOrderMark)
+	header = binary.NativeEndian.AppendUint64(header, uint64(v.Len()))
+	if _, err := bw.Write(header); err != nil {
+		return err
+	}
+
+	var err error
+	v.RangeChunks(func(chunk []T) bool {
+		_, err = bw.Write(unsafe.Slice((*byte)(unsafe.Pointer(&chunk[0])), len(chunk)*int(unsafe.Sizeof(zero))))
+		return err == nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// A MappedVector is a read-only Vector whose elements reside in a memory mapped file written
+// by WriteMappedVector. Opening it requires no decoding of the elements and the memory is
+// shared between processes mapping the same file.
+type MappedVector[T MappableElement] struct {
+	vector *Vector[T]
+	data   []byte
+}
+
+// OpenMappedVector maps the file at path, which must have been written by WriteMappedVector
+// for the same element type, into memory. The file must not be modified while mapped.
+func OpenMappedVector[T MappableElement](path string) (*MappedVector[T], error) {
+	data, err := mapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	vector, err := mappedVector[T](data)
+	if err != nil {
+		_ = unmapFile(data)
+		return nil, err
+	}
+
+	return &MappedVector[T]{vector: vector, data: data}, nil
+}
+
+// mappedVector returns a vector whose leaves and tail refer directly to the elements in
+// data. Only the internal nodes of the tree are allocated.
+func mappedVector[T MappableElement](data []byte) (*Vector[T], error) {
+	var zero T
+	elemSize := int(unsafe.Sizeof(zero))
+	if len(data) < mappedVectorHeaderSize || !bytes.Equal(data[:len(mappedVectorMagic)], mappedVectorMagic) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	header := data[len(mappedVectorMagic):]
+	if binary.NativeEndian.Uint32(header[4:]) != mappedVectorByteOrderMark {
+		return nil, errors.New("peds: mapped vector written with a different byte order")
+	}
+
+	if int(binary.NativeEndian.Uint32(header)) != elemSize {
+		return nil, errors.New("peds: mapped vector element size mismatch")
+	}
+
+	count := binary.NativeEndian.Uint64(header[8:])
+	elems := data[mappedVectorHeaderSize:]
+	if count != uint64(len(elems)/elemSize) || len(elems)%elemSize != 0 {
+		return nil, ErrInvalidBinaryData
+	}
+
+	if count == 0 {
+		return NewVector[T](), nil
+	}
+
+	items := unsafe.Slice((*T)(unsafe.Pointer(&elems[0])), count)
+	tailOffset := ((count - 1) >> shiftSize) << shiftSize
+	v := &Vector[T]{tail: items[tailOffset:], len: uint(count), shift: shiftSize}
+	if tailOffset > 0 {
+		leaves := make([]*node[T], 0, tailOffset/nodeSize)
+		for i := uint64(0); i < tailOffset; i += nodeSize {
+			leaves = append(leaves, newLeaf((*[nodeSize]T)(items[i:])))
+		}
+
+		v.root, v.shift = buildTree(leaves)
+	}
+
+	return v, nil
+}
+
+// Vector returns the elements of m as a Vector. Updates of the returned vector create new
+// vectors in regular memory, the mapped file is never modified. The vector, and any vector
+// derived from it, must not be used after m has been closed.
+func (m *MappedVector[T]) Vector() *Vector[T] {
+	return m.vector
+}
+
+// Len returns the length of m.
+func (m *MappedVector[T]) Len() int {
+	return m.vector.Len()
+}
+
+// Get returns the element at position i.
+func (m *MappedVector[T]) Get(i int) T {
+	return m.vector.Get(i)
+}
+
+// Range calls f repeatedly passing it each element in m in order as argument until either
+// all elements have been visited or f returns false.
+func (m *MappedVector[T]) Range(f func(T) bool) {
+	m.vector.Range(f)
+}
+
+// Close unmaps the file backing m.
+func (m *MappedVector[T]) Close() error {
+	data := m.data
+	m.data, m.vector = nil, nil
+	return unmapFile(data)
+}
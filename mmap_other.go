@@ -0,0 +1,14 @@
+//go:build !unix
+
+package peds
+
+import "os"
+
+// Memory mapping is not supported on this platform, the file is read into memory instead.
+func mapFile(path string) ([]byte, error) {
+	return os.ReadFile(path)
+}
+
+func unmapFile([]byte) error {
+	return nil
+}
@@ -0,0 +1,63 @@
+package peds
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeMappedVectorFile[T MappableElement](t *testing.T, v *Vector[T]) string {
+	path := filepath.Join(t.TempDir(), "vector.mmap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteMappedVector(f, v); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestMappedVector(t *testing.T) {
+	for _, l := range testSizes {
+		vec := NewVector(inputSlice(0, l)...)
+		m, err := OpenMappedVector[int](writeMappedVectorFile(t, vec))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, l, m.Len())
+		assertEqualBool(t, true, VectorEqual(vec, m.Vector()))
+		if l > 0 {
+			assertEqual(t, l-1, m.Get(l-1))
+			updated := m.Vector().Set(0, -1).Append(l)
+			assertEqual(t, -1, updated.Get(0))
+			assertEqual(t, l, updated.Get(l))
+			assertEqual(t, 0, m.Get(0))
+		}
+
+		if err := m.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMappedVectorElementSizeMismatch(t *testing.T) {
+	path := writeMappedVectorFile(t, NewVector[int32](1, 2, 3))
+	_, err := OpenMappedVector[int64](path)
+	assertEqualBool(t, true, err != nil)
+
+	m, err := OpenMappedVector[uint32](path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer m.Close()
+
+	assertEqual(t, 3, int(m.Get(2)))
+}
@@ -0,0 +1,341 @@
+package peds
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// msgpackFormat encodes collections as MessagePack arrays and maps. The collection methods
+// match the Marshaler/Unmarshaler interfaces of github.com/vmihailenco/msgpack so that
+// collections can be embedded in values encoded with that library.
+var msgpackFormat = &wireFormat{
+	appendInt:         msgpackAppendInt,
+	appendUint:        msgpackAppendUint,
+	readInteger:       msgpackReadInteger,
+	appendFloat32:     msgpackAppendFloat32,
+	appendFloat64:     msgpackAppendFloat64,
+	readFloat:         msgpackReadFloat,
+	appendBool:        msgpackAppendBool,
+	readBool:          msgpackReadBool,
+	appendString:      msgpackAppendString,
+	readString:        msgpackReadString,
+	appendBytes:       msgpackAppendBytes,
+	readBytes:         msgpackReadBytes,
+	appendArrayHeader: msgpackAppendArrayHeader,
+	readArrayHeader:   msgpackReadArrayHeader,
+	appendMapHeader:   msgpackAppendMapHeader,
+	readMapHeader:     msgpackReadMapHeader,
+}
+
+func msgpackAppendUint(dst []byte, value uint64) []byte {
+	switch {
+	case value < 0x80:
+		return append(dst, byte(value))
+	case value <= math.MaxUint8:
+		return append(dst, 0xcc, byte(value))
+	case value <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xcd), uint16(value))
+	case value <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xce), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xcf), value)
+	}
+}
+
+func msgpackAppendInt(dst []byte, value int64) []byte {
+	switch {
+	case value >= 0:
+		return msgpackAppendUint(dst, uint64(value))
+	case value >= -32:
+		return append(dst, byte(value))
+	case value >= math.MinInt8:
+		return append(dst, 0xd0, byte(value))
+	case value >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(dst, 0xd1), uint16(value))
+	case value >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(dst, 0xd2), uint32(value))
+	default:
+		return binary.BigEndian.AppendUint64(append(dst, 0xd3), uint64(value))
+	}
+}
+
+// msgpackReadFixed returns the size byte long big endian number following the type byte.
+func msgpackReadFixed(data []byte, size int) (uint64, error) {
+	if len(data) < 1+size {
+		return 0, ErrInvalidBinaryData
+	}
+
+	switch size {
+	case 1:
+		return uint64(data[1]), nil
+	case 2:
+		return uint64(binary.BigEndian.Uint16(data[1:])), nil
+	case 4:
+		return uint64(binary.BigEndian.Uint32(data[1:])), nil
+	default:
+		return binary.BigEndian.Uint64(data[1:]), nil
+	}
+}
+
+func msgpackReadInteger(data []byte) (uint64, bool, int, error) {
+	if len(data) == 0 {
+		return 0, false, 0, ErrInvalidBinaryData
+	}
+
+	t := data[0]
+	switch {
+	case t < 0x80:
+		return uint64(t), false, 1, nil
+	case t >= 0xe0:
+		return uint64(int64(int8(t))), true, 1, nil
+	case t >= 0xcc && t <= 0xcf:
+		size := 1 << (t - 0xcc)
+		u, err := msgpackReadFixed(data, size)
+		return u, false, 1 + size, err
+	case t >= 0xd0 && t <= 0xd3:
+		size := 1 << (t - 0xd0)
+		u, err := msgpackReadFixed(data, size)
+		if err != nil {
+			return 0, false, 0, err
+		}
+
+		// Sign extend
+		shift := 64 - 8*size
+		x := int64(u<<shift) >> shift
+		return uint64(x), x < 0, 1 + size, nil
+	}
+
+	return 0, false, 0, ErrInvalidBinaryData
+}
+
+func msgpackAppendFloat32(dst []byte, value float32) []byte {
+	return binary.BigEndian.AppendUint32(append(dst, 0xca), math.Float32bits(value))
+}
+
+func msgpackAppendFloat64(dst []byte, value float64) []byte {
+	return binary.BigEndian.AppendUint64(append(dst, 0xcb), math.Float64bits(value))
+}
+
+func msgpackReadFloat(data []byte) (float64, int, error) {
+	if len(data) > 0 && data[0] == 0xca {
+		u, err := msgpackReadFixed(data, 4)
+		return float64(math.Float32frombits(uint32(u))), 5, err
+	}
+
+	if len(data) > 0 && data[0] == 0xcb {
+		u, err := msgpackReadFixed(data, 8)
+		return math.Float64frombits(u), 9, err
+	}
+
+	return 0, 0, ErrInvalidBinaryData
+}
+
+func msgpackAppendBool(dst []byte, value bool) []byte {
+	if value {
+		return append(dst, 0xc3)
+	}
+
+	return append(dst, 0xc2)
+}
+
+func msgpackReadBool(data []byte) (bool, int, error) {
+	if len(data) == 0 || (data[0] != 0xc2 && data[0] != 0xc3) {
+		return false, 0, ErrInvalidBinaryData
+	}
+
+	return data[0] == 0xc3, 1, nil
+}
+
+// msgpackAppendHeader appends a type byte followed by a length. fix is the type byte to
+// use for lengths that fit in the type byte itself, maxFix is the largest such length
+// and first is the type byte for the 8 bit length form, or 0 if there is none, followed
+// by the type bytes for the 16 and 32 bit forms.
+func msgpackAppendHeader(dst []byte, length int, fix byte, maxFix int, first, t16, t32 byte) []byte {
+	switch {
+	case length <= maxFix:
+		return append(dst, fix|byte(length))
+	case first != 0 && length <= math.MaxUint8:
+		return append(dst, first, byte(length))
+	case length <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(dst, t16), uint16(length))
+	default:
+		return binary.BigEndian.AppendUint32(append(dst, t32), uint32(length))
+	}
+}
+
+// msgpackReadHeader is the inverse of msgpackAppendHeader. fixMask is the mask used to
+// identify the fix type byte.
+func msgpackReadHeader(data []byte, fix, fixMask, first, t16, t32 byte) (uint64, int, error) {
+	if len(data) == 0 {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	t := data[0]
+	switch {
+	case t&fixMask == fix:
+		return uint64(t &^ fixMask), 1, nil
+	case first != 0 && t == first:
+		u, err := msgpackReadFixed(data, 1)
+		return u, 2, err
+	case t == t16:
+		u, err := msgpackReadFixed(data, 2)
+		return u, 3, err
+	case t == t32:
+		u, err := msgpackReadFixed(data, 4)
+		return u, 5, err
+	}
+
+	return 0, 0, ErrInvalidBinaryData
+}
+
+func msgpackReadBlob(data []byte, fix, fixMask, first, t16, t32 byte) ([]byte, int, error) {
+	length, n, err := msgpackReadHeader(data, fix, fixMask, first, t16, t32)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if length > uint64(len(data)-n) {
+		return nil, 0, ErrInvalidBinaryData
+	}
+
+	return data[n : n+int(length)], n + int(length), nil
+}
+
+func msgpackAppendString(dst []byte, value string) []byte {
+	return append(msgpackAppendHeader(dst, len(value), 0xa0, 31, 0xd9, 0xda, 0xdb), value...)
+}
+
+func msgpackReadString(data []byte) ([]byte, int, error) {
+	return msgpackReadBlob(data, 0xa0, 0xe0, 0xd9, 0xda, 0xdb)
+}
+
+func msgpackAppendBytes(dst []byte, value []byte) []byte {
+	// There is no fix form for binary data, the fix type byte and mask below never match
+	return append(msgpackAppendHeader(dst, len(value), 0, -1, 0xc4, 0xc5, 0xc6), value...)
+}
+
+func msgpackReadBytes(data []byte) ([]byte, int, error) {
+	return msgpackReadBlob(data, 0xff, 0x00, 0xc4, 0xc5, 0xc6)
+}
+
+func msgpackAppendArrayHeader(dst []byte, count int) []byte {
+	return msgpackAppendHeader(dst, count, 0x90, 15, 0, 0xdc, 0xdd)
+}
+
+func msgpackReadArrayHeader(data []byte) (int, int, error) {
+	count, n, err := msgpackReadHeader(data, 0x90, 0xf0, 0, 0xdc, 0xdd)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count32, err := checkWireCount(count, len(data)-n)
+	return count32, n, err
+}
+
+func msgpackAppendMapHeader(dst []byte, count int) []byte {
+	return msgpackAppendHeader(dst, count, 0x80, 15, 0, 0xde, 0xdf)
+}
+
+func msgpackReadMapHeader(data []byte) (int, int, error) {
+	count, n, err := msgpackReadHeader(data, 0x80, 0xf0, 0, 0xde, 0xdf)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	count32, err := checkWireCount(count, len(data)-n)
+	return count32, n, err
+}
+
+// DefaultMsgpackCodec returns the codec used by MarshalMsgpack and UnmarshalMsgpack for
+// values of type T. Codecs are available for booleans, numeric types except complex
+// numbers, strings and byte slices. Other element types can be handled by implementing
+// Codec using a full featured MessagePack library.
+func DefaultMsgpackCodec[T any]() (Codec[T], error) {
+	return defaultWireCodec[T](msgpackFormat, "MessagePack")
+}
+
+// MarshalVectorMsgpack encodes v as a MessagePack array using codec to encode the elements.
+func MarshalVectorMsgpack[T any](v *Vector[T], codec Codec[T]) ([]byte, error) {
+	return marshalVectorWire(msgpackFormat, v, codec)
+}
+
+// UnmarshalVectorMsgpack decodes a Vector from a MessagePack array using codec to decode
+// the elements.
+func UnmarshalVectorMsgpack[T any](data []byte, codec Codec[T]) (*Vector[T], error) {
+	return unmarshalVectorWire(msgpackFormat, data, codec)
+}
+
+// MarshalMapMsgpack encodes m as a MessagePack map using keyCodec and valueCodec to encode
+// the keys and values.
+func MarshalMapMsgpack[K comparable, V any](m *Map[K, V], keyCodec Codec[K], valueCodec Codec[V]) ([]byte, error) {
+	return marshalMapWire(msgpackFormat, m, keyCodec, valueCodec)
+}
+
+// UnmarshalMapMsgpack decodes a Map from a MessagePack map using keyCodec and valueCodec
+// to decode the keys and values.
+func UnmarshalMapMsgpack[K comparable, V any](data []byte, keyCodec Codec[K], valueCodec Codec[V]) (*Map[K, V], error) {
+	return unmarshalMapWire(msgpackFormat, data, keyCodec, valueCodec)
+}
+
+// MarshalMsgpack encodes v as a MessagePack array using the default codec for T.
+func (v *Vector[T]) MarshalMsgpack() ([]byte, error) {
+	codec, err := DefaultMsgpackCodec[T]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalVectorMsgpack(v, codec)
+}
+
+// UnmarshalMsgpack replaces the contents of v with the elements of the MessagePack array
+// in data.
+func (v *Vector[T]) UnmarshalMsgpack(data []byte) error {
+	codec, err := DefaultMsgpackCodec[T]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalVectorMsgpack(data, codec)
+	if err != nil {
+		return err
+	}
+
+	*v = *result
+	return nil
+}
+
+// MarshalMsgpack encodes m as a MessagePack map using the default codecs for K and V.
+func (m *Map[K, V]) MarshalMsgpack() ([]byte, error) {
+	keyCodec, err := DefaultMsgpackCodec[K]()
+	if err != nil {
+		return nil, err
+	}
+
+	valueCodec, err := DefaultMsgpackCodec[V]()
+	if err != nil {
+		return nil, err
+	}
+
+	return MarshalMapMsgpack(m, keyCodec, valueCodec)
+}
+
+// UnmarshalMsgpack replaces the contents of m with the items of the MessagePack map in data.
+func (m *Map[K, V]) UnmarshalMsgpack(data []byte) error {
+	keyCodec, err := DefaultMsgpackCodec[K]()
+	if err != nil {
+		return err
+	}
+
+	valueCodec, err := DefaultMsgpackCodec[V]()
+	if err != nil {
+		return err
+	}
+
+	result, err := UnmarshalMapMsgpack(data, keyCodec, valueCodec)
+	if err != nil {
+		return err
+	}
+
+	*m = *result
+	return nil
+}
@@ -0,0 +1,113 @@
+package peds
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestVectorMsgpackKnownEncoding(t *testing.T) {
+	data, err := NewVector(1, -1, 200, -200, 70000).MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []byte{0x95, 0x01, 0xff, 0xcc, 0xc8, 0xd1, 0xff, 0x38, 0xce, 0x00, 0x01, 0x11, 0x70}
+	assertEqualBool(t, true, bytes.Equal(expected, data))
+
+	data, _ = NewVector("a", "").MarshalMsgpack()
+	assertEqualBool(t, true, bytes.Equal([]byte{0x92, 0xa1, 'a', 0xa0}, data))
+}
+
+func TestVectorMsgpackRoundTrip(t *testing.T) {
+	for _, l := range []int{0, 1, 15, 16, 1000, 70000} {
+		data, err := NewVector(inputSlice(-l/2, l)...).MarshalMsgpack()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var v Vector[int]
+		if err := v.UnmarshalMsgpack(data); err != nil {
+			t.Fatal(err)
+		}
+
+		assertEqual(t, l, v.Len())
+		for i := 0; i < l; i += 1 + l/100 {
+			assertEqual(t, i-l/2, v.Get(i))
+		}
+	}
+}
+
+func TestMsgpackScalarTypes(t *testing.T) {
+	longString := strings.Repeat("x", 70000)
+	strs := NewVector("", "abc", longString[:40], longString[:300], longString)
+	data, _ := strs.MarshalMsgpack()
+	var strsOut Vector[string]
+	if err := strsOut.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, len(longString), len(strsOut.Get(4)))
+	assertEqualString(t, "abc", strsOut.Get(1))
+
+	ints := NewVector[int64](math.MinInt64, math.MinInt32, -33, -32, 0, math.MaxInt64)
+	data, _ = ints.MarshalMsgpack()
+	var intsOut Vector[int64]
+	if err := intsOut.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < ints.Len(); i++ {
+		assertEqualBool(t, true, ints.Get(i) == intsOut.Get(i))
+	}
+
+	floats := NewVector(1.5, math.Inf(-1))
+	data, _ = floats.MarshalMsgpack()
+	var floatsOut Vector[float64]
+	if err := floatsOut.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, floatsOut.Get(0) == 1.5 && math.IsInf(floatsOut.Get(1), -1))
+
+	blobs := NewVector([]byte{1, 2}, []byte{})
+	data, _ = blobs.MarshalMsgpack()
+	var blobsOut Vector[[]byte]
+	if err := blobsOut.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, bytes.Equal([]byte{1, 2}, blobsOut.Get(0)))
+}
+
+func TestMapMsgpackRoundTrip(t *testing.T) {
+	data, err := NewMapFromNativeMap(map[string]bool{"a": true, "b": false}).MarshalMsgpack()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 0x82, int(data[0]))
+	var m Map[string, bool]
+	if err := m.UnmarshalMsgpack(data); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 2, m.Len())
+	v, _ := m.Load("a")
+	assertEqualBool(t, true, v)
+}
+
+func TestMsgpackErrors(t *testing.T) {
+	_, err := NewVector(struct{}{}).MarshalMsgpack()
+	assertEqualBool(t, true, err != nil)
+
+	data, _ := NewVector(-1, 300).MarshalMsgpack()
+	var unsigned Vector[uint]
+	assertEqualBool(t, true, errors.Is(unsigned.UnmarshalMsgpack(data), ErrInvalidBinaryData))
+
+	var v Vector[int]
+	assertEqualBool(t, true, errors.Is(v.UnmarshalMsgpack(data[:len(data)-1]), ErrInvalidBinaryData))
+	assertEqualBool(t, true, errors.Is(v.UnmarshalMsgpack([]byte{0xdd, 0xff, 0xff, 0xff, 0xff}), ErrInvalidBinaryData))
+}
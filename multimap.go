@@ -0,0 +1,102 @@
+package peds
+
+// //////////////
+// / MultiMap ///
+// //////////////
+
+// A MultiMap is a persistent/immutable map where each key is associated with a sequence of
+// values. The values for a key are kept in the order they were added.
+type MultiMap[K comparable, V comparable] struct {
+	m   *Map[K, *Vector[V]]
+	len int
+}
+
+// NewMultiMap returns a new, empty, multimap.
+func NewMultiMap[K comparable, V comparable]() *MultiMap[K, V] {
+	return &MultiMap[K, V]{m: NewMap[K, *Vector[V]]()}
+}
+
+// Len returns the total number of values in mm.
+func (mm *MultiMap[K, V]) Len() int {
+	return mm.len
+}
+
+// KeyLen returns the number of distinct keys in mm.
+func (mm *MultiMap[K, V]) KeyLen() int {
+	return mm.m.Len()
+}
+
+// Add returns a new multimap with value added last among the values for key.
+func (mm *MultiMap[K, V]) Add(key K, value V) *MultiMap[K, V] {
+	m := mm.m.Update(key, func(values *Vector[V], ok bool) *Vector[V] {
+		if !ok {
+			return NewVector(value)
+		}
+
+		return values.Append(value)
+	})
+
+	return &MultiMap[K, V]{m: m, len: mm.len + 1}
+}
+
+// LoadAll returns the values for key, in the order they were added. The vector is empty if
+// there are no values for key.
+func (mm *MultiMap[K, V]) LoadAll(key K) *Vector[V] {
+	if values, ok := mm.m.Load(key); ok {
+		return values
+	}
+
+	return NewVector[V]()
+}
+
+// Contains reports whether value is one of the values for key.
+func (mm *MultiMap[K, V]) Contains(key K, value V) bool {
+	return Contains(mm.LoadAll(key), value)
+}
+
+// RemoveValue returns a new multimap with the first occurrence of value among the values for
+// key removed. The multimap itself is returned if value is not present.
+func (mm *MultiMap[K, V]) RemoveValue(key K, value V) *MultiMap[K, V] {
+	values := mm.LoadAll(key)
+	i := IndexOf(values, value)
+	if i < 0 {
+		return mm
+	}
+
+	if values.Len() == 1 {
+		return &MultiMap[K, V]{m: mm.m.Delete(key), len: mm.len - 1}
+	}
+
+	return &MultiMap[K, V]{m: mm.m.Store(key, values.Remove(i)), len: mm.len - 1}
+}
+
+// RemoveAll returns a new multimap without any values for key.
+func (mm *MultiMap[K, V]) RemoveAll(key K) *MultiMap[K, V] {
+	values, ok, m := mm.m.LoadAndDelete(key)
+	if !ok {
+		return mm
+	}
+
+	return &MultiMap[K, V]{m: m, len: mm.len - values.Len()}
+}
+
+// Range calls f repeatedly passing it each key and value in mm until either all values have
+// been visited or f returns false. The values for a key are visited consecutively in the
+// order they were added, keys are visited in no particular order.
+func (mm *MultiMap[K, V]) Range(f func(K, V) bool) {
+	mm.m.Range(func(key K, values *Vector[V]) bool {
+		cont := true
+		values.Range(func(value V) bool {
+			cont = f(key, value)
+			return cont
+		})
+
+		return cont
+	})
+}
+
+// RangeKeys calls f repeatedly passing it each key and its values in mm until either all keys
+// have been visited or f returns false.
+func (mm *MultiMap[K, V]) RangeKeys(f func(K, *Vector[V]) bool) {
+	mm.m.Range(f)
+}
@@ -0,0 +1,70 @@
+package peds
+
+import "testing"
+
+func TestMultiMapAddLoadAll(t *testing.T) {
+	empty := NewMultiMap[string, int]()
+	mm := empty.Add("a", 1).Add("b", 2).Add("a", 3).Add("a", 1)
+	assertEqual(t, 4, mm.Len())
+	assertEqual(t, 2, mm.KeyLen())
+
+	values := mm.LoadAll("a")
+	assertEqual(t, 3, values.Len())
+	assertEqual(t, 3, values.Get(1))
+	assertEqual(t, 0, mm.LoadAll("x").Len())
+	assertEqualBool(t, true, mm.Contains("b", 2))
+	assertEqualBool(t, false, mm.Contains("b", 1))
+	assertEqual(t, 0, empty.Len())
+}
+
+func TestMultiMapRemove(t *testing.T) {
+	mm := NewMultiMap[string, int]().Add("a", 1).Add("a", 2).Add("a", 1).Add("b", 2)
+	r := mm.RemoveValue("a", 1)
+	assertEqual(t, 3, r.Len())
+	assertEqual(t, 2, r.LoadAll("a").Get(0))
+	assertEqualBool(t, true, r.RemoveValue("a", 5) == r)
+	assertEqualBool(t, true, r.RemoveValue("x", 1) == r)
+
+	r = r.RemoveValue("b", 2)
+	assertEqual(t, 1, r.KeyLen())
+
+	r = mm.RemoveAll("a")
+	assertEqual(t, 1, r.Len())
+	assertEqualBool(t, true, r.RemoveAll("a") == r)
+	assertEqual(t, 4, mm.Len())
+}
+
+func TestMultiMapRange(t *testing.T) {
+	mm := NewMultiMap[int, int]()
+	for i := 0; i < 100; i++ {
+		mm = mm.Add(i%10, i)
+	}
+
+	sum, count := 0, 0
+	mm.Range(func(key, value int) bool {
+		assertEqual(t, key, value%10)
+		sum += value
+		count++
+		return true
+	})
+
+	assertEqual(t, 100, count)
+	assertEqual(t, 4950, sum)
+
+	count = 0
+	mm.Range(func(key, value int) bool {
+		count++
+		return count < 15
+	})
+
+	assertEqual(t, 15, count)
+
+	keys := 0
+	mm.RangeKeys(func(key int, values *Vector[int]) bool {
+		assertEqual(t, 10, values.Len())
+		keys++
+		return true
+	})
+
+	assertEqual(t, 10, keys)
+}
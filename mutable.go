@@ -0,0 +1,114 @@
+package peds
+
+// ///////////////
+// / MutVector ///
+// ///////////////
+
+// A MutVector is a mutable vector with an API similar to that of a native slice, intended to
+// ease migration of imperative code. It maintains a persistent vector internally, Snapshot
+// returns the current contents as an immutable Vector in constant time. Updates between
+// snapshots are made in place, as for TransientVector.
+//
+// A MutVector is not safe for concurrent use.
+type MutVector[T any] struct {
+	t *TransientVector[T]
+}
+
+// NewMutVector returns a new MutVector with the contents of v. v is not affected by changes
+// made to the MutVector.
+func NewMutVector[T any](v *Vector[T]) *MutVector[T] {
+	return &MutVector[T]{t: v.Transient()}
+}
+
+// Len returns the length of v.
+func (v *MutVector[T]) Len() int {
+	return v.t.Len()
+}
+
+// Get returns the element at position i.
+func (v *MutVector[T]) Get(i int) T {
+	return v.t.Get(i)
+}
+
+// Set sets the element at position i to item.
+func (v *MutVector[T]) Set(i int, item T) {
+	v.t.Set(i, item)
+}
+
+// Append appends item(s) to v.
+func (v *MutVector[T]) Append(item ...T) {
+	v.t.Append(item...)
+}
+
+// Range calls f repeatedly passing it each element in v in order as argument until either
+// all elements have been visited or f returns false. v must not be changed by f.
+func (v *MutVector[T]) Range(f func(T) bool) {
+	v.t.v.Range(f)
+}
+
+// Snapshot returns an immutable Vector with the current contents of v. Later changes to v
+// do not affect the snapshot.
+func (v *MutVector[T]) Snapshot() *Vector[T] {
+	snapshot := v.t.Persistent()
+	v.t = snapshot.Transient()
+	return snapshot
+}
+
+// ////////////
+// / MutMap ///
+// ////////////
+
+// A MutMap is a mutable map with an API similar to that of a native map, intended to ease
+// migration of imperative code. It maintains a persistent Map internally, Snapshot returns
+// the current contents as an immutable Map in constant time.
+//
+// A MutMap is not safe for concurrent use.
+type MutMap[K comparable, V any] struct {
+	m *Map[K, V]
+}
+
+// NewMutMap returns a new MutMap with the contents of m.
+func NewMutMap[K comparable, V any](m *Map[K, V]) *MutMap[K, V] {
+	return &MutMap[K, V]{m: m}
+}
+
+// Len returns the number of items in m.
+func (m *MutMap[K, V]) Len() int {
+	return m.m.Len()
+}
+
+// Load returns value identified by key. ok is set to true if key exists in the map, false
+// otherwise.
+func (m *MutMap[K, V]) Load(key K) (value V, ok bool) {
+	return m.m.Load(key)
+}
+
+// Get returns the value identified by key, or the zero value if key does not exist, like
+// indexing a native map.
+func (m *MutMap[K, V]) Get(key K) V {
+	value, _ := m.m.Load(key)
+	return value
+}
+
+// Set stores value identified by key, replacing any previous value.
+func (m *MutMap[K, V]) Set(key K, value V) {
+	m.m = m.m.Store(key, value)
+}
+
+// Delete removes the item identified by key, if present.
+func (m *MutMap[K, V]) Delete(key K) {
+	m.m = m.m.Delete(key)
+}
+
+// Range calls f repeatedly passing it each key and value in m as argument until either all
+// items have been visited or f returns false. Changes made to m by f are not visible to the
+// iteration.
+func (m *MutMap[K, V]) Range(f func(K, V) bool) {
+	m.m.Range(f)
+}
+
+// Snapshot returns an immutable Map with the current contents of m. Later changes to m do
+// not affect the snapshot.
+func (m *MutMap[K, V]) Snapshot() *Map[K, V] {
+	return m.m
+}
@@ -0,0 +1,69 @@
+package peds
+
+import "testing"
+
+func TestMutVector(t *testing.T) {
+	for _, size := range testSizes {
+		original := NewVector(inputSlice(0, size)...)
+		v := NewMutVector(original)
+		for i := 0; i < size; i++ {
+			v.Set(i, v.Get(i)*2)
+		}
+
+		first := v.Snapshot()
+		v.Append(-1, -2)
+		if size > 0 {
+			v.Set(0, 100)
+		}
+
+		second := v.Snapshot()
+		assertEqual(t, size+2, v.Len())
+		assertEqual(t, size, first.Len())
+		assertEqual(t, size+2, second.Len())
+		for i := 0; i < size; i++ {
+			assertEqual(t, i, original.Get(i))
+			assertEqual(t, 2*i, first.Get(i))
+			if i > 0 {
+				assertEqual(t, 2*i, second.Get(i))
+			}
+		}
+
+		assertEqual(t, -2, second.Get(size+1))
+		count := 0
+		v.Range(func(int) bool {
+			count++
+			return true
+		})
+
+		assertEqual(t, size+2, count)
+	}
+}
+
+func TestMutMap(t *testing.T) {
+	original := NewMap[string, int]().Store("a", 1)
+	m := NewMutMap(original)
+	m.Set("b", 2)
+	m.Set("a", m.Get("a")+10)
+	assertEqual(t, 0, m.Get("missing"))
+
+	snapshot := m.Snapshot()
+	m.Delete("b")
+	m.Set("c", 3)
+
+	_, ok := m.Load("b")
+	assertEqualBool(t, false, ok)
+	assertEqual(t, 2, m.Len())
+	assertEqual(t, 2, snapshot.Len())
+	value, _ := snapshot.Load("a")
+	assertEqual(t, 11, value)
+	value, _ = original.Load("a")
+	assertEqual(t, 1, value)
+
+	sum := 0
+	m.Range(func(_ string, value int) bool {
+		sum += value
+		return true
+	})
+
+	assertEqual(t, 14, sum)
+}
@@ -0,0 +1,147 @@
+package peds
+
+import (
+	"runtime"
+	"sync"
+)
+
+// rangeLeavesParallel splits the leaves of v into at most workers contiguous partitions and
+// calls f concurrently for each of them with the partition number and the element range
+// [start,stop) it covers. Partition boundaries are aligned to leaves so that no two
+// goroutines visit the same leaf. A workers count of zero or less means GOMAXPROCS.
+func (v *Vector[T]) rangeLeavesParallel(workers int, f func(partition int, start, stop uint)) int {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	leafCount := int((v.len + nodeSize - 1) >> shiftSize)
+	workers = min(workers, leafCount)
+	var wg sync.WaitGroup
+	for p := 0; p < workers; p++ {
+		start := uint(leafCount*p/workers) << shiftSize
+		stop := uintMin(uint(leafCount*(p+1)/workers)<<shiftSize, v.len)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f(p, start, stop)
+		}()
+	}
+
+	wg.Wait()
+	return workers
+}
+
+// ParallelRange calls f once for each element in v using up to workers goroutines, or
+// GOMAXPROCS goroutines if workers is zero or less. Elements are processed concurrently and
+// in no particular order so f must be safe for concurrent use. ParallelRange returns when
+// all elements have been processed.
+func (v *Vector[T]) ParallelRange(workers int, f func(T)) {
+	v.rangeLeavesParallel(workers, func(_ int, start, stop uint) {
+		for i := start; i < stop; i += nodeSize {
+			for _, item := range v.sliceFor(i) {
+				f(item)
+			}
+		}
+	})
+}
+
+// ParallelReduce works like Reduce but reduces contiguous partitions of v concurrently, using
+// up to GOMAXPROCS goroutines, and then merges the partial results, in order, using combine.
+// Each partition starts from init so init must be an identity value for combine, and combine
+// must be associative, for the result to match that of Reduce.
+func ParallelReduce[T, A any](v *Vector[T], init A, f func(A, T) A, combine func(A, A) A) A {
+	partials := make([]A, runtime.GOMAXPROCS(0))
+	partitions := v.rangeLeavesParallel(len(partials), func(p int, start, stop uint) {
+		acc := init
+		for i := start; i < stop; i += nodeSize {
+			for _, item := range v.sliceFor(i) {
+				acc = f(acc, item)
+			}
+		}
+
+		partials[p] = acc
+	})
+
+	result := init
+	for _, partial := range partials[:partitions] {
+		result = combine(result, partial)
+	}
+
+	return result
+}
+
+// parallelMapMinItems is the smallest number of items per goroutine for which NewMapParallel
+// builds the map concurrently.
+const parallelMapMinItems = 4096
+
+type positionedItem[K comparable, V any] struct {
+	pos  int
+	item MapItem[K, V]
+}
+
+// NewMapParallel returns a new map containing all items in items, like NewMap, but hashes and
+// distributes the items to buckets using up to workers goroutines, or GOMAXPROCS goroutines
+// if workers is zero or less. If a key occurs more than once the last value wins.
+//
+// The input is split into one contiguous chunk per goroutine which are hashed concurrently,
+// each item being assigned to the shard owning the range of buckets for its key. The shards
+// are then filled concurrently, visiting the chunks in order to preserve last-wins semantics.
+func NewMapParallel[K comparable, V any](workers int, items ...MapItem[K, V]) *Map[K, V] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	workers = min(workers, len(items)/parallelMapMinItems)
+	if workers <= 1 {
+		return NewMap(items...)
+	}
+
+	buckets := newPrivateItemBuckets[K, V](len(items), nil, nil)
+	bucketCount := len(buckets.buckets)
+	shardOf := func(pos int) int { return pos * workers / bucketCount }
+
+	// sharded[chunk][shard] holds the items of chunk that belong to buckets in shard
+	sharded := make([][][]positionedItem[K, V], workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		chunk := items[len(items)*w/workers : len(items)*(w+1)/workers]
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			shards := make([][]positionedItem[K, V], workers)
+			for _, item := range chunk {
+				pos := buckets.pos(item.Key)
+				shard := shardOf(pos)
+				shards[shard] = append(shards[shard], positionedItem[K, V]{pos: pos, item: item})
+			}
+
+			sharded[w] = shards
+		}()
+	}
+
+	wg.Wait()
+
+	// Each shard writes to its own range of buckets so no synchronization is needed
+	lengths := make([]int, workers)
+	for shard := 0; shard < workers; shard++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b := &privateItemBuckets[K, V]{buckets: buckets.buckets}
+			for _, shards := range sharded {
+				for _, p := range shards[shard] {
+					b.addItemAt(p.pos, p.item)
+				}
+			}
+
+			lengths[shard] = b.length
+		}()
+	}
+
+	wg.Wait()
+	for _, length := range lengths {
+		buckets.length += length
+	}
+
+	return buckets.toMap()
+}
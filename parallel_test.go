@@ -0,0 +1,69 @@
+package peds
+
+import (
+	"fmt"
+	"sync/atomic"
+	"testing"
+)
+
+func TestParallelRange(t *testing.T) {
+	for _, l := range testSizes {
+		for _, workers := range []int{0, 1, 3, 100} {
+			t.Run(fmt.Sprintf("ParallelRange %d %d", l, workers), func(t *testing.T) {
+				vec := NewVector(inputSlice(0, l)...)
+				var sum, count atomic.Int64
+				vec.ParallelRange(workers, func(item int) {
+					sum.Add(int64(item))
+					count.Add(1)
+				})
+
+				assertEqual(t, l, int(count.Load()))
+				assertEqual(t, l*(l-1)/2, int(sum.Load()))
+			})
+		}
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("ParallelReduce %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			add := func(a, b int) int { return a + b }
+			assertEqual(t, l*(l-1)/2, ParallelReduce(vec, 0, add, add))
+
+			// Order is preserved when combining partial results
+			concat := ParallelReduce(vec, NewVector[int](),
+				func(acc *Vector[int], item int) *Vector[int] { return acc.Append(item) },
+				func(a, b *Vector[int]) *Vector[int] { return a.Concat(b) })
+			assertEqualBool(t, true, VectorEqual(vec, concat))
+		})
+	}
+}
+
+func TestNewMapParallel(t *testing.T) {
+	for _, l := range []int{0, 10, 4096, 3*4096 + 17, 100000} {
+		for _, workers := range []int{0, 1, 3, 16} {
+			t.Run(fmt.Sprintf("NewMapParallel %d %d", l, workers), func(t *testing.T) {
+				// Every key occurs twice, the second value must win
+				items := make([]MapItem[int, int], 0, 2*l)
+				for i := 0; i < l; i++ {
+					items = append(items, MapItem[int, int]{Key: i, Value: -i})
+				}
+
+				for i := 0; i < l; i++ {
+					items = append(items, MapItem[int, int]{Key: i, Value: i})
+				}
+
+				m := NewMapParallel(workers, items...)
+				assertEqual(t, l, m.Len())
+				for i := 0; i < l; i++ {
+					value, ok := m.Load(i)
+					assertEqualBool(t, true, ok)
+					assertEqual(t, i, value)
+				}
+
+				assertEqualBool(t, true, MapEqual(NewMap(items...), m))
+			})
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package peds
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInvalidPatch is returned when applying a patch that does not fit the collection.
+var ErrInvalidPatch = errors.New("peds: invalid patch")
+
+var editKindNames = map[EditKind]string{EditInsert: "insert", EditDelete: "delete", EditReplace: "replace"}
+
+// MarshalText encodes k as its name, e.g. "insert".
+func (k EditKind) MarshalText() ([]byte, error) {
+	name, ok := editKindNames[k]
+	if !ok {
+		return nil, fmt.Errorf("peds: unknown edit kind %d", k)
+	}
+
+	return []byte(name), nil
+}
+
+// UnmarshalText decodes a name produced by MarshalText.
+func (k *EditKind) UnmarshalText(text []byte) error {
+	for kind, name := range editKindNames {
+		if name == string(text) {
+			*k = kind
+			return nil
+		}
+	}
+
+	return fmt.Errorf("peds: unknown edit kind %q", text)
+}
+
+// A VectorPatch is an edit script, as produced by Vector.DiffFunc, that can be applied to a
+// vector. Patches can be serialized, for example using encoding/json or encoding/gob, to
+// replicate changes to a vector in another process.
+type VectorPatch[T any] []VectorEdit[T]
+
+// Apply returns a new vector with the edits in patch applied to v. The nodes holding the
+// elements before the first edit are shared with v. ErrInvalidPatch is returned if the edits
+// are not ordered by index or refer to elements outside of v.
+func (v *Vector[T]) Apply(patch VectorPatch[T]) (*Vector[T], error) {
+	replaceOnly := true
+	for _, e := range patch {
+		replaceOnly = replaceOnly && e.Kind == EditReplace
+	}
+
+	if replaceOnly {
+		// Replacements do not move any elements and can be done in place
+		updates := make(map[int]T)
+		for _, e := range patch {
+			if e.Index < 0 || e.Count != len(e.Items) || e.Index+e.Count > v.Len() {
+				return nil, ErrInvalidPatch
+			}
+
+			for i, item := range e.Items {
+				updates[e.Index+i] = item
+			}
+		}
+
+		return v.SetMany(updates), nil
+	}
+
+	// The elements before the first edit are unchanged, the nodes holding them are shared
+	// with v
+	first := patch[0].Index
+	if first < 0 || first > v.Len() {
+		return nil, ErrInvalidPatch
+	}
+
+	result := v.Shrink(v.Len() - first)
+	src := first
+	for _, e := range patch {
+		// Copy the unchanged elements preceding the edit
+		unchanged := e.Index - result.Len()
+		if unchanged < 0 || e.Count < 0 || src+unchanged > v.Len() {
+			return nil, ErrInvalidPatch
+		}
+
+		result = result.appendRange(v, uint(src), uint(src+unchanged))
+		src += unchanged
+		switch e.Kind {
+		case EditInsert:
+			if e.Count != len(e.Items) {
+				return nil, ErrInvalidPatch
+			}
+
+			result = result.Append(e.Items...)
+		case EditDelete:
+			src += e.Count
+		case EditReplace:
+			if e.Count != len(e.Items) {
+				return nil, ErrInvalidPatch
+			}
+
+			result = result.Append(e.Items...)
+			src += e.Count
+		default:
+			return nil, ErrInvalidPatch
+		}
+
+		if src > v.Len() {
+			return nil, ErrInvalidPatch
+		}
+	}
+
+	return result.appendRange(v, uint(src), v.len), nil
+}
+
+// A MapPatch describes the changes that turn one map into another, as produced by
+// Map.DiffFunc. Patches can be serialized, for example using encoding/json or encoding/gob,
+// to replicate changes to a map in another process.
+type MapPatch[K comparable, V any] struct {
+	// Stores holds the items that have been added or whose values have changed
+	Stores []MapItem[K, V] `json:"stores,omitempty"`
+
+	// Deletes holds the keys that have been removed
+	Deletes []K `json:"deletes,omitempty"`
+}
+
+// DiffFunc returns a patch that turns m into other, using eq to compare values. When m and
+// other are versions of the same map, buckets shared between them are skipped without
+// comparing their items.
+func (m *Map[K, V]) DiffFunc(other *Map[K, V], eq func(V, V) bool) MapPatch[K, V] {
+	var patch MapPatch[K, V]
+	diffBuckets := func(a, b privateItemBucket[K, V]) {
+		if len(a) > 0 && len(a) == len(b) && &a[0] == &b[0] {
+			return
+		}
+
+		for _, bItem := range b {
+			found := false
+			for _, aItem := range a {
+				if aItem.Key == bItem.Key {
+					found = eq(aItem.Value, bItem.Value)
+					break
+				}
+			}
+
+			if !found {
+				patch.Stores = append(patch.Stores, bItem)
+			}
+		}
+
+		for _, aItem := range a {
+			found := false
+			for _, bItem := range b {
+				if aItem.Key == bItem.Key {
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				patch.Deletes = append(patch.Deletes, aItem.Key)
+			}
+		}
+	}
+
+	if m.sameBucketLayout(other) {
+		// Items with the same key are in buckets at the same position
+		a, b := m.backingVector, other.backingVector
+		for i := uint(0); i < a.len; i += nodeSize {
+			leafA, leafB := a.sliceFor(i), b.sliceFor(i)
+			if sameLeaf(leafA, leafB) {
+				continue
+			}
+
+			for j := range leafA {
+				diffBuckets(leafA[j], leafB[j])
+			}
+		}
+
+		return patch
+	}
+
+	other.Range(func(key K, value V) bool {
+		if current, ok := m.Load(key); !ok || !eq(current, value) {
+			patch.Stores = append(patch.Stores, MapItem[K, V]{Key: key, Value: value})
+		}
+
+		return true
+	})
+
+	m.Range(func(key K, _ V) bool {
+		if _, ok := other.Load(key); !ok {
+			patch.Deletes = append(patch.Deletes, key)
+		}
+
+		return true
+	})
+
+	return patch
+}
+
+// MapDiff returns a patch that turns a into b, see Map.DiffFunc.
+func MapDiff[K, V comparable](a, b *Map[K, V]) MapPatch[K, V] {
+	return a.DiffFunc(b, func(x, y V) bool { return x == y })
+}
+
+// Apply returns a new map with the changes in patch applied to m. Deletes are applied before
+// stores.
+func (m *Map[K, V]) Apply(patch MapPatch[K, V]) *Map[K, V] {
+	result := m
+	for _, key := range patch.Deletes {
+		result = result.Delete(key)
+	}
+
+	for _, item := range patch.Stores {
+		result = result.Store(item.Key, item.Value)
+	}
+
+	return result
+}
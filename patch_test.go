@@ -0,0 +1,116 @@
+package peds
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestVectorPatchApply(t *testing.T) {
+	a := NewVector(inputSlice(0, 1000)...)
+	b := a.Set(5, -5).RemoveRange(100, 110).Append(-1, -2)
+	b = NewVector[int]().appendRange(b, 0, 500).Append(-3).appendRange(b, 500, b.len)
+
+	patch := VectorDiff(a, b)
+	result, err := a.Apply(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(b, result))
+}
+
+func TestVectorPatchReplaceOnlySharesStructure(t *testing.T) {
+	a := NewVector(inputSlice(0, 10000)...)
+	b := a.Set(5000, -1)
+	result, err := a.Apply(VectorDiff(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(b, result))
+	assertEqualBool(t, true, sameLeaf(a.sliceFor(0), result.sliceFor(0)))
+}
+
+func TestVectorPatchInsertSharesPrefix(t *testing.T) {
+	a := NewVector(inputSlice(0, 100000)...)
+	patch := VectorPatch[int]{{Kind: EditInsert, Index: 99990, Count: 1, Items: []int{-1}}}
+	result, err := a.Apply(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, -1, result.Get(99990))
+	assertEqual(t, 99990, result.Get(99991))
+	assertEqual(t, 100001, result.Len())
+
+	// Only the nodes on the path to the last leaf before the edit, and the tail, are new
+	aNodes, _ := SharedNodes(a, a)
+	_, total := SharedNodes(a, result)
+	assertEqualBool(t, true, total-aNodes < 10)
+}
+
+func TestVectorPatchJSONRoundTrip(t *testing.T) {
+	a := NewVector("a", "b", "c", "d")
+	b := NewVector("a", "x", "c", "d", "e")
+	data, err := json.Marshal(VectorDiff(a, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `[{"kind":"replace","index":1,"count":1,"items":["x"]},{"kind":"insert","index":4,"count":1,"items":["e"]}]`, string(data))
+
+	var patch VectorPatch[string]
+	if err := json.Unmarshal(data, &patch); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := a.Apply(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(b, result))
+}
+
+func TestVectorPatchInvalid(t *testing.T) {
+	v := NewVector(1, 2, 3)
+	for _, patch := range []VectorPatch[int]{
+		{{Kind: EditDelete, Index: 2, Count: 2}},
+		{{Kind: EditReplace, Index: 2, Count: 2, Items: []int{1, 2}}},
+		{{Kind: EditInsert, Index: 4, Count: 1, Items: []int{1}}},
+		{{Kind: EditInsert, Index: 2, Count: 1, Items: []int{1}}, {Kind: EditDelete, Index: 1, Count: 1}},
+		{{Kind: EditInsert, Index: 0, Count: 2, Items: []int{1}}},
+	} {
+		_, err := v.Apply(patch)
+		assertEqualBool(t, true, err == ErrInvalidPatch)
+	}
+}
+
+func TestMapPatch(t *testing.T) {
+	items := make(map[int]string)
+	for i := 0; i < 1000; i++ {
+		items[i] = fmt.Sprint(i)
+	}
+
+	a := NewMapFromNativeMap(items)
+	b := a.Store(5, "five").Store(1000, "1000").Delete(7)
+	patch := MapDiff(a, b)
+	assertEqual(t, 2, len(patch.Stores))
+	assertEqual(t, 1, len(patch.Deletes))
+	assertEqualBool(t, true, MapEqual(b, a.Apply(patch)))
+
+	// Different bucket layouts
+	c := NewMapFromNativeMap(map[int]string{1: "1", 2: "two", 3000: "3000"})
+	patch = MapDiff(a, c)
+	assertEqual(t, 2, len(patch.Stores))
+	assertEqual(t, 998, len(patch.Deletes))
+	assertEqualBool(t, true, MapEqual(c, a.Apply(patch)))
+
+	data, err := json.Marshal(MapDiff(c, c.Store(4, "4")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, `{"stores":[{"Key":4,"Value":"4"}]}`, string(data))
+}
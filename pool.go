@@ -0,0 +1,80 @@
+package peds
+
+import "sync"
+
+// scratchPools holds one sync.Pool of scratch slices per element type, keyed by a nil
+// pointer to the element type. Scratch slices are the temporary slices used while building
+// collections that are discarded once the collection has been built, such as the bucket
+// table of a MapBuilder and the leaf and branch lists of a VectorBuilder. Recycling them
+// avoids allocating them anew for each build.
+var scratchPools sync.Map
+
+// freeListKey is the key of the pool of free lists of element type E in freeListPools.
+type freeListKey[E any] struct{}
+
+// freeListPools holds one sync.Pool of free lists per element type, keyed by freeListKey.
+// A free list holds allocations discarded by a builder, for example the buckets of a
+// MapBuilder that were emptied by rehashing, ready to be handed out again. Unlike scratch
+// slices the elements of a free list are kept when it is recycled.
+var freeListPools sync.Map
+
+func poolFor(pools *sync.Map, key any) *sync.Pool {
+	if pool, ok := pools.Load(key); ok {
+		return pool.(*sync.Pool)
+	}
+
+	pool, _ := pools.LoadOrStore(key, &sync.Pool{})
+	return pool.(*sync.Pool)
+}
+
+// getScratch returns a pointer to an empty slice with room for at least capacity elements,
+// recycled from an earlier call to putScratch if possible. The pointer, rather than the slice,
+// is handed back to putScratch so that recycling does not allocate.
+func getScratch[E any](capacity int) *[]E {
+	pool := poolFor(&scratchPools, (*E)(nil))
+	if s, ok := pool.Get().(*[]E); ok {
+		if cap(*s) >= capacity {
+			*s = (*s)[:0]
+			return s
+		}
+
+		// Too small for this use, keep it for a smaller one
+		pool.Put(s)
+	}
+
+	s := make([]E, 0, capacity)
+	return &s
+}
+
+// putScratch makes the slice s points to available for reuse by getScratch. Neither s nor
+// the slice must be used by the caller afterwards.
+func putScratch[E any](s *[]E) {
+	if cap(*s) == 0 {
+		return
+	}
+
+	// Drop any references held by the slice so that they can be garbage collected
+	clear((*s)[:cap(*s)])
+	*s = (*s)[:0]
+	poolFor(&scratchPools, (*E)(nil)).Put(s)
+}
+
+// getFreeList returns a pointer to a free list recycled from an earlier call to putFreeList,
+// or to an empty one if there is none.
+func getFreeList[E any]() *[]E {
+	if s, ok := poolFor(&freeListPools, freeListKey[E]{}).Get().(*[]E); ok {
+		return s
+	}
+
+	return new([]E)
+}
+
+// putFreeList makes the free list s points to available for reuse by getFreeList. The
+// elements must not reference anything that should be garbage collected.
+func putFreeList[E any](s *[]E) {
+	if len(*s) == 0 {
+		return
+	}
+
+	poolFor(&freeListPools, freeListKey[E]{}).Put(s)
+}
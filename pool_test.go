@@ -0,0 +1,77 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestScratchIsClearedAndLargeEnough(t *testing.T) {
+	s := getScratch[*int](10)
+	assertEqual(t, 0, len(*s))
+	assertEqualBool(t, true, cap(*s) >= 10)
+
+	x := 1
+	*s = append(*s, &x, &x)
+	putScratch(s)
+
+	// Recycled or not, the slice is empty, has the requested capacity and holds no references
+	s = getScratch[*int](5)
+	assertEqual(t, 0, len(*s))
+	for _, p := range (*s)[:cap(*s)] {
+		if p != nil {
+			t.Fatalf("Expected recycled scratch slice to be cleared")
+		}
+	}
+
+	assertEqualBool(t, true, cap(*getScratch[*int](1000)) >= 1000)
+}
+
+func TestMapBuilderReusesDiscardedBuckets(t *testing.T) {
+	b := NewMapBuilder[int, int]()
+	for i := 0; i < 1000; i++ {
+		b.Set(i, i)
+	}
+
+	// Rehashing while growing has discarded buckets, which are reused for new keys
+	spare := len(*b.buckets.spare)
+	assertEqualBool(t, true, spare > 0)
+	for _, bucket := range *b.buckets.spare {
+		assertEqual(t, 0, len(bucket))
+		for _, item := range bucket[:cap(bucket)] {
+			assertEqualBool(t, true, item == MapItem[int, int]{})
+		}
+	}
+
+	b.Delete(5)
+	b.Set(5000, 5000)
+	m := b.Freeze()
+	assertEqual(t, 1000, m.Len())
+	assertEqual(t, 5000, m.LoadOr(5000, -1))
+	assertEqual(t, -1, m.LoadOr(5, -1))
+	assertInvariants(t, m)
+}
+
+func TestRepeatedBuildersDoNotShareState(t *testing.T) {
+	var maps []*Map[string, int]
+	var vectors []*Vector[int]
+	for round := 0; round < 10; round++ {
+		mb := NewMapBuilderWithCapacity[string, int](10)
+		vb := NewVectorBuilder[int](100)
+		for i := 0; i < 200; i++ {
+			mb.Set(fmt.Sprint(i), round*1000+i)
+			vb.Append(round*1000 + i)
+		}
+
+		maps = append(maps, mb.Freeze())
+		vectors = append(vectors, vb.Freeze())
+	}
+
+	for round := range maps {
+		assertEqual(t, 200, maps[round].Len())
+		assertEqual(t, 200, vectors[round].Len())
+		for i := 0; i < 200; i++ {
+			assertEqual(t, round*1000+i, maps[round].LoadOr(fmt.Sprint(i), -1))
+			assertEqual(t, round*1000+i, vectors[round].Get(i))
+		}
+	}
+}
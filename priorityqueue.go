@@ -0,0 +1,93 @@
+package peds
+
+import "cmp"
+
+// ///////////////////
+// / PriorityQueue ///
+// ///////////////////
+
+type prioritized[T any, P cmp.Ordered] struct {
+	item     T
+	priority P
+}
+
+// minPriority is the measure of a sequence of prioritized items, the lowest priority in the
+// sequence. ok is false for the empty sequence.
+type minPriority[P cmp.Ordered] struct {
+	priority P
+	ok       bool
+}
+
+type minPriorityMeasurer[T any, P cmp.Ordered] struct{}
+
+func (minPriorityMeasurer[T, P]) Zero() minPriority[P] {
+	return minPriority[P]{}
+}
+
+func (minPriorityMeasurer[T, P]) Measure(item prioritized[T, P]) minPriority[P] {
+	return minPriority[P]{priority: item.priority, ok: true}
+}
+
+func (minPriorityMeasurer[T, P]) Combine(a, b minPriority[P]) minPriority[P] {
+	if !a.ok || (b.ok && cmp.Less(b.priority, a.priority)) {
+		return b
+	}
+
+	return a
+}
+
+// A PriorityQueue is a persistent/immutable priority queue where the element with the lowest
+// priority is served first. Elements with equal priority are served in the order they were
+// pushed. It is backed by a FingerTree measured by the lowest priority, pushing is amortized
+// constant time and popping is logarithmic.
+type PriorityQueue[T any, P cmp.Ordered] struct {
+	tree *FingerTree[prioritized[T, P], minPriority[P]]
+}
+
+// NewPriorityQueue returns a new, empty, priority queue.
+func NewPriorityQueue[T any, P cmp.Ordered]() *PriorityQueue[T, P] {
+	return &PriorityQueue[T, P]{tree: NewFingerTree[prioritized[T, P], minPriority[P]](minPriorityMeasurer[T, P]{})}
+}
+
+// Len returns the number of elements in q.
+func (q *PriorityQueue[T, P]) Len() int {
+	return q.tree.Len()
+}
+
+// Push returns a new queue with item added with the given priority.
+func (q *PriorityQueue[T, P]) Push(item T, priority P) *PriorityQueue[T, P] {
+	return &PriorityQueue[T, P]{tree: q.tree.PushBack(prioritized[T, P]{item: item, priority: priority})}
+}
+
+// Peek returns the element with the lowest priority in q and its priority. ok is set to false
+// if q is empty.
+func (q *PriorityQueue[T, P]) Peek() (item T, priority P, ok bool) {
+	if q.Len() == 0 {
+		return item, priority, false
+	}
+
+	_, right := q.split()
+	first, _ := right.PeekFront()
+	return first.item, first.priority, true
+}
+
+// Pop returns the element with the lowest priority in q, its priority and a new queue with
+// that element removed.
+func (q *PriorityQueue[T, P]) Pop() (T, P, *PriorityQueue[T, P]) {
+	if q.Len() == 0 {
+		panic("Pop from empty priority queue")
+	}
+
+	left, right := q.split()
+	first, rest := right.PopFront()
+	return first.item, first.priority, &PriorityQueue[T, P]{tree: left.Concat(rest)}
+}
+
+// split splits the tree of q, which must not be empty, before the first element with the
+// lowest priority.
+func (q *PriorityQueue[T, P]) split() (left, right *FingerTree[prioritized[T, P], minPriority[P]]) {
+	lowest := q.tree.Measure().priority
+	return q.tree.Split(func(m minPriority[P]) bool {
+		return m.ok && !cmp.Less(lowest, m.priority)
+	})
+}
@@ -0,0 +1,90 @@
+package peds
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestPriorityQueuePopsInPriorityOrder(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	priorities := r.Perm(1000)
+	q := NewPriorityQueue[int, int]()
+	for _, p := range priorities {
+		q = q.Push(-p, p)
+	}
+
+	assertEqual(t, 1000, q.Len())
+	for i := 0; i < 1000; i++ {
+		peeked, peekedPriority, ok := q.Peek()
+		assertEqualBool(t, true, ok)
+
+		var item, priority int
+		item, priority, q = q.Pop()
+		assertEqual(t, i, priority)
+		assertEqual(t, -i, item)
+		assertEqual(t, priority, peekedPriority)
+		assertEqual(t, item, peeked)
+	}
+
+	assertEqual(t, 0, q.Len())
+}
+
+func TestPriorityQueueEqualPrioritiesAreFIFO(t *testing.T) {
+	q := NewPriorityQueue[string, int]()
+	q = q.Push("a", 2).Push("b", 1).Push("c", 2).Push("d", 1).Push("e", 2)
+
+	var result []string
+	for q.Len() > 0 {
+		var item string
+		item, _, q = q.Pop()
+		result = append(result, item)
+	}
+
+	assertEqualString(t, "bdace", result[0]+result[1]+result[2]+result[3]+result[4])
+}
+
+func TestPriorityQueueIsPersistent(t *testing.T) {
+	q := NewPriorityQueue[int, float64]().Push(1, 1.5).Push(2, 0.5)
+	_, _, q2 := q.Pop()
+	q3 := q.Push(3, 0.1)
+
+	item, _, _ := q.Peek()
+	assertEqual(t, 2, item)
+	item, _, _ = q2.Peek()
+	assertEqual(t, 1, item)
+	item, _, _ = q3.Peek()
+	assertEqual(t, 3, item)
+	assertEqual(t, 2, q.Len())
+}
+
+func TestPriorityQueueInterleavedPushAndPop(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	q := NewPriorityQueue[int, int]()
+	var reference []int
+	for i := 0; i < 2000; i++ {
+		if r.Intn(3) > 0 || len(reference) == 0 {
+			p := r.Intn(100)
+			q = q.Push(p, p)
+			reference = append(reference, p)
+			sort.Ints(reference)
+			continue
+		}
+
+		var item int
+		item, _, q = q.Pop()
+		assertEqual(t, reference[0], item)
+		reference = reference[1:]
+	}
+
+	assertEqual(t, len(reference), q.Len())
+}
+
+func TestPriorityQueueEmpty(t *testing.T) {
+	q := NewPriorityQueue[int, int]()
+	_, _, ok := q.Peek()
+	assertEqualBool(t, false, ok)
+
+	defer assertPanic(t, "Pop from empty priority queue")
+	q.Pop()
+}
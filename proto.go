@@ -0,0 +1,346 @@
+package peds
+
+import "encoding/binary"
+
+// ProtoWireType is the wire type of a field in the protocol buffer binary format.
+type ProtoWireType int
+
+const (
+	ProtoWireVarint  ProtoWireType = 0
+	ProtoWireFixed64 ProtoWireType = 1
+	ProtoWireBytes   ProtoWireType = 2
+	ProtoWireFixed32 ProtoWireType = 5
+)
+
+// A ProtoCodec encodes and decodes single values of type T in the protocol buffer binary
+// format, without the field tag. Values of wire type ProtoWireBytes include their length prefix.
+type ProtoCodec[T any] interface {
+	Codec[T]
+	WireType() ProtoWireType
+}
+
+type protoCodec[T any] struct {
+	Codec[T]
+	wireType ProtoWireType
+}
+
+func (c protoCodec[T]) WireType() ProtoWireType {
+	return c.wireType
+}
+
+// protoIntCodec encodes signed integers as two's complement varints, as used by the int32
+// and int64 protocol buffer types.
+type protoIntCodec[I int | int32 | int64] struct{}
+
+func (protoIntCodec[I]) Append(dst []byte, value I) ([]byte, error) {
+	return binary.AppendUvarint(dst, uint64(value)), nil
+}
+
+func (protoIntCodec[I]) Decode(data []byte) (I, int, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 || int64(I(int64(x))) != int64(x) {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return I(int64(x)), n, nil
+}
+
+type protoBoolCodec struct{}
+
+func (protoBoolCodec) Append(dst []byte, value bool) ([]byte, error) {
+	return boolCodec{}.Append(dst, value)
+}
+
+func (protoBoolCodec) Decode(data []byte) (bool, int, error) {
+	x, n := binary.Uvarint(data)
+	if n <= 0 {
+		return false, 0, ErrInvalidBinaryData
+	}
+
+	return x != 0, n, nil
+}
+
+type protoMessageCodec[T any] struct {
+	marshal   func(T) ([]byte, error)
+	unmarshal func([]byte) (T, error)
+}
+
+func (c protoMessageCodec[T]) Append(dst []byte, value T) ([]byte, error) {
+	b, err := c.marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return appendLengthPrefixed(dst, b), nil
+}
+
+func (c protoMessageCodec[T]) Decode(data []byte) (T, int, error) {
+	b, n, err := decodeLengthPrefixed(data)
+	if err != nil {
+		var zero T
+		return zero, 0, err
+	}
+
+	value, err := c.unmarshal(b)
+	return value, n, err
+}
+
+// ProtoIntCodec returns a codec for the protocol buffer int32 and int64 types.
+func ProtoIntCodec[I int | int32 | int64]() ProtoCodec[I] {
+	return protoCodec[I]{Codec: protoIntCodec[I]{}, wireType: ProtoWireVarint}
+}
+
+// ProtoSintCodec returns a codec for the zigzag encoded protocol buffer sint32 and sint64 types.
+func ProtoSintCodec[I int | int32 | int64]() ProtoCodec[I] {
+	return protoCodec[I]{Codec: varintCodec[I]{}, wireType: ProtoWireVarint}
+}
+
+// ProtoUintCodec returns a codec for the protocol buffer uint32 and uint64 types.
+func ProtoUintCodec[U uint | uint32 | uint64]() ProtoCodec[U] {
+	return protoCodec[U]{Codec: uvarintCodec[U]{}, wireType: ProtoWireVarint}
+}
+
+// ProtoBoolCodec returns a codec for the protocol buffer bool type.
+func ProtoBoolCodec() ProtoCodec[bool] {
+	return protoCodec[bool]{Codec: protoBoolCodec{}, wireType: ProtoWireVarint}
+}
+
+// ProtoDoubleCodec returns a codec for the protocol buffer double type.
+func ProtoDoubleCodec() ProtoCodec[float64] {
+	return protoCodec[float64]{Codec: float64Codec{}, wireType: ProtoWireFixed64}
+}
+
+// ProtoFloatCodec returns a codec for the protocol buffer float type.
+func ProtoFloatCodec() ProtoCodec[float32] {
+	return protoCodec[float32]{Codec: float32Codec{}, wireType: ProtoWireFixed32}
+}
+
+// ProtoStringCodec returns a codec for the protocol buffer string type.
+func ProtoStringCodec() ProtoCodec[string] {
+	return protoCodec[string]{Codec: stringCodec{}, wireType: ProtoWireBytes}
+}
+
+// ProtoBytesCodec returns a codec for the protocol buffer bytes type.
+func ProtoBytesCodec() ProtoCodec[[]byte] {
+	return protoCodec[[]byte]{Codec: bytesCodec{}, wireType: ProtoWireBytes}
+}
+
+// ProtoMessageCodec returns a codec for embedded messages, encoded and decoded by marshal and
+// unmarshal. Typically these wrap proto.Marshal and proto.Unmarshal for a generated type.
+func ProtoMessageCodec[T any](marshal func(T) ([]byte, error), unmarshal func([]byte) (T, error)) ProtoCodec[T] {
+	return protoCodec[T]{Codec: protoMessageCodec[T]{marshal: marshal, unmarshal: unmarshal}, wireType: ProtoWireBytes}
+}
+
+func appendProtoTag(dst []byte, field int, wireType ProtoWireType) []byte {
+	return binary.AppendUvarint(dst, uint64(field)<<3|uint64(wireType))
+}
+
+// readProtoTag returns the field number and wire type of the field starting at data together
+// with the number of bytes consumed.
+func readProtoTag(data []byte) (int, ProtoWireType, int, error) {
+	tag, n := binary.Uvarint(data)
+	if n <= 0 || tag>>3 == 0 || tag>>3 > 1<<29-1 {
+		return 0, 0, 0, ErrInvalidBinaryData
+	}
+
+	return int(tag >> 3), ProtoWireType(tag & 7), n, nil
+}
+
+// protoValueLen returns the length of the value, of type wireType, at the start of data.
+func protoValueLen(data []byte, wireType ProtoWireType) (int, error) {
+	switch wireType {
+	case ProtoWireVarint:
+		if _, n := binary.Uvarint(data); n > 0 {
+			return n, nil
+		}
+	case ProtoWireFixed64:
+		if len(data) >= 8 {
+			return 8, nil
+		}
+	case ProtoWireBytes:
+		_, n, err := decodeLengthPrefixed(data)
+		return n, err
+	case ProtoWireFixed32:
+		if len(data) >= 4 {
+			return 4, nil
+		}
+	}
+
+	return 0, ErrInvalidBinaryData
+}
+
+// rangeProtoFields calls f with the field number, wire type and value of each field in the
+// message in data. The value passed to f starts at the value and extends to the end of data.
+func rangeProtoFields(data []byte, f func(field int, wireType ProtoWireType, value []byte) error) error {
+	for len(data) > 0 {
+		field, wireType, n, err := readProtoTag(data)
+		if err != nil {
+			return err
+		}
+
+		data = data[n:]
+		size, err := protoValueLen(data, wireType)
+		if err != nil {
+			return err
+		}
+
+		if err := f(field, wireType, data); err != nil {
+			return err
+		}
+
+		data = data[size:]
+	}
+
+	return nil
+}
+
+// AppendProtoRepeated appends v to dst encoded as the repeated field with number field, using
+// codec to encode the elements. Scalar elements are packed, as is the default for proto3.
+func AppendProtoRepeated[T any](dst []byte, field int, v *Vector[T], codec ProtoCodec[T]) ([]byte, error) {
+	if v.Len() == 0 {
+		return dst, nil
+	}
+
+	var err error
+	if codec.WireType() != ProtoWireBytes {
+		var packed []byte
+		v.Range(func(item T) bool {
+			packed, err = codec.Append(packed, item)
+			return err == nil
+		})
+
+		if err != nil {
+			return nil, err
+		}
+
+		return appendLengthPrefixed(appendProtoTag(dst, field, ProtoWireBytes), packed), nil
+	}
+
+	v.Range(func(item T) bool {
+		dst, err = codec.Append(appendProtoTag(dst, field, ProtoWireBytes), item)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// DecodeProtoRepeated decodes the repeated field with number field from the message in data,
+// using codec to decode the elements. Both packed and unpacked scalar fields are accepted,
+// other fields in the message are skipped.
+func DecodeProtoRepeated[T any](data []byte, field int, codec ProtoCodec[T]) (*Vector[T], error) {
+	var b leafBuilder[T]
+	err := rangeProtoFields(data, func(f int, wireType ProtoWireType, value []byte) error {
+		if f != field {
+			return nil
+		}
+
+		if wireType == ProtoWireBytes && codec.WireType() != ProtoWireBytes {
+			packed, _, err := decodeLengthPrefixed(value)
+			for err == nil && len(packed) > 0 {
+				var item T
+				var n int
+				if item, n, err = codec.Decode(packed); err == nil {
+					b.add(item)
+					packed = packed[n:]
+				}
+			}
+
+			return err
+		}
+
+		if wireType != codec.WireType() {
+			return ErrInvalidBinaryData
+		}
+
+		item, _, err := codec.Decode(value)
+		if err == nil {
+			b.add(item)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.vector(), nil
+}
+
+// AppendProtoMap appends m to dst encoded as the map field with number field, using keyCodec
+// and valueCodec to encode the keys and values.
+func AppendProtoMap[K comparable, V any](dst []byte, field int, m *Map[K, V], keyCodec ProtoCodec[K], valueCodec ProtoCodec[V]) ([]byte, error) {
+	var entry []byte
+	var err error
+	m.Range(func(key K, value V) bool {
+		if entry, err = keyCodec.Append(appendProtoTag(entry[:0], 1, keyCodec.WireType()), key); err != nil {
+			return false
+		}
+
+		if entry, err = valueCodec.Append(appendProtoTag(entry, 2, valueCodec.WireType()), value); err != nil {
+			return false
+		}
+
+		dst = appendLengthPrefixed(appendProtoTag(dst, field, ProtoWireBytes), entry)
+		return true
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return dst, nil
+}
+
+// DecodeProtoMap decodes the map field with number field from the message in data, using
+// keyCodec and valueCodec to decode the keys and values. Other fields in the message are
+// skipped. As in protocol buffers a missing key or value decodes as the zero value and the
+// last entry wins if a key occurs more than once.
+func DecodeProtoMap[K comparable, V any](data []byte, field int, keyCodec ProtoCodec[K], valueCodec ProtoCodec[V]) (*Map[K, V], error) {
+	b := NewMapBuilder[K, V]()
+	err := rangeProtoFields(data, func(f int, wireType ProtoWireType, value []byte) error {
+		if f != field {
+			return nil
+		}
+
+		if wireType != ProtoWireBytes {
+			return ErrInvalidBinaryData
+		}
+
+		entry, _, err := decodeLengthPrefixed(value)
+		if err != nil {
+			return err
+		}
+
+		var key K
+		var val V
+		err = rangeProtoFields(entry, func(f int, wireType ProtoWireType, value []byte) (err error) {
+			switch {
+			case f == 1 && wireType == keyCodec.WireType():
+				key, _, err = keyCodec.Decode(value)
+			case f == 2 && wireType == valueCodec.WireType():
+				val, _, err = valueCodec.Decode(value)
+			case f == 1 || f == 2:
+				err = ErrInvalidBinaryData
+			}
+
+			return err
+		})
+
+		if err == nil {
+			b.Set(key, val)
+		}
+
+		return err
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return b.Freeze(), nil
+}
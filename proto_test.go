@@ -0,0 +1,129 @@
+package peds
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestAppendProtoRepeatedPacked(t *testing.T) {
+	// Example from the protocol buffer encoding documentation
+	data, err := AppendProtoRepeated(nil, 4, NewVector[int32](3, 270, 86942), ProtoIntCodec[int32]())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []byte{0x22, 0x06, 0x03, 0x8e, 0x02, 0x9e, 0xa7, 0x05}
+	if !bytes.Equal(expected, data) {
+		t.Errorf("Expected %x, got %x", expected, data)
+	}
+
+	v, err := DecodeProtoRepeated(data, 4, ProtoIntCodec[int32]())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(NewVector[int32](3, 270, 86942), v))
+}
+
+func TestDecodeProtoRepeatedUnpackedAndOtherFields(t *testing.T) {
+	// Field 1 = 150 unpacked, field 2 = "x", field 1 = -1 unpacked, field 3 = fixed32
+	data := []byte{0x08, 0x96, 0x01, 0x12, 0x01, 'x',
+		0x08, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01,
+		0x1d, 0x01, 0x02, 0x03, 0x04}
+
+	v, err := DecodeProtoRepeated(data, 1, ProtoIntCodec[int64]())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(NewVector[int64](150, -1), v))
+
+	strings, err := DecodeProtoRepeated(data, 2, ProtoStringCodec())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 1, strings.Len())
+	assertEqualString(t, "x", strings.Get(0))
+}
+
+func TestProtoRepeatedRoundTrip(t *testing.T) {
+	strings := NewVector("a", "", "ccc")
+	data, err := AppendProtoRepeated(nil, 7, strings, ProtoStringCodec())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Non scalar elements are not packed
+	assertEqual(t, 3+2+5, len(data))
+	data, _ = AppendProtoRepeated(data, 8, NewVector(1.5, -2), ProtoDoubleCodec())
+	data, _ = AppendProtoRepeated(data, 9, NewVector(true, false), ProtoBoolCodec())
+	data, _ = AppendProtoRepeated(data, 10, NewVector(-3, 3), ProtoSintCodec[int]())
+
+	decoded, err := DecodeProtoRepeated(data, 7, ProtoStringCodec())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualBool(t, true, VectorEqual(strings, decoded))
+	doubles, _ := DecodeProtoRepeated(data, 8, ProtoDoubleCodec())
+	assertEqualBool(t, true, VectorEqual(NewVector(1.5, -2), doubles))
+	bools, _ := DecodeProtoRepeated(data, 9, ProtoBoolCodec())
+	assertEqualBool(t, true, VectorEqual(NewVector(true, false), bools))
+	sints, _ := DecodeProtoRepeated(data, 10, ProtoSintCodec[int]())
+	assertEqualBool(t, true, VectorEqual(NewVector(-3, 3), sints))
+	empty, _ := DecodeProtoRepeated(data, 11, ProtoSintCodec[int]())
+	assertEqual(t, 0, empty.Len())
+}
+
+func TestProtoMap(t *testing.T) {
+	data, err := AppendProtoMap(nil, 1, NewMap[string, int32]().Store("a", 1), ProtoStringCodec(), ProtoIntCodec[int32]())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []byte{0x0a, 0x05, 0x0a, 0x01, 'a', 0x10, 0x01}
+	if !bytes.Equal(expected, data) {
+		t.Errorf("Expected %x, got %x", expected, data)
+	}
+
+	m := NewMap[string, int32]()
+	for i := int32(0); i < 100; i++ {
+		m = m.Store(string(rune('a'+i)), i)
+	}
+
+	data, _ = AppendProtoMap(nil, 3, m, ProtoStringCodec(), ProtoIntCodec[int32]())
+	// An entry without a value decodes to the zero value
+	data = append(data, 0x1a, 0x03, 0x0a, 0x01, 'a')
+	decoded, err := DecodeProtoMap(data, 3, ProtoStringCodec(), ProtoIntCodec[int32]())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualBool(t, true, MapEqual(m, decoded))
+}
+
+func TestProtoMessageCodec(t *testing.T) {
+	codec := ProtoMessageCodec(
+		func(s string) ([]byte, error) { return []byte(s), nil },
+		func(b []byte) (string, error) { return string(b), nil })
+	data, _ := AppendProtoRepeated(nil, 1, NewVector("msg"), codec)
+	decoded, err := DecodeProtoRepeated(data, 1, codec)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualString(t, "msg", decoded.Get(0))
+}
+
+func TestDecodeProtoInvalid(t *testing.T) {
+	for _, data := range [][]byte{{0x08}, {0x0a, 0x05, 0x01}, {0x0b}, {0x00, 0x01}, {0x09, 0x01}} {
+		_, err := DecodeProtoRepeated(data, 1, ProtoIntCodec[int]())
+		assertEqualBool(t, true, errors.Is(err, ErrInvalidBinaryData))
+	}
+
+	// Wrong wire type for an unpacked string
+	_, err := DecodeProtoRepeated([]byte{0x08, 0x01}, 1, ProtoStringCodec())
+	assertEqualBool(t, true, errors.Is(err, ErrInvalidBinaryData))
+}
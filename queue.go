@@ -0,0 +1,47 @@
+package peds
+
+// ///////////
+// / Queue ///
+// ///////////
+
+// A Queue is a persistent/immutable FIFO queue.
+type Queue[T any] struct {
+	deque *Deque[T]
+}
+
+// NewQueue returns a new queue containing the items provided in items, the first item
+// being the first one to be dequeued.
+func NewQueue[T any](items ...T) *Queue[T] {
+	return &Queue[T]{deque: NewDeque(items...)}
+}
+
+// Len returns the number of elements in q.
+func (q *Queue[T]) Len() int {
+	return q.deque.Len()
+}
+
+// Enqueue returns a new queue with item added to the back of it.
+func (q *Queue[T]) Enqueue(item T) *Queue[T] {
+	return &Queue[T]{deque: q.deque.PushBack(item)}
+}
+
+// Dequeue returns the element at the front of q and a new queue with that element removed.
+func (q *Queue[T]) Dequeue() (T, *Queue[T]) {
+	if q.Len() == 0 {
+		panic("Dequeue from empty queue")
+	}
+
+	item, deque := q.deque.PopFront()
+	return item, &Queue[T]{deque: deque}
+}
+
+// Peek returns the element at the front of q. ok is set to false if q is empty.
+func (q *Queue[T]) Peek() (item T, ok bool) {
+	return q.deque.PeekFront()
+}
+
+// Range calls f repeatedly passing it each element in q, in the order they would be
+// dequeued, as argument until either all elements have been visited or f returns false.
+func (q *Queue[T]) Range(f func(T) bool) {
+	q.deque.Range(f)
+}
@@ -0,0 +1,61 @@
+package peds
+
+import "testing"
+
+func TestQueueEnqueueAndDequeue(t *testing.T) {
+	q := NewQueue(0, 1)
+	for i := 2; i < 100; i++ {
+		q = q.Enqueue(i)
+	}
+
+	assertEqual(t, 100, q.Len())
+	q2 := q
+	for i := 0; i < 100; i++ {
+		item, ok := q2.Peek()
+		assertEqualBool(t, true, ok)
+		assertEqual(t, i, item)
+
+		item, q2 = q2.Dequeue()
+		assertEqual(t, i, item)
+	}
+
+	assertEqual(t, 0, q2.Len())
+	_, ok := q2.Peek()
+	assertEqualBool(t, false, ok)
+
+	// The original queue is unchanged
+	assertEqual(t, 100, q.Len())
+	item, _ := q.Peek()
+	assertEqual(t, 0, item)
+}
+
+func TestQueueInterleaved(t *testing.T) {
+	q := NewQueue[int]()
+	next, expected := 0, 0
+	for round := 0; round < 50; round++ {
+		for i := 0; i < 3; i++ {
+			q = q.Enqueue(next)
+			next++
+		}
+
+		for i := 0; i < 2; i++ {
+			var item int
+			item, q = q.Dequeue()
+			assertEqual(t, expected, item)
+			expected++
+		}
+	}
+
+	count := 0
+	q.Range(func(item int) bool {
+		assertEqual(t, expected+count, item)
+		count++
+		return true
+	})
+	assertEqual(t, 50, count)
+}
+
+func TestQueueDequeueEmpty(t *testing.T) {
+	defer assertPanic(t, "Dequeue from empty queue")
+	NewQueue[int]().Dequeue()
+}
@@ -0,0 +1,42 @@
+package peds
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing/quick"
+)
+
+// quickValue returns a random value of type T, generated as described by quick.Value.
+func quickValue[T any](r *rand.Rand) T {
+	value, ok := quick.Value(reflect.TypeFor[T](), r)
+	if !ok {
+		panic(fmt.Sprintf("Unable to generate random values of type %s", reflect.TypeFor[T]()))
+	}
+
+	return value.Interface().(T)
+}
+
+// Generate implements quick.Generator, making it possible to use vectors as arguments to
+// functions tested with testing/quick. The generated vector holds up to size random
+// elements. The receiver is not used, it is typically nil.
+func (v *Vector[T]) Generate(r *rand.Rand, size int) reflect.Value {
+	var b leafBuilder[T]
+	for n := r.Intn(size + 1); n > 0; n-- {
+		b.add(quickValue[T](r))
+	}
+
+	return reflect.ValueOf(b.vector())
+}
+
+// Generate implements quick.Generator, making it possible to use maps as arguments to
+// functions tested with testing/quick. The generated map holds up to size random items. The
+// receiver is not used, it is typically nil.
+func (m *Map[K, V]) Generate(r *rand.Rand, size int) reflect.Value {
+	b := NewMapBuilder[K, V]()
+	for n := r.Intn(size + 1); n > 0; n-- {
+		b.Set(quickValue[K](r), quickValue[V](r))
+	}
+
+	return reflect.ValueOf(b.Freeze())
+}
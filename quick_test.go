@@ -0,0 +1,50 @@
+package peds
+
+import (
+	"testing"
+	"testing/quick"
+)
+
+func TestVectorQuickGenerator(t *testing.T) {
+	reverse := func(v *Vector[int]) *Vector[int] {
+		result := NewVector[int]()
+		v.Backward()(func(_ int, item int) bool {
+			result = result.Append(item)
+			return true
+		})
+
+		return result
+	}
+
+	f := func(v *Vector[int]) bool {
+		return v.Len() <= 50 && reverse(reverse(v)).Equal(v)
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMapQuickGenerator(t *testing.T) {
+	sizes := 0
+	f := func(m *Map[string, []byte]) bool {
+		sizes += m.Len()
+		return NewMapFromNativeMap(m.ToNativeMap()).Equal(m)
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+
+	assertEqualBool(t, true, sizes > 0)
+}
+
+func TestNestedQuickGenerator(t *testing.T) {
+	f := func(v *Vector[*Map[int, int]]) bool {
+		return Reduce(v, 0, func(acc int, m *Map[int, int]) int { return acc + m.Len() }) >= 0
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
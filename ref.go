@@ -0,0 +1,98 @@
+package peds
+
+import (
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// /////////
+// / Ref ///
+// /////////
+
+// A Ref is a mutable reference to an immutable value, typically one of the persistent
+// collections, that can safely be shared between goroutines. Readers always see a
+// consistent version of the value while writers replace it atomically.
+type Ref[T any] struct {
+	value atomic.Pointer[T]
+
+	// watchers is replaced, never modified, under watchersLock so that it can be read
+	// without locking when notifying
+	watchersLock sync.Mutex
+	watchers     atomic.Pointer[[]*refWatcher[T]]
+}
+
+type refWatcher[T any] struct {
+	f func(old, new T)
+}
+
+// NewRef returns a new Ref holding value.
+func NewRef[T any](value T) *Ref[T] {
+	r := &Ref[T]{}
+	r.value.Store(&value)
+	return r
+}
+
+// Load returns the current value of r.
+func (r *Ref[T]) Load() T {
+	return *r.value.Load()
+}
+
+// Store replaces the value of r with value.
+func (r *Ref[T]) Store(value T) {
+	old := r.value.Swap(&value)
+	r.notify(*old, value)
+}
+
+// Update replaces the value of r with the result of calling f with the current value and
+// returns the new value. If another goroutine changes the value of r while f is running f is
+// called again with the new value, f should therefore be free from side effects.
+func (r *Ref[T]) Update(f func(T) T) T {
+	for {
+		old := r.value.Load()
+		value := f(*old)
+		if r.value.CompareAndSwap(old, &value) {
+			r.notify(*old, value)
+			return value
+		}
+	}
+}
+
+// Watch registers f to be called with the old and new value each time the value of r is
+// changed by Store or Update. f is called synchronously by the goroutine making the change,
+// after the change has taken effect. When r is changed concurrently from several goroutines
+// the notifications may arrive in a different order than the changes were made. The returned
+// function unregisters f.
+func (r *Ref[T]) Watch(f func(old, new T)) (unwatch func()) {
+	w := &refWatcher[T]{f: f}
+	r.updateWatchers(func(watchers []*refWatcher[T]) []*refWatcher[T] {
+		return append(slices.Clip(watchers), w)
+	})
+
+	return func() {
+		r.updateWatchers(func(watchers []*refWatcher[T]) []*refWatcher[T] {
+			return slices.DeleteFunc(slices.Clone(watchers), func(other *refWatcher[T]) bool { return other == w })
+		})
+	}
+}
+
+func (r *Ref[T]) updateWatchers(f func([]*refWatcher[T]) []*refWatcher[T]) {
+	r.watchersLock.Lock()
+	defer r.watchersLock.Unlock()
+
+	var watchers []*refWatcher[T]
+	if current := r.watchers.Load(); current != nil {
+		watchers = *current
+	}
+
+	watchers = f(watchers)
+	r.watchers.Store(&watchers)
+}
+
+func (r *Ref[T]) notify(old, new T) {
+	if watchers := r.watchers.Load(); watchers != nil {
+		for _, w := range *watchers {
+			w.f(old, new)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package peds
+
+import (
+	"slices"
+	"sync"
+	"testing"
+)
+
+func TestRefLoadAndStore(t *testing.T) {
+	r := NewRef(NewVector(1, 2, 3))
+	assertEqual(t, 3, r.Load().Len())
+	r.Store(NewVector(1))
+	assertEqual(t, 1, r.Load().Len())
+}
+
+func TestRefConcurrentUpdate(t *testing.T) {
+	r := NewRef(NewMap[int, int]())
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				key := g*100 + i
+				r.Update(func(m *Map[int, int]) *Map[int, int] { return m.Store(key, i) })
+			}
+		}()
+	}
+
+	wg.Wait()
+	assertEqual(t, 800, r.Load().Len())
+}
+
+func TestRefWatch(t *testing.T) {
+	r := NewRef(NewVector[int]())
+	var changes []int
+	unwatch := r.Watch(func(old, new *Vector[int]) {
+		assertEqual(t, old.Len()+1, new.Len())
+		changes = append(changes, new.Len())
+	})
+
+	calls := 0
+	r.Watch(func(old, new *Vector[int]) { calls++ })
+
+	r.Store(NewVector(1))
+	r.Update(func(v *Vector[int]) *Vector[int] { return v.Append(2) })
+	unwatch()
+	r.Update(func(v *Vector[int]) *Vector[int] { return v.Append(3) })
+
+	assertEqualBool(t, true, slices.Equal([]int{1, 2}, changes))
+	assertEqual(t, 3, calls)
+}
+
+func TestRefWatchConcurrentUpdates(t *testing.T) {
+	r := NewRef(0)
+	var mu sync.Mutex
+	notifications := 0
+	r.Watch(func(old, new int) {
+		mu.Lock()
+		notifications++
+		mu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				r.Update(func(v int) int { return v + 1 })
+			}
+		}()
+	}
+
+	wg.Wait()
+	assertEqual(t, 800, r.Load())
+	assertEqual(t, 800, notifications)
+}
@@ -0,0 +1,192 @@
+package peds
+
+import "sort"
+
+// ///////////////
+// / RLEVector ///
+// ///////////////
+
+// rleRun is a run of equal values ending, exclusively, at end. The start of a run is the end
+// of the previous run.
+type rleRun[T comparable] struct {
+	value T
+	end   int
+}
+
+// An RLEVector is a persistent/immutable sequence that stores consecutive equal elements as a
+// single run. Memory usage is proportional to the number of runs rather than the number of
+// elements, making it suitable for data with long runs of identical values such as tile maps
+// and flag arrays. Access by index is logarithmic in the number of runs.
+type RLEVector[T comparable] struct {
+	// Run ends are absolute positions, element i of the vector is found at position
+	// offset+i. This allows slicing without rewriting the ends of all runs. The runs
+	// cover exactly the positions [offset,offset+len).
+	runs   *RRBVector[rleRun[T]]
+	offset int
+	len    int
+}
+
+// NewRLEVector returns a new vector containing the items provided in items.
+func NewRLEVector[T comparable](items ...T) *RLEVector[T] {
+	return (&RLEVector[T]{runs: NewRRBVector[rleRun[T]]()}).Append(items...)
+}
+
+// Len returns the number of elements in v.
+func (v *RLEVector[T]) Len() int {
+	return v.len
+}
+
+// RunCount returns the number of runs of equal elements in v.
+func (v *RLEVector[T]) RunCount() int {
+	return v.runs.Len()
+}
+
+// find returns the index of the run holding element i.
+func (v *RLEVector[T]) find(i int) int {
+	pos := v.offset + i
+	return sort.Search(v.runs.Len(), func(k int) bool { return v.runs.Get(k).end > pos })
+}
+
+func (v *RLEVector[T]) runStart(k int) int {
+	if k == 0 {
+		return v.offset
+	}
+
+	return v.runs.Get(k - 1).end
+}
+
+// Get returns the element at position i.
+func (v *RLEVector[T]) Get(i int) T {
+	if i < 0 || i >= v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.len, Type: "RLEVector"})
+	}
+
+	return v.runs.Get(v.find(i)).value
+}
+
+// Set returns a new vector with the element at position i set to item.
+func (v *RLEVector[T]) Set(i int, item T) *RLEVector[T] {
+	if i < 0 || i >= v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.len, Type: "RLEVector"})
+	}
+
+	k := v.find(i)
+	run := v.runs.Get(k)
+	if run.value == item {
+		return v
+	}
+
+	// Replace the run, and its neighbours so that they can be merged with the new element,
+	// with the runs resulting from the update.
+	lo, hi := max(k-1, 0), min(k+2, v.runs.Len())
+	pos, start := v.offset+i, v.runStart(k)
+	var runs []rleRun[T]
+	if lo < k {
+		runs = append(runs, v.runs.Get(lo))
+	}
+
+	if pos > start {
+		runs = appendRun(runs, rleRun[T]{value: run.value, end: pos})
+	}
+
+	runs = appendRun(runs, rleRun[T]{value: item, end: pos + 1})
+	if pos+1 < run.end {
+		runs = appendRun(runs, rleRun[T]{value: run.value, end: run.end})
+	}
+
+	if k+1 < hi {
+		runs = appendRun(runs, v.runs.Get(k+1))
+	}
+
+	left, rest := v.runs.Split(lo)
+	_, right := rest.Split(hi - lo)
+	return &RLEVector[T]{runs: left.Concat(NewRRBVector(runs...)).Concat(right), offset: v.offset, len: v.len}
+}
+
+// appendRun appends run to runs, extending the last run instead if it holds the same value.
+func appendRun[T comparable](runs []rleRun[T], run rleRun[T]) []rleRun[T] {
+	if len(runs) > 0 && runs[len(runs)-1].value == run.value {
+		runs[len(runs)-1].end = run.end
+		return runs
+	}
+
+	return append(runs, run)
+}
+
+// Append returns a new vector with item(s) appended to it.
+func (v *RLEVector[T]) Append(items ...T) *RLEVector[T] {
+	if len(items) == 0 {
+		return v
+	}
+
+	runs := v.runs
+	var newRuns []rleRun[T]
+	end := v.offset + v.len
+	if runs.Len() > 0 {
+		// Include the last run so that it can be extended
+		last := runs.Len() - 1
+		newRuns = append(newRuns, runs.Get(last))
+		runs, _ = runs.Split(last)
+	}
+
+	for _, item := range items {
+		end++
+		newRuns = appendRun(newRuns, rleRun[T]{value: item, end: end})
+	}
+
+	return &RLEVector[T]{runs: runs.Concat(NewRRBVector(newRuns...)), offset: v.offset, len: v.len + len(items)}
+}
+
+// Slice returns a new vector containing the elements [start,stop) of v. The runs are shared
+// with v.
+func (v *RLEVector[T]) Slice(start, stop int) *RLEVector[T] {
+	assertSliceOk(start, stop, v.len)
+	if start == stop {
+		return NewRLEVector[T]()
+	}
+
+	first, last := v.find(start), v.find(stop-1)
+	_, runs := v.runs.Split(first)
+	runs, _ = runs.Split(last - first + 1)
+	lastRun := runs.Get(runs.Len() - 1)
+	lastRun.end = v.offset + stop
+	runs = runs.Set(runs.Len()-1, lastRun)
+	return &RLEVector[T]{runs: runs, offset: v.offset + start, len: stop - start}
+}
+
+// RangeRuns calls f repeatedly passing it each run of equal elements in v, as the value and
+// the number of elements in the run, in order until either all runs have been visited or f
+// returns false.
+func (v *RLEVector[T]) RangeRuns(f func(value T, count int) bool) {
+	start := v.offset
+	v.runs.Range(func(run rleRun[T]) bool {
+		count := run.end - start
+		start = run.end
+		return f(run.value, count)
+	})
+}
+
+// Range calls f repeatedly passing it each element in v in order as argument until either
+// all elements have been visited or f returns false.
+func (v *RLEVector[T]) Range(f func(T) bool) {
+	v.RangeRuns(func(value T, count int) bool {
+		for i := 0; i < count; i++ {
+			if !f(value) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// ToNativeSlice returns a Go slice containing all elements of v.
+func (v *RLEVector[T]) ToNativeSlice() []T {
+	result := make([]T, 0, v.len)
+	v.Range(func(item T) bool {
+		result = append(result, item)
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,94 @@
+package peds
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func assertRLEContents(t *testing.T, expected []int, v *RLEVector[int]) {
+	t.Helper()
+	assertEqual(t, len(expected), v.Len())
+	actual := v.ToNativeSlice()
+	assertEqual(t, len(expected), len(actual))
+	for i := range expected {
+		assertEqual(t, expected[i], actual[i])
+		assertEqual(t, expected[i], v.Get(i))
+	}
+}
+
+func TestRLEVectorRuns(t *testing.T) {
+	v := NewRLEVector(1, 1, 1, 2, 2, 3)
+	assertRLEContents(t, []int{1, 1, 1, 2, 2, 3}, v)
+	assertEqual(t, 3, v.RunCount())
+
+	v = v.Append(3, 3, 1)
+	assertEqual(t, 4, v.RunCount())
+
+	// Setting an element to the value of its neighbours merges the runs
+	w := v.Set(5, 2).Set(6, 2).Set(7, 2)
+	assertRLEContents(t, []int{1, 1, 1, 2, 2, 2, 2, 2, 1}, w)
+	assertEqual(t, 3, w.RunCount())
+
+	// Setting an element in the middle of a run splits it
+	w = w.Set(4, 9)
+	assertRLEContents(t, []int{1, 1, 1, 2, 9, 2, 2, 2, 1}, w)
+	assertEqual(t, 5, w.RunCount())
+	assertRLEContents(t, []int{1, 1, 1, 2, 2, 3, 3, 3, 1}, v)
+}
+
+func TestRLEVectorSlice(t *testing.T) {
+	v := NewRLEVector(1, 1, 1, 2, 2, 3, 3, 3)
+	s := v.Slice(1, 7)
+	assertRLEContents(t, []int{1, 1, 2, 2, 3, 3}, s)
+	assertRLEContents(t, []int{1, 1, 2, 2, 3, 3, 3, 4}, s.Append(3, 4))
+	assertRLEContents(t, []int{2, 2, 3}, s.Slice(2, 5))
+	assertRLEContents(t, []int{5, 1, 2, 2, 3, 3}, s.Set(0, 5))
+	assertRLEContents(t, []int{}, v.Slice(3, 3))
+
+	counts := []int{}
+	s.RangeRuns(func(value, count int) bool {
+		counts = append(counts, count)
+		return true
+	})
+
+	assertEqual(t, 3, len(counts))
+	assertEqual(t, 2, counts[0])
+}
+
+func TestRLEVectorRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	var expected []int
+	v := NewRLEVector[int]()
+	for i := 0; i < 3000; i++ {
+		switch {
+		case i%5 == 0 || len(expected) == 0:
+			item := rnd.Intn(3)
+			expected = append(expected, item)
+			v = v.Append(item)
+		case i%97 == 0:
+			start := rnd.Intn(len(expected))
+			stop := start + rnd.Intn(len(expected)-start+1)
+			expected = append([]int(nil), expected[start:stop]...)
+			v = v.Slice(start, stop)
+		default:
+			pos, item := rnd.Intn(len(expected)), rnd.Intn(3)
+			expected[pos] = item
+			v = v.Set(pos, item)
+		}
+	}
+
+	assertRLEContents(t, expected, v)
+	runs := 0
+	for i := range expected {
+		if i == 0 || expected[i] != expected[i-1] {
+			runs++
+		}
+	}
+
+	assertEqual(t, runs, v.RunCount())
+}
+
+func TestRLEVectorGetOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewRLEVector(1, 2).Get(2)
+}
@@ -0,0 +1,224 @@
+package peds
+
+import "strings"
+
+// //////////
+// / Rope ///
+// //////////
+
+// Leaves hold at most ropeLeafSize bytes.
+const ropeLeafSize = 512
+
+// ropeNode is a node of the tree of a Rope. The tree is kept balanced like an AVL tree, the
+// heights of the two subtrees of a node differ by at most one, which bounds the depth to about
+// 1.44 log2 of the number of leaves.
+type ropeNode struct {
+	left, right *ropeNode
+	leaf        string
+	length      int
+
+	// depth is the height of the subtree rooted at the node, zero for leaves
+	depth int
+}
+
+func newRopeLeaf(s string) *ropeNode {
+	if s == "" {
+		return nil
+	}
+
+	return &ropeNode{leaf: s, length: len(s)}
+}
+
+func newRopeBranch(a, b *ropeNode) *ropeNode {
+	return &ropeNode{left: a, right: b, length: a.length + b.length, depth: max(a.depth, b.depth) + 1}
+}
+
+// joinRope concatenates a and b, merging small leaves. The taller tree is descended along its
+// inner edge until a subtree of about the same height as the other tree is found, the two are
+// joined there and the nodes on the path are rebalanced on the way back up. This takes time
+// proportional to the difference in height between a and b.
+func joinRope(a, b *ropeNode) *ropeNode {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.depth > b.depth+1:
+		return balanceRope(a.left, joinRope(a.right, b))
+	case b.depth > a.depth+1:
+		return balanceRope(joinRope(a, b.left), b.right)
+	case a.depth == 0 && b.depth == 0 && a.length+b.length <= ropeLeafSize:
+		return newRopeLeaf(a.leaf + b.leaf)
+	default:
+		return newRopeBranch(a, b)
+	}
+}
+
+// balanceRope returns a node joining a and b, whose heights differ by at most two, rotating
+// the taller one if needed to restore the balance.
+func balanceRope(a, b *ropeNode) *ropeNode {
+	switch {
+	case a.depth > b.depth+1:
+		if a.left.depth >= a.right.depth {
+			return newRopeBranch(a.left, newRopeBranch(a.right, b))
+		}
+
+		return newRopeBranch(newRopeBranch(a.left, a.right.left), newRopeBranch(a.right.right, b))
+	case b.depth > a.depth+1:
+		if b.right.depth >= b.left.depth {
+			return newRopeBranch(newRopeBranch(a, b.left), b.right)
+		}
+
+		return newRopeBranch(newRopeBranch(a, b.left.left), newRopeBranch(b.left.right, b.right))
+	default:
+		return newRopeBranch(a, b)
+	}
+}
+
+// buildRope returns a balanced tree with leaves as its leaves.
+func buildRope(leaves []*ropeNode) *ropeNode {
+	switch len(leaves) {
+	case 0:
+		return nil
+	case 1:
+		return leaves[0]
+	}
+
+	mid := len(leaves) / 2
+	return joinRope(buildRope(leaves[:mid]), buildRope(leaves[mid:]))
+}
+
+func (n *ropeNode) appendLeaves(leaves []*ropeNode) []*ropeNode {
+	if n == nil {
+		return leaves
+	}
+
+	if n.depth == 0 {
+		return append(leaves, n)
+	}
+
+	return n.right.appendLeaves(n.left.appendLeaves(leaves))
+}
+
+func splitRope(n *ropeNode, i int) (*ropeNode, *ropeNode) {
+	switch {
+	case n == nil:
+		return nil, nil
+	case i <= 0:
+		return nil, n
+	case i >= n.length:
+		return n, nil
+	case n.depth == 0:
+		return newRopeLeaf(n.leaf[:i]), newRopeLeaf(n.leaf[i:])
+	case i <= n.left.length:
+		l1, l2 := splitRope(n.left, i)
+		return l1, joinRope(l2, n.right)
+	default:
+		r1, r2 := splitRope(n.right, i-n.left.length)
+		return joinRope(n.left, r1), r2
+	}
+}
+
+// A Rope is a persistent/immutable sequence of bytes, typically text, stored as a balanced
+// tree of string chunks. Unlike for a string, inserting, deleting, splitting and concatenating
+// only copy a logarithmic number of tree nodes, making ropes suitable for editor buffers and assembly
+// of large texts.
+type Rope struct {
+	root *ropeNode
+}
+
+// NewRope returns a new rope containing s.
+func NewRope(s string) *Rope {
+	leaves := make([]*ropeNode, 0, len(s)/ropeLeafSize+1)
+	for start := 0; start < len(s); start += ropeLeafSize {
+		leaves = append(leaves, newRopeLeaf(s[start:min(start+ropeLeafSize, len(s))]))
+	}
+
+	return &Rope{root: buildRope(leaves)}
+}
+
+// Len returns the length of r in bytes.
+func (r *Rope) Len() int {
+	if r.root == nil {
+		return 0
+	}
+
+	return r.root.length
+}
+
+// Index returns the byte at position i.
+func (r *Rope) Index(i int) byte {
+	if i < 0 || i >= r.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: r.Len(), Type: "Rope"})
+	}
+
+	n := r.root
+	for n.depth > 0 {
+		if i < n.left.length {
+			n = n.left
+		} else {
+			i -= n.left.length
+			n = n.right
+		}
+	}
+
+	return n.leaf[i]
+}
+
+// Concat returns a new rope containing r followed by other.
+func (r *Rope) Concat(other *Rope) *Rope {
+	return &Rope{root: joinRope(r.root, other.root)}
+}
+
+// Split returns two new ropes, one containing the bytes before position i and one containing
+// the bytes from position i.
+func (r *Rope) Split(i int) (*Rope, *Rope) {
+	assertSliceOk(i, i, r.Len())
+	left, right := splitRope(r.root, i)
+	return &Rope{root: left}, &Rope{root: right}
+}
+
+// Slice returns a new rope containing the bytes [start,stop) of r.
+func (r *Rope) Slice(start, stop int) *Rope {
+	assertSliceOk(start, stop, r.Len())
+	left, _ := splitRope(r.root, stop)
+	_, middle := splitRope(left, start)
+	return &Rope{root: middle}
+}
+
+// Insert returns a new rope with s inserted before position i.
+func (r *Rope) Insert(i int, s string) *Rope {
+	assertSliceOk(i, i, r.Len())
+	left, right := splitRope(r.root, i)
+	return &Rope{root: joinRope(joinRope(left, NewRope(s).root), right)}
+}
+
+// Delete returns a new rope with the bytes [start,stop) removed.
+func (r *Rope) Delete(start, stop int) *Rope {
+	assertSliceOk(start, stop, r.Len())
+	left, rest := splitRope(r.root, start)
+	_, right := splitRope(rest, stop-start)
+	return &Rope{root: joinRope(left, right)}
+}
+
+// RangeChunks calls f repeatedly passing it consecutive chunks of the contents of r, in order,
+// until either all chunks have been visited or f returns false.
+func (r *Rope) RangeChunks(f func(string) bool) {
+	for _, leaf := range r.root.appendLeaves(nil) {
+		if !f(leaf.leaf) {
+			return
+		}
+	}
+}
+
+// String returns the contents of r as a string.
+func (r *Rope) String() string {
+	var b strings.Builder
+	b.Grow(r.Len())
+	r.RangeChunks(func(chunk string) bool {
+		b.WriteString(chunk)
+		return true
+	})
+
+	return b.String()
+}
@@ -0,0 +1,118 @@
+package peds
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// assertRopeBalanced checks the cached lengths and heights of the nodes of r, that sibling
+// subtrees differ in height by at most one and that the height is logarithmic in the number
+// of leaves.
+func assertRopeBalanced(t *testing.T, r *Rope) {
+	t.Helper()
+	var check func(n *ropeNode) bool
+	check = func(n *ropeNode) bool {
+		if n.depth == 0 {
+			return n.left == nil && n.right == nil && n.length == len(n.leaf) && n.length > 0
+		}
+
+		return check(n.left) && check(n.right) &&
+			n.length == n.left.length+n.right.length &&
+			n.depth == max(n.left.depth, n.right.depth)+1 &&
+			n.left.depth-n.right.depth <= 1 && n.right.depth-n.left.depth <= 1
+	}
+
+	if r.root == nil {
+		return
+	}
+
+	if !check(r.root) {
+		t.Fatal("Rope tree is not balanced")
+	}
+
+	leaves := len(r.root.appendLeaves(nil))
+	if limit := 1.45 * math.Log2(float64(leaves)+2); float64(r.root.depth) > limit {
+		t.Fatalf("Rope of %d leaves has height %d, expected at most %.1f", leaves, r.root.depth, limit)
+	}
+}
+
+func TestRopeEditing(t *testing.T) {
+	r := NewRope("hello world")
+	r = r.Insert(5, ",").Insert(r.Len()+1, "!")
+	assertEqualString(t, "hello, world!", r.String())
+	assertEqualString(t, "world", r.Slice(7, 12).String())
+	assertEqualString(t, "hello!", r.Delete(5, 12).String())
+	assertEqualString(t, "hello, world! bye", r.Concat(NewRope(" bye")).String())
+	assertEqual(t, int('w'), int(r.Index(7)))
+
+	left, right := r.Split(6)
+	assertEqualString(t, "hello,", left.String())
+	assertEqualString(t, " world!", right.String())
+	assertEqual(t, 0, NewRope("").Len())
+}
+
+func TestRopeRandomEdits(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	text := strings.Repeat("0123456789", 1000)
+	r := NewRope(text)
+	for i := 0; i < 2000; i++ {
+		pos := rnd.Intn(len(text) + 1)
+		if i%3 == 0 && pos < len(text) {
+			stop := min(len(text), pos+rnd.Intn(100))
+			text = text[:pos] + text[stop:]
+			r = r.Delete(pos, stop)
+		} else {
+			s := strings.Repeat("x", rnd.Intn(20))
+			text = text[:pos] + s + text[pos:]
+			r = r.Insert(pos, s)
+		}
+	}
+
+	assertEqual(t, len(text), r.Len())
+	assertEqualString(t, text, r.String())
+	assertRopeBalanced(t, r)
+	for i := 0; i < len(text); i += 97 {
+		assertEqual(t, int(text[i]), int(r.Index(i)))
+	}
+}
+
+func TestRopeAppendManyStaysBalanced(t *testing.T) {
+	r := NewRope("")
+	for i := 0; i < 10000; i++ {
+		r = r.Concat(NewRope(strings.Repeat("a", 600)))
+	}
+
+	assertEqual(t, 6000000, r.Len())
+	assertRopeBalanced(t, r)
+}
+
+func TestRopePrependAndSplitStayBalanced(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	r := NewRope("")
+	for i := 0; i < 5000; i++ {
+		chunk := NewRope(strings.Repeat("b", 300))
+		if i%2 == 0 {
+			r = chunk.Concat(r)
+		} else {
+			r = r.Insert(rnd.Intn(r.Len()+1), chunk.String())
+		}
+	}
+
+	assertEqual(t, 1500000, r.Len())
+	assertRopeBalanced(t, r)
+	for i := 0; i < 100; i++ {
+		left, right := r.Split(rnd.Intn(r.Len() + 1))
+		assertRopeBalanced(t, left)
+		assertRopeBalanced(t, right)
+		assertEqual(t, r.Len(), left.Len()+right.Len())
+		r = right.Concat(left)
+		assertRopeBalanced(t, r)
+	}
+}
+
+func TestRopeIndexOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewRope("abc").Index(3)
+}
@@ -0,0 +1,471 @@
+package peds
+
+// ///////////////
+// / RRBVector ///
+// ///////////////
+
+// rrbNode is a node in a relaxed radix balanced tree. Leaf nodes hold items, internal
+// nodes hold children. Internal nodes that are not densely packed, and hence can not be
+// indexed using radix search alone, carry a table of cumulative child sizes.
+type rrbNode[T any] struct {
+	children []*rrbNode[T]
+	sizes    []int
+	items    []T
+	size     int
+}
+
+func newRRBLeaf[T any](items []T) *rrbNode[T] {
+	return &rrbNode[T]{items: items, size: len(items)}
+}
+
+// newRRBInternal returns a new internal node of height h containing children.
+// The size table is only populated if the node is relaxed, that is if any child but the
+// last is not completely full.
+func newRRBInternal[T any](children []*rrbNode[T], h uint) *rrbNode[T] {
+	childCapacity := 1 << (shiftSize * h)
+	size, dense := 0, true
+	for i, child := range children {
+		size += child.size
+		if i < len(children)-1 && child.size != childCapacity {
+			dense = false
+		}
+	}
+
+	node := &rrbNode[T]{children: children, size: size}
+	if !dense {
+		node.sizes = make([]int, len(children))
+		acc := 0
+		for i, child := range children {
+			acc += child.size
+			node.sizes[i] = acc
+		}
+	}
+
+	return node
+}
+
+// locate returns the index of the child of n, at height h, that holds element i and the
+// number of elements preceding that child.
+func (n *rrbNode[T]) locate(h uint, i int) (int, int) {
+	if n.sizes == nil {
+		idx := i >> (shiftSize * h)
+		return idx, idx << (shiftSize * h)
+	}
+
+	idx := i >> (shiftSize * h)
+	for n.sizes[idx] <= i {
+		idx++
+	}
+
+	if idx == 0 {
+		return 0, 0
+	}
+
+	return idx, n.sizes[idx-1]
+}
+
+// An RRBVector is an ordered persistent/immutable collection of items backed by a relaxed
+// radix balanced tree. Compared to Vector it trades some indexing and append performance
+// for logarithmic time concatenation, insertion, removal and splitting.
+type RRBVector[T any] struct {
+	root   *rrbNode[T]
+	height uint
+}
+
+// NewRRBVector returns a new RRBVector containing the items provided in items.
+func NewRRBVector[T any](items ...T) *RRBVector[T] {
+	if len(items) == 0 {
+		return &RRBVector[T]{root: newRRBLeaf[T](nil)}
+	}
+
+	nodes := make([]*rrbNode[T], 0, (len(items)+nodeSize-1)/nodeSize)
+	for start := 0; start < len(items); start += nodeSize {
+		stop := start + nodeSize
+		if stop > len(items) {
+			stop = len(items)
+		}
+
+		leafItems := make([]T, stop-start)
+		copy(leafItems, items[start:stop])
+		nodes = append(nodes, newRRBLeaf(leafItems))
+	}
+
+	h := uint(0)
+	for len(nodes) > 1 {
+		h++
+		parents := make([]*rrbNode[T], 0, (len(nodes)+nodeSize-1)/nodeSize)
+		for start := 0; start < len(nodes); start += nodeSize {
+			stop := start + nodeSize
+			if stop > len(nodes) {
+				stop = len(nodes)
+			}
+
+			parents = append(parents, newRRBInternal(nodes[start:stop:stop], h))
+		}
+
+		nodes = parents
+	}
+
+	return &RRBVector[T]{root: nodes[0], height: h}
+}
+
+// Len returns the length of v.
+func (v *RRBVector[T]) Len() int {
+	return v.root.size
+}
+
+// Get returns the element at position i.
+func (v *RRBVector[T]) Get(i int) T {
+	if i < 0 || i >= v.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "RRBVector"})
+	}
+
+	node := v.root
+	for h := v.height; h > 0; h-- {
+		idx, offset := node.locate(h, i)
+		node, i = node.children[idx], i-offset
+	}
+
+	return node.items[i]
+}
+
+// Set returns a new vector with the element at position i set to item.
+func (v *RRBVector[T]) Set(i int, item T) *RRBVector[T] {
+	if i < 0 || i >= v.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "RRBVector"})
+	}
+
+	return &RRBVector[T]{root: rrbAssoc(v.root, v.height, i, item), height: v.height}
+}
+
+func rrbAssoc[T any](n *rrbNode[T], h uint, i int, item T) *rrbNode[T] {
+	if h == 0 {
+		items := make([]T, len(n.items))
+		copy(items, n.items)
+		items[i] = item
+		return newRRBLeaf(items)
+	}
+
+	idx, offset := n.locate(h, i)
+	children := make([]*rrbNode[T], len(n.children))
+	copy(children, n.children)
+	children[idx] = rrbAssoc(children[idx], h-1, i-offset, item)
+	return &rrbNode[T]{children: children, sizes: n.sizes, size: n.size}
+}
+
+// Append returns a new vector with item(s) appended to it.
+func (v *RRBVector[T]) Append(items ...T) *RRBVector[T] {
+	if len(items) == 0 {
+		return v
+	}
+
+	return v.Concat(NewRRBVector(items...))
+}
+
+// Concat returns a new vector containing all elements of v followed by all elements of other.
+func (v *RRBVector[T]) Concat(other *RRBVector[T]) *RRBVector[T] {
+	if other.Len() == 0 {
+		return v
+	}
+
+	if v.Len() == 0 {
+		return other
+	}
+
+	nodes := rrbConcat(v.root, v.height, other.root, other.height)
+	h := v.height
+	if other.height > h {
+		h = other.height
+	}
+
+	if len(nodes) == 1 {
+		return &RRBVector[T]{root: nodes[0], height: h}
+	}
+
+	return &RRBVector[T]{root: newRRBInternal(nodes, h+1), height: h + 1}
+}
+
+// rrbConcat joins the trees left and right, of heights lh and rh, returning one or two nodes
+// of height max(lh, rh). Only the nodes along the seam between the two trees are copied,
+// all other nodes are shared with the input trees.
+func rrbConcat[T any](left *rrbNode[T], lh uint, right *rrbNode[T], rh uint) []*rrbNode[T] {
+	if lh > rh {
+		last := len(left.children) - 1
+		mid := rrbConcat(left.children[last], lh-1, right, rh)
+		children := make([]*rrbNode[T], 0, last+len(mid))
+		children = append(children, left.children[:last]...)
+		return rrbPack(append(children, mid...), lh)
+	}
+
+	if rh > lh {
+		mid := rrbConcat(left, lh, right.children[0], rh-1)
+		children := make([]*rrbNode[T], 0, len(mid)+len(right.children)-1)
+		children = append(children, mid...)
+		return rrbPack(append(children, right.children[1:]...), rh)
+	}
+
+	if lh == 0 {
+		if left.size+right.size <= nodeSize {
+			items := make([]T, 0, left.size+right.size)
+			items = append(items, left.items...)
+			return []*rrbNode[T]{newRRBLeaf(append(items, right.items...))}
+		}
+
+		if left.size == nodeSize {
+			return []*rrbNode[T]{left, right}
+		}
+
+		// Fill up the left leaf to keep the tree as dense as possible
+		split := nodeSize - left.size
+		leftItems := make([]T, 0, nodeSize)
+		leftItems = append(leftItems, left.items...)
+		leftItems = append(leftItems, right.items[:split]...)
+		rightItems := make([]T, len(right.items)-split)
+		copy(rightItems, right.items[split:])
+		return []*rrbNode[T]{newRRBLeaf(leftItems), newRRBLeaf(rightItems)}
+	}
+
+	last := len(left.children) - 1
+	mid := rrbConcat(left.children[last], lh-1, right.children[0], rh-1)
+	children := make([]*rrbNode[T], 0, last+len(mid)+len(right.children)-1)
+	children = append(children, left.children[:last]...)
+	children = append(children, mid...)
+	return rrbPack(append(children, right.children[1:]...), lh)
+}
+
+// rrbExtra is the number of nodes, beyond the optimal number, that the children of a node
+// created by concatenation may be spread over before they are redistributed, see
+// rrbRebalance.
+const rrbExtra = 2
+
+// slots returns the number of items of a leaf or children of an internal node.
+func (n *rrbNode[T]) slots() int {
+	if n.children == nil {
+		return len(n.items)
+	}
+
+	return len(n.children)
+}
+
+// rrbRebalance redistributes the contents of nodes, all of height h, so that they are spread
+// over at most rrbExtra more nodes than needed to hold them. This is the search step
+// invariant of RRB trees. It keeps the nodes created when concatenating dense, which bounds
+// the height of the tree to a logarithm of its size, and limits the number of steps locate
+// needs to find a child beyond the radix guess. Nodes that do not need to change are shared.
+func rrbRebalance[T any](nodes []*rrbNode[T], h uint) []*rrbNode[T] {
+	sizes := make([]int, len(nodes))
+	total := 0
+	for i, n := range nodes {
+		sizes[i] = n.slots()
+		total += sizes[i]
+	}
+
+	optimal := (total + nodeSize - 1) / nodeSize
+	count := len(nodes)
+	if count <= optimal+rrbExtra {
+		return nodes
+	}
+
+	// Plan the new sizes, each round merges the contents of the first node that is not full
+	// enough into the nodes following it, removing one node.
+	for i := 0; count > optimal+rrbExtra; i-- {
+		for sizes[i] > nodeSize-rrbExtra/2 {
+			i++
+		}
+
+		for remaining := sizes[i]; ; {
+			next := sizes[i+1]
+			sizes[i] = min(remaining+next, nodeSize)
+			remaining += next - sizes[i]
+			i++
+			if remaining == 0 {
+				break
+			}
+		}
+
+		copy(sizes[i:count-1], sizes[i+1:count])
+		count--
+	}
+
+	result := make([]*rrbNode[T], 0, count)
+	src, offset := 0, 0
+	for _, size := range sizes[:count] {
+		if offset == 0 && nodes[src].slots() == size {
+			result = append(result, nodes[src])
+			src++
+			continue
+		}
+
+		if h == 0 {
+			items := make([]T, 0, size)
+			for len(items) < size {
+				n := min(size-len(items), len(nodes[src].items)-offset)
+				items = append(items, nodes[src].items[offset:offset+n]...)
+				if offset += n; offset == len(nodes[src].items) {
+					src, offset = src+1, 0
+				}
+			}
+
+			result = append(result, newRRBLeaf(items))
+			continue
+		}
+
+		children := make([]*rrbNode[T], 0, size)
+		for len(children) < size {
+			n := min(size-len(children), len(nodes[src].children)-offset)
+			children = append(children, nodes[src].children[offset:offset+n]...)
+			if offset += n; offset == len(nodes[src].children) {
+				src, offset = src+1, 0
+			}
+		}
+
+		result = append(result, newRRBInternal(children, h))
+	}
+
+	return result
+}
+
+// rrbPack rebalances children, of which there are at most 2*nodeSize, and distributes them
+// into one or two nodes of height h.
+func rrbPack[T any](children []*rrbNode[T], h uint) []*rrbNode[T] {
+	children = rrbRebalance(children, h-1)
+	if len(children) <= nodeSize {
+		return []*rrbNode[T]{newRRBInternal(children, h)}
+	}
+
+	return []*rrbNode[T]{newRRBInternal(children[:nodeSize:nodeSize], h), newRRBInternal(children[nodeSize:], h)}
+}
+
+// Split returns two new vectors, the first containing the elements [0,i) of v and the
+// second containing the elements [i,len) of v.
+func (v *RRBVector[T]) Split(i int) (*RRBVector[T], *RRBVector[T]) {
+	assertSliceOk(i, i, v.Len())
+	return v.take(i), v.drop(i)
+}
+
+func (v *RRBVector[T]) take(i int) *RRBVector[T] {
+	if i == v.Len() {
+		return v
+	}
+
+	if i == 0 {
+		return NewRRBVector[T]()
+	}
+
+	return newRRBVectorFromRoot(rrbTake(v.root, v.height, i), v.height)
+}
+
+func (v *RRBVector[T]) drop(i int) *RRBVector[T] {
+	if i == 0 {
+		return v
+	}
+
+	if i == v.Len() {
+		return NewRRBVector[T]()
+	}
+
+	return newRRBVectorFromRoot(rrbDrop(v.root, v.height, i), v.height)
+}
+
+// newRRBVectorFromRoot returns a vector with root as root after removing any redundant
+// levels from the top of the tree.
+func newRRBVectorFromRoot[T any](root *rrbNode[T], h uint) *RRBVector[T] {
+	for h > 0 && len(root.children) == 1 {
+		root = root.children[0]
+		h--
+	}
+
+	return &RRBVector[T]{root: root, height: h}
+}
+
+// rrbTake returns a node containing the first i elements of n, 0 < i <= n.size.
+func rrbTake[T any](n *rrbNode[T], h uint, i int) *rrbNode[T] {
+	if i == n.size {
+		return n
+	}
+
+	if h == 0 {
+		items := make([]T, i)
+		copy(items, n.items)
+		return newRRBLeaf(items)
+	}
+
+	idx, offset := n.locate(h, i-1)
+	children := make([]*rrbNode[T], idx+1)
+	copy(children, n.children)
+	children[idx] = rrbTake(children[idx], h-1, i-offset)
+	return newRRBInternal(children, h)
+}
+
+// rrbDrop returns a node containing all but the first i elements of n, 0 <= i < n.size.
+func rrbDrop[T any](n *rrbNode[T], h uint, i int) *rrbNode[T] {
+	if i == 0 {
+		return n
+	}
+
+	if h == 0 {
+		items := make([]T, len(n.items)-i)
+		copy(items, n.items[i:])
+		return newRRBLeaf(items)
+	}
+
+	idx, offset := n.locate(h, i)
+	children := make([]*rrbNode[T], len(n.children)-idx)
+	copy(children, n.children[idx:])
+	children[0] = rrbDrop(children[0], h-1, i-offset)
+	return newRRBInternal(children, h)
+}
+
+// Insert returns a new vector with item(s) inserted before position i.
+func (v *RRBVector[T]) Insert(i int, items ...T) *RRBVector[T] {
+	left, right := v.Split(i)
+	return left.Concat(NewRRBVector(items...)).Concat(right)
+}
+
+// Remove returns a new vector with the element at position i removed.
+func (v *RRBVector[T]) Remove(i int) *RRBVector[T] {
+	if i < 0 || i >= v.Len() {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "RRBVector"})
+	}
+
+	return v.take(i).Concat(v.drop(i + 1))
+}
+
+// Range calls f repeatedly passing it each element in v in order as argument until either
+// all elements have been visited or f returns false.
+func (v *RRBVector[T]) Range(f func(T) bool) {
+	v.root.rangeLeaves(func(items []T) bool {
+		for _, item := range items {
+			if !f(item) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (n *rrbNode[T]) rangeLeaves(f func([]T) bool) bool {
+	if n.children == nil {
+		return f(n.items)
+	}
+
+	for _, child := range n.children {
+		if !child.rangeLeaves(f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ToNativeSlice returns a Go slice containing all elements of v
+func (v *RRBVector[T]) ToNativeSlice() []T {
+	result := make([]T, 0, v.Len())
+	v.root.rangeLeaves(func(items []T) bool {
+		result = append(result, items...)
+		return true
+	})
+
+	return result
+}
@@ -0,0 +1,198 @@
+package peds
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func assertRRBContents(t *testing.T, expected []int, v *RRBVector[int]) {
+	t.Helper()
+	assertEqual(t, len(expected), v.Len())
+	for i, e := range expected {
+		assertEqual(t, e, v.Get(i))
+	}
+
+	actual := v.ToNativeSlice()
+	assertEqual(t, len(expected), len(actual))
+	for i, e := range expected {
+		assertEqual(t, e, actual[i])
+	}
+}
+
+// assertRRBBalanced checks the cached sizes of the nodes of v, that all leaves are at the
+// same depth and that the height of v is logarithmic in its length.
+func assertRRBBalanced(t *testing.T, v *RRBVector[int]) {
+	t.Helper()
+	var check func(n *rrbNode[int], h uint) bool
+	check = func(n *rrbNode[int], h uint) bool {
+		if h == 0 {
+			return n.children == nil && n.size == len(n.items) && len(n.items) <= nodeSize
+		}
+
+		size := 0
+		for i, child := range n.children {
+			if !check(child, h-1) {
+				return false
+			}
+
+			size += child.size
+			if n.sizes != nil && n.sizes[i] != size {
+				return false
+			}
+		}
+
+		return len(n.children) > 0 && len(n.children) <= nodeSize && n.size == size
+	}
+
+	if !check(v.root, v.height) {
+		t.Fatal("RRBVector tree is inconsistent")
+	}
+
+	limit := math.Ceil(math.Log(float64(v.Len()+1))/math.Log(nodeSize-rrbExtra)) + 1
+	if float64(v.height) > limit {
+		t.Fatalf("RRBVector of length %d has height %d, expected at most %.0f", v.Len(), v.height, limit)
+	}
+}
+
+func TestPropertiesOfNewRRBVector(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("NewRRBVector %d", l), func(t *testing.T) {
+			assertRRBContents(t, inputSlice(0, l), NewRRBVector(inputSlice(0, l)...))
+		})
+	}
+}
+
+func TestRRBVectorSet(t *testing.T) {
+	v := NewRRBVector(inputSlice(0, 2000)...)
+	for i := 0; i < 2000; i += 7 {
+		v2 := v.Set(i, -i)
+		assertEqual(t, -i, v2.Get(i))
+		assertEqual(t, i, v.Get(i))
+	}
+}
+
+func TestRRBVectorAppend(t *testing.T) {
+	v := NewRRBVector[int]()
+	for i := 0; i < 2000; i++ {
+		v = v.Append(i)
+	}
+
+	assertRRBContents(t, inputSlice(0, 2000), v)
+}
+
+func TestRRBVectorConcat(t *testing.T) {
+	sizes := []int{0, 1, 20, 32, 33, 500, 32*32 + 1, 10000}
+	for _, l1 := range sizes {
+		for _, l2 := range sizes {
+			t.Run(fmt.Sprintf("Concat %d %d", l1, l2), func(t *testing.T) {
+				v1 := NewRRBVector(inputSlice(0, l1)...)
+				v2 := NewRRBVector(inputSlice(l1, l2)...)
+				assertRRBContents(t, inputSlice(0, l1+l2), v1.Concat(v2))
+				assertRRBContents(t, inputSlice(0, l1), v1)
+				assertRRBContents(t, inputSlice(l1, l2), v2)
+			})
+		}
+	}
+}
+
+func TestRRBVectorRepeatedConcatOfSmallVectors(t *testing.T) {
+	v := NewRRBVector[int]()
+	for i := 0; i < 5000; i += 3 {
+		v = v.Concat(NewRRBVector(inputSlice(i, 3)...))
+	}
+
+	assertRRBContents(t, inputSlice(0, 5001), v)
+}
+
+func TestRRBVectorSplit(t *testing.T) {
+	for _, l := range []int{0, 1, 33, 1025, 5000} {
+		v := NewRRBVector(inputSlice(0, l)...)
+		for i := 0; i <= l; i += 1 + l/17 {
+			t.Run(fmt.Sprintf("Split %d at %d", l, i), func(t *testing.T) {
+				left, right := v.Split(i)
+				assertRRBContents(t, inputSlice(0, i), left)
+				assertRRBContents(t, inputSlice(i, l-i), right)
+				assertRRBContents(t, inputSlice(0, l), left.Concat(right))
+			})
+		}
+	}
+}
+
+func TestRRBVectorInsertAndRemove(t *testing.T) {
+	expected := []int{}
+	v := NewRRBVector[int]()
+	for i := 0; i < 1000; i++ {
+		pos := (i * 7919) % (len(expected) + 1)
+		expected = append(expected[:pos], append([]int{i}, expected[pos:]...)...)
+		v = v.Insert(pos, i)
+	}
+
+	assertRRBContents(t, expected, v)
+	for len(expected) > 0 {
+		pos := (len(expected) * 104729) % len(expected)
+		expected = append(expected[:pos], expected[pos+1:]...)
+		v = v.Remove(pos)
+	}
+
+	assertRRBContents(t, expected, v)
+}
+
+func TestRRBVectorStaysBalanced(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	expected := []int{}
+	v := NewRRBVector[int]()
+	for i := 0; i < 20000; i++ {
+		switch pos := rnd.Intn(len(expected) + 1); i % 4 {
+		case 0:
+			expected = append([]int{i}, expected...)
+			v = NewRRBVector(i).Concat(v)
+		case 1:
+			expected = append(expected[:pos], append([]int{i, -i}, expected[pos:]...)...)
+			v = v.Insert(pos, i, -i)
+		case 2:
+			expected = append(expected[:len(expected)/2], append([]int{i}, expected[len(expected)/2:]...)...)
+			v = v.Insert(v.Len()/2, i)
+		default:
+			if pos < len(expected) {
+				expected = append(expected[:pos], expected[pos+1:]...)
+				v = v.Remove(pos)
+			}
+		}
+
+		if i%1000 == 0 {
+			assertRRBBalanced(t, v)
+		}
+	}
+
+	assertRRBBalanced(t, v)
+	assertRRBContents(t, expected, v)
+}
+
+func TestRRBVectorRangeStops(t *testing.T) {
+	count := 0
+	NewRRBVector(inputSlice(0, 100)...).Range(func(i int) bool {
+		count++
+		return i < 40
+	})
+
+	assertEqual(t, 41, count)
+}
+
+func TestRRBVectorOutOfBounds(t *testing.T) {
+	t.Run("Get", func(t *testing.T) {
+		defer assertPanic(t, "Index out of bounds")
+		NewRRBVector(1, 2, 3).Get(3)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		defer assertPanic(t, "Index out of bounds")
+		NewRRBVector(1, 2, 3).Remove(-1)
+	})
+
+	t.Run("Split", func(t *testing.T) {
+		defer assertPanic(t, "Slice bounds out of range")
+		NewRRBVector(1, 2, 3).Split(4)
+	})
+}
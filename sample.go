@@ -0,0 +1,140 @@
+package peds
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+func checkWeight(w float64) float64 {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		panic(fmt.Sprintf("Invalid weight %v (weight must be finite and non-negative)", w))
+	}
+
+	return w
+}
+
+// SampleWeighted returns an element of v picked at random by r, where the probability of
+// picking an element is proportional to its weight as returned by weight. ok is set to false
+// if v is empty or all weights are zero. The selection is done in a single pass over v, use
+// an AliasTable when sampling repeatedly from the same vector.
+func (v *Vector[T]) SampleWeighted(r *rand.Rand, weight func(T) float64) (item T, ok bool) {
+	total := 0.0
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, candidate := range v.sliceFor(i) {
+			w := checkWeight(weight(candidate))
+			if w == 0 {
+				continue
+			}
+
+			// Replacing the current pick with probability w/total makes every element
+			// seen so far picked with a probability proportional to its weight
+			total += w
+			if r.Float64()*total < w {
+				item, ok = candidate, true
+			}
+		}
+	}
+
+	return item, ok
+}
+
+// Sample returns a new vector containing k elements of v picked uniformly at random by r,
+// using reservoir sampling in a single pass over the leaves of v. The order of the sampled
+// elements is unspecified. v itself is returned if k is at least the length of v.
+func (v *Vector[T]) Sample(r *rand.Rand, k int) *Vector[T] {
+	if k < 0 {
+		panic(fmt.Sprintf("Invalid sample size %d (size must be non-negative)", k))
+	}
+
+	if k >= v.Len() {
+		return v
+	}
+
+	reservoir := make([]T, 0, k)
+	seen := 0
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, item := range v.sliceFor(i) {
+			seen++
+			if len(reservoir) < k {
+				reservoir = append(reservoir, item)
+			} else if j := r.Intn(seen); j < k {
+				reservoir[j] = item
+			}
+		}
+	}
+
+	return NewVector(reservoir...)
+}
+
+// An AliasTable supports picking random elements of a vector, with probabilities
+// proportional to their weights, in constant time using Vose's alias method. Building the
+// table is linear in the length of the vector.
+type AliasTable[T any] struct {
+	items *Vector[T]
+	prob  []float64
+	alias []int
+}
+
+// NewAliasTable returns a new alias table over the elements of v weighted by weight. It
+// panics if v is empty or if all weights are zero.
+func NewAliasTable[T any](v *Vector[T], weight func(T) float64) *AliasTable[T] {
+	n := v.Len()
+	scaled := make([]float64, 0, n)
+	total := 0.0
+	for i := uint(0); i < v.len; i += nodeSize {
+		for _, item := range v.sliceFor(i) {
+			w := checkWeight(weight(item))
+			scaled = append(scaled, w)
+			total += w
+		}
+	}
+
+	if total == 0 {
+		panic("Invalid alias table weights (total weight must be positive)")
+	}
+
+	small, large := make([]int, 0, n), make([]int, 0, n)
+	for i := range scaled {
+		scaled[i] *= float64(n) / total
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	t := &AliasTable[T]{items: v, prob: make([]float64, n), alias: make([]int, n)}
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small = small[:len(small)-1]
+		t.prob[s], t.alias[s] = scaled[s], l
+
+		scaled[l] -= 1 - scaled[s]
+		if scaled[l] < 1 {
+			large, small = large[:len(large)-1], append(small, l)
+		}
+	}
+
+	// Whatever remains has a scaled weight of one, up to rounding errors
+	for _, i := range append(small, large...) {
+		t.prob[i] = 1
+	}
+
+	return t
+}
+
+// Len returns the number of elements in the table.
+func (t *AliasTable[T]) Len() int {
+	return len(t.prob)
+}
+
+// Sample returns an element picked at random by r.
+func (t *AliasTable[T]) Sample(r *rand.Rand) T {
+	i := r.Intn(len(t.prob))
+	if r.Float64() >= t.prob[i] {
+		i = t.alias[i]
+	}
+
+	return t.items.Get(i)
+}
@@ -0,0 +1,104 @@
+package peds
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// assertDistribution checks that counts, from n samples, are within a few percent of being
+// proportional to the weights 0, 1, 2, ...
+func assertDistribution(t *testing.T, counts []int, n int) {
+	t.Helper()
+	total := len(counts) * (len(counts) - 1) / 2
+	for i, c := range counts {
+		expected := float64(n) * float64(i) / float64(total)
+		assertEqualBool(t, true, math.Abs(float64(c)-expected) <= 0.05*float64(n)/float64(len(counts))+0.02*expected)
+	}
+}
+
+func TestSampleWeighted(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	v := NewVector(inputSlice(0, 10)...)
+	counts := make([]int, 10)
+	for i := 0; i < 100000; i++ {
+		item, ok := v.SampleWeighted(r, func(x int) float64 { return float64(x) })
+		assertEqualBool(t, true, ok)
+		counts[item]++
+	}
+
+	assertEqual(t, 0, counts[0])
+	assertDistribution(t, counts, 100000)
+}
+
+func TestSampleWeightedNothingToPick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	_, ok := NewVector[int]().SampleWeighted(r, func(int) float64 { return 1 })
+	assertEqualBool(t, false, ok)
+	_, ok = NewVector(1, 2, 3).SampleWeighted(r, func(int) float64 { return 0 })
+	assertEqualBool(t, false, ok)
+}
+
+func TestSampleWeightedInvalidWeight(t *testing.T) {
+	defer assertPanic(t, "Invalid weight -1")
+	NewVector(1).SampleWeighted(rand.New(rand.NewSource(1)), func(int) float64 { return -1 })
+}
+
+func TestSample(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	v := NewVector(inputSlice(0, 1000)...)
+	counts := make([]int, 1000)
+	for i := 0; i < 2000; i++ {
+		sample := v.Sample(r, 10)
+		assertEqual(t, 10, sample.Len())
+
+		seen := make(map[int]bool)
+		sample.Range(func(item int) bool {
+			assertEqualBool(t, false, seen[item])
+			seen[item] = true
+			counts[item]++
+			return true
+		})
+	}
+
+	// Each element is expected to be picked 20 times, check the first and last leaves
+	for _, item := range []int{0, 31, 968, 999} {
+		assertEqualBool(t, true, counts[item] > 3 && counts[item] < 50)
+	}
+}
+
+func TestSampleSizes(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	v := NewVector(inputSlice(0, 100)...)
+	assertEqual(t, 0, v.Sample(r, 0).Len())
+	assertEqual(t, 99, v.Sample(r, 99).Len())
+	assertEqualBool(t, true, v == v.Sample(r, 100))
+	assertEqualBool(t, true, v == v.Sample(r, 1000))
+
+	defer assertPanic(t, "Invalid sample size -1")
+	v.Sample(r, -1)
+}
+
+func TestAliasTable(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	table := NewAliasTable(NewVector(inputSlice(0, 100)...), func(x int) float64 { return float64(x) })
+	assertEqual(t, 100, table.Len())
+
+	counts := make([]int, 100)
+	for i := 0; i < 1000000; i++ {
+		counts[table.Sample(r)]++
+	}
+
+	assertEqual(t, 0, counts[0])
+	assertDistribution(t, counts, 1000000)
+}
+
+func TestAliasTableSingleItem(t *testing.T) {
+	table := NewAliasTable(NewVector("a"), func(string) float64 { return 0.5 })
+	assertEqualString(t, "a", table.Sample(rand.New(rand.NewSource(1))))
+}
+
+func TestAliasTableZeroWeights(t *testing.T) {
+	defer assertPanic(t, "total weight must be positive")
+	NewAliasTable(NewVector(1, 2), func(int) float64 { return 0 })
+}
@@ -0,0 +1,262 @@
+package peds
+
+import "iter"
+
+// /////////
+// / Set ///
+// /////////
+
+// A Set is a persistent/immutable set of distinct items.
+type Set[T comparable] struct {
+	items *Map[T, struct{}]
+}
+
+// NewSet returns a new set containing the items provided in items. Duplicates are only
+// included once.
+func NewSet[T comparable](items ...T) *Set[T] {
+	b := NewMapBuilderWithCapacity[T, struct{}](len(items))
+	for _, item := range items {
+		b.Set(item, struct{}{})
+	}
+
+	return &Set[T]{items: b.Freeze()}
+}
+
+// Len returns the number of items in s.
+func (s *Set[T]) Len() int {
+	return s.items.Len()
+}
+
+// Contains reports whether item is in s.
+func (s *Set[T]) Contains(item T) bool {
+	return s.items.Contains(item)
+}
+
+// Add returns a new set with item added. The set itself is returned if item is already
+// present.
+func (s *Set[T]) Add(item T) *Set[T] {
+	if s.items.Contains(item) {
+		return s
+	}
+
+	return &Set[T]{items: s.items.Store(item, struct{}{})}
+}
+
+// Remove returns a new set with item removed. The set itself is returned if item is not
+// present.
+func (s *Set[T]) Remove(item T) *Set[T] {
+	if !s.items.Contains(item) {
+		return s
+	}
+
+	return &Set[T]{items: s.items.Delete(item)}
+}
+
+// Range calls f repeatedly passing it each item in s as argument until either all items
+// have been visited or f returns false. The iteration order is not specified.
+func (s *Set[T]) Range(f func(T) bool) {
+	s.items.Range(func(item T, _ struct{}) bool {
+		return f(item)
+	})
+}
+
+// All returns an iterator over the items of s. The iteration order is not specified.
+func (s *Set[T]) All() iter.Seq[T] {
+	return s.Range
+}
+
+// ToNativeSlice returns a new native slice containing the items of s, in unspecified order.
+func (s *Set[T]) ToNativeSlice() []T {
+	return rangeToNativeSlice(s.Len(), s.Range)
+}
+
+// smallerLarger returns s and other ordered by size, the smaller first.
+func (s *Set[T]) smallerLarger(other *Set[T]) (smaller, larger *Set[T]) {
+	if s.Len() <= other.Len() {
+		return s, other
+	}
+
+	return other, s
+}
+
+// Union returns a set holding the items that are in s, other or both. The items of the
+// smaller set are added to the larger one, buckets shared between the sets are reused
+// without looking at their items.
+func (s *Set[T]) Union(other *Set[T]) *Set[T] {
+	smaller, larger := s.smallerLarger(other)
+	items := larger.items.Merge(smaller.items, func(T, struct{}, struct{}) struct{} { return struct{}{} })
+	if items == larger.items {
+		return larger
+	}
+
+	return &Set[T]{items: items}
+}
+
+// Intersect returns a set holding the items that are in both s and other. The items of the
+// smaller set are looked up in the larger one.
+func (s *Set[T]) Intersect(other *Set[T]) *Set[T] {
+	smaller, larger := s.smallerLarger(other)
+	if smaller.items == larger.items {
+		return smaller
+	}
+
+	items := smaller.items.Filter(func(item T, _ struct{}) bool { return larger.Contains(item) })
+	if items == smaller.items {
+		return smaller
+	}
+
+	return &Set[T]{items: items}
+}
+
+// Difference returns a set holding the items in s that are not in other. If other is the
+// smaller set its items are removed from s, otherwise the items of s are looked up in other.
+func (s *Set[T]) Difference(other *Set[T]) *Set[T] {
+	if s.items == other.items {
+		return NewSet[T]()
+	}
+
+	items := s.items
+	if other.Len() < s.Len() {
+		other.Range(func(item T) bool {
+			items = items.Delete(item)
+			return true
+		})
+	} else {
+		items = items.Filter(func(item T, _ struct{}) bool { return !other.Contains(item) })
+	}
+
+	if items == s.items {
+		return s
+	}
+
+	return &Set[T]{items: items}
+}
+
+// SymmetricDifference returns a set holding the items that are in either s or other but not
+// in both. The items of the smaller set are added to, or removed from, the larger one.
+func (s *Set[T]) SymmetricDifference(other *Set[T]) *Set[T] {
+	if s.items == other.items {
+		return NewSet[T]()
+	}
+
+	smaller, larger := s.smallerLarger(other)
+	items := larger.items
+	smaller.Range(func(item T) bool {
+		if larger.Contains(item) {
+			items = items.Delete(item)
+		} else {
+			items = items.Store(item, struct{}{})
+		}
+
+		return true
+	})
+
+	return &Set[T]{items: items}
+}
+
+// sharedBucket reports whether a and b are the same, non-empty, bucket.
+func sharedBucket[T comparable](a, b privateItemBucket[T, struct{}]) bool {
+	return len(a) > 0 && len(a) == len(b) && &a[0] == &b[0]
+}
+
+func bucketContains[T comparable](bucket privateItemBucket[T, struct{}], item T) bool {
+	for _, bItem := range bucket {
+		if bItem.Key == item {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsSubset reports whether all items in s are also in other. When s and other are versions
+// of the same set, buckets shared between them are skipped without looking at their items.
+func (s *Set[T]) IsSubset(other *Set[T]) bool {
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	if s.items == other.items {
+		return true
+	}
+
+	if s.items.sameBucketLayout(other.items) {
+		for pos, bucket := range s.items.backingVector.All() {
+			otherBucket := other.items.backingVector.Get(pos)
+			if sharedBucket(bucket, otherBucket) {
+				continue
+			}
+
+			for _, item := range bucket {
+				if !bucketContains(otherBucket, item.Key) {
+					return false
+				}
+			}
+		}
+
+		return true
+	}
+
+	subset := true
+	s.Range(func(item T) bool {
+		subset = other.Contains(item)
+		return subset
+	})
+
+	return subset
+}
+
+// IsSuperset reports whether all items in other are also in s.
+func (s *Set[T]) IsSuperset(other *Set[T]) bool {
+	return other.IsSubset(s)
+}
+
+// Equal reports whether s and other contain the same items.
+func (s *Set[T]) Equal(other *Set[T]) bool {
+	if s == nil || other == nil {
+		return s == other
+	}
+
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// Disjoint reports whether s and other have no items in common. The items of the smaller set
+// are looked up in the larger one, the search stops at the first common item.
+func (s *Set[T]) Disjoint(other *Set[T]) bool {
+	smaller, larger := s.smallerLarger(other)
+	if smaller.Len() == 0 {
+		return true
+	}
+
+	if smaller.items == larger.items {
+		return false
+	}
+
+	disjoint := true
+	smaller.Range(func(item T) bool {
+		disjoint = !larger.Contains(item)
+		return disjoint
+	})
+
+	return disjoint
+}
+
+// KeySet returns a set holding the keys of m. The set uses the same hasher and bucket layout
+// as m, the keys are copied bucket by bucket without being rehashed.
+func (m *Map[K, V]) KeySet() *Set[K] {
+	buckets := make([]privateItemBucket[K, struct{}], 0, m.backingVector.Len())
+	m.backingVector.Range(func(bucket privateItemBucket[K, V]) bool {
+		var keys privateItemBucket[K, struct{}]
+		if len(bucket) > 0 {
+			keys = make(privateItemBucket[K, struct{}], len(bucket))
+			for i, item := range bucket {
+				keys[i].Key = item.Key
+			}
+		}
+
+		buckets = append(buckets, keys)
+		return true
+	})
+
+	return &Set[K]{items: &Map[K, struct{}]{backingVector: AdoptSlice(buckets), len: m.len, hasher: m.hasher, options: m.options}}
+}
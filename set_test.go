@@ -0,0 +1,159 @@
+package peds
+
+import (
+	"slices"
+	"testing"
+)
+
+func sortedSetItems(s *Set[int]) []int {
+	items := s.ToNativeSlice()
+	slices.Sort(items)
+	return items
+}
+
+func TestSet(t *testing.T) {
+	s := NewSet(3, 1, 2, 3, 1)
+	assertEqual(t, 3, s.Len())
+	assertEqualBool(t, true, s.Contains(2))
+	assertEqualBool(t, false, s.Contains(4))
+
+	s2 := s.Add(4)
+	assertEqualBool(t, true, s.Add(1) == s)
+	assertEqualBool(t, true, s.Remove(5) == s)
+	assertEqual(t, 4, s2.Len())
+	assertEqual(t, 3, s.Len())
+
+	s3 := s2.Remove(1)
+	assertEqualBool(t, true, slices.Equal([]int{2, 3, 4}, sortedSetItems(s3)))
+	assertEqualBool(t, true, slices.Equal([]int{1, 2, 3}, sortedSetItems(s)))
+
+	count := 0
+	for range s3.All() {
+		count++
+	}
+
+	assertEqual(t, 3, count)
+}
+
+func TestMapKeySet(t *testing.T) {
+	for _, size := range testSizes {
+		m := NewMap[int, string]()
+		for i := 0; i < size; i++ {
+			m = m.Store(i, "x")
+		}
+
+		s := m.KeySet()
+		assertEqual(t, size, s.Len())
+		assertEqualBool(t, true, slices.Equal(inputSlice(0, size), sortedSetItems(s)))
+
+		// The set can be updated like any other
+		s2 := s.Add(-1).Remove(0)
+		assertEqualBool(t, true, s2.Contains(-1))
+		assertEqualBool(t, false, s2.Contains(0))
+		assertEqual(t, max(size, 1), s2.Len())
+		assertEqual(t, size, s.Len())
+	}
+}
+
+func TestMapKeySetWithHasher(t *testing.T) {
+	m := NewMapWithHasher[string, int](HasherFunc[string](func(key string) uint64 { return uint64(len(key)) }))
+	m = m.Store("a", 1).Store("bb", 2).Store("cc", 3)
+	s := m.KeySet()
+	assertEqualBool(t, true, s.Contains("cc"))
+	assertEqualBool(t, true, s.Add("dd").Contains("dd"))
+	assertEqual(t, 2, s.Remove("a").Len())
+}
+
+func TestSetAlgebra(t *testing.T) {
+	for _, sizes := range [][2]int{{0, 0}, {0, 10}, {10, 0}, {10, 100}, {100, 10}, {1000, 1000}} {
+		// a holds 0..sizes[0]-1, b holds the even numbers starting at sizes[0]/2
+		a := NewSet(inputSlice(0, sizes[0])...)
+		var bItems []int
+		for i := 0; i < sizes[1]; i++ {
+			bItems = append(bItems, sizes[0]/2+2*i)
+		}
+
+		b := NewSet(bItems...)
+		native := func(pred func(inA, inB bool) bool) []int {
+			var result []int
+			for i := 0; i < sizes[0]+2*sizes[1]; i++ {
+				if pred(a.Contains(i), b.Contains(i)) {
+					result = append(result, i)
+				}
+			}
+
+			return result
+		}
+
+		sameItems := func(expected []int, s *Set[int]) {
+			t.Helper()
+			if !slices.Equal(expected, sortedSetItems(s)) {
+				t.Errorf("Expected %v, got %v", expected, sortedSetItems(s))
+			}
+		}
+
+		sameItems(native(func(inA, inB bool) bool { return inA || inB }), a.Union(b))
+		sameItems(native(func(inA, inB bool) bool { return inA && inB }), a.Intersect(b))
+		sameItems(native(func(inA, inB bool) bool { return inA && !inB }), a.Difference(b))
+		sameItems(native(func(inA, inB bool) bool { return inA != inB }), a.SymmetricDifference(b))
+		sameItems(native(func(inA, inB bool) bool { return inA != inB }), b.SymmetricDifference(a))
+		assertEqual(t, sizes[0], a.Len())
+		assertEqual(t, sizes[1], b.Len())
+	}
+}
+
+func TestSetAlgebraSharing(t *testing.T) {
+	a := NewSet(inputSlice(0, 100)...)
+	b := a.Add(1000)
+	assertEqualBool(t, true, a.Union(a) == a)
+	assertEqualBool(t, true, a.Intersect(a) == a)
+	assertEqual(t, 0, a.Difference(a).Len())
+	assertEqual(t, 0, a.SymmetricDifference(a).Len())
+
+	assertEqualBool(t, true, a.Union(NewSet[int]()) == a)
+	assertEqualBool(t, true, b.Intersect(a).Len() == 100)
+	assertEqualBool(t, true, a.Difference(NewSet(-1)) == a)
+	assertEqualBool(t, true, slices.Equal([]int{1000}, sortedSetItems(b.Difference(a))))
+	assertEqualBool(t, true, slices.Equal([]int{1000}, sortedSetItems(a.SymmetricDifference(b))))
+}
+
+func TestSetRelations(t *testing.T) {
+	a := NewSet(1, 2, 3)
+	b := NewSet(1, 2, 3, 4)
+	c := NewSet(5, 6)
+	empty := NewSet[int]()
+
+	assertEqualBool(t, true, a.IsSubset(b))
+	assertEqualBool(t, false, b.IsSubset(a))
+	assertEqualBool(t, true, b.IsSuperset(a))
+	assertEqualBool(t, false, a.IsSuperset(b))
+	assertEqualBool(t, true, empty.IsSubset(a))
+	assertEqualBool(t, true, a.IsSubset(a))
+	assertEqualBool(t, false, a.IsSubset(NewSet(1, 2, 4, 5)))
+
+	assertEqualBool(t, true, a.Equal(NewSet(3, 2, 1)))
+	assertEqualBool(t, false, a.Equal(b))
+	assertEqualBool(t, false, a.Equal(NewSet(1, 2, 4)))
+	assertEqualBool(t, true, empty.Equal(NewSet[int]()))
+
+	assertEqualBool(t, true, a.Disjoint(c))
+	assertEqualBool(t, true, c.Disjoint(b))
+	assertEqualBool(t, false, a.Disjoint(b))
+	assertEqualBool(t, true, a.Disjoint(empty))
+	assertEqualBool(t, true, empty.Disjoint(empty))
+	assertEqualBool(t, false, a.Disjoint(a))
+}
+
+func TestSetRelationsBetweenVersions(t *testing.T) {
+	base := NewSet(inputSlice(0, 10000)...)
+	bigger := base.Add(-1)
+	changed := base.Remove(5000).Add(-2)
+
+	assertEqualBool(t, true, base.IsSubset(bigger))
+	assertEqualBool(t, false, bigger.IsSubset(base))
+	assertEqualBool(t, false, base.IsSubset(changed))
+	assertEqualBool(t, false, changed.IsSubset(bigger))
+	assertEqualBool(t, true, changed.Remove(-2).IsSubset(base))
+	assertEqualBool(t, true, base.Equal(bigger.Remove(-1)))
+	assertEqualBool(t, false, base.Disjoint(changed))
+}
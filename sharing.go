@@ -0,0 +1,76 @@
+package peds
+
+// SharedNodes reports how much of their internal structure two vectors share. total is the
+// number of distinct nodes, including tails, making up a and b together and shared is the
+// number of those nodes that are part of both. Vectors derived from each other through small
+// updates typically share most of their nodes.
+func SharedNodes[T any](a, b *Vector[T]) (shared, total int) {
+	nodesA := make(map[any]struct{})
+	a.rangeNodes(func(n any) bool {
+		nodesA[n] = struct{}{}
+		return true
+	})
+
+	total = len(nodesA)
+	seen := make(map[any]struct{})
+	b.rangeNodes(func(n any) bool {
+		if _, ok := seen[n]; ok {
+			return false
+		}
+
+		seen[n] = struct{}{}
+		if _, ok := nodesA[n]; ok {
+			shared++
+		} else {
+			total++
+		}
+
+		return true
+	})
+
+	return shared, total
+}
+
+// SharingRatio returns the fraction of the nodes of a and b that are shared between them, see
+// SharedNodes. Two empty vectors share everything.
+func SharingRatio[T any](a, b *Vector[T]) float64 {
+	shared, total := SharedNodes(a, b)
+	if total == 0 {
+		return 1
+	}
+
+	return float64(shared) / float64(total)
+}
+
+// MapSharedNodes works like SharedNodes for maps. Maps with different bucket layouts share
+// nothing.
+func MapSharedNodes[K comparable, V any](a, b *Map[K, V]) (shared, total int) {
+	return SharedNodes(a.backingVector, b.backingVector)
+}
+
+// rangeNodes calls f with every node, and the tail, of v, parents before children. Children of
+// a node are skipped if f returns false for it. Nodes are identified by pointers, tails by a
+// pointer to their first element.
+func (v *Vector[T]) rangeNodes(f func(any) bool) {
+	if len(v.tail) > 0 {
+		f(&v.tail[0])
+	}
+
+	if v.root != nil {
+		rangeSubtree(v.shift, v.root, f)
+	}
+}
+
+func rangeSubtree[T any](level uint, n *node[T], f func(any) bool) {
+	if !f(n) || level == 0 {
+		return
+	}
+
+	for _, child := range n.children {
+		if child == nil {
+			break
+		}
+
+		rangeSubtree(level-shiftSize, child, f)
+	}
+}
@@ -0,0 +1,34 @@
+package peds
+
+import "testing"
+
+func TestSharedNodes(t *testing.T) {
+	v1 := NewVector(inputSlice(0, 10000)...)
+	shared, total := SharedNodes(v1, v1)
+	assertEqual(t, shared, total)
+	assertEqualBool(t, true, SharingRatio(v1, v1) == 1)
+
+	// Updating one element copies the path to it, a root, a branch and a leaf
+	v2 := v1.Set(0, -1)
+	shared2, total2 := SharedNodes(v1, v2)
+	assertEqual(t, total+3, total2)
+	assertEqual(t, total-3, shared2)
+
+	v3 := NewVector(inputSlice(0, 10000)...)
+	shared, _ = SharedNodes(v1, v3)
+	assertEqual(t, 0, shared)
+	assertEqualBool(t, true, SharingRatio(v1, v3) == 0)
+	assertEqualBool(t, true, SharingRatio(NewVector[int](), NewVector[int]()) == 1)
+}
+
+func TestMapSharedNodes(t *testing.T) {
+	m1 := NewMap[int, int]()
+	for i := 0; i < 20000; i++ {
+		m1 = m1.Store(i, i)
+	}
+
+	m2 := m1.Store(1, -1)
+	shared, total := MapSharedNodes(m1, m2)
+	assertEqualBool(t, true, shared > 0 && shared < total)
+	assertEqualBool(t, true, float64(shared)/float64(total) > 0.5)
+}
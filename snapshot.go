@@ -0,0 +1,106 @@
+package peds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// Snapshots consist of a magic string, the length of the payload as a little endian uint64,
+// the payload, which is a collection in the binary format, and finally the CRC-32 (IEEE)
+// checksum of the payload as a little endian uint32. The length prefix allows snapshots to
+// be read from streams containing other data as well.
+var snapshotMagic = []byte("PEDSNAP1")
+
+const snapshotHeaderSize = 8 + 8
+
+func writeSnapshot(w io.Writer, payload []byte) (int64, error) {
+	buf := make([]byte, 0, snapshotHeaderSize+len(payload)+4)
+	buf = append(buf, snapshotMagic...)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(len(payload)))
+	buf = append(buf, payload...)
+	buf = binary.LittleEndian.AppendUint32(buf, crc32.ChecksumIEEE(payload))
+	n, err := w.Write(buf)
+	return int64(n), err
+}
+
+func readSnapshot(r io.Reader) ([]byte, int64, error) {
+	header := make([]byte, snapshotHeaderSize)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return nil, int64(n), err
+	}
+
+	if !bytes.Equal(header[:len(snapshotMagic)], snapshotMagic) {
+		return nil, int64(n), errors.New("peds: not a snapshot")
+	}
+
+	payloadLen := binary.LittleEndian.Uint64(header[len(snapshotMagic):])
+	if payloadLen > math.MaxInt64-4 {
+		return nil, int64(n), ErrInvalidBinaryData
+	}
+
+	var payload bytes.Buffer
+	m, err := io.CopyN(&payload, r, int64(payloadLen)+4)
+	total := int64(n) + m
+	if err == io.EOF {
+		return nil, total, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return nil, total, err
+	}
+
+	data := payload.Bytes()
+	data, checksum := data[:payloadLen], binary.LittleEndian.Uint32(data[payloadLen:])
+	if crc32.ChecksumIEEE(data) != checksum {
+		return nil, total, errors.New("peds: snapshot checksum mismatch")
+	}
+
+	return data, total, nil
+}
+
+// WriteTo writes a snapshot of v to w, using the binary format with the default codec for T.
+// It implements io.WriterTo.
+func (v *Vector[T]) WriteTo(w io.Writer) (int64, error) {
+	payload, err := v.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	return writeSnapshot(w, payload)
+}
+
+// ReadFrom replaces the contents of v with a snapshot, written by WriteTo, read from r. It
+// implements io.ReaderFrom. Reading stops at the end of the snapshot.
+func (v *Vector[T]) ReadFrom(r io.Reader) (int64, error) {
+	payload, n, err := readSnapshot(r)
+	if err != nil {
+		return n, err
+	}
+
+	return n, v.UnmarshalBinary(payload)
+}
+
+// WriteTo writes a snapshot of m to w, using the binary format with the default codecs for K
+// and V. It implements io.WriterTo.
+func (m *Map[K, V]) WriteTo(w io.Writer) (int64, error) {
+	payload, err := m.MarshalBinary()
+	if err != nil {
+		return 0, err
+	}
+
+	return writeSnapshot(w, payload)
+}
+
+// ReadFrom replaces the contents of m with a snapshot, written by WriteTo, read from r. It
+// implements io.ReaderFrom. Reading stops at the end of the snapshot.
+func (m *Map[K, V]) ReadFrom(r io.Reader) (int64, error) {
+	payload, n, err := readSnapshot(r)
+	if err != nil {
+		return n, err
+	}
+
+	return n, m.UnmarshalBinary(payload)
+}
@@ -0,0 +1,82 @@
+package peds
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestVectorSnapshotRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vector.snap")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vec := NewVector(inputSlice(0, 10000)...)
+	written, err := vec.WriteTo(f)
+	if err != nil || f.Close() != nil {
+		t.Fatal(err)
+	}
+
+	f, err = os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var result Vector[int]
+	read, err := result.ReadFrom(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, int(written), int(read))
+	assertEqualBool(t, true, VectorEqual(vec, &result))
+}
+
+func TestMapSnapshotsInStream(t *testing.T) {
+	m1 := NewMapFromNativeMap(map[string]int{"a": 1, "b": 2})
+	m2 := NewMapFromNativeMap(map[string]int{"c": 3})
+	var buf bytes.Buffer
+	if _, err := m1.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m2.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var r1, r2 Map[string, int]
+	if _, err := r1.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r2.ReadFrom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualBool(t, true, MapEqual(m1, &r1))
+	assertEqualBool(t, true, MapEqual(m2, &r2))
+}
+
+func TestSnapshotCorruption(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := NewVector(1, 2, 3).WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	data := buf.Bytes()
+	var result Vector[int]
+	_, err := result.ReadFrom(bytes.NewReader(data[:len(data)-1]))
+	assertEqualBool(t, true, err == io.ErrUnexpectedEOF)
+
+	data[len(data)-6]++
+	_, err = result.ReadFrom(bytes.NewReader(data))
+	assertEqualBool(t, true, err != nil)
+
+	_, err = result.ReadFrom(bytes.NewReader([]byte("not a snapshot at all")))
+	assertEqualBool(t, true, err != nil)
+}
@@ -0,0 +1,108 @@
+package peds
+
+import (
+	"cmp"
+	"iter"
+)
+
+// ///////////////
+// / SortedSet ///
+// ///////////////
+
+// A SortedSet is a persistent/immutable set of distinct items kept in order. It is a thin
+// wrapper around a SortedMap with empty values.
+type SortedSet[T any] struct {
+	items SortedMap[T, struct{}]
+}
+
+// NewSortedSet returns a new set, ordered by the natural order of the items, containing the
+// items provided in items. Duplicates are only included once.
+func NewSortedSet[T cmp.Ordered](items ...T) *SortedSet[T] {
+	return NewSortedSetFunc(cmp.Compare[T], items...)
+}
+
+// NewSortedSetFunc returns a new set, ordered by compare, containing the items provided in
+// items. compare must return a negative number if a < b, a positive number if a > b and zero
+// if a and b are equal.
+func NewSortedSetFunc[T any](compare func(a, b T) int, items ...T) *SortedSet[T] {
+	m := NewBTreeMapFunc[T, struct{}](compare)
+	for _, item := range items {
+		m = m.store(item, struct{}{})
+	}
+
+	return &SortedSet[T]{items: m}
+}
+
+// NewSortedSetFromMap returns a new set backed by m, containing its keys.
+func NewSortedSetFromMap[T any](m SortedMap[T, struct{}]) *SortedSet[T] {
+	return &SortedSet[T]{items: m}
+}
+
+// Len returns the number of items in s.
+func (s *SortedSet[T]) Len() int {
+	return s.items.Len()
+}
+
+// Contains reports whether item is in s.
+func (s *SortedSet[T]) Contains(item T) bool {
+	_, ok := s.items.Load(item)
+	return ok
+}
+
+// Add returns a new set with item added. The set itself is returned if item is already
+// present.
+func (s *SortedSet[T]) Add(item T) *SortedSet[T] {
+	if s.Contains(item) {
+		return s
+	}
+
+	return &SortedSet[T]{items: s.items.Store(item, struct{}{})}
+}
+
+// Remove returns a new set with item removed. The set itself is returned if item is not
+// present.
+func (s *SortedSet[T]) Remove(item T) *SortedSet[T] {
+	if !s.Contains(item) {
+		return s
+	}
+
+	return &SortedSet[T]{items: s.items.Delete(item)}
+}
+
+// Min returns the smallest item in s, ok is false if s is empty.
+func (s *SortedSet[T]) Min() (item T, ok bool) {
+	item, _, ok = s.items.Min()
+	return item, ok
+}
+
+// Max returns the largest item in s, ok is false if s is empty.
+func (s *SortedSet[T]) Max() (item T, ok bool) {
+	item, _, ok = s.items.Max()
+	return item, ok
+}
+
+// Range calls f repeatedly passing it each item in s in ascending order as argument until
+// either all items have been visited or f returns false.
+func (s *SortedSet[T]) Range(f func(T) bool) {
+	s.items.Range(func(item T, _ struct{}) bool {
+		return f(item)
+	})
+}
+
+// RangeBetween works like Range but only visits the items between from and to, bounds
+// selects whether from and to themselves are included, see SortedMap.RangeBetween.
+func (s *SortedSet[T]) RangeBetween(from, to T, bounds RangeBounds, f func(T) bool) {
+	s.items.RangeBetween(from, to, bounds, func(item T, _ struct{}) bool {
+		return f(item)
+	})
+}
+
+// All returns an iterator over the items of s in ascending order.
+func (s *SortedSet[T]) All() iter.Seq[T] {
+	return s.Range
+}
+
+// ToNativeSlice returns a new native slice containing the items of s in ascending order.
+func (s *SortedSet[T]) ToNativeSlice() []T {
+	return rangeToNativeSlice(s.Len(), s.Range)
+}
@@ -0,0 +1,51 @@
+package peds
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSortedSetOperations(t *testing.T) {
+	s := NewSortedSet(5, 1, 3, 1)
+	assertEqual(t, 3, s.Len())
+	assertEqualBool(t, true, slices.Equal([]int{1, 3, 5}, s.ToNativeSlice()))
+	assertEqualBool(t, true, s.Add(3) == s)
+	assertEqualBool(t, true, s.Remove(4) == s)
+
+	s2 := s.Add(4).Remove(1)
+	assertEqualBool(t, true, slices.Equal([]int{3, 4, 5}, s2.ToNativeSlice()))
+	assertEqualBool(t, true, s.Contains(1))
+	assertEqualBool(t, false, s2.Contains(1))
+
+	minItem, ok := s2.Min()
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 3, minItem)
+	maxItem, _ := s2.Max()
+	assertEqual(t, 5, maxItem)
+
+	_, ok = NewSortedSet[int]().Min()
+	assertEqualBool(t, false, ok)
+}
+
+func TestSortedSetRangeBetween(t *testing.T) {
+	s := NewSortedSetFunc(func(a, b int) int { return b - a })
+	for i := 0; i < 1000; i++ {
+		s = s.Add(i)
+	}
+
+	var actual []int
+	s.RangeBetween(500, 490, IncludeFrom, func(item int) bool {
+		actual = append(actual, item)
+		return true
+	})
+
+	assertEqualBool(t, true, slices.Equal([]int{500, 499, 498, 497, 496, 495, 494, 493, 492, 491}, actual))
+
+	actual = nil
+	NewSortedSetFromMap(NewBTreeMap(MapItem[int, struct{}]{Key: 1}, MapItem[int, struct{}]{Key: 2})).RangeBetween(0, 10, IncludeBoth, func(item int) bool {
+		actual = append(actual, item)
+		return false
+	})
+
+	assertEqualBool(t, true, slices.Equal([]int{1}, actual))
+}
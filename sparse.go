@@ -0,0 +1,210 @@
+package peds
+
+import "math/bits"
+
+// //////////////////
+// / SparseVector ///
+// //////////////////
+
+const sparseBits = 6
+const sparseMask = 1<<sparseBits - 1
+
+// sparseNode is a node in a trie keyed on consecutive six bit groups of the index. Only the
+// children, or values for leaf nodes, present in bitmap are stored, in index order.
+type sparseNode[T any] struct {
+	bitmap   uint64
+	children []*sparseNode[T]
+	values   []T
+}
+
+func (n *sparseNode[T]) position(subidx uint64) (int, bool) {
+	bit := uint64(1) << subidx
+	return bits.OnesCount64(n.bitmap & (bit - 1)), n.bitmap&bit != 0
+}
+
+// A SparseVector is a persistent/immutable array indexed by uint64 where all elements that
+// have not been set hold a default value. Storage is proportional to the number of elements
+// set, not to the largest index, making it suitable for huge, mostly empty, address spaces.
+type SparseVector[T any] struct {
+	root  *sparseNode[T]
+	shift uint
+	len   int
+	def   T
+}
+
+// NewSparseVector returns a new, empty, sparse vector where all elements hold def.
+func NewSparseVector[T any](def T) *SparseVector[T] {
+	return &SparseVector[T]{root: &sparseNode[T]{}, def: def}
+}
+
+// Len returns the number of elements explicitly set in v.
+func (v *SparseVector[T]) Len() int {
+	return v.len
+}
+
+// Default returns the value held by elements that have not been set.
+func (v *SparseVector[T]) Default() T {
+	return v.def
+}
+
+// sparseCovers reports whether a trie with a root at shift can hold index i.
+func sparseCovers(shift uint, i uint64) bool {
+	return shift+sparseBits >= 64 || i>>(shift+sparseBits) == 0
+}
+
+// Load returns the element at index i and whether it has been explicitly set.
+func (v *SparseVector[T]) Load(i uint64) (T, bool) {
+	if !sparseCovers(v.shift, i) {
+		return v.def, false
+	}
+
+	n := v.root
+	for shift := v.shift; ; shift -= sparseBits {
+		pos, ok := n.position((i >> shift) & sparseMask)
+		if !ok {
+			return v.def, false
+		}
+
+		if shift == 0 {
+			return n.values[pos], true
+		}
+
+		n = n.children[pos]
+	}
+}
+
+// Get returns the element at index i, the default value if it has not been set.
+func (v *SparseVector[T]) Get(i uint64) T {
+	value, _ := v.Load(i)
+	return value
+}
+
+// Set returns a new sparse vector with the element at index i set to value.
+func (v *SparseVector[T]) Set(i uint64, value T) *SparseVector[T] {
+	root, shift := v.root, v.shift
+	for !sparseCovers(shift, i) {
+		// Grow the trie upwards until it covers i. The old root becomes the first child.
+		if root.bitmap != 0 {
+			root = &sparseNode[T]{bitmap: 1, children: []*sparseNode[T]{root}}
+		}
+
+		shift += sparseBits
+	}
+
+	root, added := sparseSet(root, shift, i, value)
+	length := v.len
+	if added {
+		length++
+	}
+
+	return &SparseVector[T]{root: root, shift: shift, len: length, def: v.def}
+}
+
+func sparseSet[T any](n *sparseNode[T], shift uint, i uint64, value T) (*sparseNode[T], bool) {
+	subidx := (i >> shift) & sparseMask
+	pos, ok := n.position(subidx)
+	result := &sparseNode[T]{bitmap: n.bitmap | 1<<subidx}
+	if shift == 0 {
+		if ok {
+			result.values = append([]T(nil), n.values...)
+			result.values[pos] = value
+		} else {
+			result.values = insertAt(n.values, pos, value)
+		}
+
+		return result, !ok
+	}
+
+	if !ok {
+		child, _ := sparseSet(&sparseNode[T]{}, shift-sparseBits, i, value)
+		result.children = insertAt(n.children, pos, child)
+		return result, true
+	}
+
+	child, added := sparseSet(n.children[pos], shift-sparseBits, i, value)
+	result.children = append([]*sparseNode[T](nil), n.children...)
+	result.children[pos] = child
+	return result, added
+}
+
+// insertAt returns a copy of items with item inserted at position pos.
+func insertAt[T any](items []T, pos int, item T) []T {
+	result := make([]T, len(items)+1)
+	copy(result, items[:pos])
+	result[pos] = item
+	copy(result[pos+1:], items[pos:])
+	return result
+}
+
+// removeAt returns a copy of items with the item at position pos removed.
+func removeAt[T any](items []T, pos int) []T {
+	result := make([]T, len(items)-1)
+	copy(result, items[:pos])
+	copy(result[pos:], items[pos+1:])
+	return result
+}
+
+// Delete returns a new sparse vector where the element at index i holds the default value.
+func (v *SparseVector[T]) Delete(i uint64) *SparseVector[T] {
+	if _, ok := v.Load(i); !ok {
+		return v
+	}
+
+	root := sparseDelete(v.root, v.shift, i)
+	if root == nil {
+		root = &sparseNode[T]{}
+	}
+
+	return &SparseVector[T]{root: root, shift: v.shift, len: v.len - 1, def: v.def}
+}
+
+// sparseDelete returns a copy of n with index i, which must be present, removed, or nil if
+// the node becomes empty.
+func sparseDelete[T any](n *sparseNode[T], shift uint, i uint64) *sparseNode[T] {
+	subidx := (i >> shift) & sparseMask
+	pos, _ := n.position(subidx)
+	if shift == 0 {
+		if n.bitmap == 1<<subidx {
+			return nil
+		}
+
+		return &sparseNode[T]{bitmap: n.bitmap &^ (1 << subidx), values: removeAt(n.values, pos)}
+	}
+
+	child := sparseDelete(n.children[pos], shift-sparseBits, i)
+	if child == nil {
+		if n.bitmap == 1<<subidx {
+			return nil
+		}
+
+		return &sparseNode[T]{bitmap: n.bitmap &^ (1 << subidx), children: removeAt(n.children, pos)}
+	}
+
+	children := append([]*sparseNode[T](nil), n.children...)
+	children[pos] = child
+	return &sparseNode[T]{bitmap: n.bitmap, children: children}
+}
+
+// Range calls f repeatedly passing it each explicitly set index and its element, in ascending
+// index order, until either all elements have been visited or f returns false.
+func (v *SparseVector[T]) Range(f func(uint64, T) bool) {
+	sparseRange(v.root, v.shift, 0, f)
+}
+
+func sparseRange[T any](n *sparseNode[T], shift uint, prefix uint64, f func(uint64, T) bool) bool {
+	bitmap := n.bitmap
+	for pos := 0; bitmap != 0; pos++ {
+		subidx := uint64(bits.TrailingZeros64(bitmap))
+		bitmap &^= 1 << subidx
+		i := prefix | subidx<<shift
+		if shift == 0 {
+			if !f(i, n.values[pos]) {
+				return false
+			}
+		} else if !sparseRange(n.children[pos], shift-sparseBits, i, f) {
+			return false
+		}
+	}
+
+	return true
+}
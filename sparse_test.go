@@ -0,0 +1,67 @@
+package peds
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestSparseVectorSetGet(t *testing.T) {
+	empty := NewSparseVector(-1)
+	v := empty.Set(5, 50).Set(math.MaxUint64, 1).Set(1<<40, 2).Set(5, 55)
+	assertEqual(t, 3, v.Len())
+	assertEqual(t, 55, v.Get(5))
+	assertEqual(t, 1, v.Get(math.MaxUint64))
+	assertEqual(t, 2, v.Get(1<<40))
+	assertEqual(t, -1, v.Get(6))
+	assertEqual(t, -1, empty.Get(5))
+	assertEqual(t, 0, empty.Len())
+
+	_, ok := v.Load(1 << 41)
+	assertEqualBool(t, false, ok)
+}
+
+func TestSparseVectorDelete(t *testing.T) {
+	v := NewSparseVector(0).Set(1, 1).Set(1000000, 2)
+	d := v.Delete(1000000)
+	assertEqual(t, 1, d.Len())
+	assertEqual(t, 0, d.Get(1000000))
+	assertEqual(t, 1, d.Get(1))
+	assertEqual(t, 2, v.Get(1000000))
+	assertEqualBool(t, true, d.Delete(7) == d)
+	assertEqual(t, 0, d.Delete(1).Len())
+}
+
+func TestSparseVectorRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	expected := make(map[uint64]int)
+	v := NewSparseVector(0)
+	for i := 0; i < 5000; i++ {
+		index := rnd.Uint64() >> uint(rnd.Intn(64))
+		if i%4 == 0 && len(expected) > 0 {
+			for k := range expected {
+				index = k
+				break
+			}
+
+			delete(expected, index)
+			v = v.Delete(index)
+		} else {
+			expected[index] = i
+			v = v.Set(index, i)
+		}
+	}
+
+	assertEqual(t, len(expected), v.Len())
+	var last uint64
+	count := 0
+	v.Range(func(i uint64, value int) bool {
+		assertEqualBool(t, true, count == 0 || i > last)
+		assertEqual(t, expected[i], value)
+		last = i
+		count++
+		return true
+	})
+
+	assertEqual(t, len(expected), count)
+}
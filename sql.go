@@ -0,0 +1,86 @@
+package peds
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// SQLVector adapts a Vector for storage in a JSON database column. It implements
+// driver.Valuer and sql.Scanner. A nil Vector is stored as NULL and NULL is scanned
+// into a nil Vector.
+type SQLVector[T any] struct {
+	*Vector[T]
+}
+
+// Value returns the JSON encoding of the vector.
+func (v SQLVector[T]) Value() (driver.Value, error) {
+	if v.Vector == nil {
+		return nil, nil
+	}
+
+	return v.Vector.MarshalJSON()
+}
+
+// Scan decodes a JSON array, stored as a string or byte slice, into a new vector.
+func (v *SQLVector[T]) Scan(src any) error {
+	data, err := sqlJSONBytes(src)
+	if err != nil || data == nil {
+		v.Vector = nil
+		return err
+	}
+
+	var result Vector[T]
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	v.Vector = &result
+	return nil
+}
+
+// SQLMap adapts a Map for storage in a JSON database column. It implements
+// driver.Valuer and sql.Scanner. A nil Map is stored as NULL and NULL is scanned
+// into a nil Map.
+type SQLMap[K comparable, V any] struct {
+	*Map[K, V]
+}
+
+// Value returns the JSON encoding of the map.
+func (m SQLMap[K, V]) Value() (driver.Value, error) {
+	if m.Map == nil {
+		return nil, nil
+	}
+
+	return m.Map.MarshalJSON()
+}
+
+// Scan decodes a JSON object, stored as a string or byte slice, into a new map.
+func (m *SQLMap[K, V]) Scan(src any) error {
+	data, err := sqlJSONBytes(src)
+	if err != nil || data == nil {
+		m.Map = nil
+		return err
+	}
+
+	var result Map[K, V]
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+
+	m.Map = &result
+	return nil
+}
+
+func sqlJSONBytes(src any) ([]byte, error) {
+	switch s := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return s, nil
+	case string:
+		return []byte(s), nil
+	}
+
+	return nil, fmt.Errorf("peds: cannot scan %T into a collection", src)
+}
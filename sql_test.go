@@ -0,0 +1,65 @@
+package peds
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"testing"
+)
+
+var _ driver.Valuer = SQLVector[int]{}
+var _ sql.Scanner = &SQLVector[int]{}
+var _ driver.Valuer = SQLMap[string, int]{}
+var _ sql.Scanner = &SQLMap[string, int]{}
+
+func TestSQLVectorRoundTrip(t *testing.T) {
+	value, err := SQLVector[int]{NewVector(1, 2, 3)}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqualString(t, "[1,2,3]", string(value.([]byte)))
+
+	var v SQLVector[int]
+	if err := v.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 3, v.Len())
+	assertEqual(t, 3, v.Get(2))
+
+	if err := v.Scan("[4]"); err != nil {
+		t.Fatal(err)
+	}
+
+	assertEqual(t, 4, v.Get(0))
+}
+
+func TestSQLMapRoundTrip(t *testing.T) {
+	value, err := SQLMap[string, int]{NewMap(MapItem[string, int]{Key: "a", Value: 1})}.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var m SQLMap[string, int]
+	if err := m.Scan(value); err != nil {
+		t.Fatal(err)
+	}
+
+	v, _ := m.Load("a")
+	assertEqual(t, 1, v)
+}
+
+func TestSQLNull(t *testing.T) {
+	value, err := SQLVector[int]{}.Value()
+	assertEqualBool(t, true, value == nil && err == nil)
+
+	m := SQLMap[string, int]{NewMap[string, int]()}
+	assertEqualBool(t, true, m.Scan(nil) == nil)
+	assertEqualBool(t, true, m.Map == nil)
+}
+
+func TestSQLScanErrors(t *testing.T) {
+	var v SQLVector[int]
+	assertEqualBool(t, true, v.Scan(42) != nil)
+	assertEqualBool(t, true, v.Scan(`{"a":1}`) != nil)
+}
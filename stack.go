@@ -0,0 +1,51 @@
+package peds
+
+// ///////////
+// / Stack ///
+// ///////////
+
+// A Stack is a persistent/immutable LIFO stack backed by a Vector.
+type Stack[T any] struct {
+	vector *Vector[T]
+}
+
+// NewStack returns a new stack containing the items provided in items, the last item
+// being at the top of the stack.
+func NewStack[T any](items ...T) *Stack[T] {
+	return &Stack[T]{vector: NewVector(items...)}
+}
+
+// Len returns the number of elements in s.
+func (s *Stack[T]) Len() int {
+	return s.vector.Len()
+}
+
+// Push returns a new stack with item placed on top of it.
+func (s *Stack[T]) Push(item T) *Stack[T] {
+	return &Stack[T]{vector: s.vector.Append(item)}
+}
+
+// Pop returns the top element of s and a new stack with that element removed.
+func (s *Stack[T]) Pop() (T, *Stack[T]) {
+	if s.Len() == 0 {
+		panic("Pop from empty stack")
+	}
+
+	item, vector := s.vector.Pop()
+	return item, &Stack[T]{vector: vector}
+}
+
+// Peek returns the top element of s. ok is set to false if s is empty.
+func (s *Stack[T]) Peek() (item T, ok bool) {
+	if s.Len() == 0 {
+		return item, false
+	}
+
+	return s.vector.Get(s.Len() - 1), true
+}
+
+// Range calls f repeatedly passing it each element in s, from the top of the stack to the
+// bottom, as argument until either all elements have been visited or f returns false.
+func (s *Stack[T]) Range(f func(T) bool) {
+	s.vector.RangeReverse(f)
+}
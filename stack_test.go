@@ -0,0 +1,58 @@
+package peds
+
+import "testing"
+
+func TestStackPushAndPop(t *testing.T) {
+	s := NewStack(0, 1)
+	for i := 2; i < 100; i++ {
+		s = s.Push(i)
+	}
+
+	assertEqual(t, 100, s.Len())
+	s2 := s
+	for i := 99; i >= 0; i-- {
+		item, ok := s2.Peek()
+		assertEqualBool(t, true, ok)
+		assertEqual(t, i, item)
+
+		item, s2 = s2.Pop()
+		assertEqual(t, i, item)
+	}
+
+	assertEqual(t, 0, s2.Len())
+	_, ok := s2.Peek()
+	assertEqualBool(t, false, ok)
+
+	// The original stack is unchanged
+	item, _ := s.Peek()
+	assertEqual(t, 99, item)
+}
+
+func TestStackRange(t *testing.T) {
+	s := NewStack(1, 2, 3, 4)
+	expected := 4
+	s.Range(func(item int) bool {
+		assertEqual(t, expected, item)
+		expected--
+		return expected > 2
+	})
+
+	assertEqual(t, 2, expected)
+}
+
+func TestStackRangeSpanningLeaves(t *testing.T) {
+	s := NewStack(inputSlice(0, 1000)...).Push(1000)
+	expected := 1000
+	s.Range(func(item int) bool {
+		assertEqual(t, expected, item)
+		expected--
+		return true
+	})
+
+	assertEqual(t, -1, expected)
+}
+
+func TestStackPopEmpty(t *testing.T) {
+	defer assertPanic(t, "Pop from empty stack")
+	NewStack[int]().Pop()
+}
@@ -0,0 +1,252 @@
+package peds
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The streaming format is written and read incrementally, one chunk of at most nodeSize
+// elements at a time, so that the memory used is bounded by the size of a chunk rather than
+// by the size of the collection. It consists of a version byte, a kind byte and the total
+// number of elements as a uvarint followed by the chunks. Each chunk is made up of the number
+// of elements in it and the length in bytes of the encoded elements, both as uvarints,
+// followed by the elements encoded back to back by the element codec(s).
+const (
+	streamKindVector byte = 'v'
+	streamKindMap    byte = 'm'
+)
+
+// streamEncoder buffers encoded elements and writes them to w one chunk at a time.
+type streamEncoder struct {
+	w     io.Writer
+	buf   []byte
+	count int
+	err   error
+}
+
+func newStreamEncoder(w io.Writer, kind byte, count int) *streamEncoder {
+	e := &streamEncoder{w: w}
+	_, e.err = w.Write(appendBinaryHeader(nil, kind, count))
+	return e
+}
+
+// added is called after an element has been appended to buf.
+func (e *streamEncoder) added() {
+	if e.count++; e.count == nodeSize {
+		e.flush()
+	}
+}
+
+func (e *streamEncoder) flush() {
+	if e.count == 0 || e.err != nil {
+		return
+	}
+
+	header := binary.AppendUvarint(nil, uint64(e.count))
+	header = binary.AppendUvarint(header, uint64(len(e.buf)))
+	if _, e.err = e.w.Write(header); e.err == nil {
+		_, e.err = e.w.Write(e.buf)
+	}
+
+	e.buf, e.count = e.buf[:0], 0
+}
+
+// streamDecoder reads chunks written by a streamEncoder from r.
+type streamDecoder struct {
+	src io.Reader
+	r   io.ByteReader
+}
+
+// byteReader reads single bytes from an io.Reader without reading ahead, so that nothing
+// following the stream is consumed.
+type byteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
+
+func (b *byteReader) ReadByte() (byte, error) {
+	if _, err := io.ReadFull(b.r, b.buf[:]); err != nil {
+		return 0, err
+	}
+
+	return b.buf[0], nil
+}
+
+// newStreamDecoder reads and verifies the header of a stream of kind from r and returns a
+// decoder for the chunks together with the total number of elements.
+func newStreamDecoder(r io.Reader, kind byte) (*streamDecoder, int, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &byteReader{r: r}
+	}
+
+	d := &streamDecoder{src: r, r: br}
+	header := make([]byte, 2)
+	for i := range header {
+		b, err := br.ReadByte()
+		if err != nil {
+			return nil, 0, d.unexpectedEOF(err)
+		}
+
+		header[i] = b
+	}
+
+	if header[0] != binaryFormatVersion {
+		return nil, 0, fmt.Errorf("peds: unsupported binary format version %d", header[0])
+	}
+
+	if header[1] != kind {
+		return nil, 0, ErrInvalidBinaryData
+	}
+
+	count, err := binary.ReadUvarint(br)
+	if err != nil {
+		return nil, 0, d.unexpectedEOF(err)
+	}
+
+	return d, int(count), nil
+}
+
+func (d *streamDecoder) unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+
+	return err
+}
+
+// next reads the next chunk and returns the number of elements in it and its encoded elements.
+func (d *streamDecoder) next() (int, []byte, error) {
+	count, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, nil, d.unexpectedEOF(err)
+	}
+
+	length, err := binary.ReadUvarint(d.r)
+	if err != nil {
+		return 0, nil, d.unexpectedEOF(err)
+	}
+
+	if count == 0 || count > nodeSize || length < count {
+		return 0, nil, ErrInvalidBinaryData
+	}
+
+	// Copying through a buffer, rather than allocating length bytes up front, protects
+	// against bogus lengths causing huge allocations
+	var chunk bytes.Buffer
+	if _, err := io.CopyN(&chunk, d.src, int64(length)); err != nil {
+		return 0, nil, d.unexpectedEOF(err)
+	}
+
+	return int(count), chunk.Bytes(), nil
+}
+
+// EncodeVectorStream writes v to w in the streaming format, using codec to encode the elements.
+// Elements are encoded and written one chunk at a time.
+func EncodeVectorStream[T any](w io.Writer, v *Vector[T], codec Codec[T]) error {
+	e := newStreamEncoder(w, streamKindVector, v.Len())
+	v.Range(func(item T) bool {
+		if e.buf, e.err = codec.Append(e.buf, item); e.err == nil {
+			e.added()
+		}
+
+		return e.err == nil
+	})
+
+	e.flush()
+	return e.err
+}
+
+// DecodeVectorStream reads a Vector written by EncodeVectorStream from r, using codec to decode
+// the elements. Reading stops at the end of the stream.
+func DecodeVectorStream[T any](r io.Reader, codec Codec[T]) (*Vector[T], error) {
+	d, count, err := newStreamDecoder(r, streamKindVector)
+	if err != nil {
+		return nil, err
+	}
+
+	var b leafBuilder[T]
+	for decoded := 0; decoded < count; {
+		n, data, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < n; i++ {
+			item, size, err := codec.Decode(data)
+			if err != nil {
+				return nil, err
+			}
+
+			b.add(item)
+			data = data[size:]
+		}
+
+		if decoded += n; len(data) != 0 || decoded > count {
+			return nil, ErrInvalidBinaryData
+		}
+	}
+
+	return b.vector(), nil
+}
+
+// EncodeMapStream writes m to w in the streaming format, using keyCodec and valueCodec to encode
+// the keys and values. Items are encoded and written one chunk at a time.
+func EncodeMapStream[K comparable, V any](w io.Writer, m *Map[K, V], keyCodec Codec[K], valueCodec Codec[V]) error {
+	e := newStreamEncoder(w, streamKindMap, m.Len())
+	m.Range(func(key K, value V) bool {
+		if e.buf, e.err = keyCodec.Append(e.buf, key); e.err != nil {
+			return false
+		}
+
+		if e.buf, e.err = valueCodec.Append(e.buf, value); e.err == nil {
+			e.added()
+		}
+
+		return e.err == nil
+	})
+
+	e.flush()
+	return e.err
+}
+
+// DecodeMapStream reads a Map written by EncodeMapStream from r, using keyCodec and valueCodec
+// to decode the keys and values. Reading stops at the end of the stream.
+func DecodeMapStream[K comparable, V any](r io.Reader, keyCodec Codec[K], valueCodec Codec[V]) (*Map[K, V], error) {
+	d, count, err := newStreamDecoder(r, streamKindMap)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewMapBuilder[K, V]()
+	for decoded := 0; decoded < count; {
+		n, data, err := d.next()
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < n; i++ {
+			key, size, err := keyCodec.Decode(data)
+			if err != nil {
+				return nil, err
+			}
+
+			data = data[size:]
+			value, size, err := valueCodec.Decode(data)
+			if err != nil {
+				return nil, err
+			}
+
+			data = data[size:]
+			b.Set(key, value)
+		}
+
+		if decoded += n; len(data) != 0 || decoded > count {
+			return nil, ErrInvalidBinaryData
+		}
+	}
+
+	return b.Freeze(), nil
+}
@@ -0,0 +1,102 @@
+package peds
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestVectorStreamRoundTrip(t *testing.T) {
+	for _, size := range testSizes {
+		var buf bytes.Buffer
+		v := NewVector(inputSlice(0, size)...)
+		if err := EncodeVectorStream(&buf, v, varintCodec[int]{}); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Trailing data is left unread
+		buf.WriteString("rest")
+		r := io.Reader(&buf)
+		if size%2 == 0 {
+			r = bufio.NewReaderSize(&buf, 16)
+		}
+
+		decoded, err := DecodeVectorStream(r, varintCodec[int]{})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		assertEqualBool(t, true, VectorEqual(v, decoded))
+		if size%2 != 0 {
+			assertEqualString(t, "rest", buf.String())
+		}
+	}
+}
+
+func TestMapStreamRoundTrip(t *testing.T) {
+	m := NewMap[string, int]()
+	for i := 0; i < 1000; i++ {
+		m = m.Store(fmt.Sprint(i), i)
+	}
+
+	var buf bytes.Buffer
+	if err := EncodeMapStream(&buf, m, stringCodec{}, varintCodec[int]{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	decoded, err := DecodeMapStream(&buf, stringCodec{}, varintCodec[int]{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqualBool(t, true, MapEqual(m, decoded))
+	assertEqual(t, 0, buf.Len())
+}
+
+type chunkCountingWriter struct {
+	writes  int
+	maxSize int
+}
+
+func (w *chunkCountingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	w.maxSize = max(w.maxSize, len(p))
+	return len(p), nil
+}
+
+func TestVectorStreamWritesChunks(t *testing.T) {
+	w := &chunkCountingWriter{}
+	v := NewVector(inputSlice(0, 10*nodeSize)...)
+	if err := EncodeVectorStream(w, v, varintCodec[int]{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Header followed by a chunk header and the chunk data per leaf
+	assertEqual(t, 1+2*10, w.writes)
+	assertEqualBool(t, true, w.maxSize <= nodeSize*3)
+}
+
+func TestVectorStreamTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeVectorStream(&buf, NewVector(inputSlice(0, 100)...), varintCodec[int]{}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	data := buf.Bytes()
+	for _, n := range []int{0, 1, 3, 10, len(data) - 1} {
+		_, err := DecodeVectorStream(bytes.NewReader(data[:n]), varintCodec[int]{})
+		assertEqualBool(t, true, errors.Is(err, io.ErrUnexpectedEOF))
+	}
+}
+
+func TestVectorStreamInvalid(t *testing.T) {
+	_, err := DecodeVectorStream(bytes.NewReader([]byte{binaryFormatVersion, streamKindMap, 0}), varintCodec[int]{})
+	assertEqualBool(t, true, errors.Is(err, ErrInvalidBinaryData))
+
+	// Chunk claiming more elements than fit in a leaf
+	_, err = DecodeVectorStream(bytes.NewReader([]byte{binaryFormatVersion, streamKindVector, 100, 100, 100}), varintCodec[int]{})
+	assertEqualBool(t, true, errors.Is(err, ErrInvalidBinaryData))
+}
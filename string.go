@@ -0,0 +1,62 @@
+package peds
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Max number of elements included when formatting a collection as a string
+const stringPreviewLen = 10
+
+// previewString formats the first elements visited by rangeFunc as name[e1 e2 …].
+func previewString(name string, rangeFunc func(func(string) bool)) string {
+	var sb strings.Builder
+	sb.WriteString(name)
+	sb.WriteByte('[')
+	count := 0
+	rangeFunc(func(item string) bool {
+		if count == stringPreviewLen {
+			sb.WriteString(" …")
+			return false
+		}
+
+		if count > 0 {
+			sb.WriteByte(' ')
+		}
+
+		sb.WriteString(item)
+		count++
+		return true
+	})
+
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// String returns a string representation of v, including the first few elements.
+func (v *Vector[T]) String() string {
+	return previewString("Vector", func(f func(string) bool) {
+		v.Range(func(item T) bool {
+			return f(fmt.Sprint(item))
+		})
+	})
+}
+
+// String returns a string representation of s, including the first few elements.
+func (s *VectorSlice[T]) String() string {
+	return previewString("VectorSlice", func(f func(string) bool) {
+		s.Range(func(item T) bool {
+			return f(fmt.Sprint(item))
+		})
+	})
+}
+
+// String returns a string representation of m, including the first few items. Items are
+// listed in iteration order, which is not specified.
+func (m *Map[K, V]) String() string {
+	return previewString("Map", func(f func(string) bool) {
+		m.Range(func(key K, value V) bool {
+			return f(fmt.Sprintf("%v:%v", key, value))
+		})
+	})
+}
@@ -0,0 +1,27 @@
+package peds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestVectorString(t *testing.T) {
+	assertEqualString(t, "Vector[]", NewVector[int]().String())
+	assertEqualString(t, "Vector[1 2 3]", NewVector(1, 2, 3).String())
+	assertEqualString(t, "Vector[0 1 2 3 4 5 6 7 8 9]", NewVector(inputSlice(0, 10)...).String())
+	assertEqualString(t, "Vector[0 1 2 3 4 5 6 7 8 9 …]", NewVector(inputSlice(0, 1000)...).String())
+	assertEqualString(t, "Vector[a b]", fmt.Sprintf("%v", NewVector("a", "b")))
+}
+
+func TestVectorSliceString(t *testing.T) {
+	assertEqualString(t, "VectorSlice[3 4]", NewVector(inputSlice(0, 10)...).Slice(3, 5).String())
+}
+
+func TestMapString(t *testing.T) {
+	assertEqualString(t, "Map[]", NewMap[string, int]().String())
+	assertEqualString(t, "Map[a:1]", NewMap(MapItem[string, int]{Key: "a", Value: 1}).String())
+
+	m := NewMapFromNativeMap(map[int]int{1: 1, 2: 2, 3: 3, 4: 4, 5: 5, 6: 6, 7: 7, 8: 8, 9: 9, 10: 10, 11: 11})
+	s := m.String()
+	assertEqualString(t, " …]", s[len(s)-len(" …]"):])
+}
@@ -0,0 +1,217 @@
+package peds
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ///////////
+// / Table ///
+// ///////////
+
+// A TableIndex describes a secondary index of a Table. Key extracts the key of a row, it must
+// return a comparable value. If Unique is set no two rows may have the same key.
+type TableIndex[T any] struct {
+	Name   string
+	Key    func(T) any
+	Unique bool
+}
+
+// ErrDuplicateKey is returned when inserting a row with a primary key that is already present
+// in a Table.
+type ErrDuplicateKey struct {
+	Key any
+}
+
+func (e ErrDuplicateKey) Error() string {
+	return fmt.Sprintf("Duplicate primary key %v", e.Key)
+}
+
+// ErrUniqueViolation is returned when a change to a Table would result in two rows with the
+// same key in a unique index.
+type ErrUniqueViolation struct {
+	Index string
+	Key   any
+}
+
+func (e ErrUniqueViolation) Error() string {
+	return fmt.Sprintf("Duplicate key %v in unique index %s", e.Key, e.Index)
+}
+
+// A Table is a persistent/immutable collection of rows identified by a primary key, with any
+// number of secondary indexes. Together with a Ref it can be used as a simple embedded in
+// memory database where every version is a consistent snapshot.
+//
+// Rows are stored in a Vector. Removing a row moves the last row into its place, the order of
+// the rows is hence the insertion order only as long as no rows are deleted.
+type Table[T any, PK comparable] struct {
+	rows      *Vector[T]
+	pk        func(T) PK
+	positions *Map[PK, int]
+	indexes   []TableIndex[T]
+
+	// One map per index from key to the primary keys of the rows with that key
+	indexData []*Map[any, *Map[PK, struct{}]]
+}
+
+// NewTable returns a new, empty, table where pk extracts the primary key of a row.
+func NewTable[T any, PK comparable](pk func(T) PK, indexes ...TableIndex[T]) *Table[T, PK] {
+	indexData := make([]*Map[any, *Map[PK, struct{}]], len(indexes))
+	for i := range indexData {
+		indexData[i] = NewMap[any, *Map[PK, struct{}]]()
+	}
+
+	return &Table[T, PK]{
+		rows:      NewVector[T](),
+		pk:        pk,
+		positions: NewMap[PK, int](),
+		indexes:   slices.Clone(indexes),
+		indexData: indexData}
+}
+
+// Len returns the number of rows in t.
+func (t *Table[T, PK]) Len() int {
+	return t.rows.Len()
+}
+
+// GetByPK returns the row with primary key pk. ok is set to false if there is no such row.
+func (t *Table[T, PK]) GetByPK(pk PK) (row T, ok bool) {
+	if pos, ok := t.positions.Load(pk); ok {
+		return t.rows.Get(pos), true
+	}
+
+	return row, false
+}
+
+// FindBy returns the rows whose key in the index named index is key, in table order.
+// FindBy panics if there is no index with that name.
+func (t *Table[T, PK]) FindBy(index string, key any) *Vector[T] {
+	pks, ok := t.indexData[t.indexPos(index)].Load(key)
+	if !ok {
+		return NewVector[T]()
+	}
+
+	positions := make([]int, 0, pks.Len())
+	pks.Range(func(pk PK, _ struct{}) bool {
+		positions = append(positions, t.positions.LoadOr(pk, 0))
+		return true
+	})
+
+	slices.Sort(positions)
+	rows := make([]T, len(positions))
+	for i, pos := range positions {
+		rows[i] = t.rows.Get(pos)
+	}
+
+	return NewVector(rows...)
+}
+
+func (t *Table[T, PK]) indexPos(index string) int {
+	for i, idx := range t.indexes {
+		if idx.Name == index {
+			return i
+		}
+	}
+
+	panic(fmt.Sprintf("Unknown table index %s", index))
+}
+
+// Scan calls f repeatedly passing it each row in t in table order until either all rows have
+// been visited or f returns false.
+func (t *Table[T, PK]) Scan(f func(T) bool) {
+	t.rows.Range(f)
+}
+
+// Insert returns a new table with row added. An ErrDuplicateKey is returned if a row with the
+// same primary key already exists and an ErrUniqueViolation if row violates a unique index.
+func (t *Table[T, PK]) Insert(row T) (*Table[T, PK], error) {
+	pk := t.pk(row)
+	if t.positions.Contains(pk) {
+		return t, ErrDuplicateKey{Key: pk}
+	}
+
+	return t.put(pk, row)
+}
+
+// Put returns a new table with row added, replacing any existing row with the same primary key.
+// An ErrUniqueViolation is returned if row violates a unique index.
+func (t *Table[T, PK]) Put(row T) (*Table[T, PK], error) {
+	return t.put(t.pk(row), row)
+}
+
+func (t *Table[T, PK]) put(pk PK, row T) (*Table[T, PK], error) {
+	pos, replace := t.positions.Load(pk)
+	for i, idx := range t.indexes {
+		if !idx.Unique {
+			continue
+		}
+
+		key := idx.Key(row)
+		if pks, ok := t.indexData[i].Load(key); ok && (pks.Len() > 1 || !pks.Contains(pk)) {
+			return t, ErrUniqueViolation{Index: idx.Name, Key: key}
+		}
+	}
+
+	result := t.withIndexData()
+	if replace {
+		result.removeFromIndexes(pk, t.rows.Get(pos))
+		result.rows = t.rows.Set(pos, row)
+	} else {
+		result.positions = t.positions.Store(pk, t.rows.Len())
+		result.rows = t.rows.Append(row)
+	}
+
+	for i, idx := range t.indexes {
+		result.indexData[i] = result.indexData[i].Update(idx.Key(row), func(pks *Map[PK, struct{}], ok bool) *Map[PK, struct{}] {
+			if !ok {
+				pks = NewMap[PK, struct{}]()
+			}
+
+			return pks.Store(pk, struct{}{})
+		})
+	}
+
+	return result, nil
+}
+
+// Delete returns a new table without the row with primary key pk. The table itself is returned
+// if there is no such row.
+func (t *Table[T, PK]) Delete(pk PK) *Table[T, PK] {
+	pos, ok := t.positions.Load(pk)
+	if !ok {
+		return t
+	}
+
+	result := t.withIndexData()
+	result.removeFromIndexes(pk, t.rows.Get(pos))
+	result.positions = t.positions.Delete(pk)
+	rows := t.rows
+	if last := rows.Len() - 1; pos != last {
+		// Move the last row into the position of the deleted row
+		moved := rows.Get(last)
+		rows = rows.Set(pos, moved)
+		result.positions = result.positions.Store(t.pk(moved), pos)
+	}
+
+	result.rows = rows.Shrink(1)
+	return result
+}
+
+// withIndexData returns a copy of t with its own slice of index data.
+func (t *Table[T, PK]) withIndexData() *Table[T, PK] {
+	result := *t
+	result.indexData = slices.Clone(t.indexData)
+	return &result
+}
+
+func (t *Table[T, PK]) removeFromIndexes(pk PK, row T) {
+	for i, idx := range t.indexes {
+		key := idx.Key(row)
+		pks, _ := t.indexData[i].Load(key)
+		if pks = pks.Delete(pk); pks.Len() == 0 {
+			t.indexData[i] = t.indexData[i].Delete(key)
+		} else {
+			t.indexData[i] = t.indexData[i].Store(key, pks)
+		}
+	}
+}
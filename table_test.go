@@ -0,0 +1,121 @@
+package peds
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type tableUser struct {
+	id    int
+	email string
+	team  string
+}
+
+func newUserTable() *Table[tableUser, int] {
+	return NewTable(func(u tableUser) int { return u.id },
+		TableIndex[tableUser]{Name: "email", Key: func(u tableUser) any { return u.email }, Unique: true},
+		TableIndex[tableUser]{Name: "team", Key: func(u tableUser) any { return u.team }})
+}
+
+func mustInsert(t *testing.T, table *Table[tableUser, int], u tableUser) *Table[tableUser, int] {
+	t.Helper()
+	result, err := table.Insert(u)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	return result
+}
+
+func TestTableInsertAndQuery(t *testing.T) {
+	table := newUserTable()
+	table = mustInsert(t, table, tableUser{id: 1, email: "a@x", team: "red"})
+	table = mustInsert(t, table, tableUser{id: 2, email: "b@x", team: "blue"})
+	table = mustInsert(t, table, tableUser{id: 3, email: "c@x", team: "red"})
+
+	assertEqual(t, 3, table.Len())
+	u, ok := table.GetByPK(2)
+	assertEqualBool(t, true, ok)
+	assertEqualString(t, "b@x", u.email)
+	_, ok = table.GetByPK(4)
+	assertEqualBool(t, false, ok)
+
+	red := table.FindBy("team", "red")
+	assertEqual(t, 2, red.Len())
+	assertEqual(t, 1, red.Get(0).id)
+	assertEqual(t, 3, red.Get(1).id)
+	assertEqual(t, 1, table.FindBy("email", "c@x").Len())
+	assertEqual(t, 0, table.FindBy("team", "green").Len())
+
+	ids := 0
+	table.Scan(func(u tableUser) bool {
+		ids += u.id
+		return true
+	})
+	assertEqual(t, 6, ids)
+}
+
+func TestTableConstraints(t *testing.T) {
+	table := mustInsert(t, newUserTable(), tableUser{id: 1, email: "a@x", team: "red"})
+	table = mustInsert(t, table, tableUser{id: 2, email: "b@x", team: "red"})
+
+	_, err := table.Insert(tableUser{id: 1, email: "c@x"})
+	var dupErr ErrDuplicateKey
+	assertEqualBool(t, true, errors.As(err, &dupErr))
+
+	_, err = table.Insert(tableUser{id: 3, email: "a@x"})
+	var uniqueErr ErrUniqueViolation
+	assertEqualBool(t, true, errors.As(err, &uniqueErr))
+	assertEqualString(t, "email", uniqueErr.Index)
+
+	_, err = table.Put(tableUser{id: 2, email: "a@x"})
+	assertEqualBool(t, true, errors.As(err, &uniqueErr))
+
+	// Replacing a row with its own unique key is fine
+	table2, err := table.Put(tableUser{id: 1, email: "a@x", team: "blue"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 2, table2.Len())
+	assertEqual(t, 1, table2.FindBy("team", "red").Len())
+	assertEqual(t, 1, table2.FindBy("team", "blue").Len())
+	assertEqual(t, 2, table.FindBy("team", "red").Len())
+}
+
+func TestTableDelete(t *testing.T) {
+	table := newUserTable()
+	for i := 0; i < 100; i++ {
+		table = mustInsert(t, table, tableUser{id: i, email: fmt.Sprintf("%d@x", i), team: fmt.Sprintf("t%d", i%3)})
+	}
+
+	deleted := table
+	for i := 0; i < 100; i += 2 {
+		deleted = deleted.Delete(i)
+	}
+
+	assertEqual(t, 50, deleted.Len())
+	assertEqual(t, 100, table.Len())
+	for i := 0; i < 100; i++ {
+		u, ok := deleted.GetByPK(i)
+		assertEqualBool(t, i%2 == 1, ok)
+		if ok {
+			assertEqual(t, i, u.id)
+		}
+	}
+
+	assertEqual(t, 17, deleted.FindBy("team", "t0").Len())
+	assertEqual(t, 0, deleted.FindBy("email", "0@x").Len())
+	if deleted.Delete(0) != deleted {
+		t.Errorf("Expected deleting missing row to return the same table")
+	}
+
+	// The email of a deleted row can be reused
+	mustInsert(t, deleted, tableUser{id: 200, email: "0@x"})
+}
+
+func TestTableUnknownIndex(t *testing.T) {
+	defer assertPanic(t, "Unknown table index")
+	newUserTable().FindBy("name", "x")
+}
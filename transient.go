@@ -0,0 +1,161 @@
+package peds
+
+// /////////////////////
+// / TransientVector ///
+// /////////////////////
+
+// editToken identifies the nodes created by one TransientVector. It must not be zero sized
+// since pointers to distinct zero sized values may compare equal.
+type editToken struct {
+	_ byte
+}
+
+// A TransientVector is a mutable vector created from a Vector in constant time, useful for
+// bursts of updates. The first update of an element path copies the nodes along the path
+// like for Vector, but the copies are owned by the transient and later updates to them are
+// made in place without further copying. Persistent turns the transient back into a Vector
+// in constant time, after which the transient must no longer be used.
+//
+// A TransientVector is not safe for concurrent use.
+type TransientVector[T any] struct {
+	v    Vector[T]
+	edit *editToken
+
+	// tailOwned is set when the backing array of the tail belongs to the transient
+	tailOwned bool
+}
+
+// Transient returns a new TransientVector with the same contents as v. v is not affected by
+// any changes made to the transient.
+func (v *Vector[T]) Transient() *TransientVector[T] {
+	return &TransientVector[T]{v: Vector[T]{root: v.root, tail: v.tail, len: v.len, shift: v.shift}, edit: &editToken{}}
+}
+
+func (t *TransientVector[T]) assertEditable() {
+	if t.edit == nil {
+		panic("TransientVector used after Persistent")
+	}
+}
+
+// Len returns the length of t.
+func (t *TransientVector[T]) Len() int {
+	t.assertEditable()
+	return t.v.Len()
+}
+
+// Get returns the element at position i.
+func (t *TransientVector[T]) Get(i int) T {
+	t.assertEditable()
+	return t.v.Get(i)
+}
+
+// editable returns n if it is owned by t, otherwise a copy of n owned by t.
+func (t *TransientVector[T]) editable(n *node[T]) *node[T] {
+	if n.edit == t.edit {
+		return n
+	}
+
+	if n.items != nil {
+		items := *n.items
+		return &node[T]{items: &items, edit: t.edit}
+	}
+
+	children := *n.children
+	return &node[T]{children: &children, edit: t.edit}
+}
+
+// ownTail makes sure that the backing array of the tail belongs to t.
+func (t *TransientVector[T]) ownTail() {
+	if !t.tailOwned {
+		tail := new([nodeSize]T)
+		t.v.tail = append(tail[:0], t.v.tail...)
+		t.tailOwned = true
+	}
+}
+
+// Set sets the element at position i to item.
+func (t *TransientVector[T]) Set(i int, item T) {
+	t.assertEditable()
+	if i < 0 || uint(i) >= t.v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: t.v.Len(), Type: "TransientVector"})
+	}
+
+	if uint(i) >= t.v.tailOffset() {
+		t.ownTail()
+		t.v.tail[i&shiftBitMask] = item
+		return
+	}
+
+	t.v.root = t.editable(t.v.root)
+	n := t.v.root
+	for level := t.v.shift; level > 0; level -= shiftSize {
+		subIdx := (uint(i) >> level) & shiftBitMask
+		n.children[subIdx] = t.editable(n.children[subIdx])
+		n = n.children[subIdx]
+	}
+
+	n.items[i&shiftBitMask] = item
+}
+
+// Append appends item(s) to t.
+func (t *TransientVector[T]) Append(item ...T) {
+	t.assertEditable()
+	for _, i := range item {
+		if len(t.v.tail) == nodeSize {
+			t.pushTail()
+		}
+
+		t.ownTail()
+		t.v.tail = append(t.v.tail, i)
+		t.v.len++
+	}
+}
+
+// pushTail moves the full tail into the tree and starts a new, empty, tail.
+func (t *TransientVector[T]) pushTail() {
+	t.ownTail()
+	leaf := &node[T]{items: (*[nodeSize]T)(t.v.tail), edit: t.edit}
+	if t.v.root == nil {
+		t.v.root = &node[T]{children: &[nodeSize]*node[T]{leaf}, edit: t.edit}
+	} else if (t.v.len >> shiftSize) > (1 << t.v.shift) {
+		// Root overflow
+		t.v.root = &node[T]{children: &[nodeSize]*node[T]{t.v.root, t.newPath(t.v.shift, leaf)}, edit: t.edit}
+		t.v.shift += shiftSize
+	} else {
+		t.v.root = t.editable(t.v.root)
+		n := t.v.root
+		for level := t.v.shift; ; level -= shiftSize {
+			subIdx := ((t.v.len - 1) >> level) & shiftBitMask
+			if level == shiftSize {
+				n.children[subIdx] = leaf
+				break
+			}
+
+			if n.children[subIdx] == nil {
+				n.children[subIdx] = t.newPath(level-shiftSize, leaf)
+				break
+			}
+
+			n.children[subIdx] = t.editable(n.children[subIdx])
+			n = n.children[subIdx]
+		}
+	}
+
+	t.v.tail, t.tailOwned = nil, false
+}
+
+func (t *TransientVector[T]) newPath(shift uint, n *node[T]) *node[T] {
+	for ; shift > 0; shift -= shiftSize {
+		n = &node[T]{children: &[nodeSize]*node[T]{n}, edit: t.edit}
+	}
+
+	return n
+}
+
+// Persistent returns a Vector with the contents of t. t must not be used afterwards.
+func (t *TransientVector[T]) Persistent() *Vector[T] {
+	t.assertEditable()
+	t.edit = nil
+	v := t.v
+	return &v
+}
@@ -0,0 +1,74 @@
+package peds
+
+import "testing"
+
+func TestTransientVectorSet(t *testing.T) {
+	for _, size := range testSizes {
+		if size == 0 {
+			continue
+		}
+
+		original := NewVector(inputSlice(0, size)...)
+		tv := original.Transient()
+		for i := 0; i < size; i += 3 {
+			tv.Set(i, -i)
+		}
+
+		tv.Set(size-1, -1)
+		v := tv.Persistent()
+
+		expected := inputSlice(0, size)
+		for i := 0; i < size; i += 3 {
+			expected[i] = -i
+		}
+
+		expected[size-1] = -1
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), v))
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), original))
+	}
+}
+
+func TestTransientVectorAppend(t *testing.T) {
+	for _, size := range testSizes {
+		original := NewVector(inputSlice(0, size)...)
+		tv := original.Transient()
+		tv.Append(inputSlice(size, 2000)...)
+		assertEqual(t, size+2000, tv.Len())
+		tv.Set(0, -1)
+		v := tv.Persistent()
+
+		expected := inputSlice(0, size+2000)
+		expected[0] = -1
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), v))
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), original))
+
+		// The result is an ordinary vector
+		v2 := v.Append(1).Set(1, 2)
+		assertEqual(t, 2, v2.Get(1))
+		assertEqual(t, 1, v.Get(1))
+	}
+}
+
+func TestTransientVectorUpdatesInPlace(t *testing.T) {
+	v := NewVector(inputSlice(0, 10000)...)
+	tv := v.Transient()
+	tv.Set(100, -1)
+
+	// Once the path has been copied further updates to the same leaf do not allocate
+	allocs := testing.AllocsPerRun(100, func() { tv.Set(101, -2) })
+	assertEqual(t, 0, int(allocs))
+	assertEqual(t, 101, v.Get(101))
+	assertEqual(t, -2, tv.Persistent().Get(101))
+}
+
+func TestTransientVectorUsedAfterPersistent(t *testing.T) {
+	tv := NewVector(1, 2, 3).Transient()
+	tv.Persistent()
+	defer assertPanic(t, "TransientVector used after Persistent")
+	tv.Set(0, 1)
+}
+
+func TestTransientVectorSetOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewVector(1, 2, 3).Transient().Set(3, 1)
+}
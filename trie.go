@@ -0,0 +1,235 @@
+package peds
+
+import (
+	"sort"
+	"strings"
+)
+
+// ///////////////////
+// / StringTrieMap ///
+// ///////////////////
+
+type trieNode[V any] struct {
+	// label is the part of the key on the edge from the parent to this node, it is
+	// only empty for the root.
+	label    string
+	value    V
+	hasValue bool
+
+	// children are sorted by the first byte of their labels, which are all distinct.
+	children []*trieNode[V]
+}
+
+func (n *trieNode[V]) childIndex(c byte) (int, bool) {
+	i := sort.Search(len(n.children), func(i int) bool { return n.children[i].label[0] >= c })
+	return i, i < len(n.children) && n.children[i].label[0] == c
+}
+
+func (n *trieNode[V]) withChild(i int, child *trieNode[V]) *trieNode[V] {
+	result := *n
+	result.children = append([]*trieNode[V](nil), n.children...)
+	result.children[i] = child
+	return &result
+}
+
+func commonPrefixLength(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+
+	return i
+}
+
+// trieStore returns a copy of n with value stored at key, relative to n, and whether the
+// key was added.
+func trieStore[V any](n *trieNode[V], key string, value V) (*trieNode[V], bool) {
+	if key == "" {
+		result := *n
+		result.value, result.hasValue = value, true
+		return &result, !n.hasValue
+	}
+
+	i, ok := n.childIndex(key[0])
+	if !ok {
+		result := *n
+		result.children = insertAt(n.children, i, &trieNode[V]{label: key, value: value, hasValue: true})
+		return &result, true
+	}
+
+	child := n.children[i]
+	common := commonPrefixLength(child.label, key)
+	if common < len(child.label) {
+		// Split the edge at the end of the common prefix
+		tail := *child
+		tail.label = child.label[common:]
+		child = &trieNode[V]{label: child.label[:common], children: []*trieNode[V]{&tail}}
+	}
+
+	child, added := trieStore(child, key[common:], value)
+	return n.withChild(i, child), added
+}
+
+// trieDelete returns a copy of n with key, relative to n, removed and whether the key was
+// present.
+func trieDelete[V any](n *trieNode[V], key string) (*trieNode[V], bool) {
+	if key == "" {
+		if !n.hasValue {
+			return n, false
+		}
+
+		result := *n
+		var zero V
+		result.value, result.hasValue = zero, false
+		return &result, true
+	}
+
+	i, ok := n.childIndex(key[0])
+	if !ok || !strings.HasPrefix(key, n.children[i].label) {
+		return n, false
+	}
+
+	child, removed := trieDelete(n.children[i], key[len(n.children[i].label):])
+	if !removed {
+		return n, false
+	}
+
+	switch {
+	case !child.hasValue && len(child.children) == 0:
+		result := *n
+		result.children = removeAt(n.children, i)
+		return &result, true
+	case !child.hasValue && len(child.children) == 1:
+		// Merge the child with its only remaining child
+		merged := *child.children[0]
+		merged.label = child.label + merged.label
+		child = &merged
+	}
+
+	return n.withChild(i, child), true
+}
+
+func (n *trieNode[V]) rangeAll(key string, f func(string, V) bool) bool {
+	if n.hasValue && !f(key, n.value) {
+		return false
+	}
+
+	for _, child := range n.children {
+		if !child.rangeAll(key+child.label, f) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// A StringTrieMap is a persistent/immutable map from string keys to values stored as a radix
+// tree. In addition to the usual map operations it supports finding all keys with a given
+// prefix and the longest key that is a prefix of a given string, as used for routing and
+// autocompletion. Keys are visited in lexicographic byte order.
+type StringTrieMap[V any] struct {
+	root *trieNode[V]
+	len  int
+}
+
+// NewStringTrieMap returns a new, empty, trie map.
+func NewStringTrieMap[V any]() *StringTrieMap[V] {
+	return &StringTrieMap[V]{root: &trieNode[V]{}}
+}
+
+// Len returns the number of items in m.
+func (m *StringTrieMap[V]) Len() int {
+	return m.len
+}
+
+// Load returns the value stored for key and whether it was found.
+func (m *StringTrieMap[V]) Load(key string) (value V, ok bool) {
+	n := m.root
+	for key != "" {
+		i, found := n.childIndex(key[0])
+		if !found || !strings.HasPrefix(key, n.children[i].label) {
+			return value, false
+		}
+
+		key = key[len(n.children[i].label):]
+		n = n.children[i]
+	}
+
+	return n.value, n.hasValue
+}
+
+// Store returns a new map with value stored for key.
+func (m *StringTrieMap[V]) Store(key string, value V) *StringTrieMap[V] {
+	root, added := trieStore(m.root, key, value)
+	length := m.len
+	if added {
+		length++
+	}
+
+	return &StringTrieMap[V]{root: root, len: length}
+}
+
+// Delete returns a new map with key removed. The map itself is returned if key is not present.
+func (m *StringTrieMap[V]) Delete(key string) *StringTrieMap[V] {
+	root, removed := trieDelete(m.root, key)
+	if !removed {
+		return m
+	}
+
+	return &StringTrieMap[V]{root: root, len: m.len - 1}
+}
+
+// Range calls f repeatedly passing it each key and value in m, in lexicographic key order,
+// until either all items have been visited or f returns false.
+func (m *StringTrieMap[V]) Range(f func(string, V) bool) {
+	m.root.rangeAll("", f)
+}
+
+// RangePrefix calls f repeatedly passing it each key starting with prefix, and its value, in
+// lexicographic key order until either all such items have been visited or f returns false.
+func (m *StringTrieMap[V]) RangePrefix(prefix string, f func(string, V) bool) {
+	n, key, rest := m.root, "", prefix
+	for rest != "" {
+		i, found := n.childIndex(rest[0])
+		if !found {
+			return
+		}
+
+		child := n.children[i]
+		if strings.HasPrefix(child.label, rest) {
+			child.rangeAll(key+child.label, f)
+			return
+		}
+
+		if !strings.HasPrefix(rest, child.label) {
+			return
+		}
+
+		key, rest, n = key+child.label, rest[len(child.label):], child
+	}
+
+	n.rangeAll(key, f)
+}
+
+// LongestPrefix returns the longest key in m that is a prefix of s, its value and whether such
+// a key was found.
+func (m *StringTrieMap[V]) LongestPrefix(s string) (key string, value V, ok bool) {
+	n, consumed := m.root, 0
+	for {
+		if n.hasValue {
+			key, value, ok = s[:consumed], n.value, true
+		}
+
+		if consumed == len(s) {
+			return key, value, ok
+		}
+
+		i, found := n.childIndex(s[consumed])
+		if !found || !strings.HasPrefix(s[consumed:], n.children[i].label) {
+			return key, value, ok
+		}
+
+		consumed += len(n.children[i].label)
+		n = n.children[i]
+	}
+}
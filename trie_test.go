@@ -0,0 +1,100 @@
+package peds
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func trieKeys(f func(func(string, int) bool)) []string {
+	var keys []string
+	f(func(key string, value int) bool {
+		keys = append(keys, key)
+		return true
+	})
+
+	return keys
+}
+
+func TestStringTrieMapStoreLoadDelete(t *testing.T) {
+	empty := NewStringTrieMap[int]()
+	m := empty.Store("romane", 1).Store("romanus", 2).Store("romulus", 3).Store("rom", 4).Store("", 5)
+	assertEqual(t, 5, m.Len())
+	for key, expected := range map[string]int{"romane": 1, "romanus": 2, "romulus": 3, "rom": 4, "": 5} {
+		value, ok := m.Load(key)
+		assertEqualBool(t, true, ok)
+		assertEqual(t, expected, value)
+	}
+
+	_, ok := m.Load("roman")
+	assertEqualBool(t, false, ok)
+	_, ok = empty.Load("rom")
+	assertEqualBool(t, false, ok)
+
+	d := m.Delete("romane").Delete("rom").Delete("missing")
+	assertEqual(t, 3, d.Len())
+	_, ok = d.Load("romane")
+	assertEqualBool(t, false, ok)
+	value, _ := d.Load("romanus")
+	assertEqual(t, 2, value)
+	assertEqualBool(t, true, d.Delete("roma") == d)
+	assertEqual(t, 5, m.Len())
+}
+
+func TestStringTrieMapRangePrefix(t *testing.T) {
+	m := NewStringTrieMap[int]()
+	for i, key := range []string{"b", "ab", "abc", "abd", "a", "ac", "bcd"} {
+		m = m.Store(key, i)
+	}
+
+	assertEqualString(t, "a,ab,abc,abd,ac,b,bcd", strings.Join(trieKeys(m.Range), ","))
+	assertEqualString(t, "ab,abc,abd", strings.Join(trieKeys(func(f func(string, int) bool) { m.RangePrefix("ab", f) }), ","))
+	assertEqualString(t, "bcd", strings.Join(trieKeys(func(f func(string, int) bool) { m.RangePrefix("bc", f) }), ","))
+	assertEqual(t, 0, len(trieKeys(func(f func(string, int) bool) { m.RangePrefix("abx", f) })))
+	assertEqual(t, 7, len(trieKeys(func(f func(string, int) bool) { m.RangePrefix("", f) })))
+}
+
+func TestStringTrieMapLongestPrefix(t *testing.T) {
+	m := NewStringTrieMap[int]().Store("/", 1).Store("/api", 2).Store("/api/users", 3)
+	key, value, ok := m.LongestPrefix("/api/users/17")
+	assertEqualString(t, "/api/users", key)
+	assertEqual(t, 3, value)
+	assertEqualBool(t, true, ok)
+
+	key, _, _ = m.LongestPrefix("/api/items")
+	assertEqualString(t, "/api", key)
+
+	_, _, ok = m.LongestPrefix("api")
+	assertEqualBool(t, false, ok)
+}
+
+func TestStringTrieMapRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	expected := make(map[string]int)
+	m := NewStringTrieMap[int]()
+	for i := 0; i < 5000; i++ {
+		key := fmt.Sprintf("%03x", rnd.Intn(4096))[:1+rnd.Intn(3)]
+		if i%3 == 0 {
+			delete(expected, key)
+			m = m.Delete(key)
+		} else {
+			expected[key] = i
+			m = m.Store(key, i)
+		}
+	}
+
+	var keys []string
+	for key := range expected {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+	assertEqual(t, len(expected), m.Len())
+	assertEqualString(t, strings.Join(keys, ","), strings.Join(trieKeys(m.Range), ","))
+	for key, expectedValue := range expected {
+		value, _ := m.Load(key)
+		assertEqual(t, expectedValue, value)
+	}
+}
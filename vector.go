@@ -1,6 +1,14 @@
 package peds
 
-import "fmt"
+import (
+	"fmt"
+	"iter"
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+	"sync/atomic"
+)
 
 const shiftSize = 5
 const nodeSize = 32
@@ -14,33 +22,405 @@ func uintMin(a, b uint) uint {
 	return b
 }
 
+// rangeToNativeSlice collects the elements visited by rangeFunc into a Go slice with
+// capacity for size elements.
+func rangeToNativeSlice[T any](size int, rangeFunc func(func(T) bool)) []T {
+	result := make([]T, 0, size)
+	rangeFunc(func(item T) bool {
+		result = append(result, item)
+		return true
+	})
+
+	return result
+}
+
 // ////////////
 // / Vector ///
 // ////////////
-type commonNode interface{}
 
-var emptyCommonNode commonNode = []commonNode{}
+// node is a node in the trie of a Vector. Internal nodes hold references to their children,
+// unused positions at the end are nil, while leaf nodes, at level zero, hold the elements.
+// Leaves are always full, partially filled leaves only occur as the tail of a vector.
+type node[T any] struct {
+	children *[nodeSize]*node[T]
+	items    *[nodeSize]T
+
+	// hash caches the content hash of the node once computed, see merkleHash. Zero means
+	// that it has not been computed yet.
+	hash atomic.Uint64
+
+	// edit identifies the TransientVector that created the node, if any. Such nodes are
+	// not shared and may be modified in place by that transient, see TransientVector.
+	edit *editToken
+}
+
+func newLeaf[T any](items *[nodeSize]T) *node[T] {
+	return &node[T]{items: items}
+}
+
+func newBranch[T any](children *[nodeSize]*node[T]) *node[T] {
+	return &node[T]{children: children}
+}
+
+// childCount returns the number of children of the internal node n.
+func (n *node[T]) childCount() int {
+	count := 0
+	for count < nodeSize && n.children[count] != nil {
+		count++
+	}
+
+	return count
+}
 
 // A Vector is an ordered persistent/immutable collection of items corresponding roughly
 // to the use cases for a slice.
+//
+// Vectors with no more than 32 elements hold all of them in the tail and have no root,
+// making them about as cheap as a copied slice.
 type Vector[T any] struct {
 	tail  []T
-	root  commonNode
+	root  *node[T]
 	len   uint
 	shift uint
+
+	// tailClaim, if set, counts the slots of the backing array of tail that have been
+	// claimed by vectors sharing it. A vector whose tail ends at the claimed count may
+	// append to the tail in place after claiming the slots, see Append.
+	tailClaim *atomic.Uint32
 }
 
 // NewVector returns a new vector containing the items provided in items.
 func NewVector[T any](items ...T) *Vector[T] {
-	// TODO: Could potentially do something smarter with a factory for a certain type
-	//       if this results in a lot of allocations.
-	tail := make([]T, 0)
-	v := &Vector[T]{root: emptyCommonNode, shift: shiftSize, tail: tail}
-	return v.Append(items...)
+	itemLen := uint(len(items))
+	if itemLen <= nodeSize {
+		tail := make([]T, itemLen)
+		copy(tail, items)
+		return &Vector[T]{shift: shiftSize, tail: tail, len: itemLen}
+	}
+
+	// Build the tree bottom up from full leaves, the remaining items end up in the tail
+	v := &Vector[T]{len: itemLen}
+	tailOffset := v.tailOffset()
+	nodes := make([]*node[T], 0, tailOffset/nodeSize)
+	for i := uint(0); i < tailOffset; i += nodeSize {
+		leaf := new([nodeSize]T)
+		copy(leaf[:], items[i:i+nodeSize])
+		nodes = append(nodes, newLeaf(leaf))
+	}
+
+	v.tail = make([]T, itemLen-tailOffset)
+	copy(v.tail, items[tailOffset:])
+	v.root, v.shift = buildTree(nodes)
+	return v
+}
+
+// AdoptSlice returns a new vector containing the items in items without copying them. The
+// vector takes ownership of items, which must not be modified afterwards as that would
+// modify the vector, and any vectors derived from it, as well.
+func AdoptSlice[T any](items []T) *Vector[T] {
+	itemLen := uint(len(items))
+	v := &Vector[T]{shift: shiftSize, len: itemLen}
+	tailOffset := v.tailOffset()
+	if tailOffset > 0 {
+		nodes := make([]*node[T], 0, tailOffset/nodeSize)
+		for i := uint(0); i < tailOffset; i += nodeSize {
+			nodes = append(nodes, newLeaf((*[nodeSize]T)(items[i:i+nodeSize])))
+		}
+
+		v.root, v.shift = buildTree(nodes)
+	}
+
+	v.tail = items[tailOffset:itemLen:itemLen]
+	return v
+}
+
+// NewVectorFunc returns a new vector of length n where the element at position i is f(i).
+// The elements are generated in order, directly into the leaves of the vector.
+func NewVectorFunc[T any](n int, f func(i int) T) *Vector[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("Invalid vector length %d (length must be non-negative)", n))
+	}
+
+	v := &Vector[T]{shift: shiftSize, len: uint(n)}
+	tailOffset := v.tailOffset()
+	if tailOffset > 0 {
+		nodes := make([]*node[T], 0, tailOffset/nodeSize)
+		for i := uint(0); i < tailOffset; i += nodeSize {
+			leaf := new([nodeSize]T)
+			for j := range leaf {
+				leaf[j] = f(int(i) + j)
+			}
+
+			nodes = append(nodes, newLeaf(leaf))
+		}
+
+		v.root, v.shift = buildTree(nodes)
+	}
+
+	v.tail = make([]T, uint(n)-tailOffset)
+	for j := range v.tail {
+		v.tail[j] = f(int(tailOffset) + j)
+	}
+
+	return v
+}
+
+// NewVectorRepeat returns a new vector holding n copies of value. All full leaves, and all
+// full subtrees, of the vector are the same node, making the memory used by the vector
+// logarithmic rather than linear in n. Updates to the vector copy the nodes on the updated
+// path, as usual, leaving the rest shared.
+func NewVectorRepeat[T any](value T, n int) *Vector[T] {
+	if n < 0 {
+		panic(fmt.Sprintf("Invalid vector length %d (length must be non-negative)", n))
+	}
+
+	v := &Vector[T]{shift: shiftSize, len: uint(n)}
+	tailOffset := v.tailOffset()
+	if tailOffset > 0 {
+		leaf := new([nodeSize]T)
+		for i := range leaf {
+			leaf[i] = value
+		}
+
+		v.root, v.shift = buildRepeatedTree(newLeaf(leaf), tailOffset/nodeSize)
+	}
+
+	v.tail = make([]T, uint(n)-tailOffset)
+	for i := range v.tail {
+		v.tail[i] = value
+	}
+
+	return v
+}
+
+// NewVectorRange returns a new vector holding the numbers start, start+step, start+2*step and
+// so on, up to but not including stop. step may be negative, in which case the numbers are
+// decreasing and stop must be less than start for the vector to be non-empty. For floating
+// point types each element is computed as start+i*step, which avoids accumulating rounding
+// errors. NewVectorRange panics if step is zero.
+func NewVectorRange[T Number](start, stop, step T) *Vector[T] {
+	return NewVectorFunc(rangeLength(start, stop, step), func(i int) T {
+		return start + T(i)*step
+	})
+}
+
+// rangeLength returns the number of elements in the range described by start, stop and step.
+// Integer distances are computed modulo 2^64, which is exact since they are less than 2^64.
+func rangeLength[T Number](start, stop, step T) int {
+	switch {
+	case step == 0:
+		panic("Invalid range step 0 (step must be non-zero)")
+	case step > 0 && start >= stop, step < 0 && start <= stop:
+		return 0
+	}
+
+	var one T = 1
+	switch {
+	case one/2 != 0:
+		// Floating point
+		return int(math.Ceil(float64(stop-start) / float64(step)))
+	case one-2 > 0:
+		// Unsigned, step is always positive
+		return int((uint64(stop)-uint64(start)-1)/uint64(step) + 1)
+	case step > 0:
+		return int((uint64(int64(stop)-int64(start))-1)/uint64(step) + 1)
+	default:
+		return int((uint64(int64(start)-int64(stop))-1)/uint64(-int64(step)) + 1)
+	}
+}
+
+// buildRepeatedTree returns the root and shift of a tree with count references to leaf as
+// its leaf nodes, laid out as by buildTree. Each level consists of a number of identical
+// full nodes, which are all the same node, possibly followed by one partially filled node.
+func buildRepeatedTree[T any](leaf *node[T], count uint) (*node[T], uint) {
+	full, fullCount, partial := leaf, count, (*node[T])(nil)
+	shift := uint(0)
+	for shift == 0 || fullCount > 1 || (fullCount == 1 && partial != nil) {
+		var parentFull, parentPartial *node[T]
+		if fullCount >= nodeSize {
+			children := new([nodeSize]*node[T])
+			for i := range children {
+				children[i] = full
+			}
+
+			parentFull = newBranch(children)
+		}
+
+		if remainder := fullCount % nodeSize; remainder > 0 || partial != nil {
+			children := new([nodeSize]*node[T])
+			for i := uint(0); i < remainder; i++ {
+				children[i] = full
+			}
+
+			children[remainder] = partial
+			parentPartial = newBranch(children)
+		}
+
+		full, fullCount, partial = parentFull, fullCount/nodeSize, parentPartial
+		shift += shiftSize
+	}
+
+	if fullCount == 1 {
+		return full, shift
+	}
+
+	return partial, shift
+}
+
+// buildTree returns the root and shift of a tree with nodes as its leaf nodes, laid out the
+// same way as a tree built by appending the leaves one by one.
+func buildTree[T any](nodes []*node[T]) (*node[T], uint) {
+	shift := uint(0)
+
+	// The lists of nodes at the intermediate levels are only needed while building the level
+	// above, they are recycled
+	var level *[]*node[T]
+	for shift == 0 || len(nodes) > 1 {
+		parents := getScratch[*node[T]]((len(nodes) + nodeSize - 1) / nodeSize)
+		for start := 0; start < len(nodes); start += nodeSize {
+			children := new([nodeSize]*node[T])
+			copy(children[:], nodes[start:])
+			*parents = append(*parents, newBranch(children))
+		}
+
+		if level != nil {
+			putScratch(level)
+		}
+
+		level, nodes = parents, *parents
+		shift += shiftSize
+	}
+
+	root := nodes[0]
+	putScratch(level)
+	return root, shift
+}
+
+// NewVectorFromSeq returns a new vector containing the items produced by seq, in order.
+func NewVectorFromSeq[T any](seq iter.Seq[T]) *Vector[T] {
+	var b leafBuilder[T]
+	for item := range seq {
+		b.add(item)
+	}
+
+	return b.vector()
+}
+
+// leafBuilder is used to construct a vector, of initially unknown length, in a single pass by
+// filling leaves one at a time and building the tree from them once all items have been added.
+type leafBuilder[T any] struct {
+	leaves  []*node[T]
+	current []T
+}
+
+func (b *leafBuilder[T]) add(item T) {
+	if b.current == nil {
+		b.current = make([]T, 0, nodeSize)
+	}
+
+	b.current = append(b.current, item)
+	if len(b.current) == nodeSize {
+		b.leaves = append(b.leaves, newLeaf((*[nodeSize]T)(b.current)))
+		b.current = nil
+	}
+}
+
+func (b *leafBuilder[T]) vector() *Vector[T] {
+	leaves, tail := b.leaves, b.current
+	if tail == nil {
+		if len(leaves) == 0 {
+			return NewVector[T]()
+		}
+
+		// The last full leaf becomes the tail
+		tail, leaves = leaves[len(leaves)-1].items[:], leaves[:len(leaves)-1]
+	}
+
+	v := &Vector[T]{tail: tail, len: uint(len(leaves)*nodeSize + len(tail)), shift: shiftSize}
+	if len(leaves) > 0 {
+		v.root, v.shift = buildTree(leaves)
+	}
+
+	return v
+}
+
+// ///////////////////
+// / VectorBuilder ///
+// ///////////////////
+
+// VectorBuilder is a transient, mutable, vector that can be used to efficiently construct a
+// Vector by appending a large number of elements. The result is turned into an immutable
+// Vector by calling Freeze.
+type VectorBuilder[T any] struct {
+	b      *leafBuilder[T]
+	frozen bool
+
+	// The recycled slice used for the leaves of b, see getScratch
+	leaves *[]*node[T]
+}
+
+// NewVectorBuilder returns a new, empty, VectorBuilder with room for capacity elements before
+// any internal growth is needed. capacity is only a hint, more elements may be appended.
+func NewVectorBuilder[T any](capacity int) *VectorBuilder[T] {
+	leaves := getScratch[*node[T]](max(capacity, 0) / nodeSize)
+	return &VectorBuilder[T]{b: &leafBuilder[T]{leaves: *leaves}, leaves: leaves}
+}
+
+func (b *VectorBuilder[T]) assertNotFrozen() {
+	if b.frozen {
+		panic("VectorBuilder used after Freeze")
+	}
+}
+
+// Len returns the number of elements appended to b.
+func (b *VectorBuilder[T]) Len() int {
+	b.assertNotFrozen()
+	return len(b.b.leaves)*nodeSize + len(b.b.current)
+}
+
+// Append appends item(s) to b.
+func (b *VectorBuilder[T]) Append(item ...T) {
+	b.assertNotFrozen()
+	for _, i := range item {
+		b.b.add(i)
+	}
+}
+
+// Freeze returns a Vector containing all elements appended to b. The builder must not be used
+// after it has been frozen.
+func (b *VectorBuilder[T]) Freeze() *Vector[T] {
+	b.assertNotFrozen()
+	b.frozen = true
+
+	// The leaves are copied into the branch nodes of the vector, the slice holding them can
+	// be reused
+	v := b.b.vector()
+	*b.leaves = b.b.leaves
+	putScratch(b.leaves)
+	return v
+}
+
+// ownedTail is a tail with room for a full leaf that can be appended to in place, the claim
+// and the items are allocated together.
+type ownedTail[T any] struct {
+	claim atomic.Uint32
+	items [nodeSize]T
 }
 
 // Append returns a new vector with item(s) appended to it.
+//
+// Appending to the most recently appended to version of a vector is amortized O(1) per
+// element, the items are written to free capacity in the tail in place rather than copying it.
 func (v *Vector[T]) Append(item ...T) *Vector[T] {
+	tailLen := len(v.tail)
+	if v.tailClaim != nil && tailLen+len(item) <= nodeSize && len(item) > 0 &&
+		v.tailClaim.CompareAndSwap(uint32(tailLen), uint32(tailLen+len(item))) {
+		// The slots after the tail have not been claimed by any other vector, it is
+		// safe to write to them since no other vector can see them.
+		tail := append(v.tail, item...)
+		return &Vector[T]{root: v.root, tail: tail, len: v.len + uint(len(item)), shift: v.shift, tailClaim: v.tailClaim}
+	}
+
 	result := v
 	itemLen := uint(len(item))
 	for insertOffset := uint(0); insertOffset < itemLen; {
@@ -54,16 +434,55 @@ func (v *Vector[T]) Append(item ...T) *Vector[T] {
 		}
 
 		batchLen := uintMin(itemLen-insertOffset, tailFree)
-		newTail := make([]T, 0, tailLen+batchLen)
-		newTail = append(newTail, result.tail...)
+		owned := new(ownedTail[T])
+		newTail := append(owned.items[:0], result.tail...)
 		newTail = append(newTail, item[insertOffset:insertOffset+batchLen]...)
-		result = &Vector[T]{root: result.root, tail: newTail, len: result.len + batchLen, shift: result.shift}
+		owned.claim.Store(uint32(len(newTail)))
+		result = &Vector[T]{root: result.root, tail: newTail, len: result.len + batchLen, shift: result.shift, tailClaim: &owned.claim}
 		insertOffset += batchLen
 	}
 
 	return result
 }
 
+// Concat returns a new vector containing all elements of v followed by all elements of other.
+// If the length of v is a multiple of the node size full leaf nodes of other are shared
+// rather than copied.
+func (v *Vector[T]) Concat(other *Vector[T]) *Vector[T] {
+	if v.len == 0 {
+		return other
+	}
+
+	return v.appendRange(other, 0, other.len)
+}
+
+// appendRange returns a new vector with the elements [start,stop) of src appended to it.
+// Full leaf nodes are shared with src when they line up with the leaves of the result.
+func (v *Vector[T]) appendRange(src *Vector[T], start, stop uint) *Vector[T] {
+	result := v
+	for i := start; i < stop; {
+		leaf := src.sliceFor(i)
+		offset := i & shiftBitMask
+		end := uintMin(uint(len(leaf)), offset+stop-i)
+		chunk := leaf[offset:end]
+		if result.len&shiftBitMask == 0 && len(chunk) == nodeSize {
+			// The tail is full, push it and use the leaf as the new tail. Tails are
+			// never modified in place so it's safe to share it.
+			if result.len > 0 {
+				result = result.pushLeafNode(result.tail)
+			}
+
+			result = &Vector[T]{root: result.root, tail: chunk, len: result.len + nodeSize, shift: result.shift}
+		} else {
+			result = result.Append(chunk...)
+		}
+
+		i += end - offset
+	}
+
+	return result
+}
+
 func (v *Vector[T]) tailOffset() uint {
 	if v.len < nodeSize {
 		return 0
@@ -72,47 +491,47 @@ func (v *Vector[T]) tailOffset() uint {
 	return ((v.len - 1) >> shiftSize) << shiftSize
 }
 
-func (v *Vector[T]) pushLeafNode(node []T) *Vector[T] {
-	var newRoot commonNode
+func (v *Vector[T]) pushLeafNode(items []T) *Vector[T] {
+	var newRoot *node[T]
 	newShift := v.shift
+	leaf := newLeaf((*[nodeSize]T)(items))
 
 	// Root overflow?
 	if (v.len >> shiftSize) > (1 << v.shift) {
-		newNode := newPath(v.shift, node)
-		newRoot = commonNode([]commonNode{v.root, newNode})
+		newRoot = newBranch(&[nodeSize]*node[T]{v.root, newPath(v.shift, leaf)})
 		newShift = v.shift + shiftSize
 	} else {
-		newRoot = v.pushTail(v.shift, v.root, node)
+		newRoot = v.pushTail(v.shift, v.root, leaf)
 	}
 
 	return &Vector[T]{root: newRoot, tail: v.tail, len: v.len, shift: newShift}
 }
 
-func newPath(shift uint, node commonNode) commonNode {
+func newPath[T any](shift uint, n *node[T]) *node[T] {
 	if shift == 0 {
-		return node
+		return n
 	}
 
-	return newPath(shift-shiftSize, commonNode([]commonNode{node}))
+	return newPath(shift-shiftSize, newBranch(&[nodeSize]*node[T]{n}))
 }
 
-func (v *Vector[T]) pushTail(level uint, parent commonNode, tailNode []T) commonNode {
+func (v *Vector[T]) pushTail(level uint, parent *node[T], leaf *node[T]) *node[T] {
 	subIdx := ((v.len - 1) >> level) & shiftBitMask
-	parentNode := parent.([]commonNode)
-	ret := make([]commonNode, subIdx+1)
-	copy(ret, parentNode)
-	var nodeToInsert commonNode
+	ret := new([nodeSize]*node[T])
+	if parent != nil {
+		// The root of a vector without any leaves in the tree is nil
+		*ret = *parent.children
+	}
 
 	if level == shiftSize {
-		nodeToInsert = tailNode
-	} else if subIdx < uint(len(parentNode)) {
-		nodeToInsert = v.pushTail(level-shiftSize, parentNode[subIdx], tailNode)
+		ret[subIdx] = leaf
+	} else if ret[subIdx] != nil {
+		ret[subIdx] = v.pushTail(level-shiftSize, ret[subIdx], leaf)
 	} else {
-		nodeToInsert = newPath(level-shiftSize, tailNode)
+		ret[subIdx] = newPath(level-shiftSize, leaf)
 	}
 
-	ret[subIdx] = nodeToInsert
-	return ret
+	return newBranch(ret)
 }
 
 // Len returns the length of v.
@@ -123,7 +542,7 @@ func (v *Vector[T]) Len() int {
 // Get returns the element at position i.
 func (v *Vector[T]) Get(i int) T {
 	if i < 0 || uint(i) >= v.len {
-		panic("Index out of bounds")
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "Vector"})
 	}
 
 	return v.sliceFor(uint(i))[i&shiftBitMask]
@@ -134,20 +553,18 @@ func (v *Vector[T]) sliceFor(i uint) []T {
 		return v.tail
 	}
 
-	node := v.root
+	n := v.root
 	for level := v.shift; level > 0; level -= shiftSize {
-		node = node.([]commonNode)[(i>>level)&shiftBitMask]
+		n = n.children[(i>>level)&shiftBitMask]
 	}
 
-	// TODO: Change the nodes of this type to be 32 element arrays of T rather than
-	//       slices to get rid of some overhead?
-	return node.([]T)
+	return n.items[:]
 }
 
 // Set returns a new vector with the element at position i set to item.
 func (v *Vector[T]) Set(i int, item T) *Vector[T] {
 	if i < 0 || uint(i) >= v.len {
-		panic("Index out of bounds")
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "Vector"})
 	}
 
 	if uint(i) >= v.tailOffset() {
@@ -160,19 +577,351 @@ func (v *Vector[T]) Set(i int, item T) *Vector[T] {
 	return &Vector[T]{root: v.doAssoc(v.shift, v.root, uint(i), item), tail: v.tail, len: v.len, shift: v.shift}
 }
 
-func (v *Vector[T]) doAssoc(level uint, node commonNode, i uint, item T) commonNode {
+func (v *Vector[T]) doAssoc(level uint, n *node[T], i uint, item T) *node[T] {
 	if level == 0 {
-		ret := make([]T, nodeSize)
-		copy(ret, node.([]T))
+		ret := *n.items
 		ret[i&shiftBitMask] = item
-		return ret
+		return newLeaf(&ret)
 	}
 
-	ret := make([]commonNode, nodeSize)
-	copy(ret, node.([]commonNode))
+	ret := *n.children
 	subidx := (i >> level) & shiftBitMask
 	ret[subidx] = v.doAssoc(level-shiftSize, ret[subidx], i, item)
-	return ret
+	return newBranch(&ret)
+}
+
+// TryGet returns the element at position i. ok is set to false if i is out of bounds.
+func (v *Vector[T]) TryGet(i int) (item T, ok bool) {
+	if i < 0 || uint(i) >= v.len {
+		return item, false
+	}
+
+	return v.sliceFor(uint(i))[i&shiftBitMask], true
+}
+
+// TrySet returns a new vector with the element at position i set to item. ok is set to false,
+// and the result is nil, if i is out of bounds.
+func (v *Vector[T]) TrySet(i int, item T) (result *Vector[T], ok bool) {
+	if i < 0 || uint(i) >= v.len {
+		return nil, false
+	}
+
+	return v.Set(i, item), true
+}
+
+// Update returns a new vector with the element at position i replaced by the result of
+// calling f with the current element.
+func (v *Vector[T]) Update(i int, f func(T) T) *Vector[T] {
+	if i < 0 || uint(i) >= v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "Vector"})
+	}
+
+	if uint(i) >= v.tailOffset() {
+		newTail := make([]T, len(v.tail))
+		copy(newTail, v.tail)
+		newTail[i&shiftBitMask] = f(newTail[i&shiftBitMask])
+		return &Vector[T]{root: v.root, tail: newTail, len: v.len, shift: v.shift}
+	}
+
+	return &Vector[T]{root: v.doUpdate(v.shift, v.root, uint(i), f), tail: v.tail, len: v.len, shift: v.shift}
+}
+
+func (v *Vector[T]) doUpdate(level uint, n *node[T], i uint, f func(T) T) *node[T] {
+	if level == 0 {
+		ret := *n.items
+		ret[i&shiftBitMask] = f(ret[i&shiftBitMask])
+		return newLeaf(&ret)
+	}
+
+	ret := *n.children
+	subidx := (i >> level) & shiftBitMask
+	ret[subidx] = v.doUpdate(level-shiftSize, ret[subidx], i, f)
+	return newBranch(&ret)
+}
+
+// SetMany returns a new vector with the elements at the positions given by the keys in updates
+// set to the corresponding values. Each node affected by the updates is copied only once.
+func (v *Vector[T]) SetMany(updates map[int]T) *Vector[T] {
+	if len(updates) == 0 {
+		return v
+	}
+
+	indices := make([]uint, 0, len(updates))
+	for i := range updates {
+		if i < 0 || uint(i) >= v.len {
+			panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "Vector"})
+		}
+
+		indices = append(indices, uint(i))
+	}
+
+	slices.Sort(indices)
+	tailOffset := v.tailOffset()
+	treeIndexCount, _ := slices.BinarySearch(indices, tailOffset)
+	result := &Vector[T]{root: v.root, tail: v.tail, len: v.len, shift: v.shift}
+	if treeIndexCount > 0 {
+		result.root = setManyInNode(v.shift, v.root, indices[:treeIndexCount], updates)
+	}
+
+	if treeIndexCount < len(indices) {
+		result.tail = make([]T, len(v.tail))
+		copy(result.tail, v.tail)
+		for _, i := range indices[treeIndexCount:] {
+			result.tail[i&shiftBitMask] = updates[int(i)]
+		}
+	}
+
+	return result
+}
+
+// setManyInNode returns a copy of node, at level, with the elements at indices, which must be
+// sorted, set to the corresponding values in updates.
+func setManyInNode[T any](level uint, n *node[T], indices []uint, updates map[int]T) *node[T] {
+	if level == 0 {
+		ret := *n.items
+		for _, i := range indices {
+			ret[i&shiftBitMask] = updates[int(i)]
+		}
+
+		return newLeaf(&ret)
+	}
+
+	ret := *n.children
+	for start := 0; start < len(indices); {
+		subidx := (indices[start] >> level) & shiftBitMask
+		stop := start + 1
+		for stop < len(indices) && (indices[stop]>>level)&shiftBitMask == subidx {
+			stop++
+		}
+
+		ret[subidx] = setManyInNode(level-shiftSize, ret[subidx], indices[start:stop], updates)
+		start = stop
+	}
+
+	return newBranch(&ret)
+}
+
+// writeRange returns a new vector with the elements [start,stop) overwritten by write. write
+// is called with consecutive chunks of the range, each within a single leaf, together with
+// the position of the chunk relative to start. Each affected node is copied only once.
+func (v *Vector[T]) writeRange(start, stop uint, write func(dst []T, offset uint)) *Vector[T] {
+	if start == stop {
+		return v
+	}
+
+	result := &Vector[T]{root: v.root, tail: v.tail, len: v.len, shift: v.shift}
+	tailOffset := v.tailOffset()
+	if start < tailOffset {
+		result.root = writeRangeInNode(v.shift, v.root, 0, start, min(stop, tailOffset), start, write)
+	}
+
+	if stop > tailOffset {
+		result.tail = slices.Clone(v.tail)
+		from := max(start, tailOffset)
+		write(result.tail[from-tailOffset:stop-tailOffset], from-start)
+	}
+
+	return result
+}
+
+// writeRangeInNode returns a copy of node n, at level and holding the elements from position
+// nodeStart, with the elements [start,stop) overwritten as described for writeRange.
+func writeRangeInNode[T any](level uint, n *node[T], nodeStart, start, stop, origin uint, write func([]T, uint)) *node[T] {
+	if level == 0 {
+		ret := *n.items
+		write(ret[start-nodeStart:stop-nodeStart], start-origin)
+		return newLeaf(&ret)
+	}
+
+	ret := *n.children
+	for i := start; i < stop; {
+		subidx := (i >> level) & shiftBitMask
+		childStart := nodeStart + subidx<<level
+		childStop := min(stop, childStart+1<<level)
+		ret[subidx] = writeRangeInNode(level-shiftSize, ret[subidx], childStart, i, childStop, origin, write)
+		i = childStop
+	}
+
+	return newBranch(&ret)
+}
+
+// Remove returns a new vector with the element at position i removed.
+func (v *Vector[T]) Remove(i int) *Vector[T] {
+	if i < 0 || uint(i) >= v.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: v.Len(), Type: "Vector"})
+	}
+
+	return v.RemoveRange(i, i+1)
+}
+
+// RemoveRange returns a new vector with all elements [start,stop) removed. The nodes holding
+// the elements before start are shared with v, so removing elements near the end is cheap.
+func (v *Vector[T]) RemoveRange(start, stop int) *Vector[T] {
+	assertSliceOk(start, stop, v.Len())
+	if start == stop {
+		return v
+	}
+
+	// The nodes holding the elements before start are shared with v
+	return v.Shrink(v.Len()-start).appendRange(v, uint(stop), v.len)
+}
+
+// Splice returns a new vector where the elements [start,stop) of v have been replaced by
+// items. The nodes holding the elements before start are shared with v. Full leaves after
+// stop are shared when they line up with the leaves of the result, for example when the
+// number of items equals the number of replaced elements.
+func (v *Vector[T]) Splice(start, stop int, items ...T) *Vector[T] {
+	assertSliceOk(start, stop, v.Len())
+	if stop-start == len(items) {
+		if len(items) == 0 {
+			return v
+		}
+
+		t := v.Transient()
+		for i, item := range items {
+			t.Set(start+i, item)
+		}
+
+		return t.Persistent()
+	}
+
+	return v.Shrink(v.Len()-start).Append(items...).appendRange(v, uint(stop), v.len)
+}
+
+// Pop returns the last element of v and a new vector with that element removed.
+func (v *Vector[T]) Pop() (T, *Vector[T]) {
+	if v.len == 0 {
+		panic("Pop from empty vector")
+	}
+
+	return v.tail[len(v.tail)-1], v.Shrink(1)
+}
+
+// Shrink returns a new vector with the last n elements of v removed. It panics with the same
+// ErrSliceOutOfBounds as Slice(0, v.Len()-n) if n is negative or greater than v.Len().
+func (v *Vector[T]) Shrink(n int) *Vector[T] {
+	if n < 0 || uint(n) > v.len {
+		panic(ErrSliceOutOfBounds{Start: 0, Stop: v.Len() - n, Len: v.Len()})
+	}
+
+	newLen := v.len - uint(n)
+	if newLen == v.len {
+		return v
+	}
+
+	if newLen == 0 {
+		return NewVector[T]()
+	}
+
+	tailOffset := v.tailOffset()
+	if newLen > tailOffset {
+		// Tails are never modified in place so it's safe to share it
+		return &Vector[T]{root: v.root, tail: v.tail[:newLen-tailOffset], len: newLen, shift: v.shift}
+	}
+
+	newTail := v.sliceFor(newLen - 1)[:((newLen-1)&shiftBitMask)+1]
+	result := &Vector[T]{tail: newTail, len: newLen, shift: shiftSize}
+	if treeLen := result.tailOffset(); treeLen > 0 {
+		result.root = trimNode(v.shift, v.root, treeLen)
+		result.shift = v.shift
+		for result.shift > shiftSize && result.root.childCount() == 1 {
+			result.root = result.root.children[0]
+			result.shift -= shiftSize
+		}
+	}
+
+	return result
+}
+
+// trimNode returns a copy of node at level containing only the first count elements.
+// count must be a non-zero multiple of the node size.
+func trimNode[T any](level uint, n *node[T], count uint) *node[T] {
+	if level == 0 {
+		return n
+	}
+
+	last := ((count - 1) >> level) & shiftBitMask
+	ret := new([nodeSize]*node[T])
+	copy(ret[:last+1], n.children[:])
+	ret[last] = trimNode(level-shiftSize, ret[last], count-(last<<level))
+	return newBranch(ret)
+}
+
+// First returns the first element of v. ok is set to false if v is empty.
+func (v *Vector[T]) First() (item T, ok bool) {
+	if v.len == 0 {
+		return item, false
+	}
+
+	return v.sliceFor(0)[0], true
+}
+
+// Last returns the last element of v. ok is set to false if v is empty. The last element is
+// always in the tail so this is O(1).
+func (v *Vector[T]) Last() (item T, ok bool) {
+	if v.len == 0 {
+		return item, false
+	}
+
+	return v.tail[len(v.tail)-1], true
+}
+
+// Head returns a slice of the first n elements of v, or of all elements of v if it has fewer
+// than n elements.
+func (v *Vector[T]) Head(n int) *VectorSlice[T] {
+	assertSliceOk(0, n, math.MaxInt)
+	return v.Slice(0, min(n, v.Len()))
+}
+
+// Tail returns a slice of the last n elements of v, or of all elements of v if it has fewer
+// than n elements.
+func (v *Vector[T]) Tail(n int) *VectorSlice[T] {
+	assertSliceOk(0, n, math.MaxInt)
+	return v.SliceFrom(v.Len() - min(n, v.Len()))
+}
+
+// Take returns a new vector containing the first n elements of v, or all elements of v if it
+// has fewer than n elements.
+func (v *Vector[T]) Take(n int) *Vector[T] {
+	assertSliceOk(0, n, math.MaxInt)
+	return v.Shrink(v.Len() - min(n, v.Len()))
+}
+
+// Drop returns a new vector containing all but the first n elements of v, or an empty vector
+// if v has fewer than n elements.
+func (v *Vector[T]) Drop(n int) *Vector[T] {
+	assertSliceOk(0, n, math.MaxInt)
+	return v.RemoveRange(0, min(n, v.Len()))
+}
+
+// Split returns two new vectors, the first containing the elements of v before position i
+// and the second containing the elements from position i. Unlike a VectorSlice the results
+// only refer to the nodes of v holding their own elements, the rest of v can be garbage
+// collected, and both can be appended to independently. The nodes before i are shared with
+// v, full leaves after i are shared when i is a multiple of the node size.
+func (v *Vector[T]) Split(i int) (*Vector[T], *Vector[T]) {
+	assertSliceOk(i, i, v.Len())
+	return v.Shrink(v.Len() - i), NewVector[T]().appendRange(v, uint(i), v.len)
+}
+
+// TakeWhile returns a new vector containing the longest prefix of v for which all elements
+// satisfy pred.
+func (v *Vector[T]) TakeWhile(pred func(T) bool) *Vector[T] {
+	return v.Take(v.prefixLen(pred))
+}
+
+// DropWhile returns a new vector containing the elements of v that remain after removing the
+// longest prefix for which all elements satisfy pred.
+func (v *Vector[T]) DropWhile(pred func(T) bool) *Vector[T] {
+	return v.Drop(v.prefixLen(pred))
+}
+
+func (v *Vector[T]) prefixLen(pred func(T) bool) int {
+	if i := v.FindIndex(func(item T) bool { return !pred(item) }); i >= 0 {
+		return i
+	}
+
+	return v.Len()
 }
 
 // Range calls f repeatedly passing it each element in v in order as argument until either
@@ -190,37 +939,331 @@ func (v *Vector[T]) Range(f func(T) bool) {
 	}
 }
 
+// RangeReverse calls f repeatedly passing it each element in v in reverse order as argument
+// until either all elements have been visited or f returns false.
+func (v *Vector[T]) RangeReverse(f func(T) bool) {
+	rangeReverse(v, 0, v.len, f)
+}
+
+// rangeReverse calls f with the elements [start,stop) of v in reverse order until f returns
+// false. The leaf holding the elements is looked up once per leaf.
+func rangeReverse[T any](v *Vector[T], start, stop uint, f func(T) bool) {
+	var currentNode []T
+	for i := stop; i > start; i-- {
+		if i == stop || i&shiftBitMask == 0 {
+			currentNode = v.sliceFor(i - 1)
+		}
+
+		if !f(currentNode[(i-1)&shiftBitMask]) {
+			return
+		}
+	}
+}
+
+// RangeIndexed calls f repeatedly passing it the index and value of each element in v in
+// order as arguments until either all elements have been visited or f returns false.
+func (v *Vector[T]) RangeIndexed(f func(int, T) bool) {
+	for i := uint(0); i < v.len; i += nodeSize {
+		for j, item := range v.sliceFor(i) {
+			if !f(int(i)+j, item) {
+				return
+			}
+		}
+	}
+}
+
+// RangeChunks calls f repeatedly passing it the leaf slices of v, holding up to 32 elements
+// each, in order until either all elements have been visited or f returns false. The slices
+// are shared with v and must not be modified.
+func (v *Vector[T]) RangeChunks(f func([]T) bool) {
+	for i := uint(0); i < v.len; i += nodeSize {
+		if !f(slices.Clip(v.sliceFor(i))) {
+			return
+		}
+	}
+}
+
+// FindIndex returns the index of the first element in v for which pred returns true, or -1
+// if there is no such element.
+func (v *Vector[T]) FindIndex(pred func(T) bool) int {
+	for i := uint(0); i < v.len; i += nodeSize {
+		for j, item := range v.sliceFor(i) {
+			if pred(item) {
+				return int(i) + j
+			}
+		}
+	}
+
+	return -1
+}
+
+// Find returns the first element in v for which pred returns true. ok is set to false if
+// there is no such element.
+func (v *Vector[T]) Find(pred func(T) bool) (item T, ok bool) {
+	if i := v.FindIndex(pred); i >= 0 {
+		return v.Get(i), true
+	}
+
+	return item, false
+}
+
+// SortFunc returns a new vector containing the elements of v sorted by less. The sort is
+// stable, equal elements keep their relative order.
+func (v *Vector[T]) SortFunc(less func(a, b T) bool) *Vector[T] {
+	items := v.ToNativeSlice()
+	sort.SliceStable(items, func(i, j int) bool { return less(items[i], items[j]) })
+	return NewVector(items...)
+}
+
+// IsSortedFunc reports whether v is sorted according to less, that is whether no element is
+// less than the element before it.
+func (v *Vector[T]) IsSortedFunc(less func(a, b T) bool) bool {
+	sorted, first := true, true
+	var prev T
+	v.RangeChunks(func(chunk []T) bool {
+		for _, item := range chunk {
+			if !first && less(item, prev) {
+				sorted = false
+				return false
+			}
+
+			prev, first = item, false
+		}
+
+		return true
+	})
+
+	return sorted
+}
+
+// Shuffle returns a new vector containing the elements of v in a random order determined by r.
+// Using a rand.Rand with a fixed seed gives reproducible results.
+func (v *Vector[T]) Shuffle(r *rand.Rand) *Vector[T] {
+	items := v.ToNativeSlice()
+	r.Shuffle(len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+	return NewVector(items...)
+}
+
+// DedupFunc returns a new vector where each run of consecutive elements considered equal by eq
+// is replaced by the first element of the run.
+func (v *Vector[T]) DedupFunc(eq func(a, b T) bool) *Vector[T] {
+	var b leafBuilder[T]
+	var last T
+	for i := uint(0); i < v.len; i += nodeSize {
+		for j, item := range v.sliceFor(i) {
+			if (i == 0 && j == 0) || !eq(last, item) {
+				b.add(item)
+				last = item
+			}
+		}
+	}
+
+	return b.vector()
+}
+
+// All returns an iterator over the indexes and elements of v in order.
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var currentNode []T
+		for i := uint(0); i < v.len; i++ {
+			if i&shiftBitMask == 0 {
+				currentNode = v.sliceFor(i)
+			}
+
+			if !yield(int(i), currentNode[i&shiftBitMask]) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the indexes and elements of v in reverse order.
+func (v *Vector[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		var currentNode []T
+		for i := v.len; i > 0; i-- {
+			if i == v.len || i&shiftBitMask == 0 {
+				currentNode = v.sliceFor(i - 1)
+			}
+
+			if !yield(int(i-1), currentNode[(i-1)&shiftBitMask]) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over the elements of v in order.
+func (v *Vector[T]) Values() iter.Seq[T] {
+	return v.Range
+}
+
+// EqualFunc reports whether v and other contain the same elements in the same order, using
+// eq to compare elements. Subtrees shared between v and other are not compared element
+// by element.
+func (v *Vector[T]) EqualFunc(other *Vector[T], eq func(T, T) bool) bool {
+	return vectorsEqual(v, other, eq, false)
+}
+
+// VectorEqual reports whether a and b contain the same elements in the same order. Besides
+// shared subtrees, subtrees whose content hashes have already been computed by ContentHash
+// are not compared element by element if their hashes are equal. Elements whose encodings,
+// see Sum64, are identical are then considered equal, so that vectors of floats containing
+// NaN may compare equal.
+func VectorEqual[T comparable](a, b *Vector[T]) bool {
+	return vectorsEqual(a, b, func(x, y T) bool { return x == y }, true)
+}
+
+// vectorsEqual compares v and other, see sameContent for useHashes.
+func vectorsEqual[T any](v, other *Vector[T], eq func(T, T) bool, useHashes bool) bool {
+	if v == other {
+		return true
+	}
+
+	if v.len != other.len {
+		return false
+	}
+
+	if v.shift == other.shift {
+		if !nodesEqual(v.shift, v.root, other.root, eq, useHashes) {
+			return false
+		}
+	} else {
+		for i := uint(0); i < v.tailOffset(); i += nodeSize {
+			if !leavesEqual(v.sliceFor(i), other.sliceFor(i), eq) {
+				return false
+			}
+		}
+	}
+
+	return leavesEqual(v.tail, other.tail, eq)
+}
+
+// sameContent reports whether a and b are known to hold the same elements without comparing
+// them, either because they are the same node or, if useHashes is set, because both have
+// equal cached content hashes. Hashes must only be used when elements are compared with ==
+// since the content hash does not know about other notions of equality.
+func sameContent[T any](a, b *node[T], useHashes bool) bool {
+	if a == b {
+		return true
+	}
+
+	if !useHashes {
+		return false
+	}
+
+	h := a.hash.Load()
+	return h != 0 && h == b.hash.Load()
+}
+
+func nodesEqual[T any](level uint, a, b *node[T], eq func(T, T) bool, useHashes bool) bool {
+	if sameContent(a, b, useHashes) {
+		return true
+	}
+
+	if level == 0 {
+		return leavesEqual(a.items[:], b.items[:], eq)
+	}
+
+	// Both trees contain the same number of elements so the children holding elements are
+	// the same in both nodes.
+	if a.children == b.children {
+		return true
+	}
+
+	aNodes, bNodes := a.children, b.children
+	for i := 0; i < nodeSize && aNodes[i] != nil && bNodes[i] != nil; i++ {
+		if !nodesEqual(level-shiftSize, aNodes[i], bNodes[i], eq, useHashes) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func leavesEqual[T any](a, b []T, eq func(T, T) bool) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	if len(a) == 0 || &a[0] == &b[0] {
+		return true
+	}
+
+	for i := range a {
+		if !eq(a[i], b[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Slice returns a VectorSlice that refers to all elements [start,stop) in v.
 func (v *Vector[T]) Slice(start, stop int) *VectorSlice[T] {
 	assertSliceOk(start, stop, v.Len())
 	return &VectorSlice[T]{vector: v, start: start, stop: stop}
 }
 
+// TrySlice returns a VectorSlice that refers to all elements [start,stop) in v. ok is set to
+// false, and the result is nil, if the indexes are out of bounds.
+func (v *Vector[T]) TrySlice(start, stop int) (result *VectorSlice[T], ok bool) {
+	if !sliceOk(start, stop, v.Len()) {
+		return nil, false
+	}
+
+	return v.Slice(start, stop), true
+}
+
+// SliceFrom returns a VectorSlice that refers to all elements from start to the end of v. A
+// negative start is counted from the end of v, SliceFrom(-3) refers to the last three elements.
+func (v *Vector[T]) SliceFrom(start int) *VectorSlice[T] {
+	return v.Slice(resolveIndex(start, v.Len()), v.Len())
+}
+
+// SliceTo returns a VectorSlice that refers to all elements before stop in v. A negative stop
+// is counted from the end of v, SliceTo(-1) refers to all but the last element.
+func (v *Vector[T]) SliceTo(stop int) *VectorSlice[T] {
+	return v.Slice(0, resolveIndex(stop, v.Len()))
+}
+
 // ToNativeSlice returns a Go slice containing all elements of v
 func (v *Vector[T]) ToNativeSlice() []T {
-	result := make([]T, 0, v.len)
+	return v.AppendToSlice(make([]T, 0, v.len))
+}
+
+// AppendToSlice appends all elements of v to dst and returns the extended slice. Passing a
+// dst with sufficient capacity avoids allocation.
+func (v *Vector[T]) AppendToSlice(dst []T) []T {
+	dst = slices.Grow(dst, int(v.len))
 	for i := uint(0); i < v.len; i += nodeSize {
-		result = append(result, v.sliceFor(i)...)
+		dst = append(dst, v.sliceFor(i)...)
 	}
 
-	return result
+	return dst
 }
 
 ////////////////
 //// Slice /////
 ////////////////
 
-func assertSliceOk(start, stop, len int) {
-	if start < 0 {
-		panic(fmt.Sprintf("Invalid slice index %d (index must be non-negative)", start))
+// resolveIndex returns i, or i counted from the end if i is negative. Indexes that remain
+// negative are returned as is to be reported by the bounds checks.
+func resolveIndex(i, len int) int {
+	if i < 0 && i+len >= 0 {
+		return i + len
 	}
 
-	if start > stop {
-		panic(fmt.Sprintf("Invalid slice index: %d > %d", start, stop))
-	}
+	return i
+}
+
+func sliceOk(start, stop, len int) bool {
+	return start >= 0 && start <= stop && stop <= len
+}
 
-	if stop > len {
-		panic(fmt.Sprintf("Slice bounds out of range, start=%d, stop=%d, len=%d", start, stop, len))
+func assertSliceOk(start, stop, len int) {
+	if !sliceOk(start, stop, len) {
+		panic(ErrSliceOutOfBounds{Start: start, Stop: stop, Len: len})
 	}
 }
 
@@ -243,7 +1286,7 @@ func (s *VectorSlice[T]) Len() int {
 // Get returns the element at position i.
 func (s *VectorSlice[T]) Get(i int) T {
 	if i < 0 || s.start+i >= s.stop {
-		panic("Index out of bounds")
+		panic(ErrIndexOutOfBounds{Index: i, Len: s.Len(), Type: "VectorSlice"})
 	}
 
 	return s.vector.Get(s.start + i)
@@ -252,26 +1295,76 @@ func (s *VectorSlice[T]) Get(i int) T {
 // Set returns a new slice with the element at position i set to item.
 func (s *VectorSlice[T]) Set(i int, item T) *VectorSlice[T] {
 	if i < 0 || s.start+i >= s.stop {
-		panic("Index out of bounds")
+		panic(ErrIndexOutOfBounds{Index: i, Len: s.Len(), Type: "VectorSlice"})
 	}
 
 	return s.vector.Set(s.start+i, item).Slice(s.start, s.stop)
 }
 
+// TryGet returns the element at position i. ok is set to false if i is out of bounds.
+func (s *VectorSlice[T]) TryGet(i int) (item T, ok bool) {
+	if i < 0 || s.start+i >= s.stop {
+		return item, false
+	}
+
+	return s.vector.Get(s.start + i), true
+}
+
+// TrySet returns a new slice with the element at position i set to item. ok is set to false,
+// and the result is nil, if i is out of bounds.
+func (s *VectorSlice[T]) TrySet(i int, item T) (result *VectorSlice[T], ok bool) {
+	if i < 0 || s.start+i >= s.stop {
+		return nil, false
+	}
+
+	return s.Set(i, item), true
+}
+
+// Fill returns a new slice with all elements set to value. The elements are written a leaf
+// at a time and each affected node is copied only once.
+func (s *VectorSlice[T]) Fill(value T) *VectorSlice[T] {
+	v := s.vector.writeRange(uint(s.start), uint(s.stop), func(dst []T, _ uint) {
+		for i := range dst {
+			dst[i] = value
+		}
+	})
+
+	return &VectorSlice[T]{vector: v, start: s.start, stop: s.stop}
+}
+
+// SetRange returns a new slice with the elements starting at position i set to items. The
+// elements are copied a leaf at a time and each affected node is copied only once. SetRange
+// panics if the items do not fit within the slice.
+func (s *VectorSlice[T]) SetRange(i int, items ...T) *VectorSlice[T] {
+	assertSliceOk(i, i+len(items), s.Len())
+	start := uint(s.start + i)
+	v := s.vector.writeRange(start, start+uint(len(items)), func(dst []T, offset uint) {
+		copy(dst, items[offset:])
+	})
+
+	return &VectorSlice[T]{vector: v, start: s.start, stop: s.stop}
+}
+
 // Append returns a new slice with item(s) appended to it.
 func (s *VectorSlice[T]) Append(items ...T) *VectorSlice[T] {
 	newSlice := VectorSlice[T]{vector: s.vector, start: s.start, stop: s.stop + len(items)}
 
 	// If this is v slice that has an upper bound that is lower than the backing
 	// vector then set the values in the backing vector to achieve some structural
-	// sharing.
-	itemPos := 0
-	for ; s.stop+itemPos < s.vector.Len() && itemPos < len(items); itemPos++ {
-		newSlice.vector = newSlice.vector.Set(s.stop+itemPos, items[itemPos])
+	// sharing. The values are set in one batch so that each affected node is only
+	// copied once.
+	overlap := min(s.vector.Len()-s.stop, len(items))
+	if overlap > 0 {
+		updates := make(map[int]T, overlap)
+		for i, item := range items[:overlap] {
+			updates[s.stop+i] = item
+		}
+
+		newSlice.vector = newSlice.vector.SetMany(updates)
 	}
 
 	// For the rest just append it to the underlying vector
-	newSlice.vector = newSlice.vector.Append(items[itemPos:]...)
+	newSlice.vector = newSlice.vector.Append(items[overlap:]...)
 	return &newSlice
 }
 
@@ -281,6 +1374,28 @@ func (s *VectorSlice[T]) Slice(start, stop int) *VectorSlice[T] {
 	return &VectorSlice[T]{vector: s.vector, start: s.start + start, stop: s.start + stop}
 }
 
+// TrySlice returns a VectorSlice that refers to all elements [start,stop) in s. ok is set to
+// false, and the result is nil, if the indexes are out of bounds.
+func (s *VectorSlice[T]) TrySlice(start, stop int) (result *VectorSlice[T], ok bool) {
+	if !sliceOk(start, stop, s.Len()) {
+		return nil, false
+	}
+
+	return s.Slice(start, stop), true
+}
+
+// SliceFrom returns a VectorSlice that refers to all elements from start to the end of s. A
+// negative start is counted from the end of s.
+func (s *VectorSlice[T]) SliceFrom(start int) *VectorSlice[T] {
+	return s.Slice(resolveIndex(start, s.Len()), s.Len())
+}
+
+// SliceTo returns a VectorSlice that refers to all elements before stop in s. A negative stop
+// is counted from the end of s.
+func (s *VectorSlice[T]) SliceTo(stop int) *VectorSlice[T] {
+	return s.Slice(0, resolveIndex(stop, s.Len()))
+}
+
 // Range calls f repeatedly passing it each element in s in order as argument until either
 // all elements have been visited or f returns false.
 func (s *VectorSlice[T]) Range(f func(T) bool) {
@@ -295,3 +1410,65 @@ func (s *VectorSlice[T]) Range(f func(T) bool) {
 		}
 	}
 }
+
+// RangeReverse calls f repeatedly passing it each element in s in reverse order as argument
+// until either all elements have been visited or f returns false.
+func (s *VectorSlice[T]) RangeReverse(f func(T) bool) {
+	rangeReverse(s.vector, uint(s.start), uint(s.stop), f)
+}
+
+// RangeIndexed calls f repeatedly passing it the index, relative to the start of s, and value
+// of each element in s in order as arguments until either all elements have been visited or
+// f returns false.
+func (s *VectorSlice[T]) RangeIndexed(f func(int, T) bool) {
+	i := 0
+	s.Range(func(item T) bool {
+		ok := f(i, item)
+		i++
+		return ok
+	})
+}
+
+// RangeChunks calls f repeatedly passing it consecutive slices of the elements in s, in order,
+// until either all elements have been visited or f returns false. The slices are shared with
+// the backing vector and must not be modified.
+func (s *VectorSlice[T]) RangeChunks(f func([]T) bool) {
+	for i := uint(s.start); i < uint(s.stop); {
+		leaf := s.vector.sliceFor(i)
+		offset := i & shiftBitMask
+		end := uintMin(uint(len(leaf)), offset+uint(s.stop)-i)
+		if !f(slices.Clip(leaf[offset:end])) {
+			return
+		}
+
+		i += end - offset
+	}
+}
+
+// AppendToSlice appends all elements of s to dst and returns the extended slice. Passing a
+// dst with sufficient capacity avoids allocation.
+func (s *VectorSlice[T]) AppendToSlice(dst []T) []T {
+	dst = slices.Grow(dst, s.Len())
+	s.RangeChunks(func(chunk []T) bool {
+		dst = append(dst, chunk...)
+		return true
+	})
+
+	return dst
+}
+
+// Materialize returns a new vector containing copies of the elements in s. Unlike s the
+// result does not refer to the backing vector, which can be garbage collected if no longer
+// used elsewhere.
+func (s *VectorSlice[T]) Materialize() *Vector[T] {
+	var b leafBuilder[T]
+	s.RangeChunks(func(chunk []T) bool {
+		for _, item := range chunk {
+			b.add(item)
+		}
+
+		return true
+	})
+
+	return b.vector()
+}
@@ -1,9 +1,13 @@
 package peds
 
 import (
+	"errors"
 	"fmt"
+	"math/rand"
 	"runtime"
+	"slices"
 	"strings"
+	"sync"
 	"testing"
 )
 
@@ -52,7 +56,7 @@ func assertPanic(t *testing.T, expectedMsg string) {
 		_, _, line, _ := runtime.Caller(1)
 		t.Errorf("Did not raise, line %d.", line)
 	} else {
-		msg := r.(string)
+		msg := fmt.Sprint(r)
 		if !strings.Contains(msg, expectedMsg) {
 			t.Errorf("Msg '%s', did not contain '%s'", msg, expectedMsg)
 		}
@@ -86,6 +90,22 @@ func TestPropertiesOfNewVector(t *testing.T) {
 	}
 }
 
+func TestNewVectorHasSameLayoutAsAppendedVector(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("NewVector %d", l), func(t *testing.T) {
+			bulk := NewVector(inputSlice(0, l)...)
+			appended := NewVector[int]()
+			for i := 0; i < l; i++ {
+				appended = appended.Append(i)
+			}
+
+			assertEqual(t, int(appended.shift), int(bulk.shift))
+			assertEqual(t, len(appended.tail), len(bulk.tail))
+			assertEqualBool(t, true, VectorEqual(appended, bulk))
+		})
+	}
+}
+
 func TestSetItem(t *testing.T) {
 	for _, l := range testSizes {
 		t.Run(fmt.Sprintf("Set %d", l), func(t *testing.T) {
@@ -99,6 +119,53 @@ func TestSetItem(t *testing.T) {
 	}
 }
 
+func TestUpdateItem(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Update %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			for i := 0; i < l; i += 1 + l/50 {
+				newVec := vec.Update(i, func(item int) int { return item * 10 })
+				assertEqual(t, i*10, newVec.Get(i))
+				assertEqual(t, i, vec.Get(i))
+			}
+		})
+	}
+}
+
+func TestUpdateOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewVector(1, 2, 3).Update(-1, func(i int) int { return i })
+}
+
+func TestSetMany(t *testing.T) {
+	for _, l := range testSizes[1:] {
+		t.Run(fmt.Sprintf("SetMany %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			updates := map[int]int{0: -1, l - 1: -2, l / 2: -3, l / 3: -4}
+			result := vec.SetMany(updates)
+			for i := 0; i < l; i++ {
+				expected, ok := updates[i]
+				if !ok {
+					expected = i
+				}
+
+				assertEqual(t, expected, result.Get(i))
+				assertEqual(t, i, vec.Get(i))
+			}
+		})
+	}
+}
+
+func TestSetManyEmpty(t *testing.T) {
+	vec := NewVector(1, 2, 3)
+	assertEqualBool(t, true, vec == vec.SetMany(nil))
+}
+
+func TestSetManyOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewVector(1, 2, 3).SetMany(map[int]int{1: 1, 3: 3})
+}
+
 func TestAppend(t *testing.T) {
 	for _, l := range testSizes {
 		vec := NewVector(inputSlice(0, l)...)
@@ -117,6 +184,494 @@ func TestAppend(t *testing.T) {
 	}
 }
 
+func TestConcat(t *testing.T) {
+	sizes := []int{0, 1, 31, 32, 33, 64, 500, 32*32 + 1}
+	for _, l1 := range sizes {
+		for _, l2 := range sizes {
+			t.Run(fmt.Sprintf("Concat %d %d", l1, l2), func(t *testing.T) {
+				v1 := NewVector(inputSlice(0, l1)...)
+				v2 := NewVector(inputSlice(l1, l2)...)
+				v3 := v1.Concat(v2)
+				assertEqual(t, l1+l2, v3.Len())
+				for i := 0; i < l1+l2; i++ {
+					assertEqual(t, i, v3.Get(i))
+				}
+
+				// Appending to the result does not affect the inputs
+				v3.Append(-1).Set(l1+l2, -2)
+				assertEqual(t, l1, v1.Len())
+				assertEqual(t, l2, v2.Len())
+				for i := 0; i < l2; i++ {
+					assertEqual(t, l1+i, v2.Get(i))
+				}
+			})
+		}
+	}
+}
+
+func TestRemoveRange(t *testing.T) {
+	l := 32*32 + 70
+	vec := NewVector(inputSlice(0, l)...)
+	ranges := []struct{ start, stop int }{{0, 0}, {0, 1}, {0, 32}, {5, 40}, {32, 64}, {100, 1000}, {l - 1, l}, {0, l}}
+	for _, r := range ranges {
+		t.Run(fmt.Sprintf("RemoveRange %d %d", r.start, r.stop), func(t *testing.T) {
+			result := vec.RemoveRange(r.start, r.stop)
+			expected := append(inputSlice(0, r.start), inputSlice(r.stop, l-r.stop)...)
+			assertEqual(t, len(expected), result.Len())
+			for i, e := range expected {
+				assertEqual(t, e, result.Get(i))
+			}
+
+			assertEqual(t, l, vec.Len())
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100)...)
+	for _, i := range []int{0, 31, 32, 99} {
+		result := vec.Remove(i)
+		assertEqual(t, 99, result.Len())
+		for j := 0; j < 99; j++ {
+			expected := j
+			if j >= i {
+				expected++
+			}
+
+			assertEqual(t, expected, result.Get(j))
+		}
+	}
+}
+
+func TestRemoveNearEndSharesPrefix(t *testing.T) {
+	l := 100000
+	vec := NewVector(inputSlice(0, l)...)
+	for _, r := range []struct{ start, stop int }{{l - 1, l}, {l - 1000, l - 10}} {
+		result := vec.RemoveRange(r.start, r.stop)
+		expected := append(inputSlice(0, r.start), inputSlice(r.stop, l-r.stop)...)
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), result))
+
+		// Only the nodes on the path to the last leaf of the prefix, and the tail, are new
+		vecNodes, _ := SharedNodes(vec, vec)
+		_, total := SharedNodes(vec, result)
+		assertEqualBool(t, true, total-vecNodes < 10)
+	}
+}
+
+func TestRemoveOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewVector(1, 2, 3).Remove(3)
+}
+
+func TestRemoveRangeOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Slice bounds out of range")
+	NewVector(1, 2, 3).RemoveRange(1, 4)
+}
+
+func TestSplice(t *testing.T) {
+	for _, size := range testSizes {
+		input := inputSlice(0, size)
+		vec := NewVector(input...)
+		for _, r := range []struct{ start, stop int }{{0, 0}, {0, size}, {size / 3, size / 2}, {size / 2, size}, {size, size}} {
+			for _, count := range []int{0, 1, r.stop - r.start, 40} {
+				items := inputSlice(-1000, count)
+				expected := slices.Replace(slices.Clone(input), r.start, r.stop, items...)
+				result := vec.Splice(r.start, r.stop, items...)
+				assertEqualBool(t, true, VectorEqual(NewVector(expected...), result))
+			}
+		}
+
+		assertEqualBool(t, true, VectorEqual(NewVector(input...), vec))
+	}
+}
+
+func TestSpliceSharesNodes(t *testing.T) {
+	vec := NewVector(inputSlice(0, 2048)...)
+	result := vec.Splice(100, 102, -1, -2)
+	assertEqualBool(t, true, result.root.children[1] == vec.root.children[1])
+	assertEqualBool(t, true, vec.Splice(5, 5) == vec)
+
+	result = vec.Splice(100, 164, inputSlice(0, 32)...)
+	assertEqual(t, 2048-32, result.Len())
+	assertEqualBool(t, true, result.root.children[0].children[0] == vec.root.children[0].children[0])
+}
+
+func TestSpliceOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Slice bounds out of range")
+	NewVector(1, 2, 3).Splice(2, 4, 1)
+}
+
+func TestPop(t *testing.T) {
+	for _, l := range testSizes[1:] {
+		t.Run(fmt.Sprintf("Pop %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			for i := l - 1; i >= 0; i-- {
+				var item int
+				item, vec = vec.Pop()
+				assertEqual(t, i, item)
+				assertEqual(t, i, vec.Len())
+			}
+		})
+	}
+}
+
+func TestShrink(t *testing.T) {
+	for _, l := range testSizes {
+		vec := NewVector(inputSlice(0, l)...)
+		for _, n := range []int{0, 1, 31, 32, 33, 1025, l} {
+			if n > l {
+				continue
+			}
+
+			t.Run(fmt.Sprintf("Shrink %d by %d", l, n), func(t *testing.T) {
+				result := vec.Shrink(n)
+				assertEqual(t, l-n, result.Len())
+				for i := 0; i < l-n; i++ {
+					assertEqual(t, i, result.Get(i))
+				}
+
+				// The shrunk vector can be grown again without affecting the original
+				result = result.Append(inputSlice(l-n, n+40)...)
+				for i := 0; i < l+40; i++ {
+					assertEqual(t, i, result.Get(i))
+				}
+
+				assertEqual(t, l, vec.Len())
+			})
+		}
+	}
+}
+
+func TestPopEmpty(t *testing.T) {
+	defer assertPanic(t, "Pop from empty vector")
+	NewVector[int]().Pop()
+}
+
+func TestShrinkOutOfRange(t *testing.T) {
+	err := recoverError(func() { NewVector(1, 2, 3).Shrink(-1) })
+	var sliceErr ErrSliceOutOfBounds
+	assertEqualBool(t, true, errors.As(err, &sliceErr))
+	assertEqualBool(t, true, sliceErr == ErrSliceOutOfBounds{Start: 0, Stop: 4, Len: 3})
+
+	defer assertPanic(t, "Invalid slice index: 0 > -1")
+	NewVector(1, 2, 3).Shrink(4)
+}
+
+func TestTakeAndDrop(t *testing.T) {
+	l := 32*32 + 70
+	vec := NewVector(inputSlice(0, l)...)
+	for _, n := range []int{0, 1, 32, 33, 1024, l, l + 1} {
+		t.Run(fmt.Sprintf("Take/Drop %d", n), func(t *testing.T) {
+			taken, dropped := vec.Take(n), vec.Drop(n)
+			takenLen := min(n, l)
+			assertEqual(t, takenLen, taken.Len())
+			assertEqual(t, l-takenLen, dropped.Len())
+			assertEqualBool(t, true, VectorEqual(vec, taken.Concat(dropped)))
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	l := 32*32 + 70
+	vec := NewVector(inputSlice(0, l)...)
+	for _, i := range []int{0, 1, 31, 32, 33, 1024, 1030, l} {
+		t.Run(fmt.Sprintf("Split %d", i), func(t *testing.T) {
+			left, right := vec.Split(i)
+			assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, i)...), left))
+			assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(i, l-i)...), right))
+
+			// Both halves can be appended to without affecting each other or the original
+			left2, right2 := left.Append(-1), right.Append(-2)
+			assertEqual(t, -1, left2.Get(i))
+			assertEqual(t, -2, right2.Get(l-i))
+			assertEqual(t, i, left.Len())
+			assertEqual(t, l-i, right.Len())
+			assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, l)...), vec))
+		})
+	}
+}
+
+func TestSplitOutOfRange(t *testing.T) {
+	defer assertPanic(t, "Slice bounds out of range")
+	NewVector(1, 2, 3).Split(4)
+}
+
+func TestTakeAndDropWhile(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100)...)
+	below := func(n int) func(int) bool {
+		return func(i int) bool { return i < n }
+	}
+
+	assertEqual(t, 40, vec.TakeWhile(below(40)).Len())
+	assertEqual(t, 60, vec.DropWhile(below(40)).Len())
+	assertEqual(t, 40, vec.DropWhile(below(40)).Get(0))
+	assertEqual(t, 100, vec.TakeWhile(below(1000)).Len())
+	assertEqual(t, 0, vec.DropWhile(below(1000)).Len())
+	assertEqual(t, 0, vec.TakeWhile(below(0)).Len())
+}
+
+func TestTakeNegative(t *testing.T) {
+	defer assertPanic(t, "Invalid slice index")
+	NewVector(1, 2, 3).Take(-1)
+}
+
+func TestShuffle(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Shuffle %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			shuffled := vec.Shuffle(rand.New(rand.NewSource(42)))
+			assertEqual(t, l, shuffled.Len())
+			assertEqualBool(t, true, VectorEqual(vec, Sort(shuffled)))
+			assertEqualBool(t, true, VectorEqual(shuffled, vec.Shuffle(rand.New(rand.NewSource(42)))))
+		})
+	}
+}
+
+func TestAdoptSlice(t *testing.T) {
+	for _, size := range testSizes {
+		items := inputSlice(0, size)
+		v := AdoptSlice(items)
+		assertEqual(t, size, v.Len())
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), v))
+
+		// Derived vectors never write to the adopted slice
+		v2 := v.Append(-1).Append(-2)
+		if size > 0 {
+			v2 = v2.Set(0, -3).Set(size-1, -4)
+		}
+
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), AdoptSlice(items)))
+		assertEqual(t, size+2, v2.Len())
+	}
+}
+
+func TestAdoptSliceDoesNotCopy(t *testing.T) {
+	items := inputSlice(0, 100)
+	allocs := testing.AllocsPerRun(10, func() { AdoptSlice(items) })
+
+	// The vector, the leaf nodes, the slice of leaf nodes and the branch nodes
+	assertEqualBool(t, true, allocs <= 8)
+	v := AdoptSlice(items)
+	items[0], items[99] = -1, -2
+	assertEqual(t, -1, v.Get(0))
+	assertEqual(t, -2, v.Get(99))
+}
+
+func TestNewVectorFunc(t *testing.T) {
+	for _, size := range testSizes {
+		var calls []int
+		v := NewVectorFunc(size, func(i int) int {
+			calls = append(calls, i)
+			return i
+		})
+
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), v))
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), NewVector(calls...)))
+
+		// The vector can be extended like any other
+		v2 := v.Append(-1)
+		assertEqual(t, -1, v2.Get(size))
+		assertEqual(t, size, v.Len())
+	}
+}
+
+func TestNewVectorFuncNegativeLength(t *testing.T) {
+	defer assertPanic(t, "Invalid vector length -1")
+	NewVectorFunc(-1, func(i int) int { return i })
+}
+
+func TestNewVectorRepeat(t *testing.T) {
+	for _, size := range append(testSizes, 32*32*32+32, 32*32*32+64, 32*32*33+40) {
+		expected := make([]int, size)
+		for i := range expected {
+			expected[i] = 7
+		}
+
+		v := NewVectorRepeat(7, size)
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), v))
+
+		// Updates do not affect other elements sharing the same leaf
+		if size > 0 {
+			v2 := v.Set(0, 1).Set(size-1, 2)
+			t2 := v.Transient()
+			t2.Set(size/2, 3)
+			expected[0], expected[size-1] = 1, 2
+			assertEqualBool(t, true, VectorEqual(NewVector(expected...), v2))
+			assertEqual(t, 3, t2.Persistent().Get(size/2))
+			assertEqual(t, 7, v.Get(0))
+			assertEqual(t, 7, v.Get(size/2))
+			assertEqual(t, 7, v.Get(size-1))
+		}
+
+		assertEqual(t, size+1, v.Append(1).Len())
+	}
+}
+
+func TestNewVectorRepeatSharesNodes(t *testing.T) {
+	v := NewVectorRepeat("x", 1<<25)
+	assertEqual(t, 1<<25, v.Len())
+	assertEqualBool(t, true, v.root.children[0] == v.root.children[1])
+	assertEqualBool(t, true, v.root.children[0].children[0] == v.root.children[0].children[31])
+	assertEqualString(t, "x", v.Get(1<<24+17))
+}
+
+func TestNewVectorRepeatNegativeLength(t *testing.T) {
+	defer assertPanic(t, "Invalid vector length -5")
+	NewVectorRepeat(0, -5)
+}
+
+func TestNewVectorRange(t *testing.T) {
+	for _, size := range testSizes {
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), NewVectorRange(0, size, 1)))
+	}
+
+	for _, tc := range []struct {
+		start, stop, step int
+		expected          []int
+	}{
+		{0, 10, 3, []int{0, 3, 6, 9}},
+		{0, 9, 3, []int{0, 3, 6}},
+		{10, 0, -3, []int{10, 7, 4, 1}},
+		{5, 5, 1, []int{}},
+		{5, 0, 1, []int{}},
+		{0, 5, -1, []int{}},
+		{-3, 3, 2, []int{-3, -1, 1}},
+	} {
+		v := NewVectorRange(tc.start, tc.stop, tc.step)
+		assertEqualBool(t, true, VectorEqual(NewVector(tc.expected...), v))
+	}
+}
+
+func TestNewVectorRangeTypes(t *testing.T) {
+	// Ranges wider than the signed type
+	v := NewVectorRange[int8](-100, 100, 50)
+	assertEqualBool(t, true, VectorEqual(NewVector[int8](-100, -50, 0, 50), v))
+	assertEqual(t, 200, NewVectorRange[int8](-100, 100, 1).Len())
+	assertEqual(t, 255, NewVectorRange[uint8](0, 255, 1).Len())
+	assertEqual(t, 2, NewVectorRange[uint8](250, 255, 3).Len())
+
+	f := NewVectorRange(0, 1, 0.1)
+	assertEqual(t, 10, f.Len())
+	assertEqualBool(t, true, f.Get(9) == 9*0.1)
+	assertEqual(t, 4, NewVectorRange[float32](1, -1, -0.5).Len())
+}
+
+func TestNewVectorRangeZeroStep(t *testing.T) {
+	defer assertPanic(t, "Invalid range step 0")
+	NewVectorRange(0, 10, 0)
+}
+
+func TestVectorBuilder(t *testing.T) {
+	for _, size := range testSizes {
+		b := NewVectorBuilder[int](size)
+		for i := 0; i < size; i++ {
+			b.Append(i)
+		}
+
+		assertEqual(t, size, b.Len())
+		v := b.Freeze()
+		assertEqualBool(t, true, VectorEqual(v, NewVector(inputSlice(0, size)...)))
+	}
+
+	// The capacity is only a hint
+	b := NewVectorBuilder[int](0)
+	b.Append(inputSlice(0, 100)...)
+	assertEqual(t, 100, b.Freeze().Len())
+}
+
+func TestVectorBuilderCapacityAvoidsGrowth(t *testing.T) {
+	// Start without recycled slices of leaves left by other tests, they would hide the growth
+	scratchPools.Delete((**node[int])(nil))
+	allocsWithCapacity := func(capacity int) int {
+		return int(testing.AllocsPerRun(10, func() {
+			b := NewVectorBuilder[int](capacity)
+			for i := 0; i < 32*64; i++ {
+				b.Append(i)
+			}
+		}))
+	}
+
+	// Growing the slice of leaves requires log2(64) extra allocations
+	assertEqual(t, allocsWithCapacity(0)-6, allocsWithCapacity(32*64))
+}
+
+func TestVectorBuilderUseAfterFreeze(t *testing.T) {
+	b := NewVectorBuilder[int](0)
+	b.Freeze()
+	defer assertPanic(t, "VectorBuilder used after Freeze")
+	b.Append(1)
+}
+
+func TestSmallVectorAllocations(t *testing.T) {
+	items := inputSlice(0, 32)
+	var vec *Vector[int]
+
+	// One allocation for the vector and one for the tail
+	assertEqual(t, 2, int(testing.AllocsPerRun(100, func() { vec = NewVector(items...) })))
+	assertEqual(t, 2, int(testing.AllocsPerRun(100, func() { vec.Set(3, 3) })))
+	vec = vec.Shrink(2)
+	assertEqual(t, 2, int(testing.AllocsPerRun(100, func() { vec.Append(3) })))
+}
+
+func TestVectorAppendInPlaceAllocations(t *testing.T) {
+	// Only the vector itself is allocated when the tail has room and no other vector has
+	// appended to it, otherwise the tail is copied as well
+	assertEqual(t, 2+2+1+1, int(testing.AllocsPerRun(100, func() { NewVector(1).Append(2).Append(3).Append(4) })))
+	vec := NewVector(1).Append(2)
+	vec.Append(3)
+	assertEqual(t, 2, int(testing.AllocsPerRun(100, func() { vec.Append(4) })))
+
+	allocs := testing.AllocsPerRun(10, func() {
+		vec = NewVector[int]()
+		for i := 0; i < 1024; i++ {
+			vec = vec.Append(i)
+		}
+	})
+
+	assertEqualBool(t, true, allocs < 1024*1.5)
+}
+
+func TestVectorAppendInPlaceKeepsVersions(t *testing.T) {
+	base := NewVector(inputSlice(0, 40)...).Append(40)
+	a := base.Append(100)
+	b := base.Append(200, 201)
+	c := a.Append(101)
+	d := a.Append(102)
+
+	assertEqual(t, 41, base.Len())
+	assertEqual(t, 100, a.Get(41))
+	assertEqual(t, 42, a.Len())
+	assertEqual(t, 200, b.Get(41))
+	assertEqual(t, 201, b.Get(42))
+	assertEqual(t, 101, c.Get(42))
+	assertEqual(t, 102, d.Get(42))
+	assertEqual(t, 100, d.Get(41))
+
+	// Shrinking shares the tail, appending to the result must not affect the original
+	s := c.Shrink(2).Append(-1)
+	assertEqual(t, -1, s.Get(41))
+	assertEqual(t, 100, c.Get(41))
+}
+
+func TestVectorAppendInPlaceConcurrent(t *testing.T) {
+	base := NewVector(inputSlice(0, 10)...).Append(10)
+	results := make([]*Vector[int], 8)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = base.Append(i)
+		}()
+	}
+
+	wg.Wait()
+	for i, v := range results {
+		assertEqual(t, 12, v.Len())
+		assertEqual(t, i, v.Get(11))
+	}
+}
+
 func TestVectorSetOutOfBoundsNegative(t *testing.T) {
 	defer assertPanic(t, "Index out of bounds")
 	NewVector(inputSlice(0, 10)...).Set(-1, 0)
@@ -187,6 +742,113 @@ func TestCanceledIteration(t *testing.T) {
 /// Slice ///
 /////////////
 
+func TestFind(t *testing.T) {
+	vec := NewVector(inputSlice(0, 1000)...)
+	assertEqual(t, 0, vec.FindIndex(func(i int) bool { return true }))
+	assertEqual(t, 734, vec.FindIndex(func(i int) bool { return i > 733 }))
+	assertEqual(t, -1, vec.FindIndex(func(i int) bool { return i < 0 }))
+
+	item, ok := vec.Find(func(i int) bool { return i%100 == 99 })
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 99, item)
+
+	item, ok = vec.Find(func(i int) bool { return i > 1000 })
+	assertEqualBool(t, false, ok)
+	assertEqual(t, 0, item)
+}
+
+func TestAllIterator(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("All %d", l), func(t *testing.T) {
+			count := 0
+			for i, item := range NewVector(inputSlice(0, l)...).All() {
+				assertEqual(t, count, i)
+				assertEqual(t, i, item)
+				count++
+			}
+
+			assertEqual(t, l, count)
+		})
+	}
+}
+
+func TestBackwardIterator(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Backward %d", l), func(t *testing.T) {
+			expected := l - 1
+			for i, item := range NewVector(inputSlice(0, l)...).Backward() {
+				assertEqual(t, expected, i)
+				assertEqual(t, i, item)
+				expected--
+			}
+
+			assertEqual(t, -1, expected)
+		})
+	}
+}
+
+func TestValuesIterator(t *testing.T) {
+	result := slices.Collect(NewVector(inputSlice(0, 100)...).Values())
+	assertEqual(t, 100, len(result))
+	for i, item := range result {
+		assertEqual(t, i, item)
+	}
+
+	count := 0
+	for item := range NewVector(inputSlice(0, 100)...).Values() {
+		if item == 10 {
+			break
+		}
+
+		count++
+	}
+
+	assertEqual(t, 10, count)
+}
+
+func TestNewVectorFromSeq(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("NewVectorFromSeq %d", l), func(t *testing.T) {
+			vec := NewVectorFromSeq(slices.Values(inputSlice(0, l)))
+			assertEqual(t, l, vec.Len())
+			for i := 0; i < l; i++ {
+				assertEqual(t, i, vec.Get(i))
+			}
+		})
+	}
+}
+
+func TestVectorEqual(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("Equal %d", l), func(t *testing.T) {
+			v1 := NewVector(inputSlice(0, l)...)
+			v2 := NewVector(inputSlice(0, l)...)
+			assertEqualBool(t, true, VectorEqual(v1, v1))
+			assertEqualBool(t, true, VectorEqual(v1, v2))
+			assertEqualBool(t, false, VectorEqual(v1, v2.Append(l)))
+			if l > 0 {
+				assertEqualBool(t, false, VectorEqual(v1, v2.Set(0, -1)))
+				assertEqualBool(t, false, VectorEqual(v1, v1.Set(l-1, -1)))
+				assertEqualBool(t, true, VectorEqual(v1, v1.Set(l/2, -1).Set(l/2, l/2)))
+			}
+		})
+	}
+}
+
+func TestVectorEqualFunc(t *testing.T) {
+	v1 := NewVector("a", "b")
+	v2 := NewVector("A", "B")
+	assertEqualBool(t, true, v1.EqualFunc(v2, strings.EqualFold))
+	assertEqualBool(t, false, v1.EqualFunc(v2, func(a, b string) bool { return a == b }))
+}
+
+func TestVectorEqualDifferentShift(t *testing.T) {
+	v1 := NewVector(inputSlice(0, 32*32+64)...).Shrink(64)
+	v2 := NewVector(inputSlice(0, 32*32)...)
+	assertEqualBool(t, true, VectorEqual(v1, v2))
+	assertEqualBool(t, false, VectorEqual(v1, v2.Set(100, -1)))
+}
+
 func TestSliceIndexes(t *testing.T) {
 	vec := NewVector(inputSlice(0, 1000)...)
 	slice := vec.Slice(0, 10)
@@ -224,6 +886,51 @@ func TestSliceSet(t *testing.T) {
 	assertEqual(t, 123, slice2.Get(5))
 }
 
+func TestSliceFill(t *testing.T) {
+	for _, size := range testSizes {
+		vector := NewVector(inputSlice(0, size)...)
+		for _, r := range []struct{ start, stop int }{{0, size}, {size / 3, size / 2}, {size / 2, size}, {size, size}} {
+			filled := vector.Slice(r.start, r.stop).Fill(-1)
+			assertEqual(t, r.stop-r.start, filled.Len())
+			filled.Range(func(item int) bool {
+				assertEqual(t, -1, item)
+				return true
+			})
+
+			// Elements outside of the slice are not affected
+			expected := inputSlice(0, size)
+			for i := r.start; i < r.stop; i++ {
+				expected[i] = -1
+			}
+
+			assertEqualBool(t, true, VectorEqual(NewVector(expected...), filled.vector))
+			assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), vector))
+		}
+	}
+}
+
+func TestSliceSetRange(t *testing.T) {
+	for _, size := range testSizes {
+		vector := NewVector(inputSlice(0, size)...)
+		slice := vector.Slice(size/4, size)
+		for _, r := range []struct{ i, count int }{{0, slice.Len()}, {slice.Len() / 3, slice.Len() / 2}, {slice.Len(), 0}} {
+			items := inputSlice(-10000, r.count)
+			result := slice.SetRange(r.i, items...)
+			expected := inputSlice(0, size)
+			copy(expected[size/4+r.i:], items)
+			assertEqualBool(t, true, VectorEqual(NewVector(expected...), result.vector))
+			assertEqual(t, slice.Len(), result.Len())
+		}
+
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), vector))
+	}
+}
+
+func TestSliceSetRangeOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Slice bounds out of range")
+	NewVector(inputSlice(0, 100)...).Slice(10, 20).SetRange(8, 1, 2, 3)
+}
+
 func TestSliceAppendInTheMiddleOfBackingVector(t *testing.T) {
 	vector := NewVector(inputSlice(0, 100)...)
 	slice := vector.Slice(0, 50)
@@ -243,6 +950,22 @@ func TestSliceAppendInTheMiddleOfBackingVector(t *testing.T) {
 	assertEqual(t, 59, vector.Get(59))
 }
 
+func TestSliceAppendManyInTheMiddleOfBackingVector(t *testing.T) {
+	vec := NewVector(inputSlice(0, 10000)...)
+	s := vec.Slice(0, 100)
+	items := inputSlice(-200, 200)
+	result := s.Append(items...)
+	assertEqual(t, 300, result.Len())
+	assertEqual(t, 99, result.Get(99))
+	assertEqual(t, -200, result.Get(100))
+	assertEqual(t, -1, result.Get(299))
+	assertEqual(t, 300, vec.Get(300))
+
+	// Nodes touched by more than one item are only copied once
+	allocs := testing.AllocsPerRun(10, func() { s.Append(items...) })
+	assertEqualBool(t, true, allocs < 100)
+}
+
 func TestSliceAppendAtTheEndOfBackingVector(t *testing.T) {
 	vector := NewVector(inputSlice(0, 100)...)
 	slice := vector.Slice(0, 100)
@@ -302,6 +1025,237 @@ func TestSliceCanceledIteration(t *testing.T) {
 	assertEqual(t, 5, count)
 }
 
+func TestFirstAndLast(t *testing.T) {
+	for _, size := range testSizes {
+		v := NewVector(inputSlice(0, size)...)
+		first, ok := v.First()
+		assertEqualBool(t, size > 0, ok)
+		assertEqual(t, 0, first)
+		last, ok := v.Last()
+		assertEqualBool(t, size > 0, ok)
+		assertEqual(t, max(size-1, 0), last)
+	}
+}
+
+func TestHeadAndTail(t *testing.T) {
+	v := NewVector(inputSlice(0, 100)...)
+	head := v.Head(10)
+	assertEqual(t, 10, head.Len())
+	assertEqual(t, 9, head.Get(9))
+	tail := v.Tail(10)
+	assertEqual(t, 10, tail.Len())
+	assertEqual(t, 90, tail.Get(0))
+	assertEqual(t, 100, v.Head(1000).Len())
+	assertEqual(t, 100, v.Tail(1000).Len())
+	assertEqual(t, 0, v.Tail(0).Len())
+	assertEqual(t, 0, NewVector[int]().Head(5).Len())
+}
+
+func TestHeadNegative(t *testing.T) {
+	defer assertPanic(t, "Invalid slice index")
+	NewVector(1, 2).Head(-1)
+}
+
+func TestRangeReverse(t *testing.T) {
+	for _, size := range testSizes {
+		v := NewVector(inputSlice(0, size)...)
+		expected := size - 1
+		v.RangeReverse(func(item int) bool {
+			assertEqual(t, expected, item)
+			expected--
+			return true
+		})
+		assertEqual(t, -1, expected)
+
+		if size < 3 {
+			continue
+		}
+
+		s := v.Slice(1, size-1)
+		expected = size - 2
+		s.RangeReverse(func(item int) bool {
+			assertEqual(t, expected, item)
+			expected--
+			return true
+		})
+		assertEqual(t, 0, expected)
+	}
+}
+
+func TestRangeReverseStop(t *testing.T) {
+	count := 0
+	NewVector(inputSlice(0, 100)...).RangeReverse(func(item int) bool {
+		count++
+		return item > 90
+	})
+	assertEqual(t, 10, count)
+}
+
+func TestRangeIndexed(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("RangeIndexed %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			count := 0
+			vec.RangeIndexed(func(i, item int) bool {
+				assertEqual(t, count, i)
+				assertEqual(t, i, item)
+				count++
+				return true
+			})
+			assertEqual(t, l, count)
+
+			if l > 10 {
+				slice := vec.Slice(5, l-5)
+				count = 0
+				slice.RangeIndexed(func(i, item int) bool {
+					assertEqual(t, count, i)
+					assertEqual(t, i+5, item)
+					count++
+					return i < 3
+				})
+				assertEqual(t, 4, count)
+			}
+		})
+	}
+}
+
+func TestRangeChunks(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("RangeChunks %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			var result []int
+			vec.RangeChunks(func(chunk []int) bool {
+				assertEqualBool(t, true, len(chunk) > 0 && len(chunk) <= 32)
+				result = append(result, chunk...)
+				return true
+			})
+			assertEqualBool(t, true, slices.Equal(inputSlice(0, l), result))
+
+			if l > 40 {
+				result = nil
+				vec.Slice(5, l-3).RangeChunks(func(chunk []int) bool {
+					result = append(result, chunk...)
+					return true
+				})
+				assertEqualBool(t, true, slices.Equal(inputSlice(5, l-8), result))
+			}
+		})
+	}
+}
+
+func TestRangeChunksStop(t *testing.T) {
+	count := 0
+	NewVector(inputSlice(0, 100)...).RangeChunks(func(chunk []int) bool {
+		count++
+		return false
+	})
+	assertEqual(t, 1, count)
+}
+
+func TestAppendToSlice(t *testing.T) {
+	for _, l := range testSizes {
+		t.Run(fmt.Sprintf("AppendToSlice %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			buf := make([]int, 0, l+10)
+			buf = append(buf, -1)
+			result := vec.AppendToSlice(buf)
+			assertEqualBool(t, true, slices.Equal(append([]int{-1}, inputSlice(0, l)...), result))
+			assertEqualBool(t, true, &buf[0] == &result[0])
+			assertEqual(t, 0, int(testing.AllocsPerRun(10, func() { vec.AppendToSlice(buf[:0]) })))
+
+			if l > 10 {
+				result = vec.Slice(5, l-5).AppendToSlice(buf[:0])
+				assertEqualBool(t, true, slices.Equal(inputSlice(5, l-10), result))
+			}
+		})
+	}
+}
+
+func TestSliceMaterialize(t *testing.T) {
+	for _, l := range testSizes[1:] {
+		t.Run(fmt.Sprintf("Materialize %d", l), func(t *testing.T) {
+			vec := NewVector(inputSlice(0, l)...)
+			start, stop := l/3, l-l/3
+			result := vec.Slice(start, stop).Materialize()
+			assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(start, stop-start)...), result))
+			if result.Len() > 0 {
+				assertEqualBool(t, true, &result.sliceFor(0)[0] != &vec.sliceFor(uint(start))[start&shiftBitMask])
+			}
+		})
+	}
+}
+
+func TestSliceFromAndTo(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100)...)
+	assertEqual(t, 30, vec.SliceFrom(70).Len())
+	assertEqual(t, 70, vec.SliceFrom(70).Get(0))
+	assertEqual(t, 3, vec.SliceFrom(-3).Len())
+	assertEqual(t, 97, vec.SliceFrom(-3).Get(0))
+	assertEqual(t, 70, vec.SliceTo(70).Len())
+	assertEqual(t, 99, vec.SliceTo(-1).Len())
+	assertEqual(t, 0, vec.SliceTo(-100).Len())
+
+	s := vec.Slice(10, 90)
+	assertEqual(t, 20, s.SliceFrom(60).Len())
+	assertEqual(t, 85, s.SliceFrom(-5).Get(0))
+	assertEqual(t, 75, s.SliceTo(-5).Len())
+	assertEqual(t, 10, s.SliceTo(-5).Get(0))
+}
+
+func TestSliceFromOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Invalid slice index")
+	NewVector(1, 2, 3).SliceFrom(-4)
+}
+
+func TestSliceToOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Slice bounds out of range")
+	NewVector(1, 2, 3).Slice(0, 2).SliceTo(3)
+}
+
+func TestTryVariants(t *testing.T) {
+	vec := NewVector(inputSlice(0, 100)...)
+	item, ok := vec.TryGet(50)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 50, item)
+	_, ok = vec.TryGet(100)
+	assertEqualBool(t, false, ok)
+	_, ok = vec.TryGet(-1)
+	assertEqualBool(t, false, ok)
+
+	updated, ok := vec.TrySet(3, -3)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, -3, updated.Get(3))
+	updated, ok = vec.TrySet(100, 0)
+	assertEqualBool(t, false, ok)
+	assertEqualBool(t, true, updated == nil)
+
+	s, ok := vec.TrySlice(10, 20)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 10, s.Len())
+	_, ok = vec.TrySlice(20, 10)
+	assertEqualBool(t, false, ok)
+	_, ok = vec.TrySlice(-1, 10)
+	assertEqualBool(t, false, ok)
+	_, ok = vec.TrySlice(0, 101)
+	assertEqualBool(t, false, ok)
+
+	item, ok = s.TryGet(9)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 19, item)
+	_, ok = s.TryGet(10)
+	assertEqualBool(t, false, ok)
+	updatedSlice, ok := s.TrySet(0, -1)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, -1, updatedSlice.Get(0))
+	_, ok = s.TrySet(-1, 0)
+	assertEqualBool(t, false, ok)
+	sub, ok := s.TrySlice(2, 4)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 12, sub.Get(0))
+	_, ok = s.TrySlice(0, 11)
+	assertEqualBool(t, false, ok)
+}
+
 func TestSliceSetOutOfBoundsNegative(t *testing.T) {
 	defer assertPanic(t, "Index out of bounds")
 	NewVector(inputSlice(0, 10)...).Slice(2, 5).Set(-1, 0)
@@ -356,3 +1310,48 @@ func TestToNativeVector(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkVectorGet(b *testing.B) {
+	vec := NewVector(inputSlice(0, 100000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vec.Get(i % 100000)
+	}
+}
+
+func BenchmarkVectorAppend(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		vec := NewVector[int]()
+		for j := 0; j < 1000; j++ {
+			vec = vec.Append(j)
+		}
+	}
+}
+
+func BenchmarkVectorSet(b *testing.B) {
+	vec := NewVector(inputSlice(0, 100000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		vec = vec.Set(i%100000, i)
+	}
+}
+
+func BenchmarkVectorRange(b *testing.B) {
+	vec := NewVector(inputSlice(0, 100000)...)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		sum := 0
+		vec.Range(func(item int) bool {
+			sum += item
+			return true
+		})
+	}
+}
+
+func BenchmarkNewVector(b *testing.B) {
+	items := inputSlice(0, 100000)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewVector(items...)
+	}
+}
@@ -0,0 +1,79 @@
+package peds
+
+import "iter"
+
+// ///////////////
+// / Versioned ///
+// ///////////////
+
+// A Versioned is a persistent/immutable store of all versions of a value, typically one of the
+// persistent collections. Each version is identified by a monotonically increasing id and may
+// be tagged with labels. Retrieving a version by id or label does not depend on the number of
+// versions stored, making Versioned usable as a simple in-memory MVCC layer. Wrap it in a Ref
+// to share it between goroutines.
+type Versioned[T any] struct {
+	versions *Vector[T]
+	labels   *Map[string, int]
+}
+
+// NewVersioned returns a new store with initial as version 0.
+func NewVersioned[T any](initial T) *Versioned[T] {
+	return &Versioned[T]{versions: NewVector(initial), labels: NewMap[string, int]()}
+}
+
+// Commit returns a new store with value added as the latest version, together with the id of
+// the new version.
+func (s *Versioned[T]) Commit(value T) (*Versioned[T], int) {
+	return &Versioned[T]{versions: s.versions.Append(value), labels: s.labels}, s.versions.Len()
+}
+
+// Latest returns the latest version in s.
+func (s *Versioned[T]) Latest() T {
+	return s.versions.Get(s.versions.Len() - 1)
+}
+
+// LatestID returns the id of the latest version in s.
+func (s *Versioned[T]) LatestID() int {
+	return s.versions.Len() - 1
+}
+
+// At returns the version with the given id. ok is set to false if there is no such version.
+func (s *Versioned[T]) At(id int) (value T, ok bool) {
+	return s.versions.TryGet(id)
+}
+
+// Tag returns a new store where label refers to the version with the given id. A label that
+// already refers to another version is moved. Tag panics if there is no version with id.
+func (s *Versioned[T]) Tag(label string, id int) *Versioned[T] {
+	if id < 0 || id >= s.versions.Len() {
+		panic(ErrIndexOutOfBounds{Index: id, Len: s.versions.Len(), Type: "Versioned"})
+	}
+
+	return &Versioned[T]{versions: s.versions, labels: s.labels.Store(label, id)}
+}
+
+// Untag returns a new store without label.
+func (s *Versioned[T]) Untag(label string) *Versioned[T] {
+	return &Versioned[T]{versions: s.versions, labels: s.labels.Delete(label)}
+}
+
+// Labeled returns the version that label refers to and its id. ok is set to false if label
+// does not exist.
+func (s *Versioned[T]) Labeled(label string) (value T, id int, ok bool) {
+	if id, ok = s.labels.Load(label); !ok {
+		return value, 0, false
+	}
+
+	return s.versions.Get(id), id, true
+}
+
+// Versions returns an iterator over the ids and values of all versions in s, oldest first.
+func (s *Versioned[T]) Versions() iter.Seq2[int, T] {
+	return s.versions.All()
+}
+
+// Labels returns an iterator over all labels in s and the ids of the versions they refer to.
+// The iteration order is not specified.
+func (s *Versioned[T]) Labels() iter.Seq2[string, int] {
+	return s.labels.All()
+}
@@ -0,0 +1,51 @@
+package peds
+
+import "testing"
+
+func TestVersioned(t *testing.T) {
+	s := NewVersioned(NewMap[string, int]())
+	s, id := s.Commit(s.Latest().Store("a", 1))
+	assertEqual(t, 1, id)
+	s = s.Tag("release", id)
+	s, id = s.Commit(s.Latest().Store("b", 2))
+	assertEqual(t, 2, id)
+	assertEqual(t, 2, s.LatestID())
+	assertEqual(t, 2, s.Latest().Len())
+
+	v, ok := s.At(0)
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 0, v.Len())
+	_, ok = s.At(3)
+	assertEqualBool(t, false, ok)
+
+	v, id, ok = s.Labeled("release")
+	assertEqualBool(t, true, ok)
+	assertEqual(t, 1, id)
+	assertEqual(t, 1, v.Len())
+
+	moved := s.Tag("release", 2)
+	_, id, _ = moved.Labeled("release")
+	assertEqual(t, 2, id)
+	_, id, _ = s.Labeled("release")
+	assertEqual(t, 1, id)
+
+	_, _, ok = s.Untag("release").Labeled("release")
+	assertEqualBool(t, false, ok)
+
+	count := 0
+	for id, version := range s.Versions() {
+		assertEqual(t, id, version.Len())
+		count++
+	}
+	assertEqual(t, 3, count)
+
+	for label, id := range s.Labels() {
+		assertEqualString(t, "release", label)
+		assertEqual(t, 1, id)
+	}
+}
+
+func TestVersionedTagMissingVersion(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewVersioned(0).Tag("x", 1)
+}
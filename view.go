@@ -0,0 +1,121 @@
+package peds
+
+// A View is a read only sequence of elements. Vector and VectorSlice are views, as are the
+// lazy MappedView and FilteredView which allow a vector to be piped through several
+// transformations without materializing intermediate vectors.
+type View[T any] interface {
+	Len() int
+	Get(i int) T
+	Range(f func(T) bool)
+}
+
+// A MappedView is a View of the results of applying a function to the elements of another
+// view. The function is applied lazily each time an element is read, it should hence be
+// cheap and free from side effects.
+type MappedView[T, U any] struct {
+	src View[T]
+	f   func(T) U
+}
+
+// NewMappedView returns a new view of the results of calling f with each element of src.
+func NewMappedView[T, U any](src View[T], f func(T) U) *MappedView[T, U] {
+	return &MappedView[T, U]{src: src, f: f}
+}
+
+// Len returns the number of elements in mv, the same as in the underlying view.
+func (mv *MappedView[T, U]) Len() int {
+	return mv.src.Len()
+}
+
+// Get returns the result of applying the function to the element at position i of the
+// underlying view.
+func (mv *MappedView[T, U]) Get(i int) U {
+	return mv.f(mv.src.Get(i))
+}
+
+// Range calls f repeatedly passing it each element in mv in order as argument until either
+// all elements have been visited or f returns false.
+func (mv *MappedView[T, U]) Range(f func(U) bool) {
+	mv.src.Range(func(item T) bool { return f(mv.f(item)) })
+}
+
+// ToVector returns a new vector containing the elements of mv.
+func (mv *MappedView[T, U]) ToVector() *Vector[U] {
+	return viewToVector[U](mv)
+}
+
+// A FilteredView is a View of the elements of another view that satisfy a predicate. The
+// predicate is applied lazily each time the view is read. Since the positions of the
+// matching elements are not known up front Len and Get are O(n), Range is the efficient way
+// of reading a FilteredView.
+type FilteredView[T any] struct {
+	src  View[T]
+	pred func(T) bool
+}
+
+// NewFilteredView returns a new view of the elements of src for which pred returns true.
+func NewFilteredView[T any](src View[T], pred func(T) bool) *FilteredView[T] {
+	return &FilteredView[T]{src: src, pred: pred}
+}
+
+// Len returns the number of elements in fv. It is computed by applying the predicate to all
+// elements of the underlying view.
+func (fv *FilteredView[T]) Len() int {
+	count := 0
+	fv.Range(func(T) bool {
+		count++
+		return true
+	})
+
+	return count
+}
+
+// Get returns the element at position i among the matching elements.
+func (fv *FilteredView[T]) Get(i int) T {
+	var result T
+	found, pos := false, 0
+	if i >= 0 {
+		fv.Range(func(item T) bool {
+			if pos == i {
+				result, found = item, true
+				return false
+			}
+
+			pos++
+			return true
+		})
+	}
+
+	if !found {
+		panic(ErrIndexOutOfBounds{Index: i, Len: pos, Type: "FilteredView"})
+	}
+
+	return result
+}
+
+// Range calls f repeatedly passing it each element in fv in order as argument until either
+// all elements have been visited or f returns false.
+func (fv *FilteredView[T]) Range(f func(T) bool) {
+	fv.src.Range(func(item T) bool {
+		if !fv.pred(item) {
+			return true
+		}
+
+		return f(item)
+	})
+}
+
+// ToVector returns a new vector containing the elements of fv.
+func (fv *FilteredView[T]) ToVector() *Vector[T] {
+	return viewToVector[T](fv)
+}
+
+func viewToVector[T any](view View[T]) *Vector[T] {
+	var b leafBuilder[T]
+	view.Range(func(item T) bool {
+		b.add(item)
+		return true
+	})
+
+	return b.vector()
+}
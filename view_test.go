@@ -0,0 +1,71 @@
+package peds
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestMappedView(t *testing.T) {
+	v := NewVector(inputSlice(0, 1000)...)
+	calls := 0
+	mv := NewMappedView[int, string](v, func(i int) string {
+		calls++
+		return strconv.Itoa(i)
+	})
+
+	assertEqual(t, 0, calls)
+	assertEqual(t, 1000, mv.Len())
+	assertEqualString(t, "500", mv.Get(500))
+	assertEqual(t, 1, calls)
+
+	result := mv.ToVector()
+	assertEqual(t, 1000, result.Len())
+	assertEqualString(t, "999", result.Get(999))
+}
+
+func TestFilteredView(t *testing.T) {
+	v := NewVector(inputSlice(0, 1000)...)
+	fv := NewFilteredView[int](v, func(i int) bool { return i%10 == 0 })
+	assertEqual(t, 100, fv.Len())
+	assertEqual(t, 500, fv.Get(50))
+	assertEqual(t, 100, fv.ToVector().Len())
+
+	count := 0
+	fv.Range(func(i int) bool {
+		assertEqual(t, count*10, i)
+		count++
+		return count < 5
+	})
+	assertEqual(t, 5, count)
+}
+
+func TestChainedViewsAreLazy(t *testing.T) {
+	v := NewVector(inputSlice(0, 100000)...)
+	mapped := 0
+	view := NewMappedView[int, int](NewFilteredView[int](NewMappedView[int, int](v, func(i int) int {
+		mapped++
+		return i * 2
+	}), func(i int) bool { return i%3 == 0 }), func(i int) int { return i + 1 })
+
+	// Only the elements needed to find the first three matches are transformed
+	var found []int
+	view.Range(func(i int) bool {
+		found = append(found, i)
+		return len(found) < 3
+	})
+
+	assertEqual(t, 3, len(found))
+	assertEqual(t, 1, found[0])
+	assertEqual(t, 7, found[1])
+	assertEqual(t, 13, found[2])
+	assertEqual(t, 7, mapped)
+
+	slice := NewMappedView[int, int](v.Slice(10, 20), func(i int) int { return -i })
+	assertEqual(t, 10, slice.Len())
+	assertEqual(t, -10, slice.Get(0))
+}
+
+func TestFilteredViewGetOutOfBounds(t *testing.T) {
+	defer assertPanic(t, "Index out of bounds")
+	NewFilteredView[int](NewVector(1, 2, 3), func(i int) bool { return i > 1 }).Get(2)
+}
@@ -0,0 +1,266 @@
+package peds
+
+import (
+	"fmt"
+	"math"
+)
+
+// wireFormat holds the primitives needed to encode collections in a self describing wire
+// format such as MessagePack or CBOR. All read functions return the number of bytes consumed.
+type wireFormat struct {
+	appendInt         func(dst []byte, value int64) []byte
+	appendUint        func(dst []byte, value uint64) []byte
+	readInteger       func(data []byte) (value uint64, negative bool, n int, err error)
+	appendFloat32     func(dst []byte, value float32) []byte
+	appendFloat64     func(dst []byte, value float64) []byte
+	readFloat         func(data []byte) (value float64, n int, err error)
+	appendBool        func(dst []byte, value bool) []byte
+	readBool          func(data []byte) (value bool, n int, err error)
+	appendString      func(dst []byte, value string) []byte
+	readString        func(data []byte) (value []byte, n int, err error)
+	appendBytes       func(dst []byte, value []byte) []byte
+	readBytes         func(data []byte) (value []byte, n int, err error)
+	appendArrayHeader func(dst []byte, count int) []byte
+	readArrayHeader   func(data []byte) (count int, n int, err error)
+	appendMapHeader   func(dst []byte, count int) []byte
+	readMapHeader     func(data []byte) (count int, n int, err error)
+}
+
+type wireIntCodec[I int | int8 | int16 | int32 | int64] struct{ format *wireFormat }
+
+func (c wireIntCodec[I]) Append(dst []byte, value I) ([]byte, error) {
+	return c.format.appendInt(dst, int64(value)), nil
+}
+
+func (c wireIntCodec[I]) Decode(data []byte) (I, int, error) {
+	u, negative, n, err := c.format.readInteger(data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	x := int64(u)
+	if negative != (x < 0) || int64(I(x)) != x {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return I(x), n, nil
+}
+
+type wireUintCodec[U uint | uint8 | uint16 | uint32 | uint64 | uintptr] struct{ format *wireFormat }
+
+func (c wireUintCodec[U]) Append(dst []byte, value U) ([]byte, error) {
+	return c.format.appendUint(dst, uint64(value)), nil
+}
+
+func (c wireUintCodec[U]) Decode(data []byte) (U, int, error) {
+	u, negative, n, err := c.format.readInteger(data)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if negative || uint64(U(u)) != u {
+		return 0, 0, ErrInvalidBinaryData
+	}
+
+	return U(u), n, nil
+}
+
+type wireFloat32Codec struct{ format *wireFormat }
+
+func (c wireFloat32Codec) Append(dst []byte, value float32) ([]byte, error) {
+	return c.format.appendFloat32(dst, value), nil
+}
+
+func (c wireFloat32Codec) Decode(data []byte) (float32, int, error) {
+	f, n, err := c.format.readFloat(data)
+	return float32(f), n, err
+}
+
+type wireFloat64Codec struct{ format *wireFormat }
+
+func (c wireFloat64Codec) Append(dst []byte, value float64) ([]byte, error) {
+	return c.format.appendFloat64(dst, value), nil
+}
+
+func (c wireFloat64Codec) Decode(data []byte) (float64, int, error) {
+	return c.format.readFloat(data)
+}
+
+type wireBoolCodec struct{ format *wireFormat }
+
+func (c wireBoolCodec) Append(dst []byte, value bool) ([]byte, error) {
+	return c.format.appendBool(dst, value), nil
+}
+
+func (c wireBoolCodec) Decode(data []byte) (bool, int, error) {
+	return c.format.readBool(data)
+}
+
+type wireStringCodec struct{ format *wireFormat }
+
+func (c wireStringCodec) Append(dst []byte, value string) ([]byte, error) {
+	return c.format.appendString(dst, value), nil
+}
+
+func (c wireStringCodec) Decode(data []byte) (string, int, error) {
+	b, n, err := c.format.readString(data)
+	return string(b), n, err
+}
+
+type wireBytesCodec struct{ format *wireFormat }
+
+func (c wireBytesCodec) Append(dst []byte, value []byte) ([]byte, error) {
+	return c.format.appendBytes(dst, value), nil
+}
+
+func (c wireBytesCodec) Decode(data []byte) ([]byte, int, error) {
+	b, n, err := c.format.readBytes(data)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return append([]byte(nil), b...), n, nil
+}
+
+// defaultWireCodec returns a codec for values of type T in format. Codecs are available for
+// booleans, numeric types except complex numbers, strings and byte slices.
+func defaultWireCodec[T any](format *wireFormat, formatName string) (Codec[T], error) {
+	var zero T
+	var codec any
+	switch any(zero).(type) {
+	case int:
+		codec = wireIntCodec[int]{format}
+	case int8:
+		codec = wireIntCodec[int8]{format}
+	case int16:
+		codec = wireIntCodec[int16]{format}
+	case int32:
+		codec = wireIntCodec[int32]{format}
+	case int64:
+		codec = wireIntCodec[int64]{format}
+	case uint:
+		codec = wireUintCodec[uint]{format}
+	case uint8:
+		codec = wireUintCodec[uint8]{format}
+	case uint16:
+		codec = wireUintCodec[uint16]{format}
+	case uint32:
+		codec = wireUintCodec[uint32]{format}
+	case uint64:
+		codec = wireUintCodec[uint64]{format}
+	case uintptr:
+		codec = wireUintCodec[uintptr]{format}
+	case float32:
+		codec = wireFloat32Codec{format}
+	case float64:
+		codec = wireFloat64Codec{format}
+	case bool:
+		codec = wireBoolCodec{format}
+	case string:
+		codec = wireStringCodec{format}
+	case []byte:
+		codec = wireBytesCodec{format}
+	default:
+		return nil, fmt.Errorf("peds: no %s codec available for type %T", formatName, zero)
+	}
+
+	return codec.(Codec[T]), nil
+}
+
+func marshalVectorWire[T any](format *wireFormat, v *Vector[T], codec Codec[T]) ([]byte, error) {
+	result := format.appendArrayHeader(nil, v.Len())
+	var err error
+	v.Range(func(item T) bool {
+		result, err = codec.Append(result, item)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func unmarshalVectorWire[T any](format *wireFormat, data []byte, codec Codec[T]) (*Vector[T], error) {
+	count, pos, err := format.readArrayHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]T, 0, count)
+	for i := 0; i < count; i++ {
+		item, n, err := codec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, item)
+		pos += n
+	}
+
+	if pos != len(data) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	return NewVector(items...), nil
+}
+
+func marshalMapWire[K comparable, V any](format *wireFormat, m *Map[K, V], keyCodec Codec[K], valueCodec Codec[V]) ([]byte, error) {
+	result := format.appendMapHeader(nil, m.Len())
+	var err error
+	m.Range(func(key K, value V) bool {
+		if result, err = keyCodec.Append(result, key); err != nil {
+			return false
+		}
+
+		result, err = valueCodec.Append(result, value)
+		return err == nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func unmarshalMapWire[K comparable, V any](format *wireFormat, data []byte, keyCodec Codec[K], valueCodec Codec[V]) (*Map[K, V], error) {
+	count, pos, err := format.readMapHeader(data)
+	if err != nil {
+		return nil, err
+	}
+
+	b := NewMapBuilder[K, V]()
+	for i := 0; i < count; i++ {
+		key, n, err := keyCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += n
+		value, n, err := valueCodec.Decode(data[pos:])
+		if err != nil {
+			return nil, err
+		}
+
+		pos += n
+		b.Set(key, value)
+	}
+
+	if pos != len(data) {
+		return nil, ErrInvalidBinaryData
+	}
+
+	return b.Freeze(), nil
+}
+
+// checkWireCount verifies that count elements, each occupying at least one byte, can fit
+// in remaining bytes. This protects against bogus counts causing huge allocations.
+func checkWireCount(count uint64, remaining int) (int, error) {
+	if count > uint64(remaining) || count > math.MaxInt32 {
+		return 0, ErrInvalidBinaryData
+	}
+
+	return int(count), nil
+}
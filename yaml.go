@@ -0,0 +1,53 @@
+package peds
+
+// The YAML hooks below follow the interfaces used by gopkg.in/yaml.v2 and gopkg.in/yaml.v3,
+// which both accept the function based Unmarshaler, so that no YAML library needs to be
+// imported by this package.
+
+// MarshalYAML encodes v as a YAML sequence.
+func (v *Vector[T]) MarshalYAML() (any, error) {
+	return v.ToNativeSlice(), nil
+}
+
+// UnmarshalYAML replaces the contents of v with the elements of a YAML sequence.
+func (v *Vector[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	var items []T
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+
+	*v = *NewVector(items...)
+	return nil
+}
+
+// MarshalYAML encodes s as a YAML sequence.
+func (s *VectorSlice[T]) MarshalYAML() (any, error) {
+	return s.AppendToSlice(nil), nil
+}
+
+// UnmarshalYAML replaces the contents of s with the elements of a YAML sequence.
+func (s *VectorSlice[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	var items []T
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+
+	*s = *NewVectorSlice(items...)
+	return nil
+}
+
+// MarshalYAML encodes m as a YAML mapping.
+func (m *Map[K, V]) MarshalYAML() (any, error) {
+	return m.ToNativeMap(), nil
+}
+
+// UnmarshalYAML replaces the contents of m with the entries of a YAML mapping.
+func (m *Map[K, V]) UnmarshalYAML(unmarshal func(any) error) error {
+	var items map[K]V
+	if err := unmarshal(&items); err != nil {
+		return err
+	}
+
+	*m = *NewMapFromNativeMap(items)
+	return nil
+}
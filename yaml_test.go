@@ -0,0 +1,72 @@
+package peds
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// jsonUnmarshal returns a function behaving like the one passed to UnmarshalYAML by the YAML
+// libraries, decoding data, JSON being a subset of YAML, into its argument.
+func jsonUnmarshal(data string) func(any) error {
+	return func(out any) error {
+		return json.Unmarshal([]byte(data), out)
+	}
+}
+
+func TestVectorYAML(t *testing.T) {
+	out, err := NewVector(1, 2, 3).MarshalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 3, len(out.([]int)))
+
+	v := NewVector[int]()
+	if err := v.UnmarshalYAML(jsonUnmarshal("[4, 5]")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 2, v.Len())
+	assertEqual(t, 5, v.Get(1))
+}
+
+func TestVectorSliceYAML(t *testing.T) {
+	out, err := NewVectorSlice(1, 2, 3).Slice(1, 3).MarshalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 2, out.([]int)[0])
+
+	s := NewVectorSlice[int]()
+	if err := s.UnmarshalYAML(jsonUnmarshal("[4, 5]")); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 2, s.Len())
+}
+
+func TestMapYAML(t *testing.T) {
+	out, err := NewMap[string, int]().Store("a", 1).MarshalYAML()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 1, out.(map[string]int)["a"])
+
+	m := NewMap[string, int]()
+	if err := m.UnmarshalYAML(jsonUnmarshal(`{"b": 2}`)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	assertEqual(t, 2, m.LoadOr("b", 0))
+}
+
+func TestYAMLUnmarshalError(t *testing.T) {
+	expected := errors.New("bad yaml")
+	v := NewVector(1)
+	err := v.UnmarshalYAML(func(any) error { return expected })
+	assertEqualBool(t, true, err == expected)
+	assertEqual(t, 1, v.Len())
+}
@@ -0,0 +1,203 @@
+package peds
+
+// //////////////////
+// / VectorZipper ///
+// //////////////////
+
+type zipperFrame[T any] struct {
+	parent *node[T]
+	index  int
+}
+
+// A VectorZipper is a cursor into the tree of a Vector that allows localized editing. The
+// focus is a node in the tree that can be moved down to a child, up to the parent or left
+// and right to a sibling. Leaves are edited through Edit. Nodes are only copied the first
+// time they are modified and the path from the focus to the root is only rebuilt when moving
+// up, so a sequence of nearby edits costs far less than the same edits made with Vector.Set.
+// Root returns a Vector with all edits applied.
+//
+// The tree of a zipper contains all elements of the vector, including those in the tail,
+// which is the last leaf. All leaves but the last are full. A VectorZipper is not safe for
+// concurrent use.
+type VectorZipper[T any] struct {
+	len   uint
+	shift uint
+	edit  *editToken
+	path  []zipperFrame[T]
+	focus *node[T]
+}
+
+// Zipper returns a new zipper over v with the focus on the root of the tree.
+func (v *Vector[T]) Zipper() *VectorZipper[T] {
+	// Push the tail into the tree, the vector is rebuilt with a proper tail by Root
+	tail := new([nodeSize]T)
+	copy(tail[:], v.tail)
+	tmp := &Vector[T]{root: v.root, shift: v.shift, len: v.tailOffset() + nodeSize}
+	full := tmp.pushLeafNode(tail[:])
+	return &VectorZipper[T]{len: v.len, shift: full.shift, edit: &editToken{}, focus: full.root}
+}
+
+func (z *VectorZipper[T]) level() uint {
+	return z.shift - uint(len(z.path))*shiftSize
+}
+
+// IsLeaf reports whether the focus is a leaf.
+func (z *VectorZipper[T]) IsLeaf() bool {
+	return z.level() == 0
+}
+
+// Offset returns the position in the vector of the first element below the focus.
+func (z *VectorZipper[T]) Offset() int {
+	offset, level := uint(0), z.shift
+	for _, frame := range z.path {
+		offset += uint(frame.index) << level
+		level -= shiftSize
+	}
+
+	return int(offset)
+}
+
+// Items returns the elements of the leaf in focus. The slice must not be modified, use Edit
+// for that. Items panics if the focus is not a leaf.
+func (z *VectorZipper[T]) Items() []T {
+	if !z.IsLeaf() {
+		panic("VectorZipper focus is not a leaf")
+	}
+
+	return z.focus.items[:uintMin(nodeSize, z.len-uint(z.Offset()))]
+}
+
+// Edit calls f with the elements of the leaf in focus, which f may modify in place. Edit
+// panics if the focus is not a leaf.
+func (z *VectorZipper[T]) Edit(f func(items []T)) {
+	items := z.Items()
+	if z.focus.edit != z.edit {
+		z.focus = z.editable(z.focus)
+		items = z.focus.items[:len(items)]
+	}
+
+	f(items)
+}
+
+func (z *VectorZipper[T]) editable(n *node[T]) *node[T] {
+	if n.edit == z.edit {
+		return n
+	}
+
+	if n.items != nil {
+		items := *n.items
+		return &node[T]{items: &items, edit: z.edit}
+	}
+
+	children := *n.children
+	return &node[T]{children: &children, edit: z.edit}
+}
+
+// Down moves the focus to child i of the focus and reports whether there is such a child.
+func (z *VectorZipper[T]) Down(i int) bool {
+	if z.IsLeaf() || i < 0 || i >= nodeSize || z.focus.children[i] == nil {
+		return false
+	}
+
+	z.path = append(z.path, zipperFrame[T]{parent: z.focus, index: i})
+	z.focus = z.focus.children[i]
+	return true
+}
+
+// Up moves the focus to the parent of the focus and reports whether there is such a parent.
+// The parent is updated to refer to the focus, if it has been modified.
+func (z *VectorZipper[T]) Up() bool {
+	if len(z.path) == 0 {
+		return false
+	}
+
+	frame := z.path[len(z.path)-1]
+	z.path = z.path[:len(z.path)-1]
+	z.focus = z.withChild(frame.parent, frame.index, z.focus)
+	return true
+}
+
+// withChild returns parent, or an editable copy of it, with child as child i.
+func (z *VectorZipper[T]) withChild(parent *node[T], i int, child *node[T]) *node[T] {
+	if parent.children[i] != child {
+		parent = z.editable(parent)
+		parent.children[i] = child
+	}
+
+	return parent
+}
+
+// Left moves the focus to the sibling before the focus and reports whether there is such a
+// sibling.
+func (z *VectorZipper[T]) Left() bool {
+	return z.sibling(-1)
+}
+
+// Right moves the focus to the sibling after the focus and reports whether there is such a
+// sibling.
+func (z *VectorZipper[T]) Right() bool {
+	return z.sibling(1)
+}
+
+func (z *VectorZipper[T]) sibling(delta int) bool {
+	if len(z.path) == 0 {
+		return false
+	}
+
+	frame := &z.path[len(z.path)-1]
+	i := frame.index + delta
+	if i < 0 || i >= nodeSize || frame.parent.children[i] == nil {
+		return false
+	}
+
+	frame.parent = z.withChild(frame.parent, frame.index, z.focus)
+	frame.index, z.focus = i, frame.parent.children[i]
+	return true
+}
+
+// Seek moves the focus to the leaf holding the element at position i, moving up only as far
+// as needed. It returns the position of the element within the leaf.
+func (z *VectorZipper[T]) Seek(i int) int {
+	if i < 0 || uint(i) >= z.len {
+		panic(ErrIndexOutOfBounds{Index: i, Len: int(z.len), Type: "VectorZipper"})
+	}
+
+	for {
+		offset, level := z.Offset(), z.level()
+		if i >= offset && i < offset+1<<(level+shiftSize) {
+			break
+		}
+
+		z.Up()
+	}
+
+	for !z.IsLeaf() {
+		z.Down((i >> z.level()) & shiftBitMask)
+	}
+
+	return i & shiftBitMask
+}
+
+// Root returns a vector with all edits made through z applied. z may continue to be used,
+// later edits do not affect the returned vector.
+func (z *VectorZipper[T]) Root() *Vector[T] {
+	if z.len == 0 {
+		return NewVector[T]()
+	}
+
+	root := z.focus
+	for j := len(z.path) - 1; j >= 0; j-- {
+		z.path[j].parent = z.withChild(z.path[j].parent, z.path[j].index, root)
+		root = z.path[j].parent
+	}
+
+	// Nodes now shared with the returned vector must be copied before being modified again
+	z.edit = &editToken{}
+
+	// A vector one element longer than the tree is large enough for the tree to hold all
+	// leaves, shrinking it turns the last leaf into the tail
+	treeLen := ((z.len-1)>>shiftSize)<<shiftSize + nodeSize
+	var zero T
+	full := &Vector[T]{root: root, shift: z.shift, len: treeLen + 1, tail: []T{zero}}
+	return full.Shrink(int(treeLen + 1 - z.len))
+}
@@ -0,0 +1,84 @@
+package peds
+
+import "testing"
+
+func TestVectorZipperNavigation(t *testing.T) {
+	v := NewVector(inputSlice(0, 2000)...)
+	z := v.Zipper()
+	assertEqualBool(t, false, z.IsLeaf())
+	assertEqualBool(t, false, z.Up())
+	assertEqualBool(t, false, z.Left())
+
+	assertEqualBool(t, true, z.Down(1))
+	assertEqual(t, 1024, z.Offset())
+	assertEqualBool(t, true, z.Down(0))
+	assertEqualBool(t, true, z.IsLeaf())
+	assertEqual(t, 1024, z.Items()[0])
+	assertEqualBool(t, false, z.Down(0))
+	assertEqualBool(t, true, z.Right())
+	assertEqual(t, 1056, z.Items()[0])
+	assertEqualBool(t, true, z.Left())
+	assertEqualBool(t, false, z.Left())
+
+	// The last leaf holds the elements of the tail
+	for z.Right() {
+	}
+
+	assertEqual(t, 1024+30*32, z.Offset())
+	assertEqual(t, 2000-(1024+30*32), len(z.Items()))
+	assertEqual(t, 1999, z.Items()[len(z.Items())-1])
+	assertEqualBool(t, false, z.Right())
+}
+
+func TestVectorZipperEdit(t *testing.T) {
+	for _, size := range testSizes {
+		if size == 0 {
+			continue
+		}
+
+		v := NewVector(inputSlice(0, size)...)
+		z := v.Zipper()
+		expected := inputSlice(0, size)
+		for _, i := range []int{size - 1, 0, size / 2, min(size/2+1, size-1), size / 3} {
+			pos := z.Seek(i)
+			z.Edit(func(items []int) { items[pos] = -i })
+			expected[i] = -i
+		}
+
+		result := z.Root()
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), result))
+		assertEqualBool(t, true, VectorEqual(NewVector(inputSlice(0, size)...), v))
+
+		// The result is an ordinary vector and later edits do not affect it
+		z.Edit(func(items []int) { items[0] = 12345 })
+		assertEqualBool(t, true, VectorEqual(NewVector(expected...), result))
+		assertEqual(t, size+1, result.Append(1).Len())
+	}
+}
+
+func TestVectorZipperNearbyEditsCopyOnce(t *testing.T) {
+	v := NewVector(inputSlice(0, 10000)...)
+	z := v.Zipper()
+	z.Seek(5000)
+	z.Edit(func(items []int) { items[0] = -1 })
+
+	// Editing the leaf again, or a sibling after it has been copied, does not allocate
+	allocs := testing.AllocsPerRun(100, func() {
+		pos := z.Seek(5001)
+		z.Edit(func(items []int) { items[pos]++ })
+	})
+	assertEqual(t, 0, int(allocs))
+	assertEqual(t, 5001+101, z.Root().Get(5001))
+}
+
+func TestVectorZipperEmpty(t *testing.T) {
+	z := NewVector[int]().Zipper()
+	assertEqualBool(t, true, z.Down(0))
+	assertEqual(t, 0, len(z.Items()))
+	assertEqual(t, 0, z.Root().Len())
+}
+
+func TestVectorZipperEditBranch(t *testing.T) {
+	defer assertPanic(t, "VectorZipper focus is not a leaf")
+	NewVector(1, 2, 3).Zipper().Edit(func([]int) {})
+}